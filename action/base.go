@@ -92,6 +92,9 @@ func ValidateBasic(data []byte, signerAddr []Address, signatures []Signature) er
 }
 
 func ValidateFee(feeOpt *fees.FeeOption, fee Fee) error {
+	if feeOpt == nil {
+		return errors.Wrap(ErrMissingData, "no fee option to validate the fee against")
+	}
 	if fee.Price.Currency != feeOpt.FeeCurrency.Name {
 		return ErrInvalidFeeCurrency
 	}
@@ -101,12 +104,23 @@ func ValidateFee(feeOpt *fees.FeeOption, fee Fee) error {
 	return nil
 }
 
-func BasicFeeHandling(ctx *Context, signedTx SignedTx, start Gas, size Gas, signatureCnt Gas) (bool, Response) {
-	ctx.State.ConsumeVerifySigGas(signatureCnt)
+// BasicIntrinsicGas is the flat cost of a tx plus the verification of each of its signatures
+func BasicIntrinsicGas(ctx *Context, signedTx SignedTx) Gas {
+	schedule := ctx.State.GasSchedule()
+	return schedule.TxFlat + Gas(len(signedTx.Signatures))*schedule.VerifySig
+}
+
+// ConsumeTxGas consumes the intrinsic gas of a tx and the gas for storing its bytes, and returns
+// the gas used by the tx since start
+func ConsumeTxGas(ctx *Context, intrinsic Gas, start Gas, size Gas) Gas {
+	ctx.State.ConsumeUpfront(intrinsic)
 	ctx.State.ConsumeStorageGas(size)
+	return ctx.State.ConsumedGas() - start
+}
+
+func BasicFeeHandling(ctx *Context, signedTx SignedTx, start Gas, size Gas, intrinsic Gas) (bool, Response) {
 	// check the used gas for the tx
-	final := ctx.Balances.State.ConsumedGas()
-	used := int64(final - start)
+	used := int64(ConsumeTxGas(ctx, intrinsic, start, size))
 	if used > signedTx.Fee.Gas {
 		return false, Response{Log: ErrGasOverflow.Error(), GasWanted: signedTx.Fee.Gas, GasUsed: signedTx.Fee.Gas}
 	}
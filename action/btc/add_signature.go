@@ -118,6 +118,11 @@ func (ast btcAddSignatureTx) ProcessFee(ctx *action.Context, signedTx action.Sig
 	return true, action.Response{}
 }
 
+// internal txs of the validators are not charged
+func (ast btcAddSignatureTx) IntrinsicGas(ctx *action.Context, signedTx action.SignedTx) action.Gas {
+	return 0
+}
+
 func runAddSignature(ctx *action.Context, tx action.RawTx) (bool, action.Response) {
 
 	addSignature := AddSignature{}
@@ -104,6 +104,11 @@ func (b *btcBroadcastSuccessTx) ProcessFee(ctx *action.Context, signedTx action.
 	return true, action.Response{}
 }
 
+// internal txs of the validators are not charged
+func (b *btcBroadcastSuccessTx) IntrinsicGas(ctx *action.Context, signedTx action.SignedTx) action.Gas {
+	return 0
+}
+
 func (b *btcBroadcastSuccessTx) process(ctx *action.Context, tx action.RawTx) (bool, action.Response) {
 
 	broadcastSuccess := BroadcastSuccess{}
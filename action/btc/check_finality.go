@@ -120,6 +120,11 @@ func (reportFinalityMintTx) ProcessFee(ctx *action.Context, signedTx action.Sign
 	return true, action.Response{}
 }
 
+// internal txs of the validators are not charged
+func (reportFinalityMintTx) IntrinsicGas(ctx *action.Context, signedTx action.SignedTx) action.Gas {
+	return 0
+}
+
 func runReportFinalityMint(ctx *action.Context, tx action.RawTx) (bool, action.Response) {
 
 	f := ReportFinalityMint{}
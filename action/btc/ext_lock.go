@@ -135,9 +135,12 @@ func (btcLockTx) ProcessDeliver(ctx *action.Context, tx action.RawTx) (bool, act
 	return runBTCLock(ctx, tx)
 }
 
-func (btcLockTx) ProcessFee(ctx *action.Context, signedTx action.SignedTx, start action.Gas, size action.Gas) (bool, action.Response) {
-	ctx.State.ConsumeUpfront(701660)
-	return action.BasicFeeHandling(ctx, signedTx, start, size, 1)
+func (b btcLockTx) ProcessFee(ctx *action.Context, signedTx action.SignedTx, start action.Gas, size action.Gas) (bool, action.Response) {
+	return action.BasicFeeHandling(ctx, signedTx, start, size, b.IntrinsicGas(ctx, signedTx))
+}
+
+func (b btcLockTx) IntrinsicGas(ctx *action.Context, signedTx action.SignedTx) action.Gas {
+	return 701660 + action.BasicIntrinsicGas(ctx, signedTx)
 }
 
 func runBTCLock(ctx *action.Context, tx action.RawTx) (bool, action.Response) {
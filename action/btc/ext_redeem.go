@@ -138,9 +138,12 @@ func (btcRedeemTx) ProcessDeliver(ctx *action.Context, tx action.RawTx) (bool, a
 	return runExtRedeem(ctx, tx)
 }
 
-func (btcRedeemTx) ProcessFee(ctx *action.Context, signedTx action.SignedTx, start action.Gas, size action.Gas) (bool, action.Response) {
-	ctx.State.ConsumeUpfront(701660)
-	return action.BasicFeeHandling(ctx, signedTx, start, size, 1)
+func (b btcRedeemTx) ProcessFee(ctx *action.Context, signedTx action.SignedTx, start action.Gas, size action.Gas) (bool, action.Response) {
+	return action.BasicFeeHandling(ctx, signedTx, start, size, b.IntrinsicGas(ctx, signedTx))
+}
+
+func (b btcRedeemTx) IntrinsicGas(ctx *action.Context, signedTx action.SignedTx) action.Gas {
+	return 701660 + action.BasicIntrinsicGas(ctx, signedTx)
 }
 
 func runExtRedeem(ctx *action.Context, tx action.RawTx) (bool, action.Response) {
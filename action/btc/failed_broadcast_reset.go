@@ -98,6 +98,11 @@ func (btcBroadcastFailureReset) ProcessFee(ctx *action.Context, signedTx action.
 	return true, action.Response{}
 }
 
+// internal txs of the validators are not charged
+func (btcBroadcastFailureReset) IntrinsicGas(ctx *action.Context, signedTx action.SignedTx) action.Gas {
+	return 0
+}
+
 func runBroadcastFailureReset(ctx *action.Context, tx action.RawTx) (bool, action.Response) {
 	fbr := FailedBroadcastReset{}
 
@@ -164,5 +169,10 @@ func runBroadcastFailureReset(ctx *action.Context, tx action.RawTx) (bool, actio
 	tracker.FinalityVotes = []keys.Address{}
 	tracker.ResetVotes = []keys.Address{}
 
+	err = ctx.BTCTrackers.SetTracker(fbr.TrackerName, tracker)
+	if err != nil {
+		return false, action.Response{Log: "failed to reset tracker"}
+	}
+
 	return true, action.Response{Tags: fbr.Tags()}
 }
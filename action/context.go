@@ -9,6 +9,7 @@ import (
 	"github.com/Oneledger/protocol/data/bitcoin"
 	"github.com/Oneledger/protocol/data/ethereum"
 	"github.com/Oneledger/protocol/data/fees"
+	"github.com/Oneledger/protocol/data/governance"
 	"github.com/Oneledger/protocol/data/jobs"
 	"github.com/Oneledger/protocol/data/ons"
 	"github.com/Oneledger/protocol/identity"
@@ -23,6 +24,7 @@ type Context struct {
 	Accounts             accounts.Wallet
 	Balances             *balance.Store
 	Domains              *ons.DomainStore
+	Govern               *governance.Store
 	FeePool              *fees.Store
 	Currencies           *balance.CurrencySet
 	FeeOpt               *fees.FeeOption
@@ -40,7 +42,7 @@ type Context struct {
 func NewContext(r Router, header *abci.Header, state *storage.State,
 	wallet accounts.Wallet, balances *balance.Store,
 	currencies *balance.CurrencySet, feeOpt *fees.FeeOption, feePool *fees.Store,
-	validators *identity.ValidatorStore, domains *ons.DomainStore,
+	validators *identity.ValidatorStore, domains *ons.DomainStore, govern *governance.Store,
 	btcTrackers *bitcoin.TrackerStore, ethTrackers *ethereum.TrackerStore,
 	jobStore *jobs.JobStore, btcChainType *chaincfg.Params, lockScriptStore *bitcoin.LockScriptStore,
 	blockCypherToken, blockCypherChainType string,
@@ -53,6 +55,7 @@ func NewContext(r Router, header *abci.Header, state *storage.State,
 		Accounts:        wallet,
 		Balances:        balances,
 		Domains:         domains,
+		Govern:          govern,
 		FeePool:         feePool,
 		Currencies:      currencies,
 		FeeOpt:          feeOpt,
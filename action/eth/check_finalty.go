@@ -150,16 +150,17 @@ func runCheckFinality(ctx *action.Context, tx action.RawTx) (bool, action.Respon
 	return true, action.Response{Log: "vote success, not ready to mint: " + strconv.Itoa(yes) + strconv.Itoa(no)}
 }
 
-func (reportFinalityMintTx) ProcessFee(ctx *action.Context, signedTx action.SignedTx, start action.Gas, size action.Gas) (bool, action.Response) {
-	ctx.State.ConsumeVerifySigGas(1)
-	ctx.State.ConsumeStorageGas(size)
+func (r reportFinalityMintTx) ProcessFee(ctx *action.Context, signedTx action.SignedTx, start action.Gas, size action.Gas) (bool, action.Response) {
 	// check the used gas for the tx
-	final := ctx.Balances.State.ConsumedGas()
-	used := int64(final - start)
+	used := int64(action.ConsumeTxGas(ctx, r.IntrinsicGas(ctx, signedTx), start, size))
 	fmt.Println("gas used:", used)
 	return true, action.Response{}
 }
 
+func (reportFinalityMintTx) IntrinsicGas(ctx *action.Context, signedTx action.SignedTx) action.Gas {
+	return action.BasicIntrinsicGas(ctx, signedTx)
+}
+
 func mintTokens(ctx *action.Context, tracker *trackerlib.Tracker, oltTx ReportFinality) error {
 	curr, ok := ctx.Currencies.GetCurrencyByName("ETH")
 	if !ok {
@@ -126,9 +126,12 @@ func (e ethLockTx) ProcessDeliver(ctx *action.Context, tx action.RawTx) (bool, a
 }
 
 // ProcessFee
-func (ethLockTx) ProcessFee(ctx *action.Context, signedTx action.SignedTx, start action.Gas, size action.Gas) (bool, action.Response) {
-	ctx.State.ConsumeUpfront(237600)
-	return action.BasicFeeHandling(ctx, signedTx, start, size, 1)
+func (e ethLockTx) ProcessFee(ctx *action.Context, signedTx action.SignedTx, start action.Gas, size action.Gas) (bool, action.Response) {
+	return action.BasicFeeHandling(ctx, signedTx, start, size, e.IntrinsicGas(ctx, signedTx))
+}
+
+func (e ethLockTx) IntrinsicGas(ctx *action.Context, signedTx action.SignedTx) action.Gas {
+	return 237600 + action.BasicIntrinsicGas(ctx, signedTx)
 }
 
 // processCommon
@@ -162,7 +162,10 @@ func processCommon(ctx *action.Context, tx action.RawTx) (bool, action.Response)
 	}
 }
 
-func (ethRedeemTx) ProcessFee(ctx *action.Context, signedTx action.SignedTx, start action.Gas, size action.Gas) (bool, action.Response) {
-	ctx.State.ConsumeUpfront(250400)
-	return action.BasicFeeHandling(ctx, signedTx, start, size, 1)
+func (e ethRedeemTx) ProcessFee(ctx *action.Context, signedTx action.SignedTx, start action.Gas, size action.Gas) (bool, action.Response) {
+	return action.BasicFeeHandling(ctx, signedTx, start, size, e.IntrinsicGas(ctx, signedTx))
+}
+
+func (e ethRedeemTx) IntrinsicGas(ctx *action.Context, signedTx action.SignedTx) action.Gas {
+	return 250400 + action.BasicIntrinsicGas(ctx, signedTx)
 }
@@ -0,0 +1,166 @@
+package governance
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"github.com/tendermint/tendermint/libs/common"
+
+	"github.com/Oneledger/protocol/action"
+	"github.com/Oneledger/protocol/data/governance"
+	"github.com/Oneledger/protocol/storage"
+)
+
+// GasScheduleVote is a validator's vote to replace the gas schedule. The schedule changes once validators
+// holding more than 2/3 of the voting power voted for the same one, and the new costs apply from the next block.
+type GasScheduleVote struct {
+	ValidatorAddress action.Address
+	Schedule         storage.GasSchedule
+}
+
+var _ action.Msg = &GasScheduleVote{}
+
+func (v *GasScheduleVote) Signers() []action.Address {
+	return []action.Address{v.ValidatorAddress}
+}
+
+func (v *GasScheduleVote) Type() action.Type {
+	return action.GAS_SCHEDULE_VOTE
+}
+
+func (v *GasScheduleVote) Tags() common.KVPairs {
+	tags := make([]common.KVPair, 0)
+
+	tag := common.KVPair{
+		Key:   []byte("tx.type"),
+		Value: []byte(v.Type().String()),
+	}
+	tag2 := common.KVPair{
+		Key:   []byte("tx.validator"),
+		Value: v.ValidatorAddress.Bytes(),
+	}
+
+	tags = append(tags, tag, tag2)
+	return tags
+}
+
+func (v *GasScheduleVote) Marshal() ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (v *GasScheduleVote) Unmarshal(data []byte) error {
+	return json.Unmarshal(data, v)
+}
+
+var _ action.Tx = gasScheduleVoteTx{}
+
+type gasScheduleVoteTx struct {
+}
+
+func (gasScheduleVoteTx) Validate(ctx *action.Context, signedTx action.SignedTx) (bool, error) {
+	vote := &GasScheduleVote{}
+	err := vote.Unmarshal(signedTx.Data)
+	if err != nil {
+		return false, errors.Wrap(action.ErrWrongTxType, err.Error())
+	}
+
+	err = action.ValidateBasic(signedTx.RawBytes(), vote.Signers(), signedTx.Signatures)
+	if err != nil {
+		return false, err
+	}
+
+	err = vote.Schedule.Validate()
+	if err != nil {
+		return false, err
+	}
+
+	if !ctx.Validators.IsValidatorAddress(vote.ValidatorAddress) {
+		return false, errors.New("only validators can vote for a gas schedule")
+	}
+
+	return true, nil
+}
+
+func (gasScheduleVoteTx) ProcessCheck(ctx *action.Context, tx action.RawTx) (bool, action.Response) {
+	return runGasScheduleVote(ctx, tx)
+}
+
+func (gasScheduleVoteTx) ProcessDeliver(ctx *action.Context, tx action.RawTx) (bool, action.Response) {
+	return runGasScheduleVote(ctx, tx)
+}
+
+// votes of the validators are not charged, like their internal txs
+func (gasScheduleVoteTx) ProcessFee(ctx *action.Context, signedTx action.SignedTx, start action.Gas, size action.Gas) (bool, action.Response) {
+	return true, action.Response{}
+}
+
+func (gasScheduleVoteTx) IntrinsicGas(ctx *action.Context, signedTx action.SignedTx) action.Gas {
+	return 0
+}
+
+func runGasScheduleVote(ctx *action.Context, tx action.RawTx) (bool, action.Response) {
+	vote := &GasScheduleVote{}
+	err := vote.Unmarshal(tx.Data)
+	if err != nil {
+		return false, action.Response{Log: err.Error()}
+	}
+
+	if !ctx.Validators.IsValidatorAddress(vote.ValidatorAddress) {
+		return false, action.Response{Log: "voter not found in validator list"}
+	}
+
+	err = vote.Schedule.Validate()
+	if err != nil {
+		return false, action.Response{Log: err.Error()}
+	}
+
+	votes, err := ctx.Govern.GetGasScheduleVotes()
+	if err != nil {
+		return false, action.Response{Log: err.Error()}
+	}
+
+	// a new vote replaces the earlier one of the validator, votes of validators that left the set are dropped
+	power := make(map[storage.GasSchedule]int64)
+	var total int64
+	valSet, err := ctx.Validators.GetValidatorSet()
+	if err != nil {
+		return false, action.Response{Log: "cannot get validator set"}
+	}
+	current := make(map[string]int64)
+	for _, v := range valSet {
+		current[v.Address.String()] = v.Power
+		total += v.Power
+	}
+
+	kept := make([]governance.GasScheduleVote, 0, len(votes)+1)
+	for _, v := range votes {
+		if bytes.Equal(v.Validator, vote.ValidatorAddress) || current[v.Validator.String()] <= 0 {
+			continue
+		}
+		kept = append(kept, v)
+		power[v.Schedule] += current[v.Validator.String()]
+	}
+	kept = append(kept, governance.GasScheduleVote{Validator: vote.ValidatorAddress, Schedule: vote.Schedule})
+	power[vote.Schedule] += current[vote.ValidatorAddress.String()]
+
+	tags := vote.Tags()
+	if 3*power[vote.Schedule] > 2*total {
+		err = ctx.Govern.SetGasSchedule(vote.Schedule)
+		if err != nil {
+			return false, action.Response{Log: errors.Wrap(err, "failed to set the gas schedule").Error()}
+		}
+		kept = kept[:0]
+		tags = append(tags, common.KVPair{
+			Key:   []byte("governance.gas_schedule"),
+			Value: []byte("updated"),
+		})
+	}
+
+	err = ctx.Govern.SetGasScheduleVotes(kept)
+	if err != nil {
+		return false, action.Response{Log: err.Error()}
+	}
+
+	return true, action.Response{Tags: tags}
+}
@@ -0,0 +1,119 @@
+package governance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/libs/common"
+	db "github.com/tendermint/tendermint/libs/db"
+
+	"github.com/Oneledger/protocol/action"
+	"github.com/Oneledger/protocol/config"
+	"github.com/Oneledger/protocol/data/balance"
+	"github.com/Oneledger/protocol/data/governance"
+	"github.com/Oneledger/protocol/data/keys"
+	"github.com/Oneledger/protocol/identity"
+	"github.com/Oneledger/protocol/storage"
+)
+
+type voter struct {
+	address keys.Address
+	key     ed25519.PrivKeyEd25519
+}
+
+// setup returns a context with validators of the given powers
+func setup(t *testing.T, powers ...int64) (*action.Context, []voter) {
+	cs := storage.NewState(storage.NewChainState("governance", db.NewDB("test", db.MemDBBackend, "")))
+	validators := identity.NewValidatorStore("v", config.Server{}, cs)
+	govern := governance.NewStore("g", cs)
+
+	voters := make([]voter, 0, len(powers))
+	for _, power := range powers {
+		key := ed25519.GenPrivKey()
+		v := voter{address: key.PubKey().Address().Bytes(), key: key}
+		require.NoError(t, validators.HandleStake(identity.Stake{
+			ValidatorAddress: v.address,
+			Amount:           *balance.NewAmount(power),
+		}))
+		voters = append(voters, v)
+	}
+	// validators are iterated from the last commit
+	cs.Commit()
+
+	ctx := &action.Context{
+		State:      cs,
+		Validators: validators,
+		Govern:     govern,
+	}
+	return ctx, voters
+}
+
+func vote(t *testing.T, ctx *action.Context, v voter, schedule storage.GasSchedule) action.Response {
+	msg := &GasScheduleVote{ValidatorAddress: v.address, Schedule: schedule}
+	data, err := msg.Marshal()
+	require.NoError(t, err)
+	tx := action.RawTx{Type: action.GAS_SCHEDULE_VOTE, Data: data, Memo: "vote"}
+
+	sig, err := v.key.Sign(tx.RawBytes())
+	require.NoError(t, err)
+	pub := v.key.PubKey().(ed25519.PubKeyEd25519)
+	pubKey, err := keys.GetPublicKeyFromBytes(pub[:], keys.ED25519)
+	require.NoError(t, err)
+	ok, err := gasScheduleVoteTx{}.Validate(ctx, action.SignedTx{RawTx: tx, Signatures: []action.Signature{{Signer: pubKey, Signed: sig}}})
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, resp := gasScheduleVoteTx{}.ProcessDeliver(ctx, tx)
+	require.True(t, ok, resp.Log)
+	return resp
+}
+
+func TestGasScheduleVote(t *testing.T) {
+	ctx, voters := setup(t, 1, 1, 1, 2)
+	cheaper := storage.DefaultGasSchedule()
+	cheaper.VerifySig = 1000
+	other := storage.DefaultGasSchedule()
+	other.WriteFlat = 10
+
+	vote(t, ctx, voters[0], cheaper)
+	vote(t, ctx, voters[1], other)
+	// voting again replaces the earlier vote
+	vote(t, ctx, voters[1], cheaper)
+	votes, err := ctx.Govern.GetGasScheduleVotes()
+	require.NoError(t, err)
+	assert.Len(t, votes, 2)
+
+	// 2 of 5 is not a majority yet
+	schedule, err := ctx.Govern.GetGasSchedule()
+	require.NoError(t, err)
+	assert.Equal(t, storage.DefaultGasSchedule(), schedule)
+
+	// 4 of 5 is
+	resp := vote(t, ctx, voters[3], cheaper)
+	assert.Contains(t, resp.Tags, common.KVPair{Key: []byte("governance.gas_schedule"), Value: []byte("updated")})
+	schedule, err = ctx.Govern.GetGasSchedule()
+	require.NoError(t, err)
+	assert.Equal(t, cheaper, schedule)
+	votes, err = ctx.Govern.GetGasScheduleVotes()
+	require.NoError(t, err)
+	assert.Empty(t, votes)
+}
+
+func TestGasScheduleVote_Invalid(t *testing.T) {
+	ctx, voters := setup(t, 1)
+
+	negative := storage.DefaultGasSchedule()
+	negative.ReadFlat = -1
+	msg := &GasScheduleVote{ValidatorAddress: voters[0].address, Schedule: negative}
+	data, _ := msg.Marshal()
+	ok, _ := gasScheduleVoteTx{}.ProcessDeliver(ctx, action.RawTx{Type: action.GAS_SCHEDULE_VOTE, Data: data})
+	assert.False(t, ok)
+
+	outsider := ed25519.GenPrivKey().PubKey().Address().Bytes()
+	msg = &GasScheduleVote{ValidatorAddress: outsider, Schedule: storage.DefaultGasSchedule()}
+	data, _ = msg.Marshal()
+	ok, _ = gasScheduleVoteTx{}.ProcessDeliver(ctx, action.RawTx{Type: action.GAS_SCHEDULE_VOTE, Data: data})
+	assert.False(t, ok)
+}
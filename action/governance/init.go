@@ -0,0 +1,25 @@
+package governance
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/Oneledger/protocol/action"
+	"github.com/Oneledger/protocol/serialize"
+)
+
+func init() {
+	serialize.RegisterConcrete(new(GasScheduleVote), "action_gsv")
+	serialize.RegisterConcrete(new(ReservedNamesVote), "action_rnv")
+}
+
+func EnableGovernance(r action.Router) error {
+	err := r.AddHandler(action.GAS_SCHEDULE_VOTE, gasScheduleVoteTx{})
+	if err != nil {
+		return errors.Wrap(err, "gasScheduleVoteTx")
+	}
+	err = r.AddHandler(action.RESERVED_NAMES_VOTE, reservedNamesVoteTx{})
+	if err != nil {
+		return errors.Wrap(err, "reservedNamesVoteTx")
+	}
+	return nil
+}
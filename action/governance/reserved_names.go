@@ -0,0 +1,171 @@
+package governance
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/tendermint/tendermint/libs/common"
+
+	"github.com/Oneledger/protocol/action"
+	"github.com/Oneledger/protocol/data/governance"
+)
+
+// ReservedNamesVote is a validator's vote to replace the top level ONS names nobody can register. The names
+// change once validators holding more than 2/3 of the voting power voted for the same list, in any order or case.
+type ReservedNamesVote struct {
+	ValidatorAddress action.Address
+	Names            []string
+}
+
+var _ action.Msg = &ReservedNamesVote{}
+
+func (v *ReservedNamesVote) Signers() []action.Address {
+	return []action.Address{v.ValidatorAddress}
+}
+
+func (v *ReservedNamesVote) Type() action.Type {
+	return action.RESERVED_NAMES_VOTE
+}
+
+func (v *ReservedNamesVote) Tags() common.KVPairs {
+	tags := make([]common.KVPair, 0)
+
+	tag := common.KVPair{
+		Key:   []byte("tx.type"),
+		Value: []byte(v.Type().String()),
+	}
+	tag2 := common.KVPair{
+		Key:   []byte("tx.validator"),
+		Value: v.ValidatorAddress.Bytes(),
+	}
+
+	tags = append(tags, tag, tag2)
+	return tags
+}
+
+func (v *ReservedNamesVote) Marshal() ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (v *ReservedNamesVote) Unmarshal(data []byte) error {
+	return json.Unmarshal(data, v)
+}
+
+var _ action.Tx = reservedNamesVoteTx{}
+
+type reservedNamesVoteTx struct {
+}
+
+func (reservedNamesVoteTx) Validate(ctx *action.Context, signedTx action.SignedTx) (bool, error) {
+	vote := &ReservedNamesVote{}
+	err := vote.Unmarshal(signedTx.Data)
+	if err != nil {
+		return false, errors.Wrap(action.ErrWrongTxType, err.Error())
+	}
+
+	err = action.ValidateBasic(signedTx.RawBytes(), vote.Signers(), signedTx.Signatures)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = governance.NormalizeReservedNames(vote.Names)
+	if err != nil {
+		return false, err
+	}
+
+	if !ctx.Validators.IsValidatorAddress(vote.ValidatorAddress) {
+		return false, errors.New("only validators can vote for the reserved names")
+	}
+
+	return true, nil
+}
+
+func (reservedNamesVoteTx) ProcessCheck(ctx *action.Context, tx action.RawTx) (bool, action.Response) {
+	return runReservedNamesVote(ctx, tx)
+}
+
+func (reservedNamesVoteTx) ProcessDeliver(ctx *action.Context, tx action.RawTx) (bool, action.Response) {
+	return runReservedNamesVote(ctx, tx)
+}
+
+// votes of the validators are not charged, like their internal txs
+func (reservedNamesVoteTx) ProcessFee(ctx *action.Context, signedTx action.SignedTx, start action.Gas, size action.Gas) (bool, action.Response) {
+	return true, action.Response{}
+}
+
+func (reservedNamesVoteTx) IntrinsicGas(ctx *action.Context, signedTx action.SignedTx) action.Gas {
+	return 0
+}
+
+func runReservedNamesVote(ctx *action.Context, tx action.RawTx) (bool, action.Response) {
+	vote := &ReservedNamesVote{}
+	err := vote.Unmarshal(tx.Data)
+	if err != nil {
+		return false, action.Response{Log: err.Error()}
+	}
+
+	if !ctx.Validators.IsValidatorAddress(vote.ValidatorAddress) {
+		return false, action.Response{Log: "voter not found in validator list"}
+	}
+
+	names, err := governance.NormalizeReservedNames(vote.Names)
+	if err != nil {
+		return false, action.Response{Log: err.Error()}
+	}
+
+	votes, err := ctx.Govern.GetReservedNamesVotes()
+	if err != nil {
+		return false, action.Response{Log: err.Error()}
+	}
+
+	// a new vote replaces the earlier one of the validator, votes of validators that left the set are dropped
+	power := make(map[string]int64)
+	var total int64
+	valSet, err := ctx.Validators.GetValidatorSet()
+	if err != nil {
+		return false, action.Response{Log: "cannot get validator set"}
+	}
+	current := make(map[string]int64)
+	for _, v := range valSet {
+		current[v.Address.String()] = v.Power
+		total += v.Power
+	}
+
+	kept := make([]governance.ReservedNamesVote, 0, len(votes)+1)
+	for _, v := range votes {
+		if bytes.Equal(v.Validator, vote.ValidatorAddress) || current[v.Validator.String()] <= 0 {
+			continue
+		}
+		kept = append(kept, v)
+		power[namesKey(v.Names)] += current[v.Validator.String()]
+	}
+	kept = append(kept, governance.ReservedNamesVote{Validator: vote.ValidatorAddress, Names: names})
+	power[namesKey(names)] += current[vote.ValidatorAddress.String()]
+
+	tags := vote.Tags()
+	if 3*power[namesKey(names)] > 2*total {
+		err = ctx.Govern.SetReservedNames(names)
+		if err != nil {
+			return false, action.Response{Log: errors.Wrap(err, "failed to set the reserved names").Error()}
+		}
+		kept = kept[:0]
+		tags = append(tags, common.KVPair{
+			Key:   []byte("governance.reserved_names"),
+			Value: []byte("updated"),
+		})
+	}
+
+	err = ctx.Govern.SetReservedNamesVotes(kept)
+	if err != nil {
+		return false, action.Response{Log: err.Error()}
+	}
+
+	return true, action.Response{Tags: tags}
+}
+
+// namesKey identifies a normalized list of names, names never hold spaces
+func namesKey(names []string) string {
+	return strings.Join(names, " ")
+}
@@ -0,0 +1,93 @@
+package governance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/libs/common"
+
+	"github.com/Oneledger/protocol/action"
+	"github.com/Oneledger/protocol/data/keys"
+)
+
+func voteNames(t *testing.T, ctx *action.Context, v voter, names ...string) action.Response {
+	msg := &ReservedNamesVote{ValidatorAddress: v.address, Names: names}
+	data, err := msg.Marshal()
+	require.NoError(t, err)
+	tx := action.RawTx{Type: action.RESERVED_NAMES_VOTE, Data: data, Memo: "vote"}
+
+	sig, err := v.key.Sign(tx.RawBytes())
+	require.NoError(t, err)
+	pub := v.key.PubKey().(ed25519.PubKeyEd25519)
+	pubKey, err := keys.GetPublicKeyFromBytes(pub[:], keys.ED25519)
+	require.NoError(t, err)
+	ok, err := reservedNamesVoteTx{}.Validate(ctx, action.SignedTx{RawTx: tx, Signatures: []action.Signature{{Signer: pubKey, Signed: sig}}})
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, resp := reservedNamesVoteTx{}.ProcessDeliver(ctx, tx)
+	require.True(t, ok, resp.Log)
+	return resp
+}
+
+func TestReservedNamesVote(t *testing.T) {
+	ctx, voters := setup(t, 1, 1, 1, 2)
+	require.NoError(t, ctx.Govern.SetReservedNames([]string{"oneledger"}))
+
+	voteNames(t, ctx, voters[0], "oneledger", "olt")
+	voteNames(t, ctx, voters[1], "bitcoin")
+	// voting again replaces the earlier vote, the same names in another order or case count together
+	voteNames(t, ctx, voters[1], "OLT", "oneledger", "olt")
+	votes, err := ctx.Govern.GetReservedNamesVotes()
+	require.NoError(t, err)
+	assert.Len(t, votes, 2)
+
+	// 2 of 5 is not a majority yet
+	names, err := ctx.Govern.GetReservedNames()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"oneledger"}, names)
+
+	// 4 of 5 is
+	resp := voteNames(t, ctx, voters[3], "olt", "oneledger")
+	assert.Contains(t, resp.Tags, common.KVPair{Key: []byte("governance.reserved_names"), Value: []byte("updated")})
+	names, err = ctx.Govern.GetReservedNames()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"olt", "oneledger"}, names)
+	reserved, err := ctx.Govern.IsReservedName("olt")
+	require.NoError(t, err)
+	assert.True(t, reserved)
+	votes, err = ctx.Govern.GetReservedNamesVotes()
+	require.NoError(t, err)
+	assert.Empty(t, votes)
+
+	// names can be released the same way
+	voteNames(t, ctx, voters[0])
+	voteNames(t, ctx, voters[1])
+	voteNames(t, ctx, voters[3])
+	names, err = ctx.Govern.GetReservedNames()
+	require.NoError(t, err)
+	assert.Empty(t, names)
+}
+
+func TestReservedNamesVote_Invalid(t *testing.T) {
+	ctx, voters := setup(t, 1)
+
+	for _, names := range [][]string{{"pay.oneledger"}, {"one ledger"}} {
+		msg := &ReservedNamesVote{ValidatorAddress: voters[0].address, Names: names}
+		data, _ := msg.Marshal()
+		ok, _ := reservedNamesVoteTx{}.ProcessDeliver(ctx, action.RawTx{Type: action.RESERVED_NAMES_VOTE, Data: data})
+		assert.False(t, ok, names)
+	}
+
+	outsider := ed25519.GenPrivKey().PubKey().Address().Bytes()
+	msg := &ReservedNamesVote{ValidatorAddress: outsider, Names: []string{"oneledger"}}
+	data, _ := msg.Marshal()
+	ok, _ := reservedNamesVoteTx{}.ProcessDeliver(ctx, action.RawTx{Type: action.RESERVED_NAMES_VOTE, Data: data})
+	assert.False(t, ok)
+
+	names, err := ctx.Govern.GetReservedNames()
+	require.NoError(t, err)
+	assert.Empty(t, names)
+}
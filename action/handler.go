@@ -15,6 +15,9 @@ type Tx interface {
 
 	//process the charge of fees
 	ProcessFee(ctx *Context, signedTx SignedTx, start Gas, size Gas) (bool, Response)
+
+	//gas the tx costs on top of the storage it touches and its own bytes, e.g. for verifying signatures
+	IntrinsicGas(ctx *Context, signedTx SignedTx) Gas
 }
 
 //used for unknow transaction in router or not registered ones
@@ -36,3 +39,7 @@ func (unknownTx) ProcessDeliver(ctx *Context, tx RawTx) (bool, Response) {
 func (unknownTx) ProcessFee(ctx *Context, signedTx SignedTx, start Gas, size Gas) (bool, Response) {
 	return false, Response{}
 }
+
+func (unknownTx) IntrinsicGas(ctx *Context, signedTx SignedTx) Gas {
+	return 0
+}
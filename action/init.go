@@ -16,12 +16,25 @@ const (
 	APPLYVALIDATOR Type = 0x11
 	WITHDRAW       Type = 0x12
 
+	//governance related transaction
+	GAS_SCHEDULE_VOTE   Type = 0x31
+	RESERVED_NAMES_VOTE Type = 0x32
+
 	//ons related transaction
-	DOMAIN_CREATE   Type = 0x21
-	DOMAIN_UPDATE   Type = 0x22
-	DOMAIN_SELL     Type = 0x23
-	DOMAIN_PURCHASE Type = 0x24
-	DOMAIN_SEND     Type = 0x25
+	DOMAIN_CREATE       Type = 0x21
+	DOMAIN_UPDATE       Type = 0x22
+	DOMAIN_SELL         Type = 0x23
+	DOMAIN_PURCHASE     Type = 0x24
+	DOMAIN_SEND         Type = 0x25
+	DOMAIN_RENEW        Type = 0x26
+	DOMAIN_DELEGATE     Type = 0x27
+	DOMAIN_SET_RECORDS  Type = 0x28
+	DOMAIN_BID          Type = 0x29
+	DOMAIN_REVEAL       Type = 0x2A
+	DOMAIN_OFFER        Type = 0x2B
+	DOMAIN_ACCEPT_OFFER Type = 0x2C
+	DOMAIN_CANCEL_OFFER Type = 0x2D
+	DOMAIN_SET_PRIMARY  Type = 0x2E
 
 	BTC_LOCK                   Type = 0x81
 	BTC_ADD_SIGNATURE          Type = 0x82
@@ -56,6 +69,10 @@ func (t Type) String() string {
 		return "APPLY_VALIDATOR"
 	case WITHDRAW:
 		return "WITHDRAW"
+	case GAS_SCHEDULE_VOTE:
+		return "GAS_SCHEDULE_VOTE"
+	case RESERVED_NAMES_VOTE:
+		return "RESERVED_NAMES_VOTE"
 	case DOMAIN_CREATE:
 		return "DOMAIN_CREATE"
 	case DOMAIN_UPDATE:
@@ -66,6 +83,24 @@ func (t Type) String() string {
 		return "DOMAIN_PURCHASE"
 	case DOMAIN_SEND:
 		return "DOMAIN_SEND"
+	case DOMAIN_RENEW:
+		return "DOMAIN_RENEW"
+	case DOMAIN_DELEGATE:
+		return "DOMAIN_DELEGATE"
+	case DOMAIN_SET_RECORDS:
+		return "DOMAIN_SET_RECORDS"
+	case DOMAIN_BID:
+		return "DOMAIN_BID"
+	case DOMAIN_REVEAL:
+		return "DOMAIN_REVEAL"
+	case DOMAIN_OFFER:
+		return "DOMAIN_OFFER"
+	case DOMAIN_ACCEPT_OFFER:
+		return "DOMAIN_ACCEPT_OFFER"
+	case DOMAIN_CANCEL_OFFER:
+		return "DOMAIN_CANCEL_OFFER"
+	case DOMAIN_SET_PRIMARY:
+		return "DOMAIN_SET_PRIMARY"
 
 	case BTC_LOCK:
 		return "BTC_LOCK"
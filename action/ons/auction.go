@@ -0,0 +1,439 @@
+package ons
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/tendermint/tendermint/libs/common"
+
+	"github.com/Oneledger/protocol/action"
+	"github.com/Oneledger/protocol/data/balance"
+	"github.com/Oneledger/protocol/data/ons"
+)
+
+var _ Ons = &DomainBid{}
+
+// DomainBid commits a sealed bid for a name nobody holds, opening an auction for it if there
+// is none. The deposit is escrowed and has to cover the bid, it may be larger to hide it.
+type DomainBid struct {
+	Bidder     action.Address `json:"bidder"`
+	Name       string         `json:"name"`
+	Commitment []byte         `json:"commitment"`
+	Deposit    action.Amount  `json:"deposit"`
+}
+
+func (db DomainBid) Marshal() ([]byte, error) {
+	return json.Marshal(db)
+}
+
+func (db *DomainBid) Unmarshal(data []byte) error {
+	return json.Unmarshal(data, db)
+}
+
+func (db DomainBid) OnsName() string {
+	return db.Name
+}
+
+func (db DomainBid) Signers() []action.Address {
+	return []action.Address{db.Bidder}
+}
+
+func (db DomainBid) Type() action.Type {
+	return action.DOMAIN_BID
+}
+
+func (db DomainBid) Tags() common.KVPairs {
+	return auctionTags(db.Type(), db.Bidder, db.Name)
+}
+
+var _ Ons = &DomainReveal{}
+
+// DomainReveal opens a sealed bid once the commit phase of its auction is over
+type DomainReveal struct {
+	Bidder action.Address `json:"bidder"`
+	Name   string         `json:"name"`
+	Amount action.Amount  `json:"amount"`
+	Salt   []byte         `json:"salt"`
+}
+
+func (dr DomainReveal) Marshal() ([]byte, error) {
+	return json.Marshal(dr)
+}
+
+func (dr *DomainReveal) Unmarshal(data []byte) error {
+	return json.Unmarshal(data, dr)
+}
+
+func (dr DomainReveal) OnsName() string {
+	return dr.Name
+}
+
+func (dr DomainReveal) Signers() []action.Address {
+	return []action.Address{dr.Bidder}
+}
+
+func (dr DomainReveal) Type() action.Type {
+	return action.DOMAIN_REVEAL
+}
+
+func (dr DomainReveal) Tags() common.KVPairs {
+	return auctionTags(dr.Type(), dr.Bidder, dr.Name)
+}
+
+func auctionTags(typ action.Type, bidder action.Address, name string) common.KVPairs {
+	tags := make([]common.KVPair, 0)
+
+	tag := common.KVPair{
+		Key:   []byte("tx.type"),
+		Value: []byte(typ.String()),
+	}
+	tag2 := common.KVPair{
+		Key:   []byte("tx.owner"),
+		Value: bidder.Bytes(),
+	}
+	tag3 := common.KVPair{
+		Key:   []byte("tx.domain_name"),
+		Value: []byte(name),
+	}
+
+	tags = append(tags, tag, tag2, tag3)
+	return tags
+}
+
+// validateBidAmount checks that an amount in the fee currency is above the lowest price of a name
+func validateBidAmount(ctx *action.Context, amt action.Amount) error {
+	if !amt.IsValid(ctx.Currencies) || amt.Currency != ctx.FeeOpt.FeeCurrency.Name {
+		return action.ErrInvalidAmount
+	}
+
+	coin := amt.ToCoin(ctx.Currencies)
+	if coin.LessThanEqualCoin(coin.Currency.NewCoinFromInt(CREATE_PRICE)) {
+		return action.ErrNotEnoughFund
+	}
+	return nil
+}
+
+var _ action.Tx = domainBidTx{}
+
+type domainBidTx struct {
+}
+
+func (domainBidTx) Validate(ctx *action.Context, tx action.SignedTx) (bool, error) {
+	bid := &DomainBid{}
+	err := bid.Unmarshal(tx.Data)
+	if err != nil {
+		return false, errors.Wrap(action.ErrWrongTxType, err.Error())
+	}
+
+	err = action.ValidateBasic(tx.RawBytes(), bid.Signers(), tx.Signatures)
+	if err != nil {
+		return false, err
+	}
+
+	err = action.ValidateFee(ctx.FeeOpt, tx.Fee)
+	if err != nil {
+		return false, err
+	}
+
+	if bid.Bidder == nil || len(bid.Name) <= 0 || len(bid.Commitment) == 0 {
+		return false, action.ErrMissingData
+	}
+
+	err = checkName(bid.Name)
+	if err != nil {
+		return false, err
+	}
+
+	if ons.IsSubdomain(bid.Name) {
+		return false, errors.Wrap(ErrInvalidDomain, "subdomains are created by the owner of their parent")
+	}
+
+	err = validateBidAmount(ctx, bid.Deposit)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (domainBidTx) ProcessCheck(ctx *action.Context, tx action.RawTx) (bool, action.Response) {
+	return runBid(ctx, tx)
+}
+
+func (domainBidTx) ProcessDeliver(ctx *action.Context, tx action.RawTx) (bool, action.Response) {
+	return runBid(ctx, tx)
+}
+
+func (d domainBidTx) ProcessFee(ctx *action.Context, signedTx action.SignedTx, start action.Gas, size action.Gas) (bool, action.Response) {
+	return action.BasicFeeHandling(ctx, signedTx, start, size, d.IntrinsicGas(ctx, signedTx))
+}
+
+func (d domainBidTx) IntrinsicGas(ctx *action.Context, signedTx action.SignedTx) action.Gas {
+	return action.BasicIntrinsicGas(ctx, signedTx)
+}
+
+func runBid(ctx *action.Context, tx action.RawTx) (bool, action.Response) {
+	bid := &DomainBid{}
+	err := bid.Unmarshal(tx.Data)
+	if err != nil {
+		return false, action.Response{Log: err.Error()}
+	}
+
+	auction, err := ctx.Domains.GetAuction(bid.Name)
+	if err == ons.ErrAuctionNotFound {
+		// the first bid opens the auction, released domains are auctioned again
+		_, err = getDomain(ctx, bid.Name)
+		if err != ons.ErrDomainNotFound {
+			return false, action.Response{Log: fmt.Sprintf("domain already exist: %s", bid.Name)}
+		}
+		reserved, err := ctx.Govern.IsReservedName(bid.Name)
+		if err != nil {
+			return false, action.Response{Log: err.Error()}
+		}
+		if reserved {
+			return false, action.Response{Log: fmt.Sprintf("domain name is reserved: %s", bid.Name)}
+		}
+		auction = ons.NewAuction(bid.Name, ctx.Header.Height)
+	} else if err != nil {
+		return false, action.Response{Log: errors.Wrapf(err, "failed to get auction: %s", bid.Name).Error()}
+	}
+
+	if !auction.IsCommitPhase(ctx.Header.Height) {
+		return false, action.Response{Log: fmt.Sprintf("auction for %s stopped taking bids at %d", bid.Name, auction.CommitEnd)}
+	}
+
+	_, err = ctx.Domains.GetBid(bid.Name, bid.Bidder)
+	if err != ons.ErrBidNotFound {
+		return false, action.Response{Log: fmt.Sprintf("%s already bid for %s", bid.Bidder, bid.Name)}
+	}
+
+	deposit := bid.Deposit.ToCoin(ctx.Currencies)
+	err = ctx.Balances.MinusFromAddress(bid.Bidder.Bytes(), deposit)
+	if err != nil {
+		return false, action.Response{Log: errors.Wrap(err, bid.Bidder.String()).Error()}
+	}
+	err = ctx.Balances.AddToAddress(ons.AuctionEscrowAddress(), deposit)
+	if err != nil {
+		return false, action.Response{Log: errors.Wrap(err, "failed to escrow deposit").Error()}
+	}
+
+	err = ctx.Domains.SetBid(&ons.Bid{
+		Name:       bid.Name,
+		Bidder:     bid.Bidder,
+		Commitment: bid.Commitment,
+		Deposit:    deposit,
+	})
+	if err != nil {
+		return false, action.Response{Log: err.Error()}
+	}
+	err = ctx.Domains.SetAuction(auction)
+	if err != nil {
+		return false, action.Response{Log: err.Error()}
+	}
+
+	return true, action.Response{Tags: bid.Tags()}
+}
+
+var _ action.Tx = domainRevealTx{}
+
+type domainRevealTx struct {
+}
+
+func (domainRevealTx) Validate(ctx *action.Context, tx action.SignedTx) (bool, error) {
+	reveal := &DomainReveal{}
+	err := reveal.Unmarshal(tx.Data)
+	if err != nil {
+		return false, errors.Wrap(action.ErrWrongTxType, err.Error())
+	}
+
+	err = action.ValidateBasic(tx.RawBytes(), reveal.Signers(), tx.Signatures)
+	if err != nil {
+		return false, err
+	}
+
+	err = action.ValidateFee(ctx.FeeOpt, tx.Fee)
+	if err != nil {
+		return false, err
+	}
+
+	if reveal.Bidder == nil || len(reveal.Name) <= 0 {
+		return false, action.ErrMissingData
+	}
+
+	err = checkName(reveal.Name)
+	if err != nil {
+		return false, err
+	}
+
+	err = validateBidAmount(ctx, reveal.Amount)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (domainRevealTx) ProcessCheck(ctx *action.Context, tx action.RawTx) (bool, action.Response) {
+	return runReveal(ctx, tx)
+}
+
+func (domainRevealTx) ProcessDeliver(ctx *action.Context, tx action.RawTx) (bool, action.Response) {
+	return runReveal(ctx, tx)
+}
+
+func (d domainRevealTx) ProcessFee(ctx *action.Context, signedTx action.SignedTx, start action.Gas, size action.Gas) (bool, action.Response) {
+	return action.BasicFeeHandling(ctx, signedTx, start, size, d.IntrinsicGas(ctx, signedTx))
+}
+
+func (d domainRevealTx) IntrinsicGas(ctx *action.Context, signedTx action.SignedTx) action.Gas {
+	return action.BasicIntrinsicGas(ctx, signedTx)
+}
+
+func runReveal(ctx *action.Context, tx action.RawTx) (bool, action.Response) {
+	reveal := &DomainReveal{}
+	err := reveal.Unmarshal(tx.Data)
+	if err != nil {
+		return false, action.Response{Log: err.Error()}
+	}
+
+	auction, err := ctx.Domains.GetAuction(reveal.Name)
+	if err != nil {
+		return false, action.Response{Log: errors.Wrapf(err, "failed to get auction: %s", reveal.Name).Error()}
+	}
+	if !auction.IsRevealPhase(ctx.Header.Height) {
+		return false, action.Response{Log: fmt.Sprintf("bids for %s are revealed from %d to %d", reveal.Name, auction.CommitEnd+1, auction.RevealEnd)}
+	}
+
+	bid, err := ctx.Domains.GetBid(reveal.Name, reveal.Bidder)
+	if err != nil {
+		return false, action.Response{Log: errors.Wrapf(err, "failed to get bid of %s", reveal.Bidder).Error()}
+	}
+	if bid.Revealed {
+		return false, action.Response{Log: "bid already revealed"}
+	}
+
+	if !bytes.Equal(bid.Commitment, ons.BidCommitment(reveal.Name, reveal.Bidder, reveal.Amount.Value, reveal.Salt)) {
+		return false, action.Response{Log: "revealed bid does not match the commitment"}
+	}
+
+	amount := reveal.Amount.ToCoin(ctx.Currencies)
+	if bid.Deposit.LessThanCoin(amount) {
+		return false, action.Response{Log: fmt.Sprintf("bid of %s is not covered by the deposit of %s", amount, bid.Deposit)}
+	}
+
+	bid.Revealed = true
+	bid.Amount = amount
+	err = ctx.Domains.SetBid(bid)
+	if err != nil {
+		return false, action.Response{Log: err.Error()}
+	}
+
+	// on equal bids the first one revealed wins
+	if auction.Winner == nil || auction.WinningBid.LessThanCoin(amount) {
+		auction.Winner = reveal.Bidder
+		auction.WinningBid = amount
+		err = ctx.Domains.SetAuction(auction)
+		if err != nil {
+			return false, action.Response{Log: err.Error()}
+		}
+	}
+
+	return true, action.Response{Tags: reveal.Tags()}
+}
+
+// CloseAuctions settles the auctions closed at the current height. Deposits are refunded, the
+// winner pays its bid to the fee pool and gets the name. Deposits of bids that were never
+// revealed go to the fee pool as well. It returns the names given to winners.
+func CloseAuctions(ctx *action.Context) ([]string, error) {
+	auctions := make([]*ons.Auction, 0)
+	ctx.Domains.IterateClosedAuctions(ctx.Header.Height, func(a *ons.Auction) bool {
+		auctions = append(auctions, a)
+		return false
+	})
+
+	names := make([]string, 0)
+	for _, a := range auctions {
+		err := settleAuction(ctx, a)
+		if err != nil {
+			return names, errors.Wrapf(err, "failed to settle auction for %s", a.Name)
+		}
+		if a.Winner != nil {
+			names = append(names, a.Name)
+		}
+	}
+	return names, nil
+}
+
+func settleAuction(ctx *action.Context, a *ons.Auction) error {
+	// bids are in the fee currency, the proceeds go to the fee pool
+	currency, ok := ctx.Currencies.GetCurrencyByName(ctx.FeeOpt.FeeCurrency.Name)
+	if !ok {
+		return errors.Errorf("fee currency %s not registered", ctx.FeeOpt.FeeCurrency.Name)
+	}
+	escrow := ons.AuctionEscrowAddress()
+
+	bids := make([]*ons.Bid, 0)
+	ctx.Domains.IterateBids(a.Name, func(b *ons.Bid) bool {
+		bids = append(bids, b)
+		return false
+	})
+
+	proceeds := currency.NewCoinFromInt(0)
+	for _, b := range bids {
+		refund := b.Deposit
+		if !b.Revealed {
+			proceeds = proceeds.Plus(b.Deposit)
+			refund = currency.NewCoinFromInt(0)
+		} else if bytes.Equal(b.Bidder, a.Winner) {
+			proceeds = proceeds.Plus(b.Amount)
+			var err error
+			refund, err = b.Deposit.Minus(b.Amount)
+			if err != nil {
+				return err
+			}
+		}
+
+		if refund.Amount.BigInt().Sign() > 0 {
+			err := moveCoin(ctx.Balances, escrow, b.Bidder, refund)
+			if err != nil {
+				return errors.Wrapf(err, "failed to refund %s", b.Bidder)
+			}
+		}
+		err := ctx.Domains.DeleteBid(b)
+		if err != nil {
+			return err
+		}
+	}
+
+	if proceeds.Amount.BigInt().Sign() > 0 {
+		err := ctx.Balances.MinusFromAddress(escrow, proceeds)
+		if err != nil {
+			return err
+		}
+		err = ctx.FeePool.AddToPool(proceeds)
+		if err != nil {
+			return err
+		}
+	}
+
+	if a.Winner != nil {
+		domain := ons.NewDomain(a.Winner, nil, a.Name, ctx.Header.Height)
+		domain.SetExpireHeight(ctx.Header.Height + ons.BlocksForPrice(a.WinningBid))
+		err := ctx.Domains.Set(domain)
+		if err != nil {
+			return err
+		}
+	}
+
+	return ctx.Domains.DeleteAuction(a)
+}
+
+func moveCoin(balances *balance.Store, from, to action.Address, coin balance.Coin) error {
+	err := balances.MinusFromAddress(from, coin)
+	if err != nil {
+		return err
+	}
+	return balances.AddToAddress(to, coin)
+}
@@ -0,0 +1,70 @@
+package ons
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/db"
+
+	"github.com/Oneledger/protocol/action"
+	"github.com/Oneledger/protocol/data/balance"
+	"github.com/Oneledger/protocol/data/chain"
+	"github.com/Oneledger/protocol/data/fees"
+	"github.com/Oneledger/protocol/data/keys"
+	"github.com/Oneledger/protocol/data/ons"
+	"github.com/Oneledger/protocol/storage"
+)
+
+func TestCloseAuctions_FeeCurrency(t *testing.T) {
+	cs := storage.NewState(storage.NewChainState("ons", db.NewDB("test", db.MemDBBackend, "")))
+	one := balance.Currency{Name: "ONE", Chain: chain.Type(0), Decimal: 18}
+	currencies := balance.NewCurrencySet()
+	require.NoError(t, currencies.Register(one))
+	feeOpt := &fees.FeeOption{FeeCurrency: one, MinFeeDecimal: 9}
+	feePool := fees.NewStore("f", cs)
+	feePool.SetupOpt(feeOpt)
+
+	auction := ons.NewAuction("alice", 1)
+	ctx := &action.Context{
+		Header:     &types.Header{Height: auction.RevealEnd + 1},
+		Balances:   balance.NewStore("b", cs),
+		Domains:    ons.NewDomainStore("d", cs),
+		Currencies: currencies,
+		FeeOpt:     feeOpt,
+		FeePool:    feePool,
+	}
+
+	winner, loser, hidden := keys.Address("winner"), keys.Address("loser"), keys.Address("hidden")
+	auction.Winner = winner
+	auction.WinningBid = one.NewCoinFromInt(CREATE_PRICE + 2)
+	require.NoError(t, ctx.Domains.SetAuction(auction))
+	for _, b := range []*ons.Bid{
+		{Name: "alice", Bidder: winner, Deposit: one.NewCoinFromInt(CREATE_PRICE + 5), Revealed: true, Amount: auction.WinningBid},
+		{Name: "alice", Bidder: loser, Deposit: one.NewCoinFromInt(CREATE_PRICE + 1), Revealed: true, Amount: one.NewCoinFromInt(CREATE_PRICE + 1)},
+		{Name: "alice", Bidder: hidden, Deposit: one.NewCoinFromInt(CREATE_PRICE + 3)},
+	} {
+		require.NoError(t, ctx.Domains.SetBid(b))
+		require.NoError(t, ctx.Balances.AddToAddress(ons.AuctionEscrowAddress(), b.Deposit))
+	}
+	cs.Commit()
+
+	names, err := CloseAuctions(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice"}, names)
+	cs.Commit()
+
+	for addr, want := range map[string]int64{string(winner): 3, string(loser): CREATE_PRICE + 1, string(hidden): 0} {
+		got, err := ctx.Balances.GetBalanceForCurr(keys.Address(addr), &one)
+		require.NoError(t, err)
+		assert.Equal(t, one.NewCoinFromInt(want).Amount.String(), got.Amount.String(), addr)
+	}
+	pool, err := feePool.Get([]byte(fees.POOL_KEY))
+	require.NoError(t, err)
+	assert.Equal(t, one.NewCoinFromInt(2*CREATE_PRICE+5).Amount.String(), pool.Amount.String())
+
+	domain, err := ctx.Domains.Get("alice")
+	require.NoError(t, err)
+	assert.Equal(t, winner, domain.OwnerAddress)
+}
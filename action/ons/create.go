@@ -1,6 +1,7 @@
 package ons
 
 import (
+	"bytes"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -14,11 +15,14 @@ import (
 
 var _ Ons = &DomainCreate{}
 
+// DomainCreate creates a subdomain below a domain the owner holds, top level names are
+// auctioned with DomainBid
 type DomainCreate struct {
 	Owner   action.Address `json:"owner"`
 	Account action.Address `json:"account"`
 	Name    string         `json:"name"`
-	Price   action.Amount  `json:"price"`
+	// unused, subdomains are free
+	Price action.Amount `json:"price"`
 }
 
 func (dc DomainCreate) Marshal() ([]byte, error) {
@@ -84,65 +88,43 @@ func (domainCreateTx) Validate(ctx *action.Context, tx action.SignedTx) (bool, e
 		return false, action.ErrMissingData
 	}
 
-	if !create.Price.IsValid(ctx.Currencies) || create.Price.Currency != "OLT" {
-		return false, action.ErrInvalidAmount
+	err = checkName(create.Name)
+	if err != nil {
+		return false, err
 	}
 
-	coin := create.Price.ToCoin(ctx.Currencies)
-	if coin.LessThanEqualCoin(coin.Currency.NewCoinFromInt(CREATE_PRICE)) {
-		return false, action.ErrNotEnoughFund
+	// top level names are auctioned so that nobody can take a name seen in the mempool
+	if !ons.IsSubdomain(create.Name) {
+		return false, errors.Wrap(ErrInvalidDomain, "top level names are registered through DOMAIN_BID")
 	}
 
 	return true, nil
 }
 
 func (domainCreateTx) ProcessCheck(ctx *action.Context, tx action.RawTx) (bool, action.Response) {
-	create := &DomainCreate{}
-	err := create.Unmarshal(tx.Data)
-	if err != nil {
-		return false, action.Response{Log: err.Error()}
-	}
-
-	if ctx.Domains.Exists(create.Name) {
-		return false, action.Response{Log: fmt.Sprintf("Domain already exist: %s", create.Name)}
-	}
-
-	price := create.Price.ToCoin(ctx.Currencies)
-	err = ctx.Balances.MinusFromAddress(create.Owner.Bytes(), price)
-	if err != nil {
-		return false, action.Response{Log: errors.Wrap(err, hex.EncodeToString(create.Owner)).Error()}
-	}
-
-	err = ctx.FeePool.AddToPool(price)
-	if err != nil {
-		return false, action.Response{Log: err.Error()}
-	}
-	result := action.Response{
-		Tags: create.Tags(),
-	}
-
-	return true, result
+	return runCreate(ctx, tx)
 }
 
 func (domainCreateTx) ProcessDeliver(ctx *action.Context, tx action.RawTx) (bool, action.Response) {
+	return runCreate(ctx, tx)
+}
+
+// runCreate creates a subdomain. Subdomains are free, they are paid for with their parent and
+// inherit its expiry.
+func runCreate(ctx *action.Context, tx action.RawTx) (bool, action.Response) {
 	create := &DomainCreate{}
 	err := create.Unmarshal(tx.Data)
 	if err != nil {
 		return false, action.Response{Log: err.Error()}
 	}
 
-	//check domain existence and set to db
-	if ctx.Domains.Exists(create.Name) {
+	// check domain existence, released domains can be created again
+	_, err = getDomain(ctx, create.Name)
+	if err != ons.ErrDomainNotFound {
 		return false, action.Response{Log: fmt.Sprintf("domain already exist: %s", create.Name)}
 	}
 
-	price := create.Price.ToCoin(ctx.Currencies)
-	err = ctx.Balances.MinusFromAddress(create.Owner.Bytes(), price)
-	if err != nil {
-		return false, action.Response{Log: errors.Wrap(err, hex.EncodeToString(create.Owner)).Error()}
-	}
-
-	err = ctx.FeePool.AddToPool(price)
+	err = checkParent(ctx, create)
 	if err != nil {
 		return false, action.Response{Log: err.Error()}
 	}
@@ -157,14 +139,30 @@ func (domainCreateTx) ProcessDeliver(ctx *action.Context, tx action.RawTx) (bool
 	if err != nil {
 		return false, action.Response{Log: err.Error()}
 	}
-	ctx.Logger.Error(domain)
-	ctx.Logger.Error(ctx.Domains.Get(domain.Name))
+
 	result := action.Response{
 		Tags: create.Tags(),
 	}
 	return true, result
 }
 
-func (domainCreateTx) ProcessFee(ctx *action.Context, signedTx action.SignedTx, start action.Gas, size action.Gas) (bool, action.Response) {
-	return action.BasicFeeHandling(ctx, signedTx, start, size, 1)
+// checkParent verifies that only the owner of a registered parent creates names below it
+func checkParent(ctx *action.Context, create *DomainCreate) error {
+	name := ons.ParentName(create.Name)
+	parent, err := getDomain(ctx, name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get parent domain: %s", name)
+	}
+	if !bytes.Equal(parent.OwnerAddress, create.Owner) {
+		return fmt.Errorf("parent domain %s is not owned by: %s", name, hex.EncodeToString(create.Owner))
+	}
+	return nil
+}
+
+func (d domainCreateTx) ProcessFee(ctx *action.Context, signedTx action.SignedTx, start action.Gas, size action.Gas) (bool, action.Response) {
+	return action.BasicFeeHandling(ctx, signedTx, start, size, d.IntrinsicGas(ctx, signedTx))
+}
+
+func (d domainCreateTx) IntrinsicGas(ctx *action.Context, signedTx action.SignedTx) action.Gas {
+	return action.BasicIntrinsicGas(ctx, signedTx)
 }
@@ -70,12 +70,12 @@ func TestDomainTx_Validate(t *testing.T) {
 		}
 
 	})
-	t.Run("invalid amount tx data(invalid currency), should return error except update domain", func(t *testing.T) {
+	t.Run("invalid currency, the fee is in an unknown currency, should return error", func(t *testing.T) {
 		tr := &txResult{
 			createResult:   false,
 			purchaseResult: false,
 			saleResult:     false,
-			updateResult:   true,
+			updateResult:   false,
 			sendResult:     false,
 		}
 		setupForTx(tr)
@@ -86,9 +86,9 @@ func TestDomainTx_Validate(t *testing.T) {
 			assert.Equal(t, txRefer.want, ok, "%s domain Validate fails ", txType)
 		}
 	})
-	t.Run("not enough token, should return error for create domain", func(t *testing.T) {
+	t.Run("small amounts, balances are checked when processing, should return ok", func(t *testing.T) {
 		tr := &txResult{
-			createResult:   false,
+			createResult:   true,
 			purchaseResult: true,
 			saleResult:     true,
 			updateResult:   true,
@@ -158,9 +158,9 @@ func TestDomainTx_ProcessCheck(t *testing.T) {
 			assert.Equal(t, txRefer.want, ok, "%s domain ProcessCheck fails ", txType)
 		}
 	})
-	t.Run("non-exist domain, should return error except create domain", func(t *testing.T) {
+	t.Run("non-exist domain, should return error for each tx type", func(t *testing.T) {
 		tr := &txResult{
-			createResult:   true,
+			createResult:   false,
 			purchaseResult: false,
 			saleResult:     false,
 			updateResult:   false,
@@ -194,13 +194,13 @@ func TestDomainTx_ProcessCheck(t *testing.T) {
 			assert.Equal(t, txRefer.want, ok, "%s domain ProcessCheck fails ", txType)
 		}
 	})
-	t.Run("create a domain, should return error except send to domain", func(t *testing.T) {
+	t.Run("domain of another owner and inactive, should return error for each tx type", func(t *testing.T) {
 		tr := &txResult{
 			createResult:   false,
 			purchaseResult: false,
 			saleResult:     false,
 			updateResult:   false,
-			sendResult:     true,
+			sendResult:     false,
 		}
 		setupForTx(tr)
 		for txType, txRefer := range txTypeList {
@@ -209,7 +209,7 @@ func TestDomainTx_ProcessCheck(t *testing.T) {
 			ctx = assemblyCtxData("OLT", int64(10), true, true, true, owner, true, false, 0, true, 1)
 			// create a test domain before test
 			d := &ons.Domain{
-				Name: "test_domain",
+				Name: "test-domain",
 			}
 			err := ctx.Domains.Set(d)
 			ctx.Domains.State.Commit()
@@ -219,13 +219,13 @@ func TestDomainTx_ProcessCheck(t *testing.T) {
 			assert.Equal(t, txRefer.want, ok, "%s domain ProcessCheck fails ", txType)
 		}
 	})
-	t.Run("create a domain with some keypair, should return error except send to domain", func(t *testing.T) {
+	t.Run("owned inactive domain, should return error for purchase and send", func(t *testing.T) {
 		tr := &txResult{
-			createResult:   false,
+			createResult:   true,
 			purchaseResult: false,
-			saleResult:     false,
+			saleResult:     true,
 			updateResult:   true,
-			sendResult:     true,
+			sendResult:     false,
 		}
 		setupForTx(tr)
 		owner, ownerPubkey, ownerPrikey := generateKeyPair()
@@ -235,7 +235,7 @@ func TestDomainTx_ProcessCheck(t *testing.T) {
 			ctx = assemblyCtxData("OLT", int64(10), true, true, true, owner, true, false, 0, true, 1)
 			// create a test domain before test
 			d := &ons.Domain{
-				Name:         "test_domain",
+				Name:         "test-domain",
 				OwnerAddress: owner.Bytes(),
 			}
 			err := ctx.Domains.Set(d)
@@ -248,9 +248,9 @@ func TestDomainTx_ProcessCheck(t *testing.T) {
 	})
 	t.Run("create a domain and set it onsale with high price(same keypair)", func(t *testing.T) {
 		tr := &txResult{
-			createResult:   false,
+			createResult:   true,
 			purchaseResult: false,
-			saleResult:     false,
+			saleResult:     true,
 			updateResult:   true,
 			sendResult:     true,
 		}
@@ -267,9 +267,9 @@ func TestDomainTx_ProcessCheck(t *testing.T) {
 	})
 	t.Run("create a domain and set it onsale with reasonable price(same keypair)", func(t *testing.T) {
 		tr := &txResult{
-			createResult:   false,
+			createResult:   true,
 			purchaseResult: true,
-			saleResult:     false,
+			saleResult:     true,
 			updateResult:   true,
 			sendResult:     true,
 		}
@@ -286,7 +286,7 @@ func TestDomainTx_ProcessCheck(t *testing.T) {
 	})
 	t.Run("create a domain and set it onsale with high price,reset ctx header height", func(t *testing.T) {
 		tr := &txResult{
-			createResult:   false,
+			createResult:   true,
 			purchaseResult: false,
 			saleResult:     false,
 			updateResult:   true,
@@ -340,9 +340,9 @@ func TestDomainTx_ProcessDeliver(t *testing.T) {
 			assert.Equal(t, txRefer.want, ok, "%s domain ProcessDeliver fails ", txType)
 		}
 	})
-	t.Run("non-exist domain, should return error except create domain", func(t *testing.T) {
+	t.Run("non-exist domain, should return error for each tx type", func(t *testing.T) {
 		tr := &txResult{
-			createResult:   true,
+			createResult:   false,
 			purchaseResult: false,
 			saleResult:     false,
 			updateResult:   false,
@@ -378,10 +378,10 @@ func TestDomainTx_ProcessDeliver(t *testing.T) {
 	})
 	t.Run("create a domain with high price and false with onsale", func(t *testing.T) {
 		tr := &txResult{
-			createResult:   false,
+			createResult:   true,
 			purchaseResult: false,
-			saleResult:     false,
-			updateResult:   false,
+			saleResult:     true,
+			updateResult:   true,
 			sendResult:     true,
 		}
 		setupForTx(tr)
@@ -396,10 +396,10 @@ func TestDomainTx_ProcessDeliver(t *testing.T) {
 	})
 	t.Run("create a domain with high price and true with onsale", func(t *testing.T) {
 		tr := &txResult{
-			createResult:   false,
+			createResult:   true,
 			purchaseResult: true,
-			saleResult:     false,
-			updateResult:   false,
+			saleResult:     true,
+			updateResult:   true,
 			sendResult:     true,
 		}
 		setupForTx(tr)
@@ -414,7 +414,7 @@ func TestDomainTx_ProcessDeliver(t *testing.T) {
 	})
 	t.Run("create a domain with reasonable price and true with onsale, reset ctx header height", func(t *testing.T) {
 		tr := &txResult{
-			createResult:   false,
+			createResult:   true,
 			purchaseResult: true,
 			saleResult:     true,
 			updateResult:   true,
@@ -0,0 +1,178 @@
+package ons
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/tendermint/tendermint/libs/common"
+
+	"github.com/Oneledger/protocol/action"
+	"github.com/Oneledger/protocol/data/ons"
+)
+
+var _ Ons = &DomainDelegate{}
+
+// DomainDelegate hands a subdomain over to a delegate, or takes it back with Revoke. It is
+// signed by the owner of the parent domain.
+type DomainDelegate struct {
+	Owner    action.Address `json:"owner"`
+	Name     string         `json:"name"`
+	Delegate action.Address `json:"delegate"`
+	Revoke   bool           `json:"revoke"`
+}
+
+func (dd DomainDelegate) Marshal() ([]byte, error) {
+	return json.Marshal(dd)
+}
+
+func (dd *DomainDelegate) Unmarshal(data []byte) error {
+	return json.Unmarshal(data, dd)
+}
+
+func (dd DomainDelegate) OnsName() string {
+	return dd.Name
+}
+
+func (dd DomainDelegate) Signers() []action.Address {
+	return []action.Address{dd.Owner}
+}
+
+func (dd DomainDelegate) Type() action.Type {
+	return action.DOMAIN_DELEGATE
+}
+
+func (dd DomainDelegate) Tags() common.KVPairs {
+	tags := make([]common.KVPair, 0)
+
+	tag := common.KVPair{
+		Key:   []byte("tx.type"),
+		Value: []byte(dd.Type().String()),
+	}
+	tag2 := common.KVPair{
+		Key:   []byte("tx.owner"),
+		Value: dd.Owner.Bytes(),
+	}
+	tag3 := common.KVPair{
+		Key:   []byte("tx.domain_name"),
+		Value: []byte(dd.Name),
+	}
+
+	tags = append(tags, tag, tag2, tag3)
+	if dd.Revoke {
+		tag4 := common.KVPair{
+			Key:   []byte("tx.is_revoke"),
+			Value: []byte{0xff},
+		}
+		tags = append(tags, tag4)
+	} else {
+		tag4 := common.KVPair{
+			Key:   []byte("tx.delegate"),
+			Value: dd.Delegate.Bytes(),
+		}
+		tags = append(tags, tag4)
+	}
+	return tags
+}
+
+var _ action.Tx = domainDelegateTx{}
+
+type domainDelegateTx struct {
+}
+
+func (domainDelegateTx) Validate(ctx *action.Context, tx action.SignedTx) (bool, error) {
+	delegate := &DomainDelegate{}
+	err := delegate.Unmarshal(tx.Data)
+	if err != nil {
+		return false, errors.Wrap(action.ErrWrongTxType, err.Error())
+	}
+
+	err = action.ValidateBasic(tx.RawBytes(), delegate.Signers(), tx.Signatures)
+	if err != nil {
+		return false, err
+	}
+
+	err = action.ValidateFee(ctx.FeeOpt, tx.Fee)
+	if err != nil {
+		return false, err
+	}
+
+	if delegate.Owner == nil || len(delegate.Name) <= 0 {
+		return false, action.ErrMissingData
+	}
+
+	err = checkName(delegate.Name)
+	if err != nil {
+		return false, err
+	}
+
+	// either a delegate or a revoke
+	if delegate.Revoke == (len(delegate.Delegate) > 0) {
+		return false, action.ErrMissingData
+	}
+
+	if !ons.IsSubdomain(delegate.Name) {
+		return false, errors.Wrap(ErrInvalidDomain, "only subdomains can be delegated")
+	}
+
+	return true, nil
+}
+
+func (domainDelegateTx) ProcessCheck(ctx *action.Context, tx action.RawTx) (bool, action.Response) {
+	return runDelegate(ctx, tx)
+}
+
+func (domainDelegateTx) ProcessDeliver(ctx *action.Context, tx action.RawTx) (bool, action.Response) {
+	return runDelegate(ctx, tx)
+}
+
+func (d domainDelegateTx) ProcessFee(ctx *action.Context, signedTx action.SignedTx, start action.Gas, size action.Gas) (bool, action.Response) {
+	return action.BasicFeeHandling(ctx, signedTx, start, size, d.IntrinsicGas(ctx, signedTx))
+}
+
+func (d domainDelegateTx) IntrinsicGas(ctx *action.Context, signedTx action.SignedTx) action.Gas {
+	return action.BasicIntrinsicGas(ctx, signedTx)
+}
+
+func runDelegate(ctx *action.Context, tx action.RawTx) (bool, action.Response) {
+	delegate := &DomainDelegate{}
+	err := delegate.Unmarshal(tx.Data)
+	if err != nil {
+		return false, action.Response{Log: err.Error()}
+	}
+
+	domain, err := getDomain(ctx, delegate.Name)
+	if err != nil {
+		return false, action.Response{Log: errors.Wrapf(err, "failed to get domain: %s", delegate.Name).Error()}
+	}
+
+	parentName := ons.ParentName(delegate.Name)
+	parent, err := getDomain(ctx, parentName)
+	if err != nil {
+		return false, action.Response{Log: errors.Wrapf(err, "failed to get parent domain: %s", parentName).Error()}
+	}
+
+	// only the parent controls who holds a subdomain, the delegate can't pass it on
+	if !bytes.Equal(parent.OwnerAddress, delegate.Owner) {
+		return false, action.Response{Log: fmt.Sprintf("parent domain is not owned by: %s", hex.EncodeToString(delegate.Owner))}
+	}
+
+	if delegate.Revoke {
+		// payments go back to the parent owner as well, the delegate may have pointed them
+		// to its own account
+		domain.ChangeOwner(parent.OwnerAddress)
+		domain.SetAccountAddress(parent.OwnerAddress)
+	} else {
+		domain.ChangeOwner(delegate.Delegate)
+	}
+
+	domain.SetLastUpdatedHeight(ctx.Header.Height)
+	err = ctx.Domains.Set(domain)
+	if err != nil {
+		return false, action.Response{Log: err.Error()}
+	}
+
+	return true, action.Response{Tags: delegate.Tags()}
+}
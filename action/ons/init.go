@@ -4,6 +4,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/Oneledger/protocol/action"
+	"github.com/Oneledger/protocol/data/ons"
 	"github.com/Oneledger/protocol/serialize"
 )
 
@@ -22,6 +23,15 @@ func init() {
 	serialize.RegisterConcrete(new(DomainSale), "action_dsale")
 	serialize.RegisterConcrete(new(DomainSend), "action_dsend")
 	serialize.RegisterConcrete(new(DomainPurchase), "action_dp")
+	serialize.RegisterConcrete(new(DomainRenew), "action_drenew")
+	serialize.RegisterConcrete(new(DomainDelegate), "action_ddelegate")
+	serialize.RegisterConcrete(new(DomainSetRecords), "action_drecords")
+	serialize.RegisterConcrete(new(DomainBid), "action_dbid")
+	serialize.RegisterConcrete(new(DomainReveal), "action_dreveal")
+	serialize.RegisterConcrete(new(DomainOffer), "action_doffer")
+	serialize.RegisterConcrete(new(DomainAcceptOffer), "action_daccept")
+	serialize.RegisterConcrete(new(DomainCancelOffer), "action_dcanceloffer")
+	serialize.RegisterConcrete(new(DomainSetPrimary), "action_dprimary")
 
 }
 
@@ -46,6 +56,42 @@ func EnableONS(r action.Router) error {
 	if err != nil {
 		return errors.Wrap(err, "domainSendTx")
 	}
+	err = r.AddHandler(action.DOMAIN_RENEW, domainRenewTx{})
+	if err != nil {
+		return errors.Wrap(err, "domainRenewTx")
+	}
+	err = r.AddHandler(action.DOMAIN_DELEGATE, domainDelegateTx{})
+	if err != nil {
+		return errors.Wrap(err, "domainDelegateTx")
+	}
+	err = r.AddHandler(action.DOMAIN_SET_RECORDS, domainSetRecordsTx{})
+	if err != nil {
+		return errors.Wrap(err, "domainSetRecordsTx")
+	}
+	err = r.AddHandler(action.DOMAIN_BID, domainBidTx{})
+	if err != nil {
+		return errors.Wrap(err, "domainBidTx")
+	}
+	err = r.AddHandler(action.DOMAIN_REVEAL, domainRevealTx{})
+	if err != nil {
+		return errors.Wrap(err, "domainRevealTx")
+	}
+	err = r.AddHandler(action.DOMAIN_OFFER, domainOfferTx{})
+	if err != nil {
+		return errors.Wrap(err, "domainOfferTx")
+	}
+	err = r.AddHandler(action.DOMAIN_ACCEPT_OFFER, domainAcceptOfferTx{})
+	if err != nil {
+		return errors.Wrap(err, "domainAcceptOfferTx")
+	}
+	err = r.AddHandler(action.DOMAIN_CANCEL_OFFER, domainCancelOfferTx{})
+	if err != nil {
+		return errors.Wrap(err, "domainCancelOfferTx")
+	}
+	err = r.AddHandler(action.DOMAIN_SET_PRIMARY, domainSetPrimaryTx{})
+	if err != nil {
+		return errors.Wrap(err, "domainSetPrimaryTx")
+	}
 
 	return nil
 }
@@ -54,3 +100,29 @@ type Ons interface {
 	action.Msg
 	OnsName() string
 }
+
+// checkName rejects names that are not in their canonical form, every ONS tx names its
+// domain exactly as it is stored
+func checkName(name string) error {
+	err := ons.ValidateName(name)
+	if err != nil {
+		return errors.Wrap(ErrInvalidDomain, err.Error())
+	}
+	return nil
+}
+
+// getDomain reads a domain that is registered at the current height. Released domains read
+// as not found, expired ones in their grace period as ErrDomainExpired.
+func getDomain(ctx *action.Context, name string) (*ons.Domain, error) {
+	d, err := ctx.Domains.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	if d.IsReleased(ctx.Header.Height) {
+		return nil, ons.ErrDomainNotFound
+	}
+	if d.IsExpired(ctx.Header.Height) {
+		return nil, ons.ErrDomainExpired
+	}
+	return d, nil
+}
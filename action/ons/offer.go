@@ -0,0 +1,499 @@
+package ons
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/tendermint/tendermint/libs/common"
+
+	"github.com/Oneledger/protocol/action"
+	"github.com/Oneledger/protocol/data/balance"
+	"github.com/Oneledger/protocol/data/ons"
+)
+
+var _ Ons = &DomainOffer{}
+
+// DomainOffer escrows an offer for a registered domain, in any currency, that the owner can
+// accept until the offer expires
+type DomainOffer struct {
+	Buyer   action.Address `json:"buyer"`
+	Account action.Address `json:"account"`
+	Name    string         `json:"name"`
+	Amount  action.Amount  `json:"amount"`
+	// number of blocks the offer stands for
+	Blocks int64 `json:"blocks"`
+}
+
+func (do DomainOffer) Marshal() ([]byte, error) {
+	return json.Marshal(do)
+}
+
+func (do *DomainOffer) Unmarshal(data []byte) error {
+	return json.Unmarshal(data, do)
+}
+
+func (do DomainOffer) OnsName() string {
+	return do.Name
+}
+
+func (do DomainOffer) Signers() []action.Address {
+	return []action.Address{do.Buyer}
+}
+
+func (do DomainOffer) Type() action.Type {
+	return action.DOMAIN_OFFER
+}
+
+func (do DomainOffer) Tags() common.KVPairs {
+	tags := offerTags(do.Type(), do.Buyer, do.Name, do.Buyer)
+	return append(tags, amountTags(do.Amount.Currency, do.Amount.Value)...)
+}
+
+var _ Ons = &DomainAcceptOffer{}
+
+// DomainAcceptOffer sells a domain to a buyer for its open offer
+type DomainAcceptOffer struct {
+	Owner action.Address `json:"owner"`
+	Name  string         `json:"name"`
+	Buyer action.Address `json:"buyer"`
+}
+
+func (da DomainAcceptOffer) Marshal() ([]byte, error) {
+	return json.Marshal(da)
+}
+
+func (da *DomainAcceptOffer) Unmarshal(data []byte) error {
+	return json.Unmarshal(data, da)
+}
+
+func (da DomainAcceptOffer) OnsName() string {
+	return da.Name
+}
+
+func (da DomainAcceptOffer) Signers() []action.Address {
+	return []action.Address{da.Owner}
+}
+
+func (da DomainAcceptOffer) Type() action.Type {
+	return action.DOMAIN_ACCEPT_OFFER
+}
+
+func (da DomainAcceptOffer) Tags() common.KVPairs {
+	return offerTags(da.Type(), da.Owner, da.Name, da.Buyer)
+}
+
+var _ Ons = &DomainCancelOffer{}
+
+// DomainCancelOffer withdraws an open offer and refunds it
+type DomainCancelOffer struct {
+	Buyer action.Address `json:"buyer"`
+	Name  string         `json:"name"`
+}
+
+func (dc DomainCancelOffer) Marshal() ([]byte, error) {
+	return json.Marshal(dc)
+}
+
+func (dc *DomainCancelOffer) Unmarshal(data []byte) error {
+	return json.Unmarshal(data, dc)
+}
+
+func (dc DomainCancelOffer) OnsName() string {
+	return dc.Name
+}
+
+func (dc DomainCancelOffer) Signers() []action.Address {
+	return []action.Address{dc.Buyer}
+}
+
+func (dc DomainCancelOffer) Type() action.Type {
+	return action.DOMAIN_CANCEL_OFFER
+}
+
+func (dc DomainCancelOffer) Tags() common.KVPairs {
+	return offerTags(dc.Type(), dc.Buyer, dc.Name, dc.Buyer)
+}
+
+// offerTags lets a marketplace follow offers by domain and by buyer
+func offerTags(typ action.Type, signer action.Address, name string, buyer action.Address) common.KVPairs {
+	tags := make([]common.KVPair, 0)
+
+	tag := common.KVPair{
+		Key:   []byte("tx.type"),
+		Value: []byte(typ.String()),
+	}
+	tag2 := common.KVPair{
+		Key:   []byte("tx.owner"),
+		Value: signer.Bytes(),
+	}
+	tag3 := common.KVPair{
+		Key:   []byte("tx.domain_name"),
+		Value: []byte(name),
+	}
+	tag4 := common.KVPair{
+		Key:   []byte("tx.buyer"),
+		Value: buyer.Bytes(),
+	}
+
+	tags = append(tags, tag, tag2, tag3, tag4)
+	return tags
+}
+
+func amountTags(currency string, value balance.Amount) common.KVPairs {
+	tag := common.KVPair{
+		Key:   []byte("tx.offer_currency"),
+		Value: []byte(currency),
+	}
+	tag2 := common.KVPair{
+		Key:   []byte("tx.offer_amount"),
+		Value: []byte(value.String()),
+	}
+	return common.KVPairs{tag, tag2}
+}
+
+var _ action.Tx = domainOfferTx{}
+
+type domainOfferTx struct {
+}
+
+func (domainOfferTx) Validate(ctx *action.Context, tx action.SignedTx) (bool, error) {
+	offer := &DomainOffer{}
+	err := offer.Unmarshal(tx.Data)
+	if err != nil {
+		return false, errors.Wrap(action.ErrWrongTxType, err.Error())
+	}
+
+	err = action.ValidateBasic(tx.RawBytes(), offer.Signers(), tx.Signatures)
+	if err != nil {
+		return false, err
+	}
+
+	err = action.ValidateFee(ctx.FeeOpt, tx.Fee)
+	if err != nil {
+		return false, err
+	}
+
+	if offer.Buyer == nil || len(offer.Name) <= 0 {
+		return false, action.ErrMissingData
+	}
+
+	err = checkName(offer.Name)
+	if err != nil {
+		return false, err
+	}
+
+	if !offer.Amount.IsValid(ctx.Currencies) {
+		return false, errors.Wrap(action.ErrInvalidAmount, offer.Amount.String())
+	}
+
+	if offer.Blocks <= 0 {
+		return false, errors.Wrap(action.ErrMissingData, "offer needs a positive number of blocks")
+	}
+
+	if ons.IsSubdomain(offer.Name) {
+		return false, errors.Wrap(ErrInvalidDomain, "subdomains cannot be sold")
+	}
+
+	return true, nil
+}
+
+func (domainOfferTx) ProcessCheck(ctx *action.Context, tx action.RawTx) (bool, action.Response) {
+	return runOffer(ctx, tx)
+}
+
+func (domainOfferTx) ProcessDeliver(ctx *action.Context, tx action.RawTx) (bool, action.Response) {
+	return runOffer(ctx, tx)
+}
+
+func (d domainOfferTx) ProcessFee(ctx *action.Context, signedTx action.SignedTx, start action.Gas, size action.Gas) (bool, action.Response) {
+	return action.BasicFeeHandling(ctx, signedTx, start, size, d.IntrinsicGas(ctx, signedTx))
+}
+
+func (d domainOfferTx) IntrinsicGas(ctx *action.Context, signedTx action.SignedTx) action.Gas {
+	return action.BasicIntrinsicGas(ctx, signedTx)
+}
+
+func runOffer(ctx *action.Context, tx action.RawTx) (bool, action.Response) {
+	offer := &DomainOffer{}
+	err := offer.Unmarshal(tx.Data)
+	if err != nil {
+		return false, action.Response{Log: err.Error()}
+	}
+
+	// without delete tracking a refunded offer is still read until the commit and could be
+	// refunded again in the same block
+	if !ctx.Domains.State.TracksDeletes() {
+		return false, action.Response{Log: "offers are not taken before the delete tracking upgrade"}
+	}
+
+	domain, err := getDomain(ctx, offer.Name)
+	if err != nil {
+		return false, action.Response{Log: errors.Wrapf(err, "failed to get domain: %s", offer.Name).Error()}
+	}
+	if bytes.Equal(domain.OwnerAddress, offer.Buyer) {
+		return false, action.Response{Log: "domain is already owned by the buyer"}
+	}
+
+	_, err = ctx.Domains.GetOffer(offer.Name, offer.Buyer)
+	if err != ons.ErrOfferNotFound {
+		return false, action.Response{Log: fmt.Sprintf("%s already has an offer for %s", offer.Buyer, offer.Name)}
+	}
+
+	coin := offer.Amount.ToCoin(ctx.Currencies)
+	err = moveCoin(ctx.Balances, offer.Buyer, ons.OfferEscrowAddress(), coin)
+	if err != nil {
+		return false, action.Response{Log: errors.Wrap(err, "failed to escrow offer").Error()}
+	}
+
+	err = ctx.Domains.SetOffer(&ons.Offer{
+		Name:           offer.Name,
+		Buyer:          offer.Buyer,
+		Account:        offer.Account,
+		Amount:         coin,
+		CreationHeight: ctx.Header.Height,
+		ExpireHeight:   ctx.Header.Height + offer.Blocks,
+	})
+	if err != nil {
+		return false, action.Response{Log: err.Error()}
+	}
+
+	return true, action.Response{Tags: offer.Tags()}
+}
+
+var _ action.Tx = domainAcceptOfferTx{}
+
+type domainAcceptOfferTx struct {
+}
+
+func (domainAcceptOfferTx) Validate(ctx *action.Context, tx action.SignedTx) (bool, error) {
+	accept := &DomainAcceptOffer{}
+	err := accept.Unmarshal(tx.Data)
+	if err != nil {
+		return false, errors.Wrap(action.ErrWrongTxType, err.Error())
+	}
+
+	err = action.ValidateBasic(tx.RawBytes(), accept.Signers(), tx.Signatures)
+	if err != nil {
+		return false, err
+	}
+
+	err = action.ValidateFee(ctx.FeeOpt, tx.Fee)
+	if err != nil {
+		return false, err
+	}
+
+	if accept.Owner == nil || accept.Buyer == nil || len(accept.Name) <= 0 {
+		return false, action.ErrMissingData
+	}
+
+	err = checkName(accept.Name)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (domainAcceptOfferTx) ProcessCheck(ctx *action.Context, tx action.RawTx) (bool, action.Response) {
+	return runAcceptOffer(ctx, tx)
+}
+
+func (domainAcceptOfferTx) ProcessDeliver(ctx *action.Context, tx action.RawTx) (bool, action.Response) {
+	return runAcceptOffer(ctx, tx)
+}
+
+func (d domainAcceptOfferTx) ProcessFee(ctx *action.Context, signedTx action.SignedTx, start action.Gas, size action.Gas) (bool, action.Response) {
+	return action.BasicFeeHandling(ctx, signedTx, start, size, d.IntrinsicGas(ctx, signedTx))
+}
+
+func (d domainAcceptOfferTx) IntrinsicGas(ctx *action.Context, signedTx action.SignedTx) action.Gas {
+	return action.BasicIntrinsicGas(ctx, signedTx)
+}
+
+func runAcceptOffer(ctx *action.Context, tx action.RawTx) (bool, action.Response) {
+	accept := &DomainAcceptOffer{}
+	err := accept.Unmarshal(tx.Data)
+	if err != nil {
+		return false, action.Response{Log: err.Error()}
+	}
+
+	domain, err := getDomain(ctx, accept.Name)
+	if err != nil {
+		return false, action.Response{Log: errors.Wrapf(err, "failed to get domain: %s", accept.Name).Error()}
+	}
+	if !bytes.Equal(domain.OwnerAddress, accept.Owner) {
+		return false, action.Response{Log: fmt.Sprintf("domain is not owned by: %s", hex.EncodeToString(accept.Owner))}
+	}
+
+	offer, err := ctx.Domains.GetOffer(accept.Name, accept.Buyer)
+	if err != nil {
+		return false, action.Response{Log: errors.Wrapf(err, "failed to get offer of %s", accept.Buyer).Error()}
+	}
+	if offer.IsExpired(ctx.Header.Height) {
+		return false, action.Response{Log: fmt.Sprintf("offer expired at %d", offer.ExpireHeight)}
+	}
+
+	// payment and transfer go together, the tx fails as a whole otherwise
+	err = moveCoin(ctx.Balances, ons.OfferEscrowAddress(), accept.Owner, offer.Amount)
+	if err != nil {
+		return false, action.Response{Log: errors.Wrap(err, "failed to pay the owner").Error()}
+	}
+
+	domain.ChangeOwner(offer.Buyer)
+	if offer.Account != nil {
+		domain.SetAccountAddress(offer.Account)
+	} else {
+		domain.SetAccountAddress(offer.Buyer)
+	}
+	domain.CancelSale()
+	domain.Activate()
+	domain.SetLastUpdatedHeight(ctx.Header.Height)
+
+	err = ctx.Domains.Set(domain)
+	if err != nil {
+		return false, action.Response{Log: errors.Wrap(err, "failed to update domain").Error()}
+	}
+	err = ctx.Domains.DeleteOffer(offer)
+	if err != nil {
+		return false, action.Response{Log: err.Error()}
+	}
+
+	tags := append(accept.Tags(), amountTags(offer.Amount.Currency.Name, *offer.Amount.Amount)...)
+	return true, action.Response{Tags: tags}
+}
+
+var _ action.Tx = domainCancelOfferTx{}
+
+type domainCancelOfferTx struct {
+}
+
+func (domainCancelOfferTx) Validate(ctx *action.Context, tx action.SignedTx) (bool, error) {
+	cancel := &DomainCancelOffer{}
+	err := cancel.Unmarshal(tx.Data)
+	if err != nil {
+		return false, errors.Wrap(action.ErrWrongTxType, err.Error())
+	}
+
+	err = action.ValidateBasic(tx.RawBytes(), cancel.Signers(), tx.Signatures)
+	if err != nil {
+		return false, err
+	}
+
+	err = action.ValidateFee(ctx.FeeOpt, tx.Fee)
+	if err != nil {
+		return false, err
+	}
+
+	if cancel.Buyer == nil || len(cancel.Name) <= 0 {
+		return false, action.ErrMissingData
+	}
+
+	err = checkName(cancel.Name)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (domainCancelOfferTx) ProcessCheck(ctx *action.Context, tx action.RawTx) (bool, action.Response) {
+	return runCancelOffer(ctx, tx)
+}
+
+func (domainCancelOfferTx) ProcessDeliver(ctx *action.Context, tx action.RawTx) (bool, action.Response) {
+	return runCancelOffer(ctx, tx)
+}
+
+func (d domainCancelOfferTx) ProcessFee(ctx *action.Context, signedTx action.SignedTx, start action.Gas, size action.Gas) (bool, action.Response) {
+	return action.BasicFeeHandling(ctx, signedTx, start, size, d.IntrinsicGas(ctx, signedTx))
+}
+
+func (d domainCancelOfferTx) IntrinsicGas(ctx *action.Context, signedTx action.SignedTx) action.Gas {
+	return action.BasicIntrinsicGas(ctx, signedTx)
+}
+
+func runCancelOffer(ctx *action.Context, tx action.RawTx) (bool, action.Response) {
+	cancel := &DomainCancelOffer{}
+	err := cancel.Unmarshal(tx.Data)
+	if err != nil {
+		return false, action.Response{Log: err.Error()}
+	}
+
+	offer, err := ctx.Domains.GetOffer(cancel.Name, cancel.Buyer)
+	if err != nil {
+		return false, action.Response{Log: errors.Wrapf(err, "failed to get offer of %s", cancel.Buyer).Error()}
+	}
+
+	err = refundOffer(ctx, offer)
+	if err != nil {
+		return false, action.Response{Log: err.Error()}
+	}
+
+	return true, action.Response{Tags: cancel.Tags()}
+}
+
+func refundOffer(ctx *action.Context, offer *ons.Offer) error {
+	err := moveCoin(ctx.Balances, ons.OfferEscrowAddress(), offer.Buyer, offer.Amount)
+	if err != nil {
+		return errors.Wrapf(err, "failed to refund offer of %s", offer.Buyer)
+	}
+	return ctx.Domains.DeleteOffer(offer)
+}
+
+// ExpireOffers refunds the offers expired at the current height and returns tags for them
+func ExpireOffers(ctx *action.Context) (common.KVPairs, error) {
+	offers := make([]*ons.Offer, 0)
+	ctx.Domains.IterateExpiredOffers(ctx.Header.Height, func(o *ons.Offer) bool {
+		offers = append(offers, o)
+		return false
+	})
+
+	tags := make([]common.KVPair, 0)
+	for _, o := range offers {
+		err := refundOffer(ctx, o)
+		if err != nil {
+			return tags, err
+		}
+		tags = append(tags, common.KVPair{
+			Key:   []byte("ons.offer_expired"),
+			Value: []byte(o.Name + "/" + o.Buyer.String()),
+		})
+	}
+	return tags, nil
+}
+
+// ReleaseDomains releases the domains whose grace period is over at the current height and
+// refunds the offers left open for them. It returns the released names and tags for the
+// refunded offers.
+func ReleaseDomains(ctx *action.Context) ([]string, common.KVPairs, error) {
+	tags := make([]common.KVPair, 0)
+	released, err := ctx.Domains.ReleaseExpired(ctx.Header.Height)
+	if err != nil {
+		return released, tags, err
+	}
+
+	// expired domains take no offers, so all of them are in the last commit, less the ones
+	// refunded earlier in the block
+	offers := make([]*ons.Offer, 0)
+	for _, name := range released {
+		ctx.Domains.IterateDomainOffers(name, func(o *ons.Offer) bool {
+			offers = append(offers, o)
+			return false
+		})
+	}
+
+	for _, o := range offers {
+		err := refundOffer(ctx, o)
+		if err != nil {
+			return released, tags, err
+		}
+		tags = append(tags, common.KVPair{
+			Key:   []byte("ons.offer_expired"),
+			Value: []byte(o.Name + "/" + o.Buyer.String()),
+		})
+	}
+	return released, tags, nil
+}
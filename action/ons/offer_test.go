@@ -0,0 +1,151 @@
+package ons
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/db"
+
+	"github.com/Oneledger/protocol/action"
+	"github.com/Oneledger/protocol/data/balance"
+	"github.com/Oneledger/protocol/data/chain"
+	"github.com/Oneledger/protocol/data/keys"
+	"github.com/Oneledger/protocol/data/ons"
+	"github.com/Oneledger/protocol/storage"
+)
+
+func TestReleaseDomains_RefundsOffers(t *testing.T) {
+	cs := storage.NewState(storage.NewChainState("ons", db.NewDB("test", db.MemDBBackend, "")))
+	olt := balance.Currency{Name: "OLT", Chain: chain.Type(0), Decimal: 18}
+	currencies := balance.NewCurrencySet()
+	require.NoError(t, currencies.Register(olt))
+
+	released := int64(10 + ons.GRACE_PERIOD_BLOCKS + 1)
+	ctx := &action.Context{
+		Header:     &types.Header{Height: released},
+		Balances:   balance.NewStore("b", cs),
+		Domains:    ons.NewDomainStore("d", cs),
+		Currencies: currencies,
+	}
+
+	owner, buyer := keys.Address("owner"), keys.Address("buyer")
+	for _, d := range []*ons.Domain{
+		{Name: "alice", OwnerAddress: owner, ExpireHeight: 10},
+		{Name: "bob", OwnerAddress: owner, ExpireHeight: released},
+	} {
+		require.NoError(t, ctx.Domains.Set(d))
+		offer := &ons.Offer{Name: d.Name, Buyer: buyer, Amount: olt.NewCoinFromInt(5), ExpireHeight: released * 2}
+		require.NoError(t, ctx.Domains.SetOffer(offer))
+		require.NoError(t, ctx.Balances.AddToAddress(ons.OfferEscrowAddress(), offer.Amount))
+	}
+	cs.Commit()
+
+	names, tags, err := ReleaseDomains(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice"}, names)
+	require.Len(t, tags, 1)
+	assert.Equal(t, "alice/"+buyer.String(), string(tags[0].Value))
+	cs.Commit()
+
+	_, err = ctx.Domains.GetOffer("alice", buyer)
+	assert.Equal(t, ons.ErrOfferNotFound, err)
+	_, err = ctx.Domains.GetOffer("bob", buyer)
+	assert.NoError(t, err)
+
+	refunded, err := ctx.Balances.GetBalanceForCurr(buyer, &olt)
+	require.NoError(t, err)
+	assert.Equal(t, olt.NewCoinFromInt(5).Amount.String(), refunded.Amount.String())
+	escrowed, err := ctx.Balances.GetBalanceForCurr(ons.OfferEscrowAddress(), &olt)
+	require.NoError(t, err)
+	assert.Equal(t, olt.NewCoinFromInt(5).Amount.String(), escrowed.Amount.String())
+}
+
+// offerTestContext holds an offer of 5 OLT from buyer for alice, with another buyer's offer in the
+// escrow as well so that a double refund would not run out of funds
+func offerTestContext(t *testing.T, track bool) (*action.Context, *storage.State, balance.Currency) {
+	cs := storage.NewState(storage.NewChainState("ons", db.NewDB("test", db.MemDBBackend, ""))).WithDeleteTracking(track)
+	olt := balance.Currency{Name: "OLT", Chain: chain.Type(0), Decimal: 18}
+	currencies := balance.NewCurrencySet()
+	require.NoError(t, currencies.Register(olt))
+
+	ctx := &action.Context{
+		Header:     &types.Header{Height: 10},
+		Balances:   balance.NewStore("b", cs),
+		Domains:    ons.NewDomainStore("d", cs),
+		Currencies: currencies,
+	}
+	require.NoError(t, ctx.Domains.Set(&ons.Domain{Name: "alice", OwnerAddress: keys.Address("owner"), ActiveFlag: true, ExpireHeight: 100}))
+	require.NoError(t, ctx.Balances.AddToAddress(keys.Address("buyer"), olt.NewCoinFromInt(5)))
+	require.NoError(t, ctx.Balances.AddToAddress(ons.OfferEscrowAddress(), olt.NewCoinFromInt(5)))
+	cs.Commit()
+	return ctx, cs, olt
+}
+
+func offerRawTx(t *testing.T, msg Ons) action.RawTx {
+	data, err := msg.Marshal()
+	require.NoError(t, err)
+	return action.RawTx{Type: msg.Type(), Data: data}
+}
+
+func TestOffer_RefundedOnce(t *testing.T) {
+	buyer := keys.Address("buyer")
+	price := balance.Currency{Name: "OLT", Decimal: 18}.NewCoinFromInt(5).Amount
+	offer := &DomainOffer{Buyer: buyer, Name: "alice", Amount: action.Amount{Currency: "OLT", Value: *price}, Blocks: 1}
+	cancel := &DomainCancelOffer{Buyer: buyer, Name: "alice"}
+	accept := &DomainAcceptOffer{Owner: keys.Address("owner"), Name: "alice", Buyer: buyer}
+
+	balanceOf := func(ctx *action.Context, olt balance.Currency, addr keys.Address) string {
+		coin, err := ctx.Balances.GetBalanceForCurr(addr, &olt)
+		require.NoError(t, err)
+		return coin.Amount.String()
+	}
+
+	t.Run("cancel twice in a block", func(t *testing.T) {
+		ctx, cs, olt := offerTestContext(t, true)
+		ok, resp := domainOfferTx{}.ProcessDeliver(ctx, offerRawTx(t, offer))
+		require.True(t, ok, resp.Log)
+		cs.Commit()
+
+		ok, resp = domainCancelOfferTx{}.ProcessDeliver(ctx, offerRawTx(t, cancel))
+		require.True(t, ok, resp.Log)
+		ok, _ = domainCancelOfferTx{}.ProcessDeliver(ctx, offerRawTx(t, cancel))
+		assert.False(t, ok)
+		assert.Equal(t, olt.NewCoinFromInt(5).Amount.String(), balanceOf(ctx, olt, buyer))
+	})
+
+	t.Run("accept then cancel in a block", func(t *testing.T) {
+		ctx, cs, olt := offerTestContext(t, true)
+		ok, resp := domainOfferTx{}.ProcessDeliver(ctx, offerRawTx(t, offer))
+		require.True(t, ok, resp.Log)
+		cs.Commit()
+
+		ok, resp = domainAcceptOfferTx{}.ProcessDeliver(ctx, offerRawTx(t, accept))
+		require.True(t, ok, resp.Log)
+		ok, _ = domainCancelOfferTx{}.ProcessDeliver(ctx, offerRawTx(t, cancel))
+		assert.False(t, ok)
+		assert.Equal(t, "0", balanceOf(ctx, olt, buyer))
+	})
+
+	t.Run("cancel then expire in a block", func(t *testing.T) {
+		ctx, cs, olt := offerTestContext(t, true)
+		ok, resp := domainOfferTx{}.ProcessDeliver(ctx, offerRawTx(t, offer))
+		require.True(t, ok, resp.Log)
+		cs.Commit()
+
+		ctx.Header.Height = 12
+		ok, resp = domainCancelOfferTx{}.ProcessDeliver(ctx, offerRawTx(t, cancel))
+		require.True(t, ok, resp.Log)
+		tags, err := ExpireOffers(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, tags)
+		assert.Equal(t, olt.NewCoinFromInt(5).Amount.String(), balanceOf(ctx, olt, buyer))
+	})
+
+	t.Run("no offers before the delete tracking upgrade", func(t *testing.T) {
+		ctx, _, _ := offerTestContext(t, false)
+		ok, _ := domainOfferTx{}.ProcessDeliver(ctx, offerRawTx(t, offer))
+		assert.False(t, ok)
+	})
+}
@@ -1,7 +1,6 @@
 package ons
 
 import (
-	"math/big"
 	"os"
 
 	"github.com/tendermint/tendermint/libs/db"
@@ -16,6 +15,7 @@ import (
 	"github.com/Oneledger/protocol/action"
 	"github.com/Oneledger/protocol/data/balance"
 	"github.com/Oneledger/protocol/data/chain"
+	"github.com/Oneledger/protocol/data/fees"
 	"github.com/Oneledger/protocol/storage"
 	"github.com/pkg/errors"
 	"github.com/tendermint/tendermint/crypto"
@@ -86,7 +86,7 @@ func assemblyCtxData(currencyName string, currencyDecimal int64, setBalanceStore
 				Amount:   balance.NewAmount(setDomainPrice),
 			}
 			d := &ons.Domain{
-				Name:           "test_domain",
+				Name:           "test-domain",
 				OwnerAddress:   setCoinAddr.Bytes(),
 				AccountAddress: setCoinAddr.Bytes(),
 				OnSaleFlag:     onsaleFlag,
@@ -114,6 +114,8 @@ func assemblyCtxData(currencyName string, currencyDecimal int64, setBalanceStore
 			errors.New("register new token error")
 		}
 		ctx.Currencies = currencyList
+		// the smallest unit is enough of a fee
+		ctx.FeeOpt = &fees.FeeOption{FeeCurrency: currency, MinFeeDecimal: currencyDecimal}
 
 		// set coin for account
 		if setInitCoin {
@@ -121,9 +123,7 @@ func assemblyCtxData(currencyName string, currencyDecimal int64, setBalanceStore
 				Currency: currency,
 				Amount:   balance.NewAmount(1000000000000000000),
 			}
-			ba := balance.NewBalance()
-			ba = ba.AddCoin(coin)
-			err = store.Set(setCoinAddr.Bytes(), *ba)
+			err = store.AddToAddress(setCoinAddr.Bytes(), coin)
 			if err != nil {
 				errors.New("setup testing token balance error")
 			}
@@ -139,7 +139,7 @@ func assemblyCreateDomainTx(owner crypto.Address, amount *action.Amount) *Domain
 	createDoamin := &DomainCreate{
 		Owner:   owner.Bytes(),
 		Account: owner.Bytes(),
-		Name:    "test_domain",
+		Name:    "pay.test-domain",
 		Price:   *amount,
 	}
 	return createDoamin
@@ -148,7 +148,7 @@ func assemblyCreateDomainTx(owner crypto.Address, amount *action.Amount) *Domain
 func assemblyPurchaseDomainTx(owner crypto.Address, amount *action.Amount) *DomainPurchase {
 
 	purchaseDomain := &DomainPurchase{
-		Name:     "test_domain",
+		Name:     "test-domain",
 		Buyer:    owner.Bytes(),
 		Account:  owner.Bytes(),
 		Offering: *amount,
@@ -159,7 +159,7 @@ func assemblyPurchaseDomainTx(owner crypto.Address, amount *action.Amount) *Doma
 func assemblySaleDomainTx(owner crypto.Address, amount *action.Amount, cancelSale bool) *DomainSale {
 
 	saleDomain := &DomainSale{
-		DomainName:   "test_domain",
+		DomainName:   "test-domain",
 		OwnerAddress: owner.Bytes(),
 		Price:        *amount,
 		CancelSale:   cancelSale,
@@ -170,7 +170,7 @@ func assemblySendDomainTx(owner crypto.Address, amount *action.Amount) *DomainSe
 
 	sendDomain := &DomainSend{
 		From:       owner.Bytes(),
-		DomainName: "test_domain",
+		DomainName: "test-domain",
 		Amount:     *amount,
 	}
 	return sendDomain
@@ -181,7 +181,7 @@ func assemblyUpdateDomainTx(owner crypto.Address, active bool) *DomainUpdate {
 	updateDomain := &DomainUpdate{
 		Owner:   owner.Bytes(),
 		Account: owner.Bytes(),
-		Name:    "test_domain",
+		Name:    "test-domain",
 		Active:  active,
 	}
 	return updateDomain
@@ -199,7 +199,7 @@ func assemblyTxData(replaceAddre bool, initTokenAmount string, txType string, fa
 		Value:    *amt,
 	}
 	fee := action.Fee{
-		Price: action.Amount{Currency: "OLT", Value: balance.Amount{Int: *big.NewInt(0).Div(&amt.Int, big.NewInt(1000))}},
+		Price: action.Amount{Currency: "OLT", Value: *balance.NewAmount(1)},
 		Gas:   int64(10),
 	}
 
@@ -239,7 +239,7 @@ func assemblyTxData(replaceAddre bool, initTokenAmount string, txType string, fa
 		RawTx: rawtx,
 		Signatures: []action.Signature{
 			{
-				Signer: keys.PublicKey{keys.ED25519, ownerPubkey.Bytes()[5:]},
+				Signer: keys.PublicKey{KeyType: keys.ED25519, Data: ownerPubkey.Bytes()[5:]},
 				Signed: signature,
 			}},
 	}
@@ -257,7 +257,7 @@ func assemblyTxDataSameKp(replaceAddre bool, initTokenAmount string, txType stri
 		Value:    *amt,
 	}
 	fee := action.Fee{
-		Price: action.Amount{Currency: "OLT", Value: balance.Amount{Int: *big.NewInt(0).Div(&amt.Int, big.NewInt(1000))}},
+		Price: action.Amount{Currency: "OLT", Value: *balance.NewAmount(1)},
 		Gas:   int64(10),
 	}
 
@@ -297,7 +297,7 @@ func assemblyTxDataSameKp(replaceAddre bool, initTokenAmount string, txType stri
 		RawTx: tx,
 		Signatures: []action.Signature{
 			{
-				Signer: keys.PublicKey{keys.ED25519, ownerPubkey.Bytes()[5:]},
+				Signer: keys.PublicKey{KeyType: keys.ED25519, Data: ownerPubkey.Bytes()[5:]},
 				Signed: signature,
 			}},
 	}
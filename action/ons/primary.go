@@ -0,0 +1,155 @@
+package ons
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/tendermint/tendermint/libs/common"
+
+	"github.com/Oneledger/protocol/action"
+)
+
+var _ Ons = &DomainSetPrimary{}
+
+// DomainSetPrimary sets the name an address is shown as, an empty Name clears it. It is
+// signed by the address, which the domain has to point to.
+type DomainSetPrimary struct {
+	Address action.Address `json:"address"`
+	Name    string         `json:"name"`
+}
+
+func (dp DomainSetPrimary) Marshal() ([]byte, error) {
+	return json.Marshal(dp)
+}
+
+func (dp *DomainSetPrimary) Unmarshal(data []byte) error {
+	return json.Unmarshal(data, dp)
+}
+
+func (dp DomainSetPrimary) OnsName() string {
+	return dp.Name
+}
+
+func (dp DomainSetPrimary) Signers() []action.Address {
+	return []action.Address{dp.Address}
+}
+
+func (dp DomainSetPrimary) Type() action.Type {
+	return action.DOMAIN_SET_PRIMARY
+}
+
+func (dp DomainSetPrimary) Tags() common.KVPairs {
+	tags := make([]common.KVPair, 0)
+
+	tag := common.KVPair{
+		Key:   []byte("tx.type"),
+		Value: []byte(dp.Type().String()),
+	}
+	tag2 := common.KVPair{
+		Key:   []byte("tx.address"),
+		Value: dp.Address.Bytes(),
+	}
+	tag3 := common.KVPair{
+		Key:   []byte("tx.domain_name"),
+		Value: []byte(dp.Name),
+	}
+
+	tags = append(tags, tag, tag2, tag3)
+	return tags
+}
+
+var _ action.Tx = domainSetPrimaryTx{}
+
+type domainSetPrimaryTx struct {
+}
+
+func (domainSetPrimaryTx) Validate(ctx *action.Context, tx action.SignedTx) (bool, error) {
+	primary := &DomainSetPrimary{}
+	err := primary.Unmarshal(tx.Data)
+	if err != nil {
+		return false, errors.Wrap(action.ErrWrongTxType, err.Error())
+	}
+
+	err = action.ValidateBasic(tx.RawBytes(), primary.Signers(), tx.Signatures)
+	if err != nil {
+		return false, err
+	}
+
+	err = action.ValidateFee(ctx.FeeOpt, tx.Fee)
+	if err != nil {
+		return false, err
+	}
+
+	if primary.Address == nil {
+		return false, action.ErrMissingData
+	}
+
+	if len(primary.Name) > 0 {
+		err = checkName(primary.Name)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+func (domainSetPrimaryTx) ProcessCheck(ctx *action.Context, tx action.RawTx) (bool, action.Response) {
+	return runSetPrimary(ctx, tx)
+}
+
+func (domainSetPrimaryTx) ProcessDeliver(ctx *action.Context, tx action.RawTx) (bool, action.Response) {
+	return runSetPrimary(ctx, tx)
+}
+
+func (d domainSetPrimaryTx) ProcessFee(ctx *action.Context, signedTx action.SignedTx, start action.Gas, size action.Gas) (bool, action.Response) {
+	return action.BasicFeeHandling(ctx, signedTx, start, size, d.IntrinsicGas(ctx, signedTx))
+}
+
+func (d domainSetPrimaryTx) IntrinsicGas(ctx *action.Context, signedTx action.SignedTx) action.Gas {
+	return action.BasicIntrinsicGas(ctx, signedTx)
+}
+
+func runSetPrimary(ctx *action.Context, tx action.RawTx) (bool, action.Response) {
+	primary := &DomainSetPrimary{}
+	err := primary.Unmarshal(tx.Data)
+	if err != nil {
+		return false, action.Response{Log: err.Error()}
+	}
+
+	if len(primary.Name) == 0 {
+		_, err = ctx.Domains.GetPrimary(primary.Address)
+		if err != nil {
+			return false, action.Response{Log: err.Error()}
+		}
+		err = ctx.Domains.DeletePrimary(primary.Address)
+		if err != nil {
+			return false, action.Response{Log: err.Error()}
+		}
+		return true, action.Response{Tags: primary.Tags()}
+	}
+
+	domain, err := getDomain(ctx, primary.Name)
+	if err != nil {
+		return false, action.Response{Log: errors.Wrapf(err, "failed to get domain: %s", primary.Name).Error()}
+	}
+
+	// the reverse record has to agree with the forward one, otherwise anybody could show up
+	// under somebody else's name
+	if !bytes.Equal(domain.AccountAddress, primary.Address) {
+		return false, action.Response{Log: fmt.Sprintf("domain does not point to: %s", hex.EncodeToString(primary.Address))}
+	}
+	if !domain.ActiveFlag {
+		return false, action.Response{Log: fmt.Sprintf("domain is not active: %s", primary.Name)}
+	}
+
+	err = ctx.Domains.SetPrimary(primary.Address, primary.Name)
+	if err != nil {
+		return false, action.Response{Log: err.Error()}
+	}
+
+	return true, action.Response{Tags: primary.Tags()}
+}
@@ -88,6 +88,11 @@ func (domainPurchaseTx) Validate(ctx *action.Context, tx action.SignedTx) (bool,
 		return false, action.ErrMissingData
 	}
 
+	err = checkName(buy.Name)
+	if err != nil {
+		return false, err
+	}
+
 	return true, nil
 }
 
@@ -99,12 +104,12 @@ func (domainPurchaseTx) ProcessCheck(ctx *action.Context, tx action.RawTx) (bool
 		return false, action.Response{Log: err.Error()}
 	}
 
-	domain, err := ctx.Domains.Get(buy.Name)
+	domain, err := getDomain(ctx, buy.Name)
 	if err != nil {
 		if err == ons.ErrDomainNotFound {
 			return false, action.Response{Log: "domain not found"}
 		}
-		return false, action.Response{Log: "error getting domain"}
+		return false, action.Response{Log: errors.Wrap(err, "error getting domain").Error()}
 	}
 
 	if !domain.OnSaleFlag {
@@ -115,7 +120,7 @@ func (domainPurchaseTx) ProcessCheck(ctx *action.Context, tx action.RawTx) (bool
 		return false, action.Response{Log: "offering price not enough"}
 	}
 
-	ctx.Balances.MinusFromAddress(buy.Buyer.Bytes(), buy.Offering.ToCoin(ctx.Currencies))
+	err = ctx.Balances.MinusFromAddress(buy.Buyer.Bytes(), buy.Offering.ToCoin(ctx.Currencies))
 	if err != nil {
 		return false, action.Response{Log: errors.Wrap(err, "insufficient buyer balance").Error()}
 	}
@@ -131,12 +136,12 @@ func (domainPurchaseTx) ProcessDeliver(ctx *action.Context, tx action.RawTx) (bo
 		return false, action.Response{Log: err.Error()}
 	}
 
-	domain, err := ctx.Domains.Get(buy.Name)
+	domain, err := getDomain(ctx, buy.Name)
 	if err != nil {
 		if err == ons.ErrDomainNotFound {
 			return false, action.Response{Log: "domain not found"}
 		}
-		return false, action.Response{Log: "error getting domain"}
+		return false, action.Response{Log: errors.Wrap(err, "error getting domain").Error()}
 	}
 
 	if !domain.OnSaleFlag {
@@ -148,7 +153,7 @@ func (domainPurchaseTx) ProcessDeliver(ctx *action.Context, tx action.RawTx) (bo
 		return false, action.Response{Log: "offering price not enough"}
 	}
 
-	ctx.Balances.MinusFromAddress(buy.Buyer.Bytes(), coin)
+	err = ctx.Balances.MinusFromAddress(buy.Buyer.Bytes(), coin)
 	if err != nil {
 		return false, action.Response{Log: errors.Wrap(err, "failed to debit buyer balance").Error()}
 	}
@@ -176,6 +181,10 @@ func (domainPurchaseTx) ProcessDeliver(ctx *action.Context, tx action.RawTx) (bo
 	return true, action.Response{Tags: buy.Tags()}
 }
 
-func (domainPurchaseTx) ProcessFee(ctx *action.Context, signedTx action.SignedTx, start action.Gas, size action.Gas) (bool, action.Response) {
-	return action.BasicFeeHandling(ctx, signedTx, start, size, 1)
+func (d domainPurchaseTx) ProcessFee(ctx *action.Context, signedTx action.SignedTx, start action.Gas, size action.Gas) (bool, action.Response) {
+	return action.BasicFeeHandling(ctx, signedTx, start, size, d.IntrinsicGas(ctx, signedTx))
+}
+
+func (d domainPurchaseTx) IntrinsicGas(ctx *action.Context, signedTx action.SignedTx) action.Gas {
+	return action.BasicIntrinsicGas(ctx, signedTx)
 }
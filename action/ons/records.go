@@ -0,0 +1,184 @@
+package ons
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/btcsuite/btcutil"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"github.com/tendermint/tendermint/libs/common"
+
+	"github.com/Oneledger/protocol/action"
+	"github.com/Oneledger/protocol/data/chain"
+	"github.com/Oneledger/protocol/data/ons"
+)
+
+var _ Ons = &DomainSetRecords{}
+
+// DomainSetRecords replaces the records a domain resolves to
+type DomainSetRecords struct {
+	Owner   action.Address `json:"owner"`
+	Name    string         `json:"name"`
+	Records ons.Records    `json:"records"`
+}
+
+func (dr DomainSetRecords) Marshal() ([]byte, error) {
+	return json.Marshal(dr)
+}
+
+func (dr *DomainSetRecords) Unmarshal(data []byte) error {
+	return json.Unmarshal(data, dr)
+}
+
+func (dr DomainSetRecords) OnsName() string {
+	return dr.Name
+}
+
+func (dr DomainSetRecords) Signers() []action.Address {
+	return []action.Address{dr.Owner}
+}
+
+func (dr DomainSetRecords) Type() action.Type {
+	return action.DOMAIN_SET_RECORDS
+}
+
+func (dr DomainSetRecords) Tags() common.KVPairs {
+	tags := make([]common.KVPair, 0)
+
+	tag := common.KVPair{
+		Key:   []byte("tx.type"),
+		Value: []byte(dr.Type().String()),
+	}
+	tag2 := common.KVPair{
+		Key:   []byte("tx.owner"),
+		Value: dr.Owner.Bytes(),
+	}
+	tag3 := common.KVPair{
+		Key:   []byte("tx.domain_name"),
+		Value: []byte(dr.Name),
+	}
+
+	tags = append(tags, tag, tag2, tag3)
+	return tags
+}
+
+var _ action.Tx = domainSetRecordsTx{}
+
+type domainSetRecordsTx struct {
+}
+
+func (domainSetRecordsTx) Validate(ctx *action.Context, tx action.SignedTx) (bool, error) {
+	set := &DomainSetRecords{}
+	err := set.Unmarshal(tx.Data)
+	if err != nil {
+		return false, errors.Wrap(action.ErrWrongTxType, err.Error())
+	}
+
+	err = action.ValidateBasic(tx.RawBytes(), set.Signers(), tx.Signatures)
+	if err != nil {
+		return false, err
+	}
+
+	err = action.ValidateFee(ctx.FeeOpt, tx.Fee)
+	if err != nil {
+		return false, err
+	}
+
+	if set.Owner == nil || len(set.Name) <= 0 {
+		return false, action.ErrMissingData
+	}
+
+	err = checkName(set.Name)
+	if err != nil {
+		return false, err
+	}
+
+	err = set.Records.Validate()
+	if err != nil {
+		return false, err
+	}
+
+	for _, a := range set.Records.Addresses {
+		err = validateChainAddress(ctx, a)
+		if err != nil {
+			return false, errors.Wrap(ons.ErrInvalidRecords, err.Error())
+		}
+	}
+
+	return true, nil
+}
+
+// validateChainAddress checks an address record has the format of its chain, OneLedger
+// addresses are checked when they are set
+func validateChainAddress(ctx *action.Context, a ons.AddressRecord) error {
+	switch a.Chain {
+	case chain.BITCOIN:
+		if ctx.BTCChainType == nil {
+			return nil
+		}
+		addr, err := btcutil.DecodeAddress(a.Address, ctx.BTCChainType)
+		if err != nil {
+			return err
+		}
+		if !addr.IsForNet(ctx.BTCChainType) {
+			return fmt.Errorf("bitcoin address %s is not for %s", a.Address, ctx.BTCChainType.Name)
+		}
+	case chain.ETHEREUM:
+		if !ethcommon.IsHexAddress(a.Address) {
+			return fmt.Errorf("invalid ethereum address %s", a.Address)
+		}
+	}
+	return nil
+}
+
+func (domainSetRecordsTx) ProcessCheck(ctx *action.Context, tx action.RawTx) (bool, action.Response) {
+	return runSetRecords(ctx, tx)
+}
+
+func (domainSetRecordsTx) ProcessDeliver(ctx *action.Context, tx action.RawTx) (bool, action.Response) {
+	return runSetRecords(ctx, tx)
+}
+
+func (d domainSetRecordsTx) ProcessFee(ctx *action.Context, signedTx action.SignedTx, start action.Gas, size action.Gas) (bool, action.Response) {
+	return action.BasicFeeHandling(ctx, signedTx, start, size, d.IntrinsicGas(ctx, signedTx))
+}
+
+func (d domainSetRecordsTx) IntrinsicGas(ctx *action.Context, signedTx action.SignedTx) action.Gas {
+	return action.BasicIntrinsicGas(ctx, signedTx)
+}
+
+func runSetRecords(ctx *action.Context, tx action.RawTx) (bool, action.Response) {
+	set := &DomainSetRecords{}
+	err := set.Unmarshal(tx.Data)
+	if err != nil {
+		return false, action.Response{Log: err.Error()}
+	}
+
+	d, err := getDomain(ctx, set.Name)
+	if err != nil {
+		return false, action.Response{Log: errors.Wrapf(err, "failed to get domain: %s", set.Name).Error()}
+	}
+
+	if !d.IsChangeable(ctx.Header.Height) {
+		return false, action.Response{Log: fmt.Sprintf("domain is not changable: %s, last change: %d", set.Name, d.LastUpdateHeight)}
+	}
+
+	if !bytes.Equal(d.OwnerAddress, set.Owner) {
+		return false, action.Response{Log: fmt.Sprintf("domain is not owned by: %s", hex.EncodeToString(set.Owner))}
+	}
+
+	err = d.SetRecords(set.Records)
+	if err != nil {
+		return false, action.Response{Log: err.Error()}
+	}
+	d.SetLastUpdatedHeight(ctx.Header.Height)
+
+	err = ctx.Domains.Set(d)
+	if err != nil {
+		return false, action.Response{Log: err.Error()}
+	}
+	return true, action.Response{Tags: set.Tags()}
+}
@@ -0,0 +1,169 @@
+package ons
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/tendermint/tendermint/libs/common"
+
+	"github.com/Oneledger/protocol/action"
+	"github.com/Oneledger/protocol/data/ons"
+)
+
+var _ Ons = &DomainRenew{}
+
+type DomainRenew struct {
+	Owner action.Address `json:"owner"`
+	Name  string         `json:"name"`
+	Price action.Amount  `json:"price"`
+}
+
+func (dr DomainRenew) Marshal() ([]byte, error) {
+	return json.Marshal(dr)
+}
+
+func (dr *DomainRenew) Unmarshal(data []byte) error {
+	return json.Unmarshal(data, dr)
+}
+
+func (dr DomainRenew) OnsName() string {
+	return dr.Name
+}
+
+func (dr DomainRenew) Signers() []action.Address {
+	return []action.Address{dr.Owner}
+}
+
+func (dr DomainRenew) Type() action.Type {
+	return action.DOMAIN_RENEW
+}
+
+func (dr DomainRenew) Tags() common.KVPairs {
+	tags := make([]common.KVPair, 0)
+
+	tag := common.KVPair{
+		Key:   []byte("tx.type"),
+		Value: []byte(dr.Type().String()),
+	}
+	tag2 := common.KVPair{
+		Key:   []byte("tx.owner"),
+		Value: dr.Owner.Bytes(),
+	}
+	tag3 := common.KVPair{
+		Key:   []byte("tx.domain_name"),
+		Value: []byte(dr.Name),
+	}
+
+	tags = append(tags, tag, tag2, tag3)
+	return tags
+}
+
+var _ action.Tx = domainRenewTx{}
+
+type domainRenewTx struct {
+}
+
+func (domainRenewTx) Validate(ctx *action.Context, tx action.SignedTx) (bool, error) {
+	renew := &DomainRenew{}
+	err := renew.Unmarshal(tx.Data)
+	if err != nil {
+		return false, errors.Wrap(action.ErrWrongTxType, err.Error())
+	}
+
+	err = action.ValidateBasic(tx.RawBytes(), renew.Signers(), tx.Signatures)
+	if err != nil {
+		return false, err
+	}
+
+	err = action.ValidateFee(ctx.FeeOpt, tx.Fee)
+	if err != nil {
+		return false, err
+	}
+
+	if renew.Owner == nil || len(renew.Name) <= 0 {
+		return false, action.ErrMissingData
+	}
+
+	err = checkName(renew.Name)
+	if err != nil {
+		return false, err
+	}
+
+	if ons.IsSubdomain(renew.Name) {
+		return false, errors.Wrap(ErrInvalidDomain, "subdomains expire with their parent")
+	}
+
+	if !renew.Price.IsValid(ctx.Currencies) || renew.Price.Currency != ctx.FeeOpt.FeeCurrency.Name {
+		return false, action.ErrInvalidAmount
+	}
+
+	return true, nil
+}
+
+func (domainRenewTx) ProcessCheck(ctx *action.Context, tx action.RawTx) (bool, action.Response) {
+	return runRenew(ctx, tx)
+}
+
+func (domainRenewTx) ProcessDeliver(ctx *action.Context, tx action.RawTx) (bool, action.Response) {
+	return runRenew(ctx, tx)
+}
+
+func (d domainRenewTx) ProcessFee(ctx *action.Context, signedTx action.SignedTx, start action.Gas, size action.Gas) (bool, action.Response) {
+	return action.BasicFeeHandling(ctx, signedTx, start, size, d.IntrinsicGas(ctx, signedTx))
+}
+
+func (d domainRenewTx) IntrinsicGas(ctx *action.Context, signedTx action.SignedTx) action.Gas {
+	return action.BasicIntrinsicGas(ctx, signedTx)
+}
+
+func runRenew(ctx *action.Context, tx action.RawTx) (bool, action.Response) {
+	renew := &DomainRenew{}
+	err := renew.Unmarshal(tx.Data)
+	if err != nil {
+		return false, action.Response{Log: err.Error()}
+	}
+
+	domain, err := ctx.Domains.Get(renew.Name)
+	if err != nil {
+		return false, action.Response{Log: errors.Wrapf(err, "failed to get domain: %s", renew.Name).Error()}
+	}
+	// expired domains can still be renewed by their owner during the grace period
+	if domain.IsReleased(ctx.Header.Height) {
+		return false, action.Response{Log: fmt.Sprintf("domain has been released: %s", renew.Name)}
+	}
+
+	if !bytes.Equal(domain.OwnerAddress, renew.Owner) {
+		return false, action.Response{Log: fmt.Sprintf("domain is not owned by: %s", hex.EncodeToString(renew.Owner))}
+	}
+
+	if domain.ExpireHeight == 0 {
+		return false, action.Response{Log: fmt.Sprintf("domain never expires: %s", renew.Name)}
+	}
+
+	price := renew.Price.ToCoin(ctx.Currencies)
+	domain.Renew(ons.BlocksForPrice(price))
+	if domain.IsExpired(ctx.Header.Height) {
+		return false, action.Response{Log: fmt.Sprintf("price too low to renew past the current height, expires at %d", domain.ExpireHeight)}
+	}
+
+	err = ctx.Balances.MinusFromAddress(renew.Owner.Bytes(), price)
+	if err != nil {
+		return false, action.Response{Log: errors.Wrap(err, hex.EncodeToString(renew.Owner)).Error()}
+	}
+
+	err = ctx.FeePool.AddToPool(price)
+	if err != nil {
+		return false, action.Response{Log: err.Error()}
+	}
+
+	domain.SetLastUpdatedHeight(ctx.Header.Height)
+	err = ctx.Domains.Set(domain)
+	if err != nil {
+		return false, action.Response{Log: err.Error()}
+	}
+
+	return true, action.Response{Tags: renew.Tags()}
+}
@@ -0,0 +1,83 @@
+package ons
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/db"
+
+	"github.com/Oneledger/protocol/action"
+	"github.com/Oneledger/protocol/data/balance"
+	"github.com/Oneledger/protocol/data/chain"
+	"github.com/Oneledger/protocol/data/fees"
+	"github.com/Oneledger/protocol/data/keys"
+	"github.com/Oneledger/protocol/data/ons"
+	"github.com/Oneledger/protocol/storage"
+)
+
+func TestDomainRenew_FeeCurrency(t *testing.T) {
+	cs := storage.NewState(storage.NewChainState("ons", db.NewDB("test", db.MemDBBackend, "")))
+	one := balance.Currency{Name: "ONE", Chain: chain.Type(0), Decimal: 18}
+	olt := balance.Currency{Name: "OLT", Chain: chain.Type(0), Decimal: 18}
+	currencies := balance.NewCurrencySet()
+	require.NoError(t, currencies.Register(one))
+	require.NoError(t, currencies.Register(olt))
+	feeOpt := &fees.FeeOption{FeeCurrency: one, MinFeeDecimal: 9}
+	feePool := fees.NewStore("f", cs)
+	feePool.SetupOpt(feeOpt)
+
+	ctx := &action.Context{
+		Header:     &types.Header{Height: 10},
+		Balances:   balance.NewStore("b", cs),
+		Domains:    ons.NewDomainStore("d", cs),
+		Currencies: currencies,
+		FeeOpt:     feeOpt,
+		FeePool:    feePool,
+	}
+
+	pub, priv, err := keys.NewKeyPairFromTendermint()
+	require.NoError(t, err)
+	h, err := priv.GetHandler()
+	require.NoError(t, err)
+	pubH, err := pub.GetHandler()
+	require.NoError(t, err)
+	owner := pubH.Address()
+
+	require.NoError(t, ctx.Domains.Set(&ons.Domain{Name: "alice", OwnerAddress: owner, ActiveFlag: true, ExpireHeight: 100}))
+	require.NoError(t, ctx.Balances.AddToAddress(owner, one.NewCoinFromInt(10)))
+	require.NoError(t, ctx.Balances.AddToAddress(owner, olt.NewCoinFromInt(10)))
+	cs.Commit()
+
+	renewIn := func(c balance.Currency) action.SignedTx {
+		price := c.NewCoinFromInt(1)
+		raw := offerRawTx(t, &DomainRenew{Owner: owner, Name: "alice", Price: action.Amount{Currency: c.Name, Value: *price.Amount}})
+		raw.Fee = action.Fee{Price: action.Amount{Currency: one.Name, Value: *feeOpt.MinFee().Amount}, Gas: 1}
+		signed, err := h.Sign(raw.RawBytes())
+		require.NoError(t, err)
+		return action.SignedTx{RawTx: raw, Signatures: []action.Signature{{Signer: pub, Signed: signed}}}
+	}
+
+	_, err = domainRenewTx{}.Validate(ctx, renewIn(olt))
+	assert.Equal(t, action.ErrInvalidAmount, err)
+
+	tx := renewIn(one)
+	ok, err := domainRenewTx{}.Validate(ctx, tx)
+	require.True(t, ok, err)
+	ok, resp := domainRenewTx{}.ProcessDeliver(ctx, tx.RawTx)
+	require.True(t, ok, resp.Log)
+	cs.Commit()
+
+	domain, err := ctx.Domains.Get("alice")
+	require.NoError(t, err)
+	assert.Equal(t, int64(100+ons.BLOCKS_PER_OLT), domain.ExpireHeight)
+
+	paid, err := ctx.Balances.GetBalanceForCurr(owner, &one)
+	require.NoError(t, err)
+	assert.Equal(t, one.NewCoinFromInt(9).Amount.String(), paid.Amount.String())
+	pool, err := feePool.Get([]byte(fees.POOL_KEY))
+	require.NoError(t, err)
+	assert.Equal(t, one.Name, pool.Currency.Name)
+	assert.Equal(t, one.NewCoinFromInt(1).Amount.String(), pool.Amount.String())
+}
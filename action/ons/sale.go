@@ -112,6 +112,16 @@ func (domainSaleTx) Validate(ctx *action.Context, tx action.SignedTx) (bool, err
 		return false, action.ErrMissingData
 	}
 
+	err = checkName(sale.DomainName)
+	if err != nil {
+		return false, err
+	}
+
+	// subdomains stay with their parent, it delegates them instead
+	if ons.IsSubdomain(sale.DomainName) {
+		return false, errors.Wrap(ErrInvalidDomain, "subdomains cannot be sold")
+	}
+
 	return true, nil
 }
 
@@ -132,7 +142,7 @@ func (domainSaleTx) ProcessCheck(ctx *action.Context, tx action.RawTx) (bool, ac
 		return false, action.Response{Log: "invalid data"}
 	}
 
-	domain, err := ctx.Domains.Get(sale.DomainName)
+	domain, err := getDomain(ctx, sale.DomainName)
 	if err != nil {
 		if err == ons.ErrDomainNotFound {
 			return false, action.Response{Log: "domain not found"}
@@ -177,12 +187,12 @@ func (domainSaleTx) ProcessDeliver(ctx *action.Context, tx action.RawTx) (bool,
 		return false, action.Response{Log: "invalid data"}
 	}
 
-	domain, err := ctx.Domains.Get(sale.DomainName)
+	domain, err := getDomain(ctx, sale.DomainName)
 	if err != nil {
 		if err == ons.ErrDomainNotFound {
 			return false, action.Response{Log: "domain not found"}
 		}
-		return false, action.Response{Log: "error getting domain"}
+		return false, action.Response{Log: errors.Wrap(err, "error getting domain").Error()}
 	}
 
 	// verify the ownership
@@ -211,6 +221,10 @@ func (domainSaleTx) ProcessDeliver(ctx *action.Context, tx action.RawTx) (bool,
 	return true, action.Response{Tags: sale.Tags()}
 }
 
-func (domainSaleTx) ProcessFee(ctx *action.Context, signedTx action.SignedTx, start action.Gas, size action.Gas) (bool, action.Response) {
-	return action.BasicFeeHandling(ctx, signedTx, start, size, 1)
+func (d domainSaleTx) ProcessFee(ctx *action.Context, signedTx action.SignedTx, start action.Gas, size action.Gas) (bool, action.Response) {
+	return action.BasicFeeHandling(ctx, signedTx, start, size, d.IntrinsicGas(ctx, signedTx))
+}
+
+func (d domainSaleTx) IntrinsicGas(ctx *action.Context, signedTx action.SignedTx) action.Gas {
+	return action.BasicIntrinsicGas(ctx, signedTx)
 }
@@ -112,6 +112,11 @@ func (domainSendTx) Validate(ctx *action.Context, tx action.SignedTx) (bool, err
 		return false, action.ErrMissingData
 	}
 
+	err = checkName(send.DomainName)
+	if err != nil {
+		return false, err
+	}
+
 	return true, nil
 }
 
@@ -132,7 +137,7 @@ func (domainSendTx) ProcessCheck(ctx *action.Context, tx action.RawTx) (bool, ac
 	}
 	coin := send.Amount.ToCoin(ctx.Currencies)
 
-	domain, err := ctx.Domains.Get(send.DomainName)
+	domain, err := getDomain(ctx, send.DomainName)
 	if err != nil {
 		log := fmt.Sprint("error getting domain:", err)
 		return false, action.Response{Log: log}
@@ -176,7 +181,7 @@ func (domainSendTx) ProcessDeliver(ctx *action.Context, tx action.RawTx) (bool,
 
 	coin := send.Amount.ToCoin(ctx.Currencies)
 
-	domain, err := ctx.Domains.Get(send.DomainName)
+	domain, err := getDomain(ctx, send.DomainName)
 	if err != nil {
 		log := fmt.Sprint("error getting domain:", err)
 		return false, action.Response{Log: log}
@@ -204,6 +209,10 @@ func (domainSendTx) ProcessDeliver(ctx *action.Context, tx action.RawTx) (bool,
 	return true, action.Response{Tags: send.Tags(), Info: to.String()}
 }
 
-func (domainSendTx) ProcessFee(ctx *action.Context, signedTx action.SignedTx, start action.Gas, size action.Gas) (bool, action.Response) {
-	return action.BasicFeeHandling(ctx, signedTx, start, size, 1)
+func (d domainSendTx) ProcessFee(ctx *action.Context, signedTx action.SignedTx, start action.Gas, size action.Gas) (bool, action.Response) {
+	return action.BasicFeeHandling(ctx, signedTx, start, size, d.IntrinsicGas(ctx, signedTx))
+}
+
+func (d domainSendTx) IntrinsicGas(ctx *action.Context, signedTx action.SignedTx) action.Gas {
+	return action.BasicIntrinsicGas(ctx, signedTx)
 }
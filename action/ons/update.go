@@ -83,6 +83,11 @@ func (domainUpdateTx) Validate(ctx *action.Context, tx action.SignedTx) (bool, e
 		return false, action.ErrMissingData
 	}
 
+	err = checkName(update.Name)
+	if err != nil {
+		return false, err
+	}
+
 	if update.Active == false && update.Account == nil {
 		return false, action.ErrMissingData
 	}
@@ -101,9 +106,9 @@ func (domainUpdateTx) ProcessCheck(ctx *action.Context, tx action.RawTx) (bool,
 		return false, action.Response{Log: fmt.Sprintf("domain doesn't exist: %s", update.Name)}
 	}
 
-	d, err := ctx.Domains.Get(update.Name)
+	d, err := getDomain(ctx, update.Name)
 	if err != nil {
-		return false, action.Response{Log: fmt.Sprintf("failed to get domain: %s", update.Name)}
+		return false, action.Response{Log: errors.Wrapf(err, "failed to get domain: %s", update.Name).Error()}
 	}
 
 	if !bytes.Equal(d.OwnerAddress, update.Owner) {
@@ -124,9 +129,9 @@ func (domainUpdateTx) ProcessDeliver(ctx *action.Context, tx action.RawTx) (bool
 		return false, action.Response{Log: fmt.Sprintf("domain doesn't exist: %s", update.Name)}
 	}
 
-	d, err := ctx.Domains.Get(update.Name)
+	d, err := getDomain(ctx, update.Name)
 	if err != nil {
-		return false, action.Response{Log: fmt.Sprintf("failed to get domain: %s", update.Name)}
+		return false, action.Response{Log: errors.Wrapf(err, "failed to get domain: %s", update.Name).Error()}
 	}
 
 	if !d.IsChangeable(ctx.Header.Height) {
@@ -152,6 +157,10 @@ func (domainUpdateTx) ProcessDeliver(ctx *action.Context, tx action.RawTx) (bool
 	return true, action.Response{Tags: update.Tags()}
 }
 
-func (domainUpdateTx) ProcessFee(ctx *action.Context, signedTx action.SignedTx, start action.Gas, size action.Gas) (bool, action.Response) {
-	return action.BasicFeeHandling(ctx, signedTx, start, size, 1)
+func (d domainUpdateTx) ProcessFee(ctx *action.Context, signedTx action.SignedTx, start action.Gas, size action.Gas) (bool, action.Response) {
+	return action.BasicFeeHandling(ctx, signedTx, start, size, d.IntrinsicGas(ctx, signedTx))
+}
+
+func (d domainUpdateTx) IntrinsicGas(ctx *action.Context, signedTx action.SignedTx) action.Gas {
+	return action.BasicIntrinsicGas(ctx, signedTx)
 }
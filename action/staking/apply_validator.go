@@ -112,7 +112,11 @@ func (a applyTx) ProcessDeliver(ctx *action.Context, tx action.RawTx) (bool, act
 }
 
 func (a applyTx) ProcessFee(ctx *action.Context, signedTx action.SignedTx, start action.Gas, size action.Gas) (bool, action.Response) {
-	return action.BasicFeeHandling(ctx, signedTx, start, size, 2)
+	return action.BasicFeeHandling(ctx, signedTx, start, size, a.IntrinsicGas(ctx, signedTx))
+}
+
+func (a applyTx) IntrinsicGas(ctx *action.Context, signedTx action.SignedTx) action.Gas {
+	return action.BasicIntrinsicGas(ctx, signedTx)
 }
 
 func (apply ApplyValidator) Tags() common.KVPairs {
@@ -99,8 +99,12 @@ func (s withdrawTx) ProcessDeliver(ctx *action.Context, tx action.RawTx) (ok boo
 	return
 }
 
-func (withdrawTx) ProcessFee(ctx *action.Context, signedTx action.SignedTx, start action.Gas, size action.Gas) (bool, action.Response) {
-	return action.BasicFeeHandling(ctx, signedTx, start, size, 1)
+func (w withdrawTx) ProcessFee(ctx *action.Context, signedTx action.SignedTx, start action.Gas, size action.Gas) (bool, action.Response) {
+	return action.BasicFeeHandling(ctx, signedTx, start, size, w.IntrinsicGas(ctx, signedTx))
+}
+
+func (w withdrawTx) IntrinsicGas(ctx *action.Context, signedTx action.SignedTx) action.Gas {
+	return action.BasicIntrinsicGas(ctx, signedTx)
 }
 
 func runWithdraw(ctx *action.Context, tx action.RawTx) (bool, action.Response) {
@@ -114,7 +118,10 @@ func runWithdraw(ctx *action.Context, tx action.RawTx) (bool, action.Response) {
 		return false, action.Response{Log: err.Error()}
 	}
 
-	allow := feePool.GetAllowedWithdraw(draw.From)
+	allow, err := feePool.GetAllowedWithdraw(draw.From)
+	if err != nil {
+		return false, action.Response{Log: err.Error()}
+	}
 	if allow.LessThanCoin(ctx.FeeOpt.MinFee()) {
 		return false, action.Response{Log: "No reward is allowed to withdraw"}
 	}
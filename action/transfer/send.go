@@ -99,8 +99,12 @@ func (s sendTx) ProcessDeliver(ctx *action.Context, tx action.RawTx) (ok bool, r
 	return
 }
 
-func (sendTx) ProcessFee(ctx *action.Context, signedTx action.SignedTx, start action.Gas, size action.Gas) (bool, action.Response) {
-	return action.BasicFeeHandling(ctx, signedTx, start, size, 1)
+func (s sendTx) ProcessFee(ctx *action.Context, signedTx action.SignedTx, start action.Gas, size action.Gas) (bool, action.Response) {
+	return action.BasicFeeHandling(ctx, signedTx, start, size, s.IntrinsicGas(ctx, signedTx))
+}
+
+func (s sendTx) IntrinsicGas(ctx *action.Context, signedTx action.SignedTx) action.Gas {
+	return action.BasicIntrinsicGas(ctx, signedTx)
 }
 
 func runTx(ctx *action.Context, tx action.RawTx) (bool, action.Response) {
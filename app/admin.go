@@ -0,0 +1,168 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/tendermint/tendermint/p2p"
+
+	"github.com/Oneledger/protocol/client"
+	"github.com/Oneledger/protocol/config"
+	"github.com/Oneledger/protocol/consensus"
+	"github.com/Oneledger/protocol/service/admin"
+)
+
+var _ admin.Node = &nodeAdmin{}
+
+var errNodeNotStarted = errors.New("the consensus node isn't started")
+
+// nodeAdmin is the running node as the admin service manages it. Banned peers are turned
+// away by the peer filter tendermint queries the app with.
+type nodeAdmin struct {
+	sync.RWMutex
+	cfg    *config.Server
+	node   *consensus.Node
+	banned map[string]bool
+}
+
+func newNodeAdmin(cfg *config.Server) *nodeAdmin {
+	banned := make(map[string]bool)
+	for _, id := range cfg.P2P.BannedPeers {
+		banned[id] = true
+	}
+	return &nodeAdmin{cfg: cfg, banned: banned}
+}
+
+func (a *nodeAdmin) setNode(node *consensus.Node) {
+	a.Lock()
+	defer a.Unlock()
+	a.node = node
+}
+
+func (a *nodeAdmin) switchboard() (*p2p.Switch, error) {
+	a.RLock()
+	defer a.RUnlock()
+	if a.node == nil {
+		return nil, errNodeNotStarted
+	}
+	return a.node.Switch(), nil
+}
+
+// SetOption changes the config of the running node, ABCI SetOption goes through it too
+func (a *nodeAdmin) SetOption(key, value string) error {
+	a.Lock()
+	defer a.Unlock()
+	return config.Setup(a.cfg, key, value)
+}
+
+func (a *nodeAdmin) Config() ([]byte, error) {
+	a.RLock()
+	defer a.RUnlock()
+	return a.cfg.Redacted().Marshal()
+}
+
+func (a *nodeAdmin) Peers() []client.AdminPeer {
+	list := make([]client.AdminPeer, 0)
+	sw, err := a.switchboard()
+	if err != nil {
+		return list
+	}
+	for _, p := range sw.Peers().List() {
+		list = append(list, client.AdminPeer{
+			ID:         string(p.ID()),
+			Address:    p.SocketAddr().String(),
+			Outbound:   p.IsOutbound(),
+			Persistent: p.IsPersistent(),
+		})
+	}
+	return list
+}
+
+func (a *nodeAdmin) BannedPeers() []string {
+	a.RLock()
+	defer a.RUnlock()
+	return a.bannedList()
+}
+
+func (a *nodeAdmin) bannedList() []string {
+	list := make([]string, 0, len(a.banned))
+	for id := range a.banned {
+		list = append(list, id)
+	}
+	sort.Strings(list)
+	return list
+}
+
+func (a *nodeAdmin) DialPeers(addrs []string, persistent bool) error {
+	sw, err := a.switchboard()
+	if err != nil {
+		return err
+	}
+	for _, addr := range addrs {
+		if _, err := p2p.NewNetAddressString(addr); err != nil {
+			return errors.Wrap(err, "invalid peer address")
+		}
+	}
+	return sw.DialPeersAsync(nil, addrs, persistent)
+}
+
+func (a *nodeAdmin) BanPeer(id string) error {
+	a.Lock()
+	a.banned[id] = true
+	err := a.saveBanned()
+	node := a.node
+	a.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if node == nil {
+		return nil
+	}
+	sw := node.Switch()
+	if peer := sw.Peers().Get(p2p.ID(id)); peer != nil {
+		sw.StopPeerForError(peer, "banned")
+	}
+	return nil
+}
+
+func (a *nodeAdmin) UnbanPeer(id string) error {
+	a.Lock()
+	defer a.Unlock()
+	if !a.banned[id] {
+		return errors.Errorf("peer %s isn't banned", id)
+	}
+	delete(a.banned, id)
+	return a.saveBanned()
+}
+
+// saveBanned writes the banned peers to the config file, so the bans outlive a restart
+func (a *nodeAdmin) saveBanned() error {
+	a.cfg.P2P.BannedPeers = a.bannedList()
+	err := a.cfg.SaveFile(filepath.Join(a.cfg.RootDir(), config.FileName))
+	return errors.Wrap(err, "failed to save the banned peers")
+}
+
+const (
+	filterPath   = "/p2p/filter/"
+	filterIDPath = filterPath + "id/"
+)
+
+// filterPeer answers the queries of the tendermint peer filter, every address is allowed
+// and every peer but the banned ones
+func (a *nodeAdmin) filterPeer(path string) ResponseQuery {
+	if strings.HasPrefix(path, filterIDPath) {
+		id := strings.TrimPrefix(path, filterIDPath)
+		a.RLock()
+		banned := a.banned[id]
+		a.RUnlock()
+		if banned {
+			return ResponseQuery{Code: CodeNotOK.uint32(), Log: fmt.Sprintf("peer %s is banned", id)}
+		}
+	}
+	return ResponseQuery{Code: CodeOK.uint32()}
+}
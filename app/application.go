@@ -21,11 +21,12 @@ import (
 	"github.com/Oneledger/protocol/data/balance"
 	"github.com/Oneledger/protocol/data/bitcoin"
 	"github.com/Oneledger/protocol/data/chain"
+	"github.com/Oneledger/protocol/data/history"
 	"github.com/Oneledger/protocol/data/keys"
-	"github.com/Oneledger/protocol/data/ons"
 	"github.com/Oneledger/protocol/event"
 	"github.com/Oneledger/protocol/identity"
 	"github.com/Oneledger/protocol/log"
+	"github.com/Oneledger/protocol/rpc/pubsub"
 	"github.com/Oneledger/protocol/serialize"
 	"github.com/Oneledger/protocol/storage"
 )
@@ -43,6 +44,10 @@ type App struct {
 
 	header Header // Tendermint last header info
 
+	events []*pubsub.Event // events of the current block, published once it is committed
+	// the last tx of the current block that changed each tracker
+	trackerTxs *trackerTxs
+
 	abci *ABCI
 
 	node       *consensus.Node
@@ -57,6 +62,10 @@ func NewApp(cfg *config.Server, nodeContext *node.Context) (*App, error) {
 
 	// TODO: Determine the final logWriter in the configuration file
 	w := os.Stdout
+	err := cfg.Node.SetupLogging()
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid log config")
+	}
 
 	app := &App{
 		name:   "OneLedger",
@@ -70,6 +79,7 @@ func NewApp(cfg *config.Server, nodeContext *node.Context) (*App, error) {
 	}
 
 	app.Context = ctx
+	app.Context.admin = newNodeAdmin(&app.Context.cfg)
 	app.setNewABCI()
 	return app, nil
 }
@@ -108,11 +118,20 @@ func (app *App) setNewABCI() {
 func (app *App) setupState(stateBytes []byte) error {
 	app.logger.Info("Setting up state...")
 	var initial consensus.AppState
+	// bring states exported by older releases up to date first
+	stateBytes, err := consensus.MigrateAppState(stateBytes)
+	if err != nil {
+		return errors.Wrap(err, "setupState migration")
+	}
 	// Deserialize and get the proper app state
-	err := serialize.GetSerializer(serialize.JSON).Deserialize(stateBytes, &initial)
+	err = serialize.GetSerializer(serialize.JSON).Deserialize(stateBytes, &initial)
 	if err != nil {
 		return errors.Wrap(err, "setupState deserialization")
 	}
+	err = initial.CheckInvariants()
+	if err != nil {
+		return errors.Wrap(err, "setupState")
+	}
 
 	// commit the initial currencies to the governance db
 
@@ -143,6 +162,30 @@ func (app *App) setupState(stateBytes []byte) error {
 		return errors.Wrap(err, "Setup State")
 	}
 	app.Context.feePool.SetupOpt(app.Context.feeOption)
+	if initial.Governance.Epoch != 0 {
+		err = app.Context.govern.SetEpoch(initial.Governance.Epoch)
+		if err != nil {
+			return errors.Wrap(err, "Setup State")
+		}
+	}
+	if initial.Governance.GasSchedule != nil {
+		err = app.Context.govern.SetGasSchedule(*initial.Governance.GasSchedule)
+		if err != nil {
+			return errors.Wrap(err, "Setup State")
+		}
+	}
+	if initial.Governance.Upgrades != nil {
+		err = app.Context.govern.SetUpgrades(*initial.Governance.Upgrades)
+		if err != nil {
+			return errors.Wrap(err, "Setup State")
+		}
+	}
+	if len(initial.Governance.ReservedNames) > 0 {
+		err = app.Context.govern.SetReservedNames(initial.Governance.ReservedNames)
+		if err != nil {
+			return errors.Wrap(err, "Setup State")
+		}
+	}
 
 	// (2) Set balances to all those mentioned
 	for _, bal := range initial.Balances {
@@ -166,11 +209,40 @@ func (app *App) setupState(stateBytes []byte) error {
 	}
 
 	for _, domain := range initial.Domains {
-		d := ons.NewDomain(domain.OwnerAddress, domain.AccountAddress, domain.Name, 0)
-		err := app.Context.domains.WithState(app.Context.deliver).Set(d)
+		d := importDomain(domain)
+		domains := app.Context.domains.WithState(app.Context.deliver)
+		err := domains.Set(d)
 		if err != nil {
 			return errors.Wrap(err, "failed to setup initial domain")
 		}
+		if domain.Primary {
+			err = domains.SetPrimary(d.AccountAddress, d.Name)
+			if err != nil {
+				return errors.Wrap(err, "failed to setup initial primary name")
+			}
+		}
+	}
+
+	for _, auction := range initial.Auctions {
+		domains := app.Context.domains.WithState(app.Context.deliver)
+		a := auction.Auction
+		err := domains.SetAuction(&a)
+		if err != nil {
+			return errors.Wrap(err, "failed to setup initial auction")
+		}
+		for i := range auction.Bids {
+			err = domains.SetBid(&auction.Bids[i])
+			if err != nil {
+				return errors.Wrap(err, "failed to setup initial bid")
+			}
+		}
+	}
+
+	for i := range initial.Offers {
+		err := app.Context.domains.WithState(app.Context.deliver).SetOffer(&initial.Offers[i])
+		if err != nil {
+			return errors.Wrap(err, "failed to setup initial offer")
+		}
 	}
 
 	for _, fee := range initial.Fees {
@@ -183,6 +255,51 @@ func (app *App) setupState(stateBytes []byte) error {
 			return errors.Wrap(err, "failed to setup initial fee")
 		}
 	}
+
+	for _, fee := range initial.FeeWithdrawals {
+		c := app.Context.feeOption.FeeCurrency
+		err := app.Context.feePool.WithState(app.Context.deliver).SetUnlocked(fee.Address, c.NewCoinFromAmount(fee.Amount))
+		if err != nil {
+			return errors.Wrap(err, "failed to setup initial fee withdrawal")
+		}
+	}
+
+	for _, t := range initial.BTCTrackers {
+		tracker := t.Tracker
+		err := app.Context.btcTrackers.WithState(app.Context.deliver).SetTracker(tracker.Name, &tracker)
+		if err != nil {
+			return errors.Wrap(err, "failed to setup initial bitcoin tracker")
+		}
+		for _, script := range t.LockScripts {
+			err = app.Context.lockScriptStore.SaveLockScript(script.Address, script.Script)
+			if err != nil {
+				return errors.Wrap(err, "failed to setup initial lock script")
+			}
+		}
+	}
+
+	for i := range initial.ETHTrackers {
+		err := app.Context.ethTrackers.WithState(app.Context.deliver).Set(&initial.ETHTrackers[i])
+		if err != nil {
+			return errors.Wrap(err, "failed to setup initial ethereum tracker")
+		}
+	}
+
+	for _, h := range []struct {
+		store   *history.Store
+		entries []history.Entry
+	}{
+		{app.Context.btcHistory, initial.BTCHistory},
+		{app.Context.ethHistory, initial.ETHHistory},
+	} {
+		for _, e := range h.entries {
+			_, err := h.store.WithState(app.Context.deliver).Append(e)
+			if err != nil {
+				return errors.Wrap(err, "failed to setup initial tracker history")
+			}
+		}
+	}
+
 	app.Context.deliver.Write()
 	return nil
 }
@@ -217,6 +334,10 @@ func (app *App) setupValidators(req RequestInitChain, currencies *balance.Curren
 		}
 
 		name := fmt.Sprintf("tracker_%d", i)
+		if _, err := app.Context.btcTrackers.Get(name); err == nil {
+			// carried over from an exported state
+			continue
+		}
 		err = app.Context.btcTrackers.SetTracker(name, tracker)
 		if err != nil {
 			return nil, err
@@ -321,6 +442,7 @@ func (app *App) Start() error {
 		app.logger.Error("Failed to start consensus.Node")
 		return errors.Wrap(err, "failed to start new consensus.Node")
 	}
+	app.Context.admin.setNode(node)
 
 	startRPC, err := app.rpcStarter()
 	if err != nil {
@@ -345,7 +467,7 @@ func (app *App) Start() error {
 	}
 
 	app.node = node
-	app.Context.internalService = event.NewService(app.Context.node,
+	app.Context.internalService = event.NewService(app.Context.signer,
 		log.NewLoggerWithPrefix(app.Context.logWriter, "internal_service"), internalRouter, node)
 
 	_ = app.Context.jobBus.Start(app.Context.JobContext())
@@ -376,13 +498,22 @@ func (app *App) rpcStarter() (func() error, error) {
 	if err != nil {
 		return noop, err
 	}
+	roles, err := app.Context.ServiceRoles()
+	if err != nil {
+		return noop, err
+	}
 	for name, svc := range services {
-		err := app.Context.rpc.Register(name, svc)
+		err := app.Context.rpc.RegisterWithRole(name, svc, roles[name])
 		if err != nil {
 			app.logger.Errorf("failed to register service %s", name)
 		}
 	}
 
+	err = app.Context.rpc.RegisterRoutes(app.Context.Routes()...)
+	if err != nil {
+		return noop, err
+	}
+
 	restfulRouter, err := app.Context.Restful()
 	if err != nil {
 		return noop, err
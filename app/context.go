@@ -11,6 +11,7 @@ import (
 	"github.com/Oneledger/protocol/action"
 	"github.com/Oneledger/protocol/action/btc"
 	"github.com/Oneledger/protocol/action/eth"
+	action_governance "github.com/Oneledger/protocol/action/governance"
 	action_ons "github.com/Oneledger/protocol/action/ons"
 	"github.com/Oneledger/protocol/action/staking"
 	"github.com/Oneledger/protocol/action/transfer"
@@ -25,21 +26,25 @@ import (
 	"github.com/Oneledger/protocol/data/ethereum"
 	"github.com/Oneledger/protocol/data/fees"
 	"github.com/Oneledger/protocol/data/governance"
+	"github.com/Oneledger/protocol/data/history"
 	"github.com/Oneledger/protocol/data/jobs"
 	"github.com/Oneledger/protocol/data/ons"
 	"github.com/Oneledger/protocol/event"
 	"github.com/Oneledger/protocol/identity"
 	"github.com/Oneledger/protocol/log"
+	"github.com/Oneledger/protocol/metrics"
 	"github.com/Oneledger/protocol/rpc"
 	"github.com/Oneledger/protocol/service"
+	"github.com/Oneledger/protocol/signer"
 	"github.com/Oneledger/protocol/storage"
 )
 
 // The base context for the application, holds databases and other stateful information contained by the app.
 // Used to derive other package-level Contexts
 type context struct {
-	node node.Context
-	cfg  config.Server
+	node   node.Context
+	cfg    config.Server
+	signer signer.Signer
 
 	rpc          *rpc.Server
 	actionRouter action.Router
@@ -57,6 +62,8 @@ type context struct {
 	govern      *governance.Store
 	btcTrackers *bitcoin.TrackerStore  // tracker for bitcoin balance UTXO
 	ethTrackers *ethereum.TrackerStore // tracker for ethereum tracker store
+	btcHistory  *history.Store         // state transitions of the bitcoin trackers
+	ethHistory  *history.Store         // state transitions of the ethereum trackers
 
 	currencies *balance.CurrencySet
 	feeOption  *fees.FeeOption
@@ -69,6 +76,9 @@ type context struct {
 	internalService *event.Service
 	jobBus          *event.JobBus
 
+	metrics *metrics.Metrics
+	admin   *nodeAdmin
+
 	logWriter io.Writer
 }
 
@@ -80,7 +90,18 @@ func newContext(logWriter io.Writer, cfg config.Server, nodeCtx *node.Context) (
 		node:       *nodeCtx,
 	}
 
+	ctx.metrics = metrics.NopMetrics()
+	if cfg.Node.Metrics {
+		ctx.metrics = metrics.PrometheusMetrics()
+	}
 	ctx.rpc = rpc.NewServer(logWriter, &cfg)
+	ctx.rpc.SetMetrics(ctx.metrics)
+
+	s, err := newSigner(cfg.Signer, nodeCtx)
+	if err != nil {
+		return ctx, errors.Wrap(err, "failed to create signer")
+	}
+	ctx.signer = s
 
 	db, err := storage.GetDatabase("chainstate", ctx.dbDir(), ctx.cfg.Node.DB)
 	if err != nil {
@@ -88,7 +109,11 @@ func newContext(logWriter io.Writer, cfg config.Server, nodeCtx *node.Context) (
 	}
 	ctx.db = db
 	ctx.chainstate = storage.NewChainState("chainstate", db)
-	ctx.chainstate.SetupRotation(10, 100, 10)
+	recent, every, cycles, err := cfg.Node.PruningOptions()
+	if err != nil {
+		return ctx, errors.Wrap(err, "invalid pruning config")
+	}
+	ctx.chainstate.SetupRotation(recent, every, cycles)
 	ctx.deliver = storage.NewState(ctx.chainstate)
 	ctx.check = storage.NewState(ctx.chainstate)
 
@@ -99,6 +124,8 @@ func newContext(logWriter io.Writer, cfg config.Server, nodeCtx *node.Context) (
 	ctx.govern = governance.NewStore("g", storage.NewState(ctx.chainstate))
 	ctx.btcTrackers = bitcoin.NewTrackerStore("btct", storage.NewState(ctx.chainstate))
 	ctx.ethTrackers = ethereum.NewTrackerStore("etht", storage.NewState(ctx.chainstate))
+	ctx.btcHistory = history.NewStore("btch", storage.NewState(ctx.chainstate))
+	ctx.ethHistory = history.NewStore("ethh", storage.NewState(ctx.chainstate))
 	ctx.accounts = accounts.NewWallet(cfg, ctx.dbDir())
 
 	// TODO check if validator
@@ -116,6 +143,7 @@ func newContext(logWriter io.Writer, cfg config.Server, nodeCtx *node.Context) (
 	ctx.jobBus = event.NewJobBus(event.Option{
 		BtcInterval: 30 * time.Second,
 		EthInterval: 3 * time.Second,
+		Metrics:     ctx.metrics,
 	}, ctx.jobStore)
 
 	_ = transfer.EnableSend(ctx.actionRouter)
@@ -123,9 +151,25 @@ func newContext(logWriter io.Writer, cfg config.Server, nodeCtx *node.Context) (
 	_ = action_ons.EnableONS(ctx.actionRouter)
 	_ = btc.EnableBTC(ctx.actionRouter)
 	_ = eth.EnableETH(ctx.actionRouter)
+	_ = action_governance.EnableGovernance(ctx.actionRouter)
 	return ctx, nil
 }
 
+// newSigner returns the signer holding the validator and bridge keys. Without a [signer]
+// section in the config the keys read by the node context are used.
+func newSigner(cfg *config.SignerConfig, nodeCtx *node.Context) (signer.Signer, error) {
+	if cfg == nil || cfg.Mode == "" || cfg.Mode == signer.ModeLocal {
+		ecdsaKey := nodeCtx.ValidatorECDSAPrivateKey()
+		return signer.NewLocalSigner(nodeCtx.PrivVal(), ecdsaKey, ecdsaKey, nil)
+	}
+
+	if cfg.Mode == signer.ModeRemote {
+		return signer.NewRemoteSigner(cfg)
+	}
+
+	return nil, errors.Wrap(signer.ErrUnknownMode, cfg.Mode)
+}
+
 func (ctx context) dbDir() string {
 	return filepath.Join(ctx.cfg.RootDir(), ctx.cfg.Node.DBDir)
 }
@@ -136,6 +180,9 @@ func (ctx *context) Action(header *Header, state *storage.State) *action.Context
 
 	bcct := bitcoin2.GetBlockCypherChainType(ctx.cfg.ChainDriver.BitcoinChainType)
 
+	// the governance store is shared with the rpc services, so it is not switched over
+	govern := *ctx.govern
+
 	actionCtx := action.NewContext(
 		ctx.actionRouter,
 		header,
@@ -147,6 +194,7 @@ func (ctx *context) Action(header *Header, state *storage.State) *action.Context
 		ctx.feePool.WithState(state),
 		ctx.validators.WithState(state),
 		ctx.domains.WithState(state),
+		govern.WithState(state),
 
 		ctx.btcTrackers.WithState(state),
 		ctx.ethTrackers.WithState(state),
@@ -182,7 +230,7 @@ func (ctx *context) Balances() *balance.Context {
 }
 
 func (ctx *context) Services() (service.Map, error) {
-	extSvcs, err := client.NewExtServiceContext(ctx.cfg.Network.RPCAddress, ctx.cfg.Network.SDKAddress)
+	extSvcs, err := client.NewExtServiceContextWithOptions(ctx.cfg.Network.RPCAddress, ctx.cfg.Network.SDKAddress, client.SDKOptions(ctx.cfg))
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to start service context")
 	}
@@ -193,20 +241,38 @@ func (ctx *context) Services() (service.Map, error) {
 		FeeOpt:       ctx.feeOption,
 		Cfg:          ctx.cfg,
 		NodeContext:  ctx.node,
+		Signer:       ctx.signer,
 		ValidatorSet: ctx.validators,
 		Domains:      ctx.domains,
+		Govern:       ctx.govern,
 		Router:       ctx.actionRouter,
 		Logger:       log.NewLoggerWithPrefix(ctx.logWriter, "rpc").WithLevel(log.Level(ctx.cfg.Node.LogLevel)),
 		Services:     extSvcs,
 
-		Trackers: ctx.btcTrackers,
+		Trackers:    ctx.btcTrackers,
+		BTCHistory:  ctx.btcHistory,
+		ETHHistory:  ctx.ethHistory,
+		ChainState:  ctx.chainstate,
+		TxSimulator: ctx,
+		JobBus:      ctx.jobBus,
+		Admin:       ctx.admin,
+		RPCServices: ctx.rpc,
 	}
 
 	return service.NewMap(svcCtx)
 }
 
+// ServiceRoles returns the role needed to call each service
+func (ctx *context) ServiceRoles() (map[string]rpc.Role, error) {
+	return service.Roles(ctx.cfg)
+}
+
+func (ctx *context) Routes() []rpc.Route {
+	return service.Routes()
+}
+
 func (ctx *context) Restful() (service.RestfulRouter, error) {
-	extSvcs, err := client.NewExtServiceContext(ctx.cfg.Network.RPCAddress, ctx.cfg.Network.SDKAddress)
+	extSvcs, err := client.NewExtServiceContextWithOptions(ctx.cfg.Network.RPCAddress, ctx.cfg.Network.SDKAddress, client.SDKOptions(ctx.cfg))
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to start service context")
 	}
@@ -217,13 +283,18 @@ func (ctx *context) Restful() (service.RestfulRouter, error) {
 		FeeOpt:       ctx.feeOption,
 		Cfg:          ctx.cfg,
 		NodeContext:  ctx.node,
+		Signer:       ctx.signer,
 		ValidatorSet: ctx.validators,
 		Domains:      ctx.domains,
+		Govern:       ctx.govern,
 		Router:       ctx.actionRouter,
 		Logger:       log.NewLoggerWithPrefix(ctx.logWriter, "restful").WithLevel(log.Level(ctx.cfg.Node.LogLevel)),
 		Services:     extSvcs,
 
-		Trackers: ctx.btcTrackers,
+		Trackers:    ctx.btcTrackers,
+		ChainState:  ctx.chainstate,
+		TxSimulator: ctx,
+		JobBus:      ctx.jobBus,
 	}
 	return service.NewRestfulService(svcCtx).Router(), nil
 }
@@ -257,7 +328,11 @@ func (ctx *context) Storage() StorageCtx {
 
 // Close all things that need to be closed
 func (ctx *context) Close() {
-	closers := []closer{ctx.db, ctx.accounts, ctx.rpc, ctx.jobBus}
+	// the jobs drain before the stores they use are closed
+	closers := []closer{ctx.jobBus, ctx.rpc, ctx.accounts, ctx.db}
+	if c, ok := ctx.signer.(closer); ok {
+		closers = append(closers, c)
+	}
 	for _, closer := range closers {
 		closer.Close()
 	}
@@ -281,8 +356,7 @@ func (ctx *context) JobContext() *event.JobsContext {
 		ctx.cfg,
 		cdConfig.BitcoinChainType,
 		ctx.internalService, ctx.btcTrackers, ctx.validators,
-		ctx.node.ValidatorECDSAPrivateKey(),
-		ctx.node.ValidatorECDSAPrivateKey(),
+		ctx.signer,
 		ctx.node.ValidatorAddress(), ctx.cfg.ChainDriver.BlockCypherToken,
 		ctx.lockScriptStore,
 		cdConfig.BitcoinNodeAddress,
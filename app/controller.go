@@ -5,14 +5,15 @@ import (
 	"fmt"
 	"math"
 	"runtime/debug"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/tendermint/tendermint/libs/common"
 	"github.com/tendermint/tendermint/types"
 
 	"github.com/Oneledger/protocol/action"
+	action_ons "github.com/Oneledger/protocol/action/ons"
 	ceth "github.com/Oneledger/protocol/chains/ethereum"
-	"github.com/Oneledger/protocol/config"
 	"github.com/Oneledger/protocol/data/bitcoin"
 	"github.com/Oneledger/protocol/data/chain"
 	"github.com/Oneledger/protocol/data/ethereum"
@@ -22,6 +23,7 @@ import (
 	"github.com/Oneledger/protocol/event"
 	"github.com/Oneledger/protocol/identity"
 	"github.com/Oneledger/protocol/log"
+	"github.com/Oneledger/protocol/rpc/pubsub"
 	"github.com/Oneledger/protocol/serialize"
 	"github.com/Oneledger/protocol/storage"
 	"github.com/Oneledger/protocol/utils/transition"
@@ -49,9 +51,11 @@ func (app *App) infoServer() infoServer {
 }
 
 func (app *App) queryer() queryer {
-	return func(RequestQuery) ResponseQuery {
+	return func(req RequestQuery) ResponseQuery {
 		defer app.handlePanic()
-		// Do stuff
+		if strings.HasPrefix(req.Path, filterPath) {
+			return app.Context.admin.filterPeer(req.Path)
+		}
 		return ResponseQuery{}
 	}
 }
@@ -59,7 +63,7 @@ func (app *App) queryer() queryer {
 func (app *App) optionSetter() optionSetter {
 	return func(req RequestSetOption) ResponseSetOption {
 		defer app.handlePanic()
-		err := config.Setup(&app.Context.cfg, req.Key, req.Value)
+		err := app.Context.admin.SetOption(req.Key, req.Value)
 		if err != nil {
 			return ResponseSetOption{
 				Code: CodeNotOK.uint32(),
@@ -108,8 +112,9 @@ func (app *App) chainInitializer() chainInitializer {
 func (app *App) blockBeginner() blockBeginner {
 	return func(req RequestBeginBlock) ResponseBeginBlock {
 		defer app.handlePanic()
-		gc := getGasCalculator(app.genesisDoc.ConsensusParams)
-		app.Context.deliver = storage.NewState(app.Context.chainstate).WithGas(gc)
+		gc := getGasCalculator(app.genesisDoc.ConsensusParams, app.Context.gasSchedule())
+		tracking := app.Context.upgrades().DeleteTrackingAt(req.Header.Height)
+		app.Context.deliver = storage.NewState(app.Context.chainstate).WithGas(gc).WithDeleteTracking(tracking)
 
 		// update the validator set
 		err := app.Context.validators.Setup(req, app.Context.node.ValidatorAddress())
@@ -123,6 +128,8 @@ func (app *App) blockBeginner() blockBeginner {
 
 		//update the header to current block
 		app.header = req.Header
+		app.events = make([]*pubsub.Event, 0)
+		app.trackerTxs = newTrackerTxs()
 
 		app.logger.Debug("Begin Block:", result, "height:", req.Header.Height, "AppHash:", hex.EncodeToString(req.Header.AppHash))
 		return result
@@ -144,8 +151,6 @@ func (app *App) txChecker() txChecker {
 		txCtx := app.Context.Action(&app.header, app.Context.check)
 		handler := txCtx.Router.Handler(tx.Type)
 
-		gas := txCtx.State.ConsumedGas()
-
 		ok, err := handler.Validate(txCtx, *tx)
 		if err != nil {
 			app.logger.Debug("Check Tx invalid: ", err.Error())
@@ -154,6 +159,10 @@ func (app *App) txChecker() txChecker {
 				Log:  err.Error(),
 			}
 		}
+
+		// start counting after Validate, so CheckTx uses the same gas as DeliverTx
+		gas := txCtx.State.ConsumedGas()
+
 		ok, response := handler.ProcessCheck(txCtx, tx.RawTx)
 
 		feeOk, feeResponse := handler.ProcessFee(txCtx, *tx, gas, storage.Gas(len(msg)))
@@ -179,10 +188,12 @@ func (app *App) txDeliverer() txDeliverer {
 		defer app.handlePanic()
 
 		tx := &action.SignedTx{}
+		txHash := hex.EncodeToString(types.Tx(msg).Hash())
+		logger := app.logger.With("height", app.header.Height, "tx", txHash)
 
 		err := serialize.GetSerializer(serialize.NETWORK).Deserialize(msg, tx)
 		if err != nil {
-			app.logger.Errorf("deliverTx failed to deserialize msg: %s, error: %s ", msg, err)
+			logger.Errorf("deliverTx failed to deserialize msg: %s, error: %s ", msg, err)
 		}
 		txCtx := app.Context.Action(&app.header, app.Context.deliver)
 
@@ -204,7 +215,14 @@ func (app *App) txDeliverer() txDeliverer {
 			Tags:      response.Tags,
 			Codespace: "",
 		}
-		app.logger.Debug("Deliver Tx: ", result)
+		app.events = append(app.events, txEvents(app.header.Height, msg, tx, result)...)
+		if isBridgeTx(tx.Type) {
+			app.trackerTxs.update(txHash,
+				app.Context.btcTrackers.WithState(app.Context.deliver), app.Context.ethTrackers.WithState(app.Context.deliver))
+		}
+		txMetrics(app.Context.metrics, tx.Type.String(), result.Code)
+
+		logger.Debug("Deliver Tx: ", result)
 		return result
 	}
 }
@@ -221,9 +239,35 @@ func (app *App) blockEnder() blockEnder {
 			Tags:             []common.KVPair(nil),
 		}
 
-		doTransitions(app.Context.jobStore, app.Context.btcTrackers.WithState(app.Context.deliver), app.Context.validators)
+		btcLog := transitionLog{app.Context.btcHistory.WithState(app.Context.deliver), "btc", req.Height, app.trackerTxs, app.logger}
+		doTransitions(app.Context.jobStore, app.Context.btcTrackers.WithState(app.Context.deliver), app.Context.validators, btcLog)
+
+		ethLog := transitionLog{app.Context.ethHistory.WithState(app.Context.deliver), "eth", req.Height, app.trackerTxs, app.logger}
+		doEthTransitions(app.Context.jobStore, app.Context.ethTrackers.WithState(app.Context.deliver), app.Context.node.ValidatorAddress(), app.logger, app.Context.validators, ethLog)
 
-		doEthTransitions(app.Context.jobStore, app.Context.ethTrackers.WithState(app.Context.deliver), app.Context.node.ValidatorAddress(), app.logger, app.Context.validators)
+		onsCtx := app.Context.Action(&app.header, app.Context.deliver)
+		released, releaseTags, err := action_ons.ReleaseDomains(onsCtx)
+		if err != nil {
+			app.logger.Error("failed to release expired domains", err)
+		} else if len(released) > 0 {
+			app.logger.Info("released expired domains", released)
+		}
+		result.Tags = append(result.Tags, releaseTags...)
+
+		awarded, err := action_ons.CloseAuctions(onsCtx)
+		if err != nil {
+			app.logger.Error("failed to close domain auctions", err)
+		} else if len(awarded) > 0 {
+			app.logger.Info("domains awarded in auctions", awarded)
+		}
+
+		offerTags, err := action_ons.ExpireOffers(onsCtx)
+		if err != nil {
+			app.logger.Error("failed to refund expired domain offers", err)
+		}
+		result.Tags = append(result.Tags, offerTags...)
+
+		app.events = append(app.events, endBlockDomainEvents(req.Height, released, awarded, append(releaseTags, offerTags...))...)
 
 		app.logger.Debug("End Block: ", result, "height:", req.Height)
 
@@ -235,16 +279,27 @@ func (app *App) commitor() commitor {
 	return func() ResponseCommit {
 		defer app.handlePanic()
 
+		// the changes are only visible until the commit
+		deliver := app.Context.deliver
+		app.events = append(app.events, balanceEvents(app.header.Height, app.Context.balances.WithState(deliver))...)
+		app.events = append(app.events, trackerEvents(app.header.Height,
+			app.Context.btcTrackers.WithState(deliver), app.Context.ethTrackers.WithState(deliver))...)
+		app.Context.blockMetrics(app.header.Height, app.header.NumTxs, deliver)
+
 		hash, ver := app.Context.deliver.Commit()
-		app.logger.Debugf("Committed LockNew Block height[%d], hash[%s], versions[%d]", app.header.Height, hex.EncodeToString(hash), ver)
+		app.logger.With("height", app.header.Height).Debugf("Committed LockNew Block height[%d], hash[%s], versions[%d]", app.header.Height, hex.EncodeToString(hash), ver)
 
 		// update check state by deliver state
-		gc := getGasCalculator(app.genesisDoc.ConsensusParams)
-		app.Context.check = storage.NewState(app.Context.chainstate).WithGas(gc)
+		gc := getGasCalculator(app.genesisDoc.ConsensusParams, app.Context.gasSchedule())
+		tracking := app.Context.upgrades().DeleteTrackingAt(app.header.Height + 1)
+		app.Context.check = storage.NewState(app.Context.chainstate).WithGas(gc).WithDeleteTracking(tracking)
 		result := ResponseCommit{
 			Data: hash,
 		}
 
+		app.Context.rpc.Events().Publish(append([]*pubsub.Event{blockEvent(app.header, hash)}, app.events...)...)
+		app.events = nil
+
 		app.logger.Debug("Commit Result", result)
 		return result
 	}
@@ -272,7 +327,7 @@ func (app *App) handlePanic() {
 	}
 }
 
-func getGasCalculator(params *types.ConsensusParams) storage.GasCalculator {
+func getGasCalculator(params *types.ConsensusParams, schedule storage.GasSchedule) storage.GasCalculator {
 	limit := int64(0)
 	if params != nil {
 		limit = params.Block.MaxGas
@@ -283,10 +338,10 @@ func getGasCalculator(params *types.ConsensusParams) storage.GasCalculator {
 	} else {
 		gas = storage.Gas(limit)
 	}
-	return storage.NewGasCalculator(gas)
+	return storage.NewGasCalculatorWithSchedule(gas, schedule)
 }
 
-func doTransitions(js *jobs.JobStore, ts *bitcoin.TrackerStore, validators *identity.ValidatorStore) {
+func doTransitions(js *jobs.JobStore, ts *bitcoin.TrackerStore, validators *identity.ValidatorStore, transitions transitionLog) {
 
 	btcTracker := []bitcoin.Tracker{}
 	if js != nil {
@@ -309,18 +364,26 @@ func doTransitions(js *jobs.JobStore, ts *bitcoin.TrackerStore, validators *iden
 
 		ctx := bitcoin.BTCTransitionContext{&t, js.WithChain(chain.BITCOIN), validators}
 
-		stt, err := event.BtcEngine.Process(t.NextStep(), ctx, transition.Status(t.State))
+		from := t.State
+		step := t.NextStep()
+		stt, err := event.BtcEngine.Process(step, ctx, transition.Status(t.State))
 		if err != nil {
+			transitions.failed(t.Name, step, from.String(), err)
 			continue
 		}
-		if stt != -1 {
+		if stt != transition.NoTransition {
 			t.State = bitcoin.TrackerState(stt)
 			err = ts.SetTracker(t.Name, &t)
+			if err != nil {
+				transitions.failed(t.Name, step, from.String(), err)
+				continue
+			}
+			transitions.record(t.Name, step, from.String(), t.State.String())
 		}
 	}
 }
 
-func doEthTransitions(js *jobs.JobStore, ts *ethereum.TrackerStore, myValAddr keys.Address, logger *log.Logger, validators *identity.ValidatorStore) {
+func doEthTransitions(js *jobs.JobStore, ts *ethereum.TrackerStore, myValAddr keys.Address, logger *log.Logger, validators *identity.ValidatorStore, transitions transitionLog) {
 
 	tnames := make([]*ceth.TrackerName, 0, 20)
 	ts.Iterate(func(name *ceth.TrackerName, tracker *ethereum.Tracker) bool {
@@ -329,24 +392,39 @@ func doEthTransitions(js *jobs.JobStore, ts *ethereum.TrackerStore, myValAddr ke
 	})
 	for _, name := range tnames {
 		t, _ := ts.Get(*name)
+		logger := logger.With("tracker", name.Hex())
 
 		ctx := ethereum.NewTrackerCtx(t, myValAddr, js.WithChain(chain.ETHEREUM), ts, validators)
 
+		from := t.State
+		step := t.NextStep()
+		var err error
 		if t.Type == ethereum.ProcessTypeLock {
-			_, err := event.EthLockEngine.Process(t.NextStep(), ctx, transition.Status(t.State))
+			_, err = event.EthLockEngine.Process(step, ctx, transition.Status(t.State))
 			if err != nil {
 				logger.Error("failed to process eth tracker ProcessTypeLock", err)
 			}
 		} else if t.Type == ethereum.ProcessTypeRedeem {
-			_, err := event.EthRedeemEngine.Process(t.NextStep(), ctx, transition.Status(t.State))
+			_, err = event.EthRedeemEngine.Process(step, ctx, transition.Status(t.State))
 			if err != nil {
 				logger.Error("failed to process eth tracker ProcessTypeRedeem", err)
 			}
 		}
+		if err != nil {
+			// the tracker is left as it was, the changes of the failed transition are dropped. The
+			// transitions only log the failures of the local job store, so they fail on every node.
+			transitions.failed(name.String(), step, from.String(), err)
+			continue
+		}
 
-		err := ts.Set(ctx.Tracker)
+		err = ts.Set(ctx.Tracker)
 		if err != nil {
 			logger.Error("failed to save eth tracker", err)
+			continue
+		}
+		// the eth transitions set the state themselves, and may wait for votes without changing it
+		if step != transition.NOOP && ctx.Tracker.State != from {
+			transitions.record(name.String(), step, from.String(), ctx.Tracker.State.String())
 		}
 	}
 
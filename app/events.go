@@ -0,0 +1,213 @@
+package app
+
+import (
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/tendermint/tendermint/libs/common"
+	"github.com/tendermint/tendermint/types"
+
+	"github.com/Oneledger/protocol/action"
+	"github.com/Oneledger/protocol/data/balance"
+	"github.com/Oneledger/protocol/data/bitcoin"
+	"github.com/Oneledger/protocol/data/ethereum"
+	"github.com/Oneledger/protocol/data/keys"
+	"github.com/Oneledger/protocol/rpc/pubsub"
+)
+
+// tags carrying the addresses a tx touches
+var addressTags = []string{
+	"tx.owner", "tx.from", "tx.to", "tx.buyer", "tx.address",
+	"tx.delegate", "tx.locker", "tx.redeemer", "tx.validator",
+}
+
+const (
+	domainTagName    = "tx.domain_name"
+	offerExpiredTag  = "ons.offer_expired"
+	domainTypePrefix = "DOMAIN_"
+)
+
+type BlockEventData struct {
+	Height  int64     `json:"height"`
+	AppHash string    `json:"appHash"`
+	Time    time.Time `json:"time"`
+	NumTxs  int64     `json:"numTxs"`
+}
+
+type TxEventData struct {
+	Hash    string            `json:"hash"`
+	Type    string            `json:"type"`
+	Code    uint32            `json:"code"`
+	Log     string            `json:"log"`
+	GasUsed int64             `json:"gasUsed"`
+	Tags    map[string]string `json:"tags"`
+}
+
+type BalanceEventData struct {
+	Address  keys.Address   `json:"address"`
+	Currency string         `json:"currency"`
+	Amount   balance.Amount `json:"amount"`
+}
+
+type DomainEventData struct {
+	Name   string `json:"name"`
+	Action string `json:"action"`
+	TxHash string `json:"txHash,omitempty"`
+}
+
+type TrackerEventData struct {
+	Chain   string      `json:"chain"`
+	Name    string      `json:"name"`
+	From    string      `json:"from,omitempty"`
+	To      string      `json:"to"`
+	Tracker interface{} `json:"tracker"`
+}
+
+// txEvents makes the events of a delivered tx: the tx itself and, for ONS txs, the domain
+// it changed
+func txEvents(height int64, msg []byte, tx *action.SignedTx, result ResponseDeliverTx) []*pubsub.Event {
+	hash := hex.EncodeToString(types.Tx(msg).Hash())
+
+	tags := make(map[string]string)
+	addrs := make([]keys.Address, 0)
+	for _, s := range tx.Signatures {
+		h, err := s.Signer.GetHandler()
+		if err == nil {
+			addrs = append(addrs, h.Address())
+		}
+	}
+	for _, t := range result.Tags {
+		tags[string(t.Key)] = tagValue(t)
+	}
+	for _, key := range addressTags {
+		for _, t := range result.Tags {
+			if string(t.Key) == key {
+				addrs = append(addrs, tagAddress(t.Value))
+			}
+		}
+	}
+
+	typ := tx.Type.String()
+	txEvent := pubsub.NewEvent(pubsub.EventTx, height, TxEventData{
+		Hash:    hash,
+		Type:    typ,
+		Code:    result.Code,
+		Log:     result.Log,
+		GasUsed: result.GasUsed,
+		Tags:    tags,
+	}).With(pubsub.KeyTxType, typ).WithAddress(addrs...)
+
+	events := []*pubsub.Event{txEvent}
+	name, ok := tags[domainTagName]
+	if ok && result.Code == CodeOK.uint32() && strings.HasPrefix(typ, domainTypePrefix) {
+		act := strings.ToLower(strings.TrimPrefix(typ, domainTypePrefix))
+		events = append(events, domainEvent(height, name, act, hash).WithAddress(addrs...))
+	}
+	return events
+}
+
+func domainEvent(height int64, name, act, txHash string) *pubsub.Event {
+	return pubsub.NewEvent(pubsub.EventDomain, height, DomainEventData{
+		Name:   name,
+		Action: act,
+		TxHash: txHash,
+	}).With(pubsub.KeyName, name).With(pubsub.KeyAction, act)
+}
+
+// endBlockDomainEvents makes the events of the domains changed by the block itself
+func endBlockDomainEvents(height int64, released, awarded []string, offerTags common.KVPairs) []*pubsub.Event {
+	events := make([]*pubsub.Event, 0)
+	for _, name := range released {
+		events = append(events, domainEvent(height, name, "released", ""))
+	}
+	for _, name := range awarded {
+		events = append(events, domainEvent(height, name, "awarded", ""))
+	}
+	for _, t := range offerTags {
+		if string(t.Key) != offerExpiredTag {
+			continue
+		}
+		// name/buyer
+		parts := strings.SplitN(string(t.Value), "/", 2)
+		e := domainEvent(height, parts[0], "offer_expired", "")
+		if len(parts) == 2 {
+			e.WithAddress(tagAddress([]byte(parts[1])))
+		}
+		events = append(events, e)
+	}
+	return events
+}
+
+// balanceEvents makes an event for every balance changed in the block
+func balanceEvents(height int64, balances *balance.Store) []*pubsub.Event {
+	events := make([]*pubsub.Event, 0)
+	balances.IterateUpdated(func(addr keys.Address, c string, amt balance.Amount) bool {
+		events = append(events, pubsub.NewEvent(pubsub.EventBalance, height, BalanceEventData{
+			Address:  addr,
+			Currency: c,
+			Amount:   amt,
+		}).WithAddress(addr).With(pubsub.KeyCurrency, c))
+		return false
+	})
+	return events
+}
+
+// trackerEvents makes an event for every tracker whose state changed in the block
+func trackerEvents(height int64, btc *bitcoin.TrackerStore, eth *ethereum.TrackerStore) []*pubsub.Event {
+	events := make([]*pubsub.Event, 0)
+	add := func(chain, name, from, to string, tracker interface{}) {
+		events = append(events, pubsub.NewEvent(pubsub.EventTracker, height, TrackerEventData{
+			Chain:   chain,
+			Name:    name,
+			From:    from,
+			To:      to,
+			Tracker: tracker,
+		}).With(pubsub.KeyChain, chain).With(pubsub.KeyName, name).With(pubsub.KeyState, to))
+	}
+
+	btc.IterateUpdated(func(prev, t *bitcoin.Tracker) bool {
+		if prev == nil {
+			add("btc", t.Name, "", t.State.String(), t)
+		} else if prev.State != t.State {
+			add("btc", t.Name, prev.State.String(), t.State.String(), t)
+		}
+		return false
+	})
+	eth.IterateUpdated(func(prev, t *ethereum.Tracker) bool {
+		if prev == nil {
+			add("eth", t.TrackerName.String(), "", t.State.String(), t)
+		} else if prev.State != t.State {
+			add("eth", t.TrackerName.String(), prev.State.String(), t.State.String(), t)
+		}
+		return false
+	})
+	return events
+}
+
+func blockEvent(header Header, appHash []byte) *pubsub.Event {
+	return pubsub.NewEvent(pubsub.EventNewBlock, header.Height, BlockEventData{
+		Height:  header.Height,
+		AppHash: hex.EncodeToString(appHash),
+		Time:    header.Time,
+		NumTxs:  header.NumTxs,
+	})
+}
+
+// tagAddress reads an address tag, some actions tag addresses as text and some as bytes
+func tagAddress(value []byte) keys.Address {
+	addr := keys.Address{}
+	if len(value) != 20 && addr.UnmarshalText(value) == nil {
+		return addr
+	}
+	return keys.Address(value)
+}
+
+func tagValue(t common.KVPair) string {
+	for _, key := range addressTags {
+		if string(t.Key) == key {
+			return tagAddress(t.Value).String()
+		}
+	}
+	return string(t.Value)
+}
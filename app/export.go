@@ -0,0 +1,290 @@
+package app
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/Oneledger/protocol/chains/ethereum"
+	"github.com/Oneledger/protocol/consensus"
+	"github.com/Oneledger/protocol/data/balance"
+	"github.com/Oneledger/protocol/data/bitcoin"
+	ethdata "github.com/Oneledger/protocol/data/ethereum"
+	"github.com/Oneledger/protocol/data/governance"
+	"github.com/Oneledger/protocol/data/history"
+	"github.com/Oneledger/protocol/data/keys"
+	"github.com/Oneledger/protocol/data/ons"
+	"github.com/Oneledger/protocol/identity"
+	"github.com/Oneledger/protocol/serialize"
+	"github.com/Oneledger/protocol/storage"
+	"github.com/Oneledger/protocol/version"
+)
+
+// ExportState reads the complete app state at height, or at the latest height for 0, in the
+// form InitChain loads it. The chainstate stays at height afterwards, so the app must not
+// be started after an export.
+func (app *App) ExportState(height int64) (*consensus.AppState, error) {
+	ctx := &app.Context
+	if height != 0 && height != ctx.chainstate.Version {
+		err := ctx.chainstate.LoadVersion(height)
+		if err != nil {
+			return nil, errors.Wrapf(err, "height %d", height)
+		}
+	}
+	state := storage.NewState(ctx.chainstate)
+
+	appState := &consensus.AppState{
+		Version: version.Protocol.String(),
+		Chain: consensus.ChainState{
+			Version: ctx.chainstate.Version,
+			Hash:    ctx.chainstate.Hash,
+		},
+	}
+
+	err := exportGovernance(appState, ctx.govern.WithState(state))
+	if err != nil {
+		return nil, err
+	}
+	ctx.feePool.SetupOpt(&appState.FeeOption)
+
+	appState.Balances = make([]consensus.BalanceState, 0)
+	ctx.balances.WithState(state).IterateAll(func(addr keys.Address, currency string, amt balance.Amount) bool {
+		appState.Balances = append(appState.Balances, consensus.BalanceState{
+			Address:  addr,
+			Currency: currency,
+			Amount:   amt,
+		})
+		return false
+	})
+
+	appState.Staking = make([]consensus.Stake, 0)
+	ctx.validators.WithState(state).Iterate(func(addr keys.Address, v *identity.Validator) bool {
+		appState.Staking = append(appState.Staking, consensus.Stake{
+			ValidatorAddress: v.Address,
+			StakeAddress:     v.StakeAddress,
+			Pubkey:           v.PubKey,
+			ECDSAPubKey:      v.ECDSAPubKey,
+			Name:             v.Name,
+			Amount:           v.Staking,
+		})
+		return false
+	})
+
+	appState.Domains = make([]consensus.DomainState, 0)
+	domains := *ctx.domains
+	domains.WithState(state).Iterate(func(name string, d *ons.Domain) bool {
+		// released domains may still be stored until the next EndBlock
+		if d.IsReleased(appState.Chain.Version + 1) {
+			return false
+		}
+		exported := exportDomain(d, appState.Chain.Version)
+		primary, err := domains.ReverseLookup(d.AccountAddress, appState.Chain.Version+1)
+		exported.Primary = err == nil && primary.Name == d.Name
+		appState.Domains = append(appState.Domains, exported)
+		return false
+	})
+
+	domains.IterateAuctions(func(a *ons.Auction) bool {
+		appState.Auctions = append(appState.Auctions, exportAuction(&domains, *a, appState.Chain.Version))
+		return false
+	})
+	domains.IterateOffers(func(o *ons.Offer) bool {
+		o.CreationHeight -= appState.Chain.Version
+		o.ExpireHeight -= appState.Chain.Version
+		appState.Offers = append(appState.Offers, *o)
+		return false
+	})
+
+	appState.Fees = make([]consensus.BalanceState, 0)
+	feeCurrency := appState.FeeOption.FeeCurrency.Name
+	ctx.feePool.WithState(state).Iterate(func(addr keys.Address, coin balance.Coin) bool {
+		appState.Fees = append(appState.Fees, consensus.BalanceState{
+			Address:  addr,
+			Currency: feeCurrency,
+			Amount:   *coin.Amount,
+		})
+		return false
+	})
+
+	// history is lost in a new chain, so whatever could be withdrawn at height is
+	// carried over explicitly
+	for _, fee := range appState.Fees {
+		allowed, err := ctx.feePool.GetAllowedWithdraw(fee.Address)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to export fee withdrawals")
+		}
+		if allowed.Amount == nil || allowed.Amount.BigInt().Sign() == 0 {
+			continue
+		}
+		appState.FeeWithdrawals = append(appState.FeeWithdrawals, consensus.BalanceState{
+			Address:  fee.Address,
+			Currency: feeCurrency,
+			Amount:   *allowed.Amount,
+		})
+	}
+
+	appState.BTCTrackers, err = exportBTCTrackers(ctx.btcTrackers.WithState(state), ctx.lockScriptStore)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.ethTrackers.WithState(state).Iterate(func(name *ethereum.TrackerName, t *ethdata.Tracker) bool {
+		appState.ETHTrackers = append(appState.ETHTrackers, *t)
+		return false
+	})
+
+	appState.BTCHistory = exportHistory(ctx.btcHistory.WithState(state))
+	appState.ETHHistory = exportHistory(ctx.ethHistory.WithState(state))
+
+	err = appState.CheckInvariants()
+	if err != nil {
+		return nil, err
+	}
+	return appState, nil
+}
+
+func exportHistory(store *history.Store) []history.Entry {
+	entries := make([]history.Entry, 0)
+	store.IterateAll(func(e *history.Entry) bool {
+		entries = append(entries, *e)
+		return false
+	})
+	return entries
+}
+
+func exportGovernance(appState *consensus.AppState, govern *governance.Store) error {
+	currencies, err := govern.GetCurrencies()
+	if err != nil {
+		return err
+	}
+	appState.Currencies = currencies
+
+	feeOpt, err := govern.GetFeeOption()
+	if err != nil {
+		return err
+	}
+	appState.FeeOption = *feeOpt
+
+	if govern.Exists([]byte(governance.ADMIN_ETH_CHAINDRIVER_OPTION)) {
+		cdOpt, err := govern.GetETHChainDriverOption()
+		if err != nil {
+			return err
+		}
+		appState.ETHCDOption = *cdOpt
+	}
+
+	if govern.Exists([]byte(governance.ADMIN_EPOCH_BLOCK_INTERVAL)) {
+		appState.Governance.Epoch, err = govern.GetEpoch()
+		if err != nil {
+			return err
+		}
+	}
+
+	// pending gas schedule votes are not exported, the validators of the new chain vote again
+	if govern.Exists([]byte(governance.ADMIN_GAS_SCHEDULE)) {
+		schedule, err := govern.GetGasSchedule()
+		if err != nil {
+			return err
+		}
+		appState.Governance.GasSchedule = &schedule
+	}
+
+	// the exported state starts a new chain, which runs every upgrade from its first block
+	upgrades := governance.NewChainUpgrades()
+	appState.Governance.Upgrades = &upgrades
+
+	if govern.Exists([]byte(governance.ADMIN_ONS_RESERVED_NAMES)) {
+		names, err := govern.GetReservedNames()
+		if err != nil {
+			return err
+		}
+		appState.Governance.ReservedNames = names
+	}
+	return nil
+}
+
+func exportDomain(d *ons.Domain, height int64) consensus.DomainState {
+	state := consensus.DomainState{
+		OwnerAddress:   d.OwnerAddress,
+		AccountAddress: d.AccountAddress,
+		Name:           d.Name,
+		Inactive:       !d.ActiveFlag,
+		OnSale:         d.OnSaleFlag,
+	}
+	if d.OnSaleFlag {
+		price := d.SalePrice
+		state.SalePrice = &price
+	}
+	if d.ExpireHeight != 0 {
+		state.ExpiresIn = d.ExpireHeight - height
+		if state.ExpiresIn == 0 {
+			// 0 would never expire
+			state.ExpiresIn = -1
+		}
+	}
+	if len(d.Records.Addresses) > 0 || len(d.Records.Texts) > 0 || len(d.Records.ContentHash) > 0 {
+		records := d.Records
+		state.Records = &records
+	}
+	return state
+}
+
+func importDomain(state consensus.DomainState) *ons.Domain {
+	d := ons.NewDomain(state.OwnerAddress, state.AccountAddress, state.Name, 0)
+	if state.Inactive {
+		d.Deactivate()
+	}
+	if state.OnSale && state.SalePrice != nil {
+		d.PutOnSale(*state.SalePrice)
+	}
+	if state.ExpiresIn != 0 {
+		// the new chain starts at height 1, domains in their grace period keep what is left of it
+		d.SetExpireHeight(state.ExpiresIn)
+	}
+	if state.Records != nil {
+		d.Records = *state.Records
+	}
+	return d
+}
+
+func exportAuction(domains *ons.DomainStore, a ons.Auction, height int64) consensus.AuctionState {
+	a.StartHeight -= height
+	a.CommitEnd -= height
+	a.RevealEnd -= height
+
+	state := consensus.AuctionState{Auction: a, Bids: make([]ons.Bid, 0)}
+	domains.IterateBids(a.Name, func(b *ons.Bid) bool {
+		state.Bids = append(state.Bids, *b)
+		return false
+	})
+	return state
+}
+
+func exportBTCTrackers(trackers *bitcoin.TrackerStore, lockScripts *bitcoin.LockScriptStore) ([]consensus.BTCTrackerState, error) {
+	var err error
+	list := make([]consensus.BTCTrackerState, 0)
+	trackers.Iterate(func(key, value []byte) bool {
+		t := consensus.BTCTrackerState{LockScripts: make([]consensus.LockScript, 0)}
+		err = serialize.GetSerializer(serialize.PERSISTENT).Deserialize(value, &t.Tracker)
+		if err != nil {
+			err = errors.Wrapf(err, "failed to read bitcoin tracker %s", key)
+			return true
+		}
+
+		seen := make(map[string]bool)
+		for _, addr := range [][]byte{t.Tracker.CurrentLockScriptAddress, t.Tracker.ProcessLockScriptAddress} {
+			if len(addr) == 0 || seen[string(addr)] {
+				continue
+			}
+			seen[string(addr)] = true
+			script, serr := lockScripts.GetLockScript(addr)
+			if serr != nil || len(script) == 0 {
+				err = errors.Errorf("missing lock script %X of bitcoin tracker %s", addr, t.Tracker.Name)
+				return true
+			}
+			t.LockScripts = append(t.LockScripts, consensus.LockScript{Address: addr, Script: script})
+		}
+
+		list = append(list, t)
+		return false
+	})
+	return list, err
+}
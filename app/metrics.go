@@ -0,0 +1,60 @@
+package app
+
+import (
+	"math/big"
+	"strconv"
+
+	"github.com/Oneledger/protocol/chains/ethereum"
+	"github.com/Oneledger/protocol/data/bitcoin"
+	"github.com/Oneledger/protocol/data/chain"
+	ethdata "github.com/Oneledger/protocol/data/ethereum"
+	"github.com/Oneledger/protocol/data/fees"
+	"github.com/Oneledger/protocol/data/keys"
+	"github.com/Oneledger/protocol/identity"
+	"github.com/Oneledger/protocol/metrics"
+	"github.com/Oneledger/protocol/serialize"
+	"github.com/Oneledger/protocol/storage"
+)
+
+// txMetrics counts a delivered tx by its type and result code
+func txMetrics(m *metrics.Metrics, txType string, code uint32) {
+	m.Txs.WithLabelValues(txType, strconv.FormatUint(uint64(code), 10)).Inc()
+}
+
+// blockMetrics records the block read from the deliver state, it has to be called before
+// the state is committed
+func (ctx *context) blockMetrics(height int64, numTxs int64, state *storage.State) {
+	m := ctx.metrics
+
+	m.Height.Set(float64(height))
+	m.BlockTxs.Set(float64(numTxs))
+	m.BlockGas.Set(float64(state.ConsumedGas()))
+
+	m.FeePool.Reset()
+	pool, err := ctx.feePool.WithState(state).Get([]byte(fees.POOL_KEY))
+	if err == nil && pool.Amount != nil {
+		f, _ := new(big.Float).SetInt(pool.Amount.BigInt()).Float64()
+		m.FeePool.WithLabelValues(pool.Currency.Name).Set(f)
+	}
+
+	m.ValidatorPower.Reset()
+	ctx.validators.WithState(state).Iterate(func(addr keys.Address, v *identity.Validator) bool {
+		m.ValidatorPower.WithLabelValues(addr.String(), v.Name).Set(float64(v.Power))
+		return false
+	})
+
+	m.Trackers.Reset()
+	ctx.btcTrackers.WithState(state).Iterate(func(k, v []byte) bool {
+		t := &bitcoin.Tracker{}
+		err := serialize.GetSerializer(serialize.PERSISTENT).Deserialize(v, t)
+		if err != nil {
+			return false
+		}
+		m.Trackers.WithLabelValues(chain.BITCOIN.String(), t.State.String()).Inc()
+		return false
+	})
+	ctx.ethTrackers.WithState(state).Iterate(func(name *ethereum.TrackerName, t *ethdata.Tracker) bool {
+		m.Trackers.WithLabelValues(chain.ETHEREUM.String(), t.State.String()).Inc()
+		return false
+	})
+}
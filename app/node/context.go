@@ -185,8 +185,15 @@ func readKeyFiles(cfg *consensus.Config) (*Context, error) {
 		return nil, err
 	}
 
+	// The ECDSA key is optional, it may be held by a remote signer instead
 	ecdsaFile := strings.Replace(pvKeyF, ".json", "_ecdsa.json", 1)
 	ecdsaPrivateKeyB64, err := ioutil.ReadFile(ecdsaFile)
+	if os.IsNotExist(err) {
+		return &Context{
+			privateKey: priv,
+			privval:    pvkey,
+		}, nil
+	}
 	if err != nil {
 		return nil, err
 	}
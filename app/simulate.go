@@ -0,0 +1,81 @@
+package app
+
+import (
+	"math"
+	"time"
+
+	"github.com/Oneledger/protocol/action"
+	bitcoin2 "github.com/Oneledger/protocol/chains/bitcoin"
+	"github.com/Oneledger/protocol/data/governance"
+	"github.com/Oneledger/protocol/log"
+	"github.com/Oneledger/protocol/storage"
+)
+
+// gasSchedule reads the gas schedule from the last committed state
+func (ctx *context) gasSchedule() storage.GasSchedule {
+	govern := *ctx.govern
+	schedule, err := govern.WithState(storage.NewState(ctx.chainstate)).GetGasSchedule()
+	if err != nil {
+		logger := log.NewLoggerWithPrefix(ctx.logWriter, "app").WithLevel(log.Level(ctx.cfg.Node.LogLevel))
+		logger.Error("failed to read the gas schedule, using the default one", err)
+	}
+	return schedule
+}
+
+// upgrades reads the upgrade heights of the chain from the last committed state
+func (ctx *context) upgrades() governance.Upgrades {
+	govern := *ctx.govern
+	upgrades, err := govern.WithState(storage.NewState(ctx.chainstate)).GetUpgrades()
+	if err != nil {
+		logger := log.NewLoggerWithPrefix(ctx.logWriter, "app").WithLevel(log.Level(ctx.cfg.Node.LogLevel))
+		logger.Error("failed to read the upgrades", err)
+	}
+	return upgrades
+}
+
+// SimulateTx runs ProcessCheck of a tx against a throwaway copy of the last committed state
+// and returns the gas the tx would use, including its intrinsic gas and its size. Signatures
+// are not verified, they only count towards the gas. Nothing is written back.
+func (ctx *context) SimulateTx(tx action.SignedTx, size action.Gas) (bool, action.Response) {
+	gc := storage.NewGasCalculatorWithSchedule(math.MaxInt64, ctx.gasSchedule())
+	height := ctx.chainstate.Version + 1
+	state := storage.NewState(ctx.chainstate).WithGas(gc).WithDeleteTracking(ctx.upgrades().DeleteTrackingAt(height))
+
+	// the shared stores follow the check and deliver states, so the simulation works on
+	// copies of them
+	balances, feePool, validators, domains, govern := *ctx.balances, *ctx.feePool, *ctx.validators, *ctx.domains, *ctx.govern
+	btcTrackers, ethTrackers := *ctx.btcTrackers, *ctx.ethTrackers
+
+	header := &Header{Height: height, Time: time.Now()}
+	txCtx := action.NewContext(
+		ctx.actionRouter,
+		header,
+		state,
+		ctx.accounts,
+		balances.WithState(state),
+		ctx.currencies,
+		ctx.feeOption,
+		feePool.WithState(state),
+		validators.WithState(state),
+		domains.WithState(state),
+		govern.WithState(state),
+
+		btcTrackers.WithState(state),
+		ethTrackers.WithState(state),
+		ctx.jobStore,
+		bitcoin2.GetChainParams(ctx.cfg.ChainDriver.BitcoinChainType),
+		ctx.lockScriptStore,
+		ctx.cfg.ChainDriver.BlockCypherToken,
+		bitcoin2.GetBlockCypherChainType(ctx.cfg.ChainDriver.BitcoinChainType),
+
+		log.NewLoggerWithPrefix(ctx.logWriter, "simulate").WithLevel(log.Level(ctx.cfg.Node.LogLevel)))
+
+	handler := txCtx.Router.Handler(tx.Type)
+	start := state.ConsumedGas()
+	ok, response := handler.ProcessCheck(txCtx, tx.RawTx)
+	used := action.ConsumeTxGas(txCtx, handler.IntrinsicGas(txCtx, tx), start, size)
+
+	response.GasWanted = tx.Fee.Gas
+	response.GasUsed = int64(used)
+	return ok, response
+}
@@ -0,0 +1,90 @@
+package app
+
+import (
+	"bytes"
+
+	"github.com/Oneledger/protocol/action"
+	"github.com/Oneledger/protocol/data/bitcoin"
+	"github.com/Oneledger/protocol/data/ethereum"
+	"github.com/Oneledger/protocol/data/history"
+	"github.com/Oneledger/protocol/log"
+	"github.com/Oneledger/protocol/serialize"
+)
+
+// trackerTxs remembers the last tx of the block that changed each tracker, the transitions at
+// the end of the block are recorded as triggered by it
+type trackerTxs struct {
+	szlr serialize.Serializer
+	// the trackers as the last tx left them, by chain and name
+	last   map[string][]byte
+	hashes map[string]string
+}
+
+func newTrackerTxs() *trackerTxs {
+	return &trackerTxs{
+		szlr:   serialize.GetSerializer(serialize.PERSISTENT),
+		last:   make(map[string][]byte),
+		hashes: make(map[string]string),
+	}
+}
+
+func trackerKey(chain, name string) string {
+	return chain + ":" + name
+}
+
+// isBridgeTx tells if the tx is one of the btc or eth actions, the only ones changing trackers
+func isBridgeTx(typ action.Type) bool {
+	return typ >= action.BTC_LOCK && typ <= action.ETH_REDEEM
+}
+
+// update notes the trackers the tx changed
+func (tt *trackerTxs) update(hash string, btc *bitcoin.TrackerStore, eth *ethereum.TrackerStore) {
+	note := func(key string, tracker interface{}) {
+		data, err := tt.szlr.Serialize(tracker)
+		if err != nil || bytes.Equal(tt.last[key], data) {
+			return
+		}
+		tt.last[key] = data
+		tt.hashes[key] = hash
+	}
+
+	btc.IterateUpdated(func(_, t *bitcoin.Tracker) bool {
+		note(trackerKey("btc", t.Name), t)
+		return false
+	})
+	eth.IterateUpdated(func(_, t *ethereum.Tracker) bool {
+		note(trackerKey("eth", t.TrackerName.String()), t)
+		return false
+	})
+}
+
+// transitionLog records the transitions of the trackers of a chain at the end of a block
+type transitionLog struct {
+	store  *history.Store
+	chain  string
+	height int64
+	txs    *trackerTxs
+	logger *log.Logger
+}
+
+// record adds a transition committed to the tracker to its history
+func (l transitionLog) record(name, transition, from, to string) {
+	e := history.Entry{
+		Tracker:    name,
+		Transition: transition,
+		From:       from,
+		To:         to,
+		Height:     l.height,
+		TxHash:     l.txs.hashes[trackerKey(l.chain, name)],
+	}
+
+	_, err := l.store.Append(e)
+	if err != nil {
+		l.logger.Error("failed to record tracker transition", l.chain, name, err)
+	}
+}
+
+// failed logs a transition that failed, it's not kept in the history
+func (l transitionLog) failed(name, transition, from string, err error) {
+	l.logger.Error("tracker transition failed", l.chain, name, transition, "from", from, err)
+}
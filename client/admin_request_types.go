@@ -0,0 +1,118 @@
+package client
+
+import (
+	"encoding/json"
+	"time"
+)
+
+/*
+	Admin Request Types
+*/
+
+// AdminJob is a job of the job bus, Job holds it as it's stored. Status is one of new,
+// in_progress, completed, failed and dead, NextRun is when a failed job is retried.
+type AdminJob struct {
+	Chain     string          `json:"chain"`
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	Done      bool            `json:"done"`
+	Status    string          `json:"status"`
+	Attempts  int             `json:"attempts"`
+	NextRun   *time.Time      `json:"nextRun,omitempty"`
+	LastError string          `json:"lastError,omitempty"`
+	Job       json.RawMessage `json:"job"`
+}
+
+// AdminListJobsRequest lists the jobs of a chain, bitcoin or ethereum, or of every chain
+// when Chain is empty. Only the jobs with the status are listed when it's given, "dead" lists
+// the jobs out of retries.
+type AdminListJobsRequest struct {
+	Chain  string `json:"chain"`
+	Status string `json:"status"`
+}
+
+type AdminListJobsReply struct {
+	Jobs []AdminJob `json:"jobs"`
+}
+
+type AdminJobRequest struct {
+	Chain string `json:"chain"`
+	ID    string `json:"id"`
+}
+
+type AdminJobReply struct {
+	Job AdminJob `json:"job"`
+}
+
+type AdminRunJobsRequest struct{}
+
+// AdminRunJobsReply holds the jobs still in the store after the cycle
+type AdminRunJobsReply struct {
+	Jobs []AdminJob `json:"jobs"`
+}
+
+// AdminLogLevelRequest sets the log levels, e.g. "debug" or "app=info, event=debug"
+type AdminLogLevelRequest struct {
+	Levels string `json:"levels"`
+}
+
+type AdminLogLevelReply struct {
+	Levels string `json:"levels"`
+}
+
+// AdminSetOptionRequest changes an option of the running node, like ABCI SetOption
+type AdminSetOptionRequest struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type AdminSetOptionReply struct {
+	OK bool `json:"ok"`
+}
+
+type AdminPeer struct {
+	ID         string `json:"id"`
+	Address    string `json:"address"`
+	Outbound   bool   `json:"outbound"`
+	Persistent bool   `json:"persistent"`
+}
+
+type AdminListPeersRequest struct{}
+
+type AdminListPeersReply struct {
+	Peers  []AdminPeer `json:"peers"`
+	Banned []string    `json:"banned"`
+}
+
+// AdminDialPeersRequest dials peers given as id@host:port
+type AdminDialPeersRequest struct {
+	Peers      []string `json:"peers"`
+	Persistent bool     `json:"persistent"`
+}
+
+type AdminPeerRequest struct {
+	ID string `json:"id"`
+}
+
+type AdminService struct {
+	Name    string `json:"name"`
+	Role    string `json:"role"`
+	Enabled bool   `json:"enabled"`
+}
+
+type AdminListServicesRequest struct{}
+
+type AdminServiceRequest struct {
+	Name string `json:"name"`
+}
+
+type AdminServicesReply struct {
+	Services []AdminService `json:"services"`
+}
+
+type AdminConfigRequest struct{}
+
+// AdminConfigReply holds the config the node runs with as TOML, without its secrets
+type AdminConfigReply struct {
+	Config string `json:"config"`
+}
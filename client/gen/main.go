@@ -0,0 +1,193 @@
+// Command gen writes the typed client of the services of the SDK port to
+// client/service_client_gen.go. Run it with go generate in the client package after changing
+// the methods of a service.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/Oneledger/protocol/service"
+)
+
+const clientPkg = "github.com/Oneledger/protocol/client"
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// services whose names are written in capitals
+var acronyms = map[string]bool{"btc": true, "eth": true}
+
+// method prefixes of calls that don't change anything on the node, besides the query and
+// node services which only read
+var idempotentPrefixes = []string{"Get", "List", "CreateRaw", "Prepare", "Resolve", "CheckName", "ReverseLookup"}
+
+type method struct {
+	name       string
+	args       string
+	reply      string
+	idempotent bool
+}
+
+type svc struct {
+	name    string
+	typ     string
+	methods []method
+}
+
+// imports collects the packages of the types outside the client package
+type imports map[string]string
+
+func (im imports) typeName(t reflect.Type) string {
+	switch {
+	case t.Name() != "" && t.PkgPath() == clientPkg:
+		return t.Name()
+	case t.Name() != "" && t.PkgPath() == "":
+		return t.Name()
+	case t.Name() != "":
+		alias := path.Base(t.PkgPath())
+		im[t.PkgPath()] = alias
+		return alias + "." + t.Name()
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return "*" + im.typeName(t.Elem())
+	case reflect.Slice:
+		return "[]" + im.typeName(t.Elem())
+	case reflect.Map:
+		return "map[" + im.typeName(t.Key()) + "]" + im.typeName(t.Elem())
+	case reflect.Struct:
+		if t.NumField() == 0 {
+			return "struct{}"
+		}
+	}
+	panic("unsupported type " + t.String())
+}
+
+func idempotent(service, name string) bool {
+	if service == "query" || service == "node" {
+		return true
+	}
+	name = strings.TrimPrefix(name, "ONS_")
+	for _, p := range idempotentPrefixes {
+		if strings.HasPrefix(name, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// rpcMethods lists the methods net/rpc serves: exported, taking the args and a pointer to
+// the reply, returning an error
+func rpcMethods(name string, typ reflect.Type, im imports) []method {
+	methods := make([]method, 0, typ.NumMethod())
+	for i := 0; i < typ.NumMethod(); i++ {
+		m := typ.Method(i)
+		mt := m.Type
+		if m.PkgPath != "" || mt.NumIn() != 3 || mt.NumOut() != 1 || mt.Out(0) != errorType {
+			continue
+		}
+		if mt.In(2).Kind() != reflect.Ptr {
+			continue
+		}
+		methods = append(methods, method{
+			name:       m.Name,
+			args:       im.typeName(mt.In(1)),
+			reply:      im.typeName(mt.In(2).Elem()),
+			idempotent: idempotent(name, m.Name),
+		})
+	}
+	return methods
+}
+
+func exported(name string) string {
+	if acronyms[name] {
+		return strings.ToUpper(name)
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+func generate() ([]byte, error) {
+	im := make(imports)
+	services := make([]svc, 0)
+	for name, s := range service.Types() {
+		services = append(services, svc{
+			name:    name,
+			typ:     exported(name),
+			methods: rpcMethods(name, reflect.TypeOf(s), im),
+		})
+	}
+	sort.Slice(services, func(i, j int) bool { return services[i].name < services[j].name })
+
+	b := &bytes.Buffer{}
+	fmt.Fprintln(b, "// Code generated by go run ./gen; DO NOT EDIT.")
+	fmt.Fprintln(b)
+	fmt.Fprintln(b, "package client")
+	fmt.Fprintln(b)
+	fmt.Fprintln(b, "import (")
+	fmt.Fprintln(b, `"context"`)
+	fmt.Fprintln(b)
+	pkgs := make([]string, 0, len(im))
+	for pkg := range im {
+		pkgs = append(pkgs, pkg)
+	}
+	pkgs = append(pkgs, "github.com/Oneledger/protocol/rpc")
+	sort.Strings(pkgs)
+	for _, pkg := range pkgs {
+		fmt.Fprintf(b, "%q\n", pkg)
+	}
+	fmt.Fprintln(b, ")")
+
+	for _, s := range services {
+		client := s.typ + "Client"
+		fmt.Fprintf(b, "\n// %s calls the methods of the %s service\n", client, s.name)
+		fmt.Fprintf(b, "type %s struct {\nc *ServiceClient\n}\n", client)
+		fmt.Fprintf(b, "\n// %s returns the client of the %s service\n", s.typ, s.name)
+		fmt.Fprintf(b, "func (c *ServiceClient) %s() %s {\nreturn %s{c}\n}\n", s.typ, client, client)
+
+		for _, m := range s.methods {
+			rpcMethod := s.name + "." + m.name
+			call := "CallContext"
+			if m.idempotent {
+				call = "CallIdempotent"
+			}
+
+			fmt.Fprintf(b, "\n// %s calls %s", m.name, rpcMethod)
+			if m.idempotent {
+				fmt.Fprint(b, ", it's retried when the node can't be reached or is busy")
+			}
+			fmt.Fprintln(b)
+			fmt.Fprintf(b, "func (c %s) %s(ctx context.Context, req %s) (out %s, err error) {\n", client, m.name, m.args, m.reply)
+			fmt.Fprintf(b, "err = c.c.%s(ctx, %q, req, &out)\nreturn\n}\n", call, rpcMethod)
+
+			fmt.Fprintf(b, "\n// %sCall is %s in a batch, out gets the reply\n", m.name, rpcMethod)
+			fmt.Fprintf(b, "func (%s) %sCall(req %s, out *%s) *rpc.BatchCall {\n", client, m.name, m.args, m.reply)
+			fmt.Fprintf(b, "return &rpc.BatchCall{Method: %q, Args: req, Reply: out, Idempotent: %t}\n}\n", rpcMethod, m.idempotent)
+		}
+	}
+
+	return format.Source(b.Bytes())
+}
+
+func main() {
+	out := flag.String("o", "service_client_gen.go", "file to write the client to")
+	flag.Parse()
+
+	src, err := generate()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := ioutil.WriteFile(*out, src, 0644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
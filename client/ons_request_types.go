@@ -53,6 +53,144 @@ type ONSSendRequest struct {
 	Gas      int64         `json:"gas"`
 }
 
+type ONSRenewRequest struct {
+	Owner    keys.Address  `json:"owner"`
+	Name     string        `json:"name"`
+	Price    action.Amount `json:"price"`
+	GasPrice action.Amount `json:"gasprice"`
+	Gas      int64         `json:"gas"`
+}
+
+type ONSDelegateRequest struct {
+	// owner of the parent domain
+	Owner    keys.Address  `json:"owner"`
+	Name     string        `json:"name"`
+	Delegate keys.Address  `json:"delegate"`
+	Revoke   bool          `json:"revoke"`
+	GasPrice action.Amount `json:"gasprice"`
+	Gas      int64         `json:"gas"`
+}
+
+type ONSSetRecordsRequest struct {
+	Owner    keys.Address  `json:"owner"`
+	Name     string        `json:"name"`
+	Records  ons.Records   `json:"records"`
+	GasPrice action.Amount `json:"gasprice"`
+	Gas      int64         `json:"gas"`
+}
+
+type ONSResolveRequest struct {
+	Name string `json:"name"`
+	// name of the chain to resolve on, OneLedger if empty
+	Chain string `json:"chain"`
+}
+
+type ONSResolveReply struct {
+	Name    string      `json:"name"`
+	Chain   string      `json:"chain"`
+	Address string      `json:"address"`
+	Records ons.Records `json:"records"`
+}
+
+type ONSCheckNameRequest struct {
+	Name string `json:"name"`
+}
+
+type ONSCheckNameReply struct {
+	Name string `json:"name"`
+	// the form the name has to be sent in txs, and its unicode form for display
+	Normalized string `json:"normalized"`
+	Display    string `json:"display"`
+	Valid      bool   `json:"valid"`
+	Reason     string `json:"reason,omitempty"`
+	Reserved   bool   `json:"reserved"`
+	Available  bool   `json:"available"`
+}
+
+type ONSSetPrimaryRequest struct {
+	Address keys.Address `json:"address"`
+	// empty to clear the primary name
+	Name     string        `json:"name"`
+	GasPrice action.Amount `json:"gasprice"`
+	Gas      int64         `json:"gas"`
+}
+
+type ONSReverseLookupRequest struct {
+	Address keys.Address `json:"address"`
+}
+
+type ONSReverseLookupReply struct {
+	Address keys.Address `json:"address"`
+	Name    string       `json:"name"`
+	Display string       `json:"display"`
+}
+
+type ONSBidRequest struct {
+	Bidder keys.Address `json:"bidder"`
+	Name   string       `json:"name"`
+	// the bid is sealed into a commitment by the node, only the deposit is sent out
+	Amount   action.Amount `json:"amount"`
+	Salt     []byte        `json:"salt"`
+	Deposit  action.Amount `json:"deposit"`
+	GasPrice action.Amount `json:"gasprice"`
+	Gas      int64         `json:"gas"`
+}
+
+type ONSRevealRequest struct {
+	Bidder   keys.Address  `json:"bidder"`
+	Name     string        `json:"name"`
+	Amount   action.Amount `json:"amount"`
+	Salt     []byte        `json:"salt"`
+	GasPrice action.Amount `json:"gasprice"`
+	Gas      int64         `json:"gas"`
+}
+
+type ONSGetAuctionRequest struct {
+	Name string `json:"name"`
+}
+
+type ONSGetAuctionReply struct {
+	Auction ons.Auction `json:"auction"`
+	Bids    []ons.Bid   `json:"bids"`
+	Height  int64       `json:"height"`
+}
+
+type ONSOfferRequest struct {
+	Buyer    keys.Address  `json:"buyer"`
+	Account  keys.Address  `json:"account"`
+	Name     string        `json:"name"`
+	Amount   action.Amount `json:"amount"`
+	Blocks   int64         `json:"blocks"`
+	GasPrice action.Amount `json:"gasprice"`
+	Gas      int64         `json:"gas"`
+}
+
+type ONSAcceptOfferRequest struct {
+	Owner    keys.Address  `json:"owner"`
+	Name     string        `json:"name"`
+	Buyer    keys.Address  `json:"buyer"`
+	GasPrice action.Amount `json:"gasprice"`
+	Gas      int64         `json:"gas"`
+}
+
+type ONSCancelOfferRequest struct {
+	Buyer    keys.Address  `json:"buyer"`
+	Name     string        `json:"name"`
+	GasPrice action.Amount `json:"gasprice"`
+	Gas      int64         `json:"gas"`
+}
+
+type ONSGetOffersRequest struct {
+	// offers for a domain, or offers made by a buyer
+	Name  string       `json:"name"`
+	Buyer keys.Address `json:"buyer"`
+}
+
+type ONSGetOffersReply struct {
+	Offers []ons.Offer `json:"offers"`
+	Height int64       `json:"height"`
+}
+
 type ONSGetDomainsRequest struct {
 	Name        string       `json:"name"`
 	Owner       keys.Address `json:"owner"`
@@ -68,6 +206,17 @@ type ONSGetDomainsOnSaleReply struct {
 	Domains []ons.Domain `json:"domains"`
 }
 
+type ONSGetExpiringDomainsRequest struct {
+	// number of blocks ahead of the current height to look for expiring domains
+	Blocks int64 `json:"blocks"`
+}
+
+type ONSGetExpiringDomainsReply struct {
+	// Domains expiring within the blocks asked for, and expired ones still in their grace period
+	Domains []ons.Domain `json:"domains"`
+	Height  int64        `json:"height"`
+}
+
 //type DomainData struct {
 //	Name             string       `json:"name"`
 //	OwnerAddress     keys.Address `json:"owner_address"`
@@ -10,13 +10,18 @@
 package client
 
 import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
 
 	"github.com/Oneledger/protocol/action"
 	"github.com/Oneledger/protocol/data/accounts"
 	"github.com/Oneledger/protocol/data/balance"
+	"github.com/Oneledger/protocol/data/history"
 	"github.com/Oneledger/protocol/data/keys"
 	"github.com/Oneledger/protocol/identity"
+	"github.com/Oneledger/protocol/storage"
 )
 
 /*
@@ -72,6 +77,22 @@ type ApplyValidatorReply struct {
 	RawTx []byte `json:"rawTx"`
 }
 
+type VoteGasScheduleRequest struct {
+	Schedule storage.GasSchedule `json:"schedule"`
+}
+
+type VoteGasScheduleReply struct {
+	RawTx []byte `json:"rawTx"`
+}
+
+type VoteReservedNamesRequest struct {
+	Names []string `json:"names"`
+}
+
+type VoteReservedNamesReply struct {
+	RawTx []byte `json:"rawTx"`
+}
+
 type WithdrawRewardRequest struct {
 	From     keys.Address  `json:"from"`
 	To       keys.Address  `json:"to"`
@@ -130,6 +151,32 @@ type ListCurrenciesReply struct {
 	Currencies balance.Currencies `json:"currencies"`
 }
 
+type StateVersionsRequest struct{}
+type StateVersionsReply struct {
+	// Latest committed version, the same as the block height
+	Latest int64 `json:"latest"`
+	// Versions that can still be queried, as inclusive ranges
+	Available []VersionRange `json:"available"`
+}
+
+type VersionRange struct {
+	From int64 `json:"from"`
+	To   int64 `json:"to"`
+}
+
+// SimulateTxRequest carries a tx as it would be broadcast. The signatures are not verified,
+// placeholders of the same size give the same gas.
+type SimulateTxRequest struct {
+	RawTx      []byte             `json:"rawTx"`
+	Signatures []action.Signature `json:"signatures"`
+}
+type SimulateTxReply struct {
+	OK bool `json:"ok"`
+	// Gas the tx uses, Fee.Gas has to be at least this
+	GasUsed int64  `json:"gasUsed"`
+	Log     string `json:"log"`
+}
+
 type BroadcastRequest struct {
 	RawTx     []byte         `json:"rawTx"`
 	Signature []byte         `json:"signature"`
@@ -207,6 +254,18 @@ type BTCGetTrackerReply struct {
 	TrackerData string `json:"tracker"`
 }
 
+// TrackerHistoryRequest asks for the state transitions of a btc or eth tracker
+type TrackerHistoryRequest struct {
+	Name string `json:"name"`
+}
+
+// TrackerHistoryReply holds the transitions of the tracker from the oldest, as of the last
+// committed block
+type TrackerHistoryReply struct {
+	Name    string          `json:"name"`
+	Entries []history.Entry `json:"entries"`
+}
+
 type BTCRedeemRequest struct {
 	Address    keys.Address  `json:"address"`
 	BTCAddress string        `json:"btc_address"`
@@ -227,6 +286,36 @@ type ETHLockRequest struct {
 	Gas     int64         `json:"gas"`
 }
 
+// ETHExtLockRequest carries a lock call from the user to the smart contract, signed and RLP
+// encoded with the ethereum address of the user
+type ETHExtLockRequest struct {
+	RawTx   []byte `json:"rawTx"`
+	Address keys.Address
+	Fee     action.Amount `json:"fee"`
+	Gas     int64         `json:"gas"`
+}
+
+type ETHExtLockReply struct {
+	RawTX []byte `json:"UnsignedOLTLock"`
+}
+
+type ETHRedeemRequest struct {
+	UserOLTaddress action.Address `json:"user_olt_address"`
+	UserETHaddress action.Address `json:"user_eth_address"`
+	ETHTxn         []byte         `json:"eth_txn"`
+	Fee            action.Amount  `json:"fee"`
+	Gas            int64          `json:"gas"`
+}
+
+type ETHRawLockRequest struct {
+	UserAddress common.Address `json:"user_eth_address"`
+	Amount      *big.Int       `json:"amount"`
+}
+
+type ETHRawLockReply struct {
+	UnsignedRawTx []byte `json:"unsigned_raw_tx"`
+}
+
 type CurrencyBalanceRequest struct {
 	Currency string       `json:"currency"`
 	Address  keys.Address `json:"address"`
@@ -5,15 +5,22 @@ import (
 	"github.com/Oneledger/protocol/rpc"
 )
 
-// A type-safe client for accessing rpc services.
-// Eventually each service will be broken out onto its own type
-// TODO: The methods defined here should handle context.Context
+//go:generate go run ./gen
+
+// A type-safe client for accessing rpc services. The methods defined here block without a
+// deadline, the clients of single services returned by Query, Tx, BTC and the like take a
+// context and can be batched. Those are generated in service_client_gen.go.
 type ServiceClient struct {
 	*rpc.Client
 }
 
 func NewServiceClient(conn string) (*ServiceClient, error) {
-	client, err := rpc.NewClient(conn)
+	return NewServiceClientWithOptions(conn, rpc.ClientOptions{})
+}
+
+// NewServiceClientWithOptions returns a client sending auth and using TLS as the options say
+func NewServiceClientWithOptions(conn string, opts rpc.ClientOptions) (*ServiceClient, error) {
+	client, err := rpc.NewClientWithOptions(conn, opts)
 	return &ServiceClient{client}, err
 }
 
@@ -80,6 +87,16 @@ func (c *ServiceClient) ApplyValidator(req ApplyValidatorRequest) (out ApplyVali
 	return
 }
 
+func (c *ServiceClient) VoteGasSchedule(req VoteGasScheduleRequest) (out VoteGasScheduleReply, err error) {
+	err = c.Call("tx.VoteGasSchedule", req, &out)
+	return
+}
+
+func (c *ServiceClient) VoteReservedNames(req VoteReservedNamesRequest) (out VoteReservedNamesReply, err error) {
+	err = c.Call("tx.VoteReservedNames", req, &out)
+	return
+}
+
 func (c *ServiceClient) WithdrawReward(req WithdrawRewardRequest) (out WithdrawRewardReply, err error) {
 	err = c.Call("tx.WithdrawReward", req, &out)
 	return
@@ -108,6 +125,86 @@ func (c *ServiceClient) ONS_CreateRawSend(req ONSSendRequest) (out SendTxReply,
 	return
 }
 
+func (c *ServiceClient) ONS_CreateRawRenew(req ONSRenewRequest) (out SendTxReply, err error) {
+	err = c.Call("tx.ONS_CreateRawRenew", req, &out)
+	return
+}
+
+func (c *ServiceClient) ONS_CreateRawDelegate(req ONSDelegateRequest) (out SendTxReply, err error) {
+	err = c.Call("tx.ONS_CreateRawDelegate", req, &out)
+	return
+}
+
+func (c *ServiceClient) ONS_GetSubdomains(req ONSGetDomainsRequest) (out ONSGetDomainsReply, err error) {
+	err = c.Call("query.ONS_GetSubdomains", req, &out)
+	return
+}
+
+func (c *ServiceClient) ONS_CreateRawSetRecords(req ONSSetRecordsRequest) (out SendTxReply, err error) {
+	err = c.Call("tx.ONS_CreateRawSetRecords", req, &out)
+	return
+}
+
+func (c *ServiceClient) ONS_Resolve(req ONSResolveRequest) (out ONSResolveReply, err error) {
+	err = c.Call("query.ONS_Resolve", req, &out)
+	return
+}
+
+func (c *ServiceClient) ONS_CheckName(req ONSCheckNameRequest) (out ONSCheckNameReply, err error) {
+	err = c.Call("query.ONS_CheckName", req, &out)
+	return
+}
+
+func (c *ServiceClient) ONS_CreateRawSetPrimary(req ONSSetPrimaryRequest) (out SendTxReply, err error) {
+	err = c.Call("tx.ONS_CreateRawSetPrimary", req, &out)
+	return
+}
+
+func (c *ServiceClient) ONS_ReverseLookup(req ONSReverseLookupRequest) (out ONSReverseLookupReply, err error) {
+	err = c.Call("query.ONS_ReverseLookup", req, &out)
+	return
+}
+
+func (c *ServiceClient) ONS_CreateRawBid(req ONSBidRequest) (out SendTxReply, err error) {
+	err = c.Call("tx.ONS_CreateRawBid", req, &out)
+	return
+}
+
+func (c *ServiceClient) ONS_CreateRawReveal(req ONSRevealRequest) (out SendTxReply, err error) {
+	err = c.Call("tx.ONS_CreateRawReveal", req, &out)
+	return
+}
+
+func (c *ServiceClient) ONS_GetAuction(req ONSGetAuctionRequest) (out ONSGetAuctionReply, err error) {
+	err = c.Call("query.ONS_GetAuction", req, &out)
+	return
+}
+
+func (c *ServiceClient) ONS_CreateRawOffer(req ONSOfferRequest) (out SendTxReply, err error) {
+	err = c.Call("tx.ONS_CreateRawOffer", req, &out)
+	return
+}
+
+func (c *ServiceClient) ONS_CreateRawAcceptOffer(req ONSAcceptOfferRequest) (out SendTxReply, err error) {
+	err = c.Call("tx.ONS_CreateRawAcceptOffer", req, &out)
+	return
+}
+
+func (c *ServiceClient) ONS_CreateRawCancelOffer(req ONSCancelOfferRequest) (out SendTxReply, err error) {
+	err = c.Call("tx.ONS_CreateRawCancelOffer", req, &out)
+	return
+}
+
+func (c *ServiceClient) ONS_GetOffers(req ONSGetOffersRequest) (out ONSGetOffersReply, err error) {
+	err = c.Call("query.ONS_GetOffers", req, &out)
+	return
+}
+
+func (c *ServiceClient) ONS_GetExpiringDomains(req ONSGetExpiringDomainsRequest) (out ONSGetExpiringDomainsReply, err error) {
+	err = c.Call("query.ONS_GetExpiringDomains", req, &out)
+	return
+}
+
 func (c *ServiceClient) CreateRawSend(req SendTxRequest) (out *SendTxReply, err error) {
 	err = c.Call("tx.CreateRawSend", req, &out)
 	return
@@ -123,6 +220,16 @@ func (c *ServiceClient) ListCurrencies() (out *ListCurrenciesReply, err error) {
 	return
 }
 
+func (c *ServiceClient) StateVersions() (out *StateVersionsReply, err error) {
+	err = c.Call("query.StateVersions", struct{}{}, &out)
+	return
+}
+
+func (c *ServiceClient) SimulateTx(req SimulateTxRequest) (out *SimulateTxReply, err error) {
+	err = c.Call("query.SimulateTx", req, &out)
+	return
+}
+
 /* Broadcast */
 func (c *ServiceClient) TxAsync(req BroadcastRequest) (out BroadcastReply, err error) {
 	err = c.Call("broadcast.TxAsync", req, &out)
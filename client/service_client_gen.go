@@ -0,0 +1,894 @@
+// Code generated by go run ./gen; DO NOT EDIT.
+
+package client
+
+import (
+	"context"
+
+	"github.com/Oneledger/protocol/data/accounts"
+	"github.com/Oneledger/protocol/data/keys"
+	"github.com/Oneledger/protocol/rpc"
+)
+
+// AdminClient calls the methods of the admin service
+type AdminClient struct {
+	c *ServiceClient
+}
+
+// Admin returns the client of the admin service
+func (c *ServiceClient) Admin() AdminClient {
+	return AdminClient{c}
+}
+
+// BanPeer calls admin.BanPeer
+func (c AdminClient) BanPeer(ctx context.Context, req AdminPeerRequest) (out AdminListPeersReply, err error) {
+	err = c.c.CallContext(ctx, "admin.BanPeer", req, &out)
+	return
+}
+
+// BanPeerCall is admin.BanPeer in a batch, out gets the reply
+func (AdminClient) BanPeerCall(req AdminPeerRequest, out *AdminListPeersReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "admin.BanPeer", Args: req, Reply: out, Idempotent: false}
+}
+
+// CancelJob calls admin.CancelJob
+func (c AdminClient) CancelJob(ctx context.Context, req AdminJobRequest) (out AdminJobReply, err error) {
+	err = c.c.CallContext(ctx, "admin.CancelJob", req, &out)
+	return
+}
+
+// CancelJobCall is admin.CancelJob in a batch, out gets the reply
+func (AdminClient) CancelJobCall(req AdminJobRequest, out *AdminJobReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "admin.CancelJob", Args: req, Reply: out, Idempotent: false}
+}
+
+// Config calls admin.Config
+func (c AdminClient) Config(ctx context.Context, req AdminConfigRequest) (out AdminConfigReply, err error) {
+	err = c.c.CallContext(ctx, "admin.Config", req, &out)
+	return
+}
+
+// ConfigCall is admin.Config in a batch, out gets the reply
+func (AdminClient) ConfigCall(req AdminConfigRequest, out *AdminConfigReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "admin.Config", Args: req, Reply: out, Idempotent: false}
+}
+
+// DialPeers calls admin.DialPeers
+func (c AdminClient) DialPeers(ctx context.Context, req AdminDialPeersRequest) (out AdminListPeersReply, err error) {
+	err = c.c.CallContext(ctx, "admin.DialPeers", req, &out)
+	return
+}
+
+// DialPeersCall is admin.DialPeers in a batch, out gets the reply
+func (AdminClient) DialPeersCall(req AdminDialPeersRequest, out *AdminListPeersReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "admin.DialPeers", Args: req, Reply: out, Idempotent: false}
+}
+
+// DisableService calls admin.DisableService
+func (c AdminClient) DisableService(ctx context.Context, req AdminServiceRequest) (out AdminServicesReply, err error) {
+	err = c.c.CallContext(ctx, "admin.DisableService", req, &out)
+	return
+}
+
+// DisableServiceCall is admin.DisableService in a batch, out gets the reply
+func (AdminClient) DisableServiceCall(req AdminServiceRequest, out *AdminServicesReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "admin.DisableService", Args: req, Reply: out, Idempotent: false}
+}
+
+// EnableService calls admin.EnableService
+func (c AdminClient) EnableService(ctx context.Context, req AdminServiceRequest) (out AdminServicesReply, err error) {
+	err = c.c.CallContext(ctx, "admin.EnableService", req, &out)
+	return
+}
+
+// EnableServiceCall is admin.EnableService in a batch, out gets the reply
+func (AdminClient) EnableServiceCall(req AdminServiceRequest, out *AdminServicesReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "admin.EnableService", Args: req, Reply: out, Idempotent: false}
+}
+
+// ListJobs calls admin.ListJobs, it's retried when the node can't be reached or is busy
+func (c AdminClient) ListJobs(ctx context.Context, req AdminListJobsRequest) (out AdminListJobsReply, err error) {
+	err = c.c.CallIdempotent(ctx, "admin.ListJobs", req, &out)
+	return
+}
+
+// ListJobsCall is admin.ListJobs in a batch, out gets the reply
+func (AdminClient) ListJobsCall(req AdminListJobsRequest, out *AdminListJobsReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "admin.ListJobs", Args: req, Reply: out, Idempotent: true}
+}
+
+// ListPeers calls admin.ListPeers, it's retried when the node can't be reached or is busy
+func (c AdminClient) ListPeers(ctx context.Context, req AdminListPeersRequest) (out AdminListPeersReply, err error) {
+	err = c.c.CallIdempotent(ctx, "admin.ListPeers", req, &out)
+	return
+}
+
+// ListPeersCall is admin.ListPeers in a batch, out gets the reply
+func (AdminClient) ListPeersCall(req AdminListPeersRequest, out *AdminListPeersReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "admin.ListPeers", Args: req, Reply: out, Idempotent: true}
+}
+
+// ListServices calls admin.ListServices, it's retried when the node can't be reached or is busy
+func (c AdminClient) ListServices(ctx context.Context, req AdminListServicesRequest) (out AdminServicesReply, err error) {
+	err = c.c.CallIdempotent(ctx, "admin.ListServices", req, &out)
+	return
+}
+
+// ListServicesCall is admin.ListServices in a batch, out gets the reply
+func (AdminClient) ListServicesCall(req AdminListServicesRequest, out *AdminServicesReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "admin.ListServices", Args: req, Reply: out, Idempotent: true}
+}
+
+// RetryJob calls admin.RetryJob
+func (c AdminClient) RetryJob(ctx context.Context, req AdminJobRequest) (out AdminJobReply, err error) {
+	err = c.c.CallContext(ctx, "admin.RetryJob", req, &out)
+	return
+}
+
+// RetryJobCall is admin.RetryJob in a batch, out gets the reply
+func (AdminClient) RetryJobCall(req AdminJobRequest, out *AdminJobReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "admin.RetryJob", Args: req, Reply: out, Idempotent: false}
+}
+
+// RunJobs calls admin.RunJobs
+func (c AdminClient) RunJobs(ctx context.Context, req AdminRunJobsRequest) (out AdminRunJobsReply, err error) {
+	err = c.c.CallContext(ctx, "admin.RunJobs", req, &out)
+	return
+}
+
+// RunJobsCall is admin.RunJobs in a batch, out gets the reply
+func (AdminClient) RunJobsCall(req AdminRunJobsRequest, out *AdminRunJobsReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "admin.RunJobs", Args: req, Reply: out, Idempotent: false}
+}
+
+// SetLogLevel calls admin.SetLogLevel
+func (c AdminClient) SetLogLevel(ctx context.Context, req AdminLogLevelRequest) (out AdminLogLevelReply, err error) {
+	err = c.c.CallContext(ctx, "admin.SetLogLevel", req, &out)
+	return
+}
+
+// SetLogLevelCall is admin.SetLogLevel in a batch, out gets the reply
+func (AdminClient) SetLogLevelCall(req AdminLogLevelRequest, out *AdminLogLevelReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "admin.SetLogLevel", Args: req, Reply: out, Idempotent: false}
+}
+
+// SetOption calls admin.SetOption
+func (c AdminClient) SetOption(ctx context.Context, req AdminSetOptionRequest) (out AdminSetOptionReply, err error) {
+	err = c.c.CallContext(ctx, "admin.SetOption", req, &out)
+	return
+}
+
+// SetOptionCall is admin.SetOption in a batch, out gets the reply
+func (AdminClient) SetOptionCall(req AdminSetOptionRequest, out *AdminSetOptionReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "admin.SetOption", Args: req, Reply: out, Idempotent: false}
+}
+
+// UnbanPeer calls admin.UnbanPeer
+func (c AdminClient) UnbanPeer(ctx context.Context, req AdminPeerRequest) (out AdminListPeersReply, err error) {
+	err = c.c.CallContext(ctx, "admin.UnbanPeer", req, &out)
+	return
+}
+
+// UnbanPeerCall is admin.UnbanPeer in a batch, out gets the reply
+func (AdminClient) UnbanPeerCall(req AdminPeerRequest, out *AdminListPeersReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "admin.UnbanPeer", Args: req, Reply: out, Idempotent: false}
+}
+
+// BroadcastClient calls the methods of the broadcast service
+type BroadcastClient struct {
+	c *ServiceClient
+}
+
+// Broadcast returns the client of the broadcast service
+func (c *ServiceClient) Broadcast() BroadcastClient {
+	return BroadcastClient{c}
+}
+
+// TxAsync calls broadcast.TxAsync
+func (c BroadcastClient) TxAsync(ctx context.Context, req BroadcastRequest) (out BroadcastReply, err error) {
+	err = c.c.CallContext(ctx, "broadcast.TxAsync", req, &out)
+	return
+}
+
+// TxAsyncCall is broadcast.TxAsync in a batch, out gets the reply
+func (BroadcastClient) TxAsyncCall(req BroadcastRequest, out *BroadcastReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "broadcast.TxAsync", Args: req, Reply: out, Idempotent: false}
+}
+
+// TxCommit calls broadcast.TxCommit
+func (c BroadcastClient) TxCommit(ctx context.Context, req BroadcastRequest) (out BroadcastReply, err error) {
+	err = c.c.CallContext(ctx, "broadcast.TxCommit", req, &out)
+	return
+}
+
+// TxCommitCall is broadcast.TxCommit in a batch, out gets the reply
+func (BroadcastClient) TxCommitCall(req BroadcastRequest, out *BroadcastReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "broadcast.TxCommit", Args: req, Reply: out, Idempotent: false}
+}
+
+// TxSync calls broadcast.TxSync
+func (c BroadcastClient) TxSync(ctx context.Context, req BroadcastRequest) (out BroadcastReply, err error) {
+	err = c.c.CallContext(ctx, "broadcast.TxSync", req, &out)
+	return
+}
+
+// TxSyncCall is broadcast.TxSync in a batch, out gets the reply
+func (BroadcastClient) TxSyncCall(req BroadcastRequest, out *BroadcastReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "broadcast.TxSync", Args: req, Reply: out, Idempotent: false}
+}
+
+// BTCClient calls the methods of the btc service
+type BTCClient struct {
+	c *ServiceClient
+}
+
+// BTC returns the client of the btc service
+func (c *ServiceClient) BTC() BTCClient {
+	return BTCClient{c}
+}
+
+// AddUserSignatureAndProcessLock calls btc.AddUserSignatureAndProcessLock
+func (c BTCClient) AddUserSignatureAndProcessLock(ctx context.Context, req BTCLockRequest) (out SendTxReply, err error) {
+	err = c.c.CallContext(ctx, "btc.AddUserSignatureAndProcessLock", req, &out)
+	return
+}
+
+// AddUserSignatureAndProcessLockCall is btc.AddUserSignatureAndProcessLock in a batch, out gets the reply
+func (BTCClient) AddUserSignatureAndProcessLockCall(req BTCLockRequest, out *SendTxReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "btc.AddUserSignatureAndProcessLock", Args: req, Reply: out, Idempotent: false}
+}
+
+// GetTracker calls btc.GetTracker, it's retried when the node can't be reached or is busy
+func (c BTCClient) GetTracker(ctx context.Context, req BTCGetTrackerRequest) (out BTCGetTrackerReply, err error) {
+	err = c.c.CallIdempotent(ctx, "btc.GetTracker", req, &out)
+	return
+}
+
+// GetTrackerCall is btc.GetTracker in a batch, out gets the reply
+func (BTCClient) GetTrackerCall(req BTCGetTrackerRequest, out *BTCGetTrackerReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "btc.GetTracker", Args: req, Reply: out, Idempotent: true}
+}
+
+// GetTrackerHistory calls btc.GetTrackerHistory, it's retried when the node can't be reached or is busy
+func (c BTCClient) GetTrackerHistory(ctx context.Context, req TrackerHistoryRequest) (out TrackerHistoryReply, err error) {
+	err = c.c.CallIdempotent(ctx, "btc.GetTrackerHistory", req, &out)
+	return
+}
+
+// GetTrackerHistoryCall is btc.GetTrackerHistory in a batch, out gets the reply
+func (BTCClient) GetTrackerHistoryCall(req TrackerHistoryRequest, out *TrackerHistoryReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "btc.GetTrackerHistory", Args: req, Reply: out, Idempotent: true}
+}
+
+// PrepareLock calls btc.PrepareLock, it's retried when the node can't be reached or is busy
+func (c BTCClient) PrepareLock(ctx context.Context, req BTCLockPrepareRequest) (out BTCLockPrepareResponse, err error) {
+	err = c.c.CallIdempotent(ctx, "btc.PrepareLock", req, &out)
+	return
+}
+
+// PrepareLockCall is btc.PrepareLock in a batch, out gets the reply
+func (BTCClient) PrepareLockCall(req BTCLockPrepareRequest, out *BTCLockPrepareResponse) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "btc.PrepareLock", Args: req, Reply: out, Idempotent: true}
+}
+
+// PrepareRedeem calls btc.PrepareRedeem, it's retried when the node can't be reached or is busy
+func (c BTCClient) PrepareRedeem(ctx context.Context, req BTCRedeemRequest) (out BTCRedeemPrepareResponse, err error) {
+	err = c.c.CallIdempotent(ctx, "btc.PrepareRedeem", req, &out)
+	return
+}
+
+// PrepareRedeemCall is btc.PrepareRedeem in a batch, out gets the reply
+func (BTCClient) PrepareRedeemCall(req BTCRedeemRequest, out *BTCRedeemPrepareResponse) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "btc.PrepareRedeem", Args: req, Reply: out, Idempotent: true}
+}
+
+// ETHClient calls the methods of the eth service
+type ETHClient struct {
+	c *ServiceClient
+}
+
+// ETH returns the client of the eth service
+func (c *ServiceClient) ETH() ETHClient {
+	return ETHClient{c}
+}
+
+// CreateRawExtLock calls eth.CreateRawExtLock, it's retried when the node can't be reached or is busy
+func (c ETHClient) CreateRawExtLock(ctx context.Context, req ETHExtLockRequest) (out ETHExtLockReply, err error) {
+	err = c.c.CallIdempotent(ctx, "eth.CreateRawExtLock", req, &out)
+	return
+}
+
+// CreateRawExtLockCall is eth.CreateRawExtLock in a batch, out gets the reply
+func (ETHClient) CreateRawExtLockCall(req ETHExtLockRequest, out *ETHExtLockReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "eth.CreateRawExtLock", Args: req, Reply: out, Idempotent: true}
+}
+
+// CreateRawExtRedeem calls eth.CreateRawExtRedeem, it's retried when the node can't be reached or is busy
+func (c ETHClient) CreateRawExtRedeem(ctx context.Context, req ETHRedeemRequest) (out ETHExtLockReply, err error) {
+	err = c.c.CallIdempotent(ctx, "eth.CreateRawExtRedeem", req, &out)
+	return
+}
+
+// CreateRawExtRedeemCall is eth.CreateRawExtRedeem in a batch, out gets the reply
+func (ETHClient) CreateRawExtRedeemCall(req ETHRedeemRequest, out *ETHExtLockReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "eth.CreateRawExtRedeem", Args: req, Reply: out, Idempotent: true}
+}
+
+// GetRawLockTX calls eth.GetRawLockTX, it's retried when the node can't be reached or is busy
+func (c ETHClient) GetRawLockTX(ctx context.Context, req ETHRawLockRequest) (out ETHRawLockReply, err error) {
+	err = c.c.CallIdempotent(ctx, "eth.GetRawLockTX", req, &out)
+	return
+}
+
+// GetRawLockTXCall is eth.GetRawLockTX in a batch, out gets the reply
+func (ETHClient) GetRawLockTXCall(req ETHRawLockRequest, out *ETHRawLockReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "eth.GetRawLockTX", Args: req, Reply: out, Idempotent: true}
+}
+
+// GetTrackerHistory calls eth.GetTrackerHistory, it's retried when the node can't be reached or is busy
+func (c ETHClient) GetTrackerHistory(ctx context.Context, req TrackerHistoryRequest) (out TrackerHistoryReply, err error) {
+	err = c.c.CallIdempotent(ctx, "eth.GetTrackerHistory", req, &out)
+	return
+}
+
+// GetTrackerHistoryCall is eth.GetTrackerHistory in a batch, out gets the reply
+func (ETHClient) GetTrackerHistoryCall(req TrackerHistoryRequest, out *TrackerHistoryReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "eth.GetTrackerHistory", Args: req, Reply: out, Idempotent: true}
+}
+
+// NodeClient calls the methods of the node service
+type NodeClient struct {
+	c *ServiceClient
+}
+
+// Node returns the client of the node service
+func (c *ServiceClient) Node() NodeClient {
+	return NodeClient{c}
+}
+
+// Address calls node.Address, it's retried when the node can't be reached or is busy
+func (c NodeClient) Address(ctx context.Context, req NodeAddressRequest) (out keys.Address, err error) {
+	err = c.c.CallIdempotent(ctx, "node.Address", req, &out)
+	return
+}
+
+// AddressCall is node.Address in a batch, out gets the reply
+func (NodeClient) AddressCall(req NodeAddressRequest, out *keys.Address) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "node.Address", Args: req, Reply: out, Idempotent: true}
+}
+
+// ID calls node.ID, it's retried when the node can't be reached or is busy
+func (c NodeClient) ID(ctx context.Context, req NodeIDRequest) (out string, err error) {
+	err = c.c.CallIdempotent(ctx, "node.ID", req, &out)
+	return
+}
+
+// IDCall is node.ID in a batch, out gets the reply
+func (NodeClient) IDCall(req NodeIDRequest, out *string) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "node.ID", Args: req, Reply: out, Idempotent: true}
+}
+
+// NodeName calls node.NodeName, it's retried when the node can't be reached or is busy
+func (c NodeClient) NodeName(ctx context.Context, req NodeNameRequest) (out string, err error) {
+	err = c.c.CallIdempotent(ctx, "node.NodeName", req, &out)
+	return
+}
+
+// NodeNameCall is node.NodeName in a batch, out gets the reply
+func (NodeClient) NodeNameCall(req NodeNameRequest, out *string) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "node.NodeName", Args: req, Reply: out, Idempotent: true}
+}
+
+// OwnerClient calls the methods of the owner service
+type OwnerClient struct {
+	c *ServiceClient
+}
+
+// Owner returns the client of the owner service
+func (c *ServiceClient) Owner() OwnerClient {
+	return OwnerClient{c}
+}
+
+// AddAccount calls owner.AddAccount
+func (c OwnerClient) AddAccount(ctx context.Context, req accounts.Account) (out AddAccountReply, err error) {
+	err = c.c.CallContext(ctx, "owner.AddAccount", req, &out)
+	return
+}
+
+// AddAccountCall is owner.AddAccount in a batch, out gets the reply
+func (OwnerClient) AddAccountCall(req accounts.Account, out *AddAccountReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "owner.AddAccount", Args: req, Reply: out, Idempotent: false}
+}
+
+// DeleteAccount calls owner.DeleteAccount
+func (c OwnerClient) DeleteAccount(ctx context.Context, req DeleteAccountRequest) (out bool, err error) {
+	err = c.c.CallContext(ctx, "owner.DeleteAccount", req, &out)
+	return
+}
+
+// DeleteAccountCall is owner.DeleteAccount in a batch, out gets the reply
+func (OwnerClient) DeleteAccountCall(req DeleteAccountRequest, out *bool) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "owner.DeleteAccount", Args: req, Reply: out, Idempotent: false}
+}
+
+// GenerateNewAccount calls owner.GenerateNewAccount
+func (c OwnerClient) GenerateNewAccount(ctx context.Context, req GenerateAccountRequest) (out AddAccountReply, err error) {
+	err = c.c.CallContext(ctx, "owner.GenerateNewAccount", req, &out)
+	return
+}
+
+// GenerateNewAccountCall is owner.GenerateNewAccount in a batch, out gets the reply
+func (OwnerClient) GenerateNewAccountCall(req GenerateAccountRequest, out *AddAccountReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "owner.GenerateNewAccount", Args: req, Reply: out, Idempotent: false}
+}
+
+// ListAccountAddresses calls owner.ListAccountAddresses, it's retried when the node can't be reached or is busy
+func (c OwnerClient) ListAccountAddresses(ctx context.Context, req ListAccountsRequest) (out ListAccountAddressesReply, err error) {
+	err = c.c.CallIdempotent(ctx, "owner.ListAccountAddresses", req, &out)
+	return
+}
+
+// ListAccountAddressesCall is owner.ListAccountAddresses in a batch, out gets the reply
+func (OwnerClient) ListAccountAddressesCall(req ListAccountsRequest, out *ListAccountAddressesReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "owner.ListAccountAddresses", Args: req, Reply: out, Idempotent: true}
+}
+
+// ListAccounts calls owner.ListAccounts, it's retried when the node can't be reached or is busy
+func (c OwnerClient) ListAccounts(ctx context.Context, req ListAccountsRequest) (out ListAccountsReply, err error) {
+	err = c.c.CallIdempotent(ctx, "owner.ListAccounts", req, &out)
+	return
+}
+
+// ListAccountsCall is owner.ListAccounts in a batch, out gets the reply
+func (OwnerClient) ListAccountsCall(req ListAccountsRequest, out *ListAccountsReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "owner.ListAccounts", Args: req, Reply: out, Idempotent: true}
+}
+
+// NewAccount calls owner.NewAccount
+func (c OwnerClient) NewAccount(ctx context.Context, req NewAccountRequest) (out NewAccountReply, err error) {
+	err = c.c.CallContext(ctx, "owner.NewAccount", req, &out)
+	return
+}
+
+// NewAccountCall is owner.NewAccount in a batch, out gets the reply
+func (OwnerClient) NewAccountCall(req NewAccountRequest, out *NewAccountReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "owner.NewAccount", Args: req, Reply: out, Idempotent: false}
+}
+
+// SignWithAddress calls owner.SignWithAddress
+func (c OwnerClient) SignWithAddress(ctx context.Context, req SignRawTxRequest) (out SignRawTxResponse, err error) {
+	err = c.c.CallContext(ctx, "owner.SignWithAddress", req, &out)
+	return
+}
+
+// SignWithAddressCall is owner.SignWithAddress in a batch, out gets the reply
+func (OwnerClient) SignWithAddressCall(req SignRawTxRequest, out *SignRawTxResponse) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "owner.SignWithAddress", Args: req, Reply: out, Idempotent: false}
+}
+
+// QueryClient calls the methods of the query service
+type QueryClient struct {
+	c *ServiceClient
+}
+
+// Query returns the client of the query service
+func (c *ServiceClient) Query() QueryClient {
+	return QueryClient{c}
+}
+
+// Balance calls query.Balance, it's retried when the node can't be reached or is busy
+func (c QueryClient) Balance(ctx context.Context, req BalanceRequest) (out BalanceReply, err error) {
+	err = c.c.CallIdempotent(ctx, "query.Balance", req, &out)
+	return
+}
+
+// BalanceCall is query.Balance in a batch, out gets the reply
+func (QueryClient) BalanceCall(req BalanceRequest, out *BalanceReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "query.Balance", Args: req, Reply: out, Idempotent: true}
+}
+
+// CurrencyBalance calls query.CurrencyBalance, it's retried when the node can't be reached or is busy
+func (c QueryClient) CurrencyBalance(ctx context.Context, req CurrencyBalanceRequest) (out CurrencyBalanceReply, err error) {
+	err = c.c.CallIdempotent(ctx, "query.CurrencyBalance", req, &out)
+	return
+}
+
+// CurrencyBalanceCall is query.CurrencyBalance in a batch, out gets the reply
+func (QueryClient) CurrencyBalanceCall(req CurrencyBalanceRequest, out *CurrencyBalanceReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "query.CurrencyBalance", Args: req, Reply: out, Idempotent: true}
+}
+
+// ListCurrencies calls query.ListCurrencies, it's retried when the node can't be reached or is busy
+func (c QueryClient) ListCurrencies(ctx context.Context, req ListCurrenciesRequest) (out ListCurrenciesReply, err error) {
+	err = c.c.CallIdempotent(ctx, "query.ListCurrencies", req, &out)
+	return
+}
+
+// ListCurrenciesCall is query.ListCurrencies in a batch, out gets the reply
+func (QueryClient) ListCurrenciesCall(req ListCurrenciesRequest, out *ListCurrenciesReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "query.ListCurrencies", Args: req, Reply: out, Idempotent: true}
+}
+
+// ListValidators calls query.ListValidators, it's retried when the node can't be reached or is busy
+func (c QueryClient) ListValidators(ctx context.Context, req ListValidatorsRequest) (out ListValidatorsReply, err error) {
+	err = c.c.CallIdempotent(ctx, "query.ListValidators", req, &out)
+	return
+}
+
+// ListValidatorsCall is query.ListValidators in a batch, out gets the reply
+func (QueryClient) ListValidatorsCall(req ListValidatorsRequest, out *ListValidatorsReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "query.ListValidators", Args: req, Reply: out, Idempotent: true}
+}
+
+// ONS_CheckName calls query.ONS_CheckName, it's retried when the node can't be reached or is busy
+func (c QueryClient) ONS_CheckName(ctx context.Context, req ONSCheckNameRequest) (out ONSCheckNameReply, err error) {
+	err = c.c.CallIdempotent(ctx, "query.ONS_CheckName", req, &out)
+	return
+}
+
+// ONS_CheckNameCall is query.ONS_CheckName in a batch, out gets the reply
+func (QueryClient) ONS_CheckNameCall(req ONSCheckNameRequest, out *ONSCheckNameReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "query.ONS_CheckName", Args: req, Reply: out, Idempotent: true}
+}
+
+// ONS_GetAuction calls query.ONS_GetAuction, it's retried when the node can't be reached or is busy
+func (c QueryClient) ONS_GetAuction(ctx context.Context, req ONSGetAuctionRequest) (out ONSGetAuctionReply, err error) {
+	err = c.c.CallIdempotent(ctx, "query.ONS_GetAuction", req, &out)
+	return
+}
+
+// ONS_GetAuctionCall is query.ONS_GetAuction in a batch, out gets the reply
+func (QueryClient) ONS_GetAuctionCall(req ONSGetAuctionRequest, out *ONSGetAuctionReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "query.ONS_GetAuction", Args: req, Reply: out, Idempotent: true}
+}
+
+// ONS_GetDomainByBeneficiary calls query.ONS_GetDomainByBeneficiary, it's retried when the node can't be reached or is busy
+func (c QueryClient) ONS_GetDomainByBeneficiary(ctx context.Context, req ONSGetDomainsRequest) (out ONSGetDomainsOnSaleReply, err error) {
+	err = c.c.CallIdempotent(ctx, "query.ONS_GetDomainByBeneficiary", req, &out)
+	return
+}
+
+// ONS_GetDomainByBeneficiaryCall is query.ONS_GetDomainByBeneficiary in a batch, out gets the reply
+func (QueryClient) ONS_GetDomainByBeneficiaryCall(req ONSGetDomainsRequest, out *ONSGetDomainsOnSaleReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "query.ONS_GetDomainByBeneficiary", Args: req, Reply: out, Idempotent: true}
+}
+
+// ONS_GetDomainByName calls query.ONS_GetDomainByName, it's retried when the node can't be reached or is busy
+func (c QueryClient) ONS_GetDomainByName(ctx context.Context, req ONSGetDomainsRequest) (out ONSGetDomainsReply, err error) {
+	err = c.c.CallIdempotent(ctx, "query.ONS_GetDomainByName", req, &out)
+	return
+}
+
+// ONS_GetDomainByNameCall is query.ONS_GetDomainByName in a batch, out gets the reply
+func (QueryClient) ONS_GetDomainByNameCall(req ONSGetDomainsRequest, out *ONSGetDomainsReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "query.ONS_GetDomainByName", Args: req, Reply: out, Idempotent: true}
+}
+
+// ONS_GetDomainByOwner calls query.ONS_GetDomainByOwner, it's retried when the node can't be reached or is busy
+func (c QueryClient) ONS_GetDomainByOwner(ctx context.Context, req ONSGetDomainsRequest) (out ONSGetDomainsReply, err error) {
+	err = c.c.CallIdempotent(ctx, "query.ONS_GetDomainByOwner", req, &out)
+	return
+}
+
+// ONS_GetDomainByOwnerCall is query.ONS_GetDomainByOwner in a batch, out gets the reply
+func (QueryClient) ONS_GetDomainByOwnerCall(req ONSGetDomainsRequest, out *ONSGetDomainsReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "query.ONS_GetDomainByOwner", Args: req, Reply: out, Idempotent: true}
+}
+
+// ONS_GetDomainOnSale calls query.ONS_GetDomainOnSale, it's retried when the node can't be reached or is busy
+func (c QueryClient) ONS_GetDomainOnSale(ctx context.Context, req ONSGetDomainsRequest) (out ONSGetDomainsOnSaleReply, err error) {
+	err = c.c.CallIdempotent(ctx, "query.ONS_GetDomainOnSale", req, &out)
+	return
+}
+
+// ONS_GetDomainOnSaleCall is query.ONS_GetDomainOnSale in a batch, out gets the reply
+func (QueryClient) ONS_GetDomainOnSaleCall(req ONSGetDomainsRequest, out *ONSGetDomainsOnSaleReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "query.ONS_GetDomainOnSale", Args: req, Reply: out, Idempotent: true}
+}
+
+// ONS_GetExpiringDomains calls query.ONS_GetExpiringDomains, it's retried when the node can't be reached or is busy
+func (c QueryClient) ONS_GetExpiringDomains(ctx context.Context, req ONSGetExpiringDomainsRequest) (out ONSGetExpiringDomainsReply, err error) {
+	err = c.c.CallIdempotent(ctx, "query.ONS_GetExpiringDomains", req, &out)
+	return
+}
+
+// ONS_GetExpiringDomainsCall is query.ONS_GetExpiringDomains in a batch, out gets the reply
+func (QueryClient) ONS_GetExpiringDomainsCall(req ONSGetExpiringDomainsRequest, out *ONSGetExpiringDomainsReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "query.ONS_GetExpiringDomains", Args: req, Reply: out, Idempotent: true}
+}
+
+// ONS_GetOffers calls query.ONS_GetOffers, it's retried when the node can't be reached or is busy
+func (c QueryClient) ONS_GetOffers(ctx context.Context, req ONSGetOffersRequest) (out ONSGetOffersReply, err error) {
+	err = c.c.CallIdempotent(ctx, "query.ONS_GetOffers", req, &out)
+	return
+}
+
+// ONS_GetOffersCall is query.ONS_GetOffers in a batch, out gets the reply
+func (QueryClient) ONS_GetOffersCall(req ONSGetOffersRequest, out *ONSGetOffersReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "query.ONS_GetOffers", Args: req, Reply: out, Idempotent: true}
+}
+
+// ONS_GetSubdomains calls query.ONS_GetSubdomains, it's retried when the node can't be reached or is busy
+func (c QueryClient) ONS_GetSubdomains(ctx context.Context, req ONSGetDomainsRequest) (out ONSGetDomainsReply, err error) {
+	err = c.c.CallIdempotent(ctx, "query.ONS_GetSubdomains", req, &out)
+	return
+}
+
+// ONS_GetSubdomainsCall is query.ONS_GetSubdomains in a batch, out gets the reply
+func (QueryClient) ONS_GetSubdomainsCall(req ONSGetDomainsRequest, out *ONSGetDomainsReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "query.ONS_GetSubdomains", Args: req, Reply: out, Idempotent: true}
+}
+
+// ONS_Resolve calls query.ONS_Resolve, it's retried when the node can't be reached or is busy
+func (c QueryClient) ONS_Resolve(ctx context.Context, req ONSResolveRequest) (out ONSResolveReply, err error) {
+	err = c.c.CallIdempotent(ctx, "query.ONS_Resolve", req, &out)
+	return
+}
+
+// ONS_ResolveCall is query.ONS_Resolve in a batch, out gets the reply
+func (QueryClient) ONS_ResolveCall(req ONSResolveRequest, out *ONSResolveReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "query.ONS_Resolve", Args: req, Reply: out, Idempotent: true}
+}
+
+// ONS_ReverseLookup calls query.ONS_ReverseLookup, it's retried when the node can't be reached or is busy
+func (c QueryClient) ONS_ReverseLookup(ctx context.Context, req ONSReverseLookupRequest) (out ONSReverseLookupReply, err error) {
+	err = c.c.CallIdempotent(ctx, "query.ONS_ReverseLookup", req, &out)
+	return
+}
+
+// ONS_ReverseLookupCall is query.ONS_ReverseLookup in a batch, out gets the reply
+func (QueryClient) ONS_ReverseLookupCall(req ONSReverseLookupRequest, out *ONSReverseLookupReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "query.ONS_ReverseLookup", Args: req, Reply: out, Idempotent: true}
+}
+
+// SimulateTx calls query.SimulateTx, it's retried when the node can't be reached or is busy
+func (c QueryClient) SimulateTx(ctx context.Context, req SimulateTxRequest) (out SimulateTxReply, err error) {
+	err = c.c.CallIdempotent(ctx, "query.SimulateTx", req, &out)
+	return
+}
+
+// SimulateTxCall is query.SimulateTx in a batch, out gets the reply
+func (QueryClient) SimulateTxCall(req SimulateTxRequest, out *SimulateTxReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "query.SimulateTx", Args: req, Reply: out, Idempotent: true}
+}
+
+// StateVersions calls query.StateVersions, it's retried when the node can't be reached or is busy
+func (c QueryClient) StateVersions(ctx context.Context, req StateVersionsRequest) (out StateVersionsReply, err error) {
+	err = c.c.CallIdempotent(ctx, "query.StateVersions", req, &out)
+	return
+}
+
+// StateVersionsCall is query.StateVersions in a batch, out gets the reply
+func (QueryClient) StateVersionsCall(req StateVersionsRequest, out *StateVersionsReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "query.StateVersions", Args: req, Reply: out, Idempotent: true}
+}
+
+// TxClient calls the methods of the tx service
+type TxClient struct {
+	c *ServiceClient
+}
+
+// Tx returns the client of the tx service
+func (c *ServiceClient) Tx() TxClient {
+	return TxClient{c}
+}
+
+// ApplyValidator calls tx.ApplyValidator
+func (c TxClient) ApplyValidator(ctx context.Context, req ApplyValidatorRequest) (out ApplyValidatorReply, err error) {
+	err = c.c.CallContext(ctx, "tx.ApplyValidator", req, &out)
+	return
+}
+
+// ApplyValidatorCall is tx.ApplyValidator in a batch, out gets the reply
+func (TxClient) ApplyValidatorCall(req ApplyValidatorRequest, out *ApplyValidatorReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "tx.ApplyValidator", Args: req, Reply: out, Idempotent: false}
+}
+
+// CreateRawSend calls tx.CreateRawSend, it's retried when the node can't be reached or is busy
+func (c TxClient) CreateRawSend(ctx context.Context, req SendTxRequest) (out SendTxReply, err error) {
+	err = c.c.CallIdempotent(ctx, "tx.CreateRawSend", req, &out)
+	return
+}
+
+// CreateRawSendCall is tx.CreateRawSend in a batch, out gets the reply
+func (TxClient) CreateRawSendCall(req SendTxRequest, out *SendTxReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "tx.CreateRawSend", Args: req, Reply: out, Idempotent: true}
+}
+
+// ONS_CreateRawAcceptOffer calls tx.ONS_CreateRawAcceptOffer, it's retried when the node can't be reached or is busy
+func (c TxClient) ONS_CreateRawAcceptOffer(ctx context.Context, req ONSAcceptOfferRequest) (out SendTxReply, err error) {
+	err = c.c.CallIdempotent(ctx, "tx.ONS_CreateRawAcceptOffer", req, &out)
+	return
+}
+
+// ONS_CreateRawAcceptOfferCall is tx.ONS_CreateRawAcceptOffer in a batch, out gets the reply
+func (TxClient) ONS_CreateRawAcceptOfferCall(req ONSAcceptOfferRequest, out *SendTxReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "tx.ONS_CreateRawAcceptOffer", Args: req, Reply: out, Idempotent: true}
+}
+
+// ONS_CreateRawBid calls tx.ONS_CreateRawBid, it's retried when the node can't be reached or is busy
+func (c TxClient) ONS_CreateRawBid(ctx context.Context, req ONSBidRequest) (out SendTxReply, err error) {
+	err = c.c.CallIdempotent(ctx, "tx.ONS_CreateRawBid", req, &out)
+	return
+}
+
+// ONS_CreateRawBidCall is tx.ONS_CreateRawBid in a batch, out gets the reply
+func (TxClient) ONS_CreateRawBidCall(req ONSBidRequest, out *SendTxReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "tx.ONS_CreateRawBid", Args: req, Reply: out, Idempotent: true}
+}
+
+// ONS_CreateRawBuy calls tx.ONS_CreateRawBuy, it's retried when the node can't be reached or is busy
+func (c TxClient) ONS_CreateRawBuy(ctx context.Context, req ONSPurchaseRequest) (out SendTxReply, err error) {
+	err = c.c.CallIdempotent(ctx, "tx.ONS_CreateRawBuy", req, &out)
+	return
+}
+
+// ONS_CreateRawBuyCall is tx.ONS_CreateRawBuy in a batch, out gets the reply
+func (TxClient) ONS_CreateRawBuyCall(req ONSPurchaseRequest, out *SendTxReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "tx.ONS_CreateRawBuy", Args: req, Reply: out, Idempotent: true}
+}
+
+// ONS_CreateRawCancelOffer calls tx.ONS_CreateRawCancelOffer, it's retried when the node can't be reached or is busy
+func (c TxClient) ONS_CreateRawCancelOffer(ctx context.Context, req ONSCancelOfferRequest) (out SendTxReply, err error) {
+	err = c.c.CallIdempotent(ctx, "tx.ONS_CreateRawCancelOffer", req, &out)
+	return
+}
+
+// ONS_CreateRawCancelOfferCall is tx.ONS_CreateRawCancelOffer in a batch, out gets the reply
+func (TxClient) ONS_CreateRawCancelOfferCall(req ONSCancelOfferRequest, out *SendTxReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "tx.ONS_CreateRawCancelOffer", Args: req, Reply: out, Idempotent: true}
+}
+
+// ONS_CreateRawCreate calls tx.ONS_CreateRawCreate, it's retried when the node can't be reached or is busy
+func (c TxClient) ONS_CreateRawCreate(ctx context.Context, req ONSCreateRequest) (out SendTxReply, err error) {
+	err = c.c.CallIdempotent(ctx, "tx.ONS_CreateRawCreate", req, &out)
+	return
+}
+
+// ONS_CreateRawCreateCall is tx.ONS_CreateRawCreate in a batch, out gets the reply
+func (TxClient) ONS_CreateRawCreateCall(req ONSCreateRequest, out *SendTxReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "tx.ONS_CreateRawCreate", Args: req, Reply: out, Idempotent: true}
+}
+
+// ONS_CreateRawDelegate calls tx.ONS_CreateRawDelegate, it's retried when the node can't be reached or is busy
+func (c TxClient) ONS_CreateRawDelegate(ctx context.Context, req ONSDelegateRequest) (out SendTxReply, err error) {
+	err = c.c.CallIdempotent(ctx, "tx.ONS_CreateRawDelegate", req, &out)
+	return
+}
+
+// ONS_CreateRawDelegateCall is tx.ONS_CreateRawDelegate in a batch, out gets the reply
+func (TxClient) ONS_CreateRawDelegateCall(req ONSDelegateRequest, out *SendTxReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "tx.ONS_CreateRawDelegate", Args: req, Reply: out, Idempotent: true}
+}
+
+// ONS_CreateRawOffer calls tx.ONS_CreateRawOffer, it's retried when the node can't be reached or is busy
+func (c TxClient) ONS_CreateRawOffer(ctx context.Context, req ONSOfferRequest) (out SendTxReply, err error) {
+	err = c.c.CallIdempotent(ctx, "tx.ONS_CreateRawOffer", req, &out)
+	return
+}
+
+// ONS_CreateRawOfferCall is tx.ONS_CreateRawOffer in a batch, out gets the reply
+func (TxClient) ONS_CreateRawOfferCall(req ONSOfferRequest, out *SendTxReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "tx.ONS_CreateRawOffer", Args: req, Reply: out, Idempotent: true}
+}
+
+// ONS_CreateRawRenew calls tx.ONS_CreateRawRenew, it's retried when the node can't be reached or is busy
+func (c TxClient) ONS_CreateRawRenew(ctx context.Context, req ONSRenewRequest) (out SendTxReply, err error) {
+	err = c.c.CallIdempotent(ctx, "tx.ONS_CreateRawRenew", req, &out)
+	return
+}
+
+// ONS_CreateRawRenewCall is tx.ONS_CreateRawRenew in a batch, out gets the reply
+func (TxClient) ONS_CreateRawRenewCall(req ONSRenewRequest, out *SendTxReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "tx.ONS_CreateRawRenew", Args: req, Reply: out, Idempotent: true}
+}
+
+// ONS_CreateRawReveal calls tx.ONS_CreateRawReveal, it's retried when the node can't be reached or is busy
+func (c TxClient) ONS_CreateRawReveal(ctx context.Context, req ONSRevealRequest) (out SendTxReply, err error) {
+	err = c.c.CallIdempotent(ctx, "tx.ONS_CreateRawReveal", req, &out)
+	return
+}
+
+// ONS_CreateRawRevealCall is tx.ONS_CreateRawReveal in a batch, out gets the reply
+func (TxClient) ONS_CreateRawRevealCall(req ONSRevealRequest, out *SendTxReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "tx.ONS_CreateRawReveal", Args: req, Reply: out, Idempotent: true}
+}
+
+// ONS_CreateRawSale calls tx.ONS_CreateRawSale, it's retried when the node can't be reached or is busy
+func (c TxClient) ONS_CreateRawSale(ctx context.Context, req ONSSaleRequest) (out SendTxReply, err error) {
+	err = c.c.CallIdempotent(ctx, "tx.ONS_CreateRawSale", req, &out)
+	return
+}
+
+// ONS_CreateRawSaleCall is tx.ONS_CreateRawSale in a batch, out gets the reply
+func (TxClient) ONS_CreateRawSaleCall(req ONSSaleRequest, out *SendTxReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "tx.ONS_CreateRawSale", Args: req, Reply: out, Idempotent: true}
+}
+
+// ONS_CreateRawSend calls tx.ONS_CreateRawSend, it's retried when the node can't be reached or is busy
+func (c TxClient) ONS_CreateRawSend(ctx context.Context, req ONSSendRequest) (out SendTxReply, err error) {
+	err = c.c.CallIdempotent(ctx, "tx.ONS_CreateRawSend", req, &out)
+	return
+}
+
+// ONS_CreateRawSendCall is tx.ONS_CreateRawSend in a batch, out gets the reply
+func (TxClient) ONS_CreateRawSendCall(req ONSSendRequest, out *SendTxReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "tx.ONS_CreateRawSend", Args: req, Reply: out, Idempotent: true}
+}
+
+// ONS_CreateRawSetPrimary calls tx.ONS_CreateRawSetPrimary, it's retried when the node can't be reached or is busy
+func (c TxClient) ONS_CreateRawSetPrimary(ctx context.Context, req ONSSetPrimaryRequest) (out SendTxReply, err error) {
+	err = c.c.CallIdempotent(ctx, "tx.ONS_CreateRawSetPrimary", req, &out)
+	return
+}
+
+// ONS_CreateRawSetPrimaryCall is tx.ONS_CreateRawSetPrimary in a batch, out gets the reply
+func (TxClient) ONS_CreateRawSetPrimaryCall(req ONSSetPrimaryRequest, out *SendTxReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "tx.ONS_CreateRawSetPrimary", Args: req, Reply: out, Idempotent: true}
+}
+
+// ONS_CreateRawSetRecords calls tx.ONS_CreateRawSetRecords, it's retried when the node can't be reached or is busy
+func (c TxClient) ONS_CreateRawSetRecords(ctx context.Context, req ONSSetRecordsRequest) (out SendTxReply, err error) {
+	err = c.c.CallIdempotent(ctx, "tx.ONS_CreateRawSetRecords", req, &out)
+	return
+}
+
+// ONS_CreateRawSetRecordsCall is tx.ONS_CreateRawSetRecords in a batch, out gets the reply
+func (TxClient) ONS_CreateRawSetRecordsCall(req ONSSetRecordsRequest, out *SendTxReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "tx.ONS_CreateRawSetRecords", Args: req, Reply: out, Idempotent: true}
+}
+
+// ONS_CreateRawUpdate calls tx.ONS_CreateRawUpdate, it's retried when the node can't be reached or is busy
+func (c TxClient) ONS_CreateRawUpdate(ctx context.Context, req ONSUpdateRequest) (out SendTxReply, err error) {
+	err = c.c.CallIdempotent(ctx, "tx.ONS_CreateRawUpdate", req, &out)
+	return
+}
+
+// ONS_CreateRawUpdateCall is tx.ONS_CreateRawUpdate in a batch, out gets the reply
+func (TxClient) ONS_CreateRawUpdateCall(req ONSUpdateRequest, out *SendTxReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "tx.ONS_CreateRawUpdate", Args: req, Reply: out, Idempotent: true}
+}
+
+// SendTx calls tx.SendTx
+func (c TxClient) SendTx(ctx context.Context, req SendTxRequest) (out SendTxReply, err error) {
+	err = c.c.CallContext(ctx, "tx.SendTx", req, &out)
+	return
+}
+
+// SendTxCall is tx.SendTx in a batch, out gets the reply
+func (TxClient) SendTxCall(req SendTxRequest, out *SendTxReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "tx.SendTx", Args: req, Reply: out, Idempotent: false}
+}
+
+// VoteGasSchedule calls tx.VoteGasSchedule
+func (c TxClient) VoteGasSchedule(ctx context.Context, req VoteGasScheduleRequest) (out VoteGasScheduleReply, err error) {
+	err = c.c.CallContext(ctx, "tx.VoteGasSchedule", req, &out)
+	return
+}
+
+// VoteGasScheduleCall is tx.VoteGasSchedule in a batch, out gets the reply
+func (TxClient) VoteGasScheduleCall(req VoteGasScheduleRequest, out *VoteGasScheduleReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "tx.VoteGasSchedule", Args: req, Reply: out, Idempotent: false}
+}
+
+// VoteReservedNames calls tx.VoteReservedNames
+func (c TxClient) VoteReservedNames(ctx context.Context, req VoteReservedNamesRequest) (out VoteReservedNamesReply, err error) {
+	err = c.c.CallContext(ctx, "tx.VoteReservedNames", req, &out)
+	return
+}
+
+// VoteReservedNamesCall is tx.VoteReservedNames in a batch, out gets the reply
+func (TxClient) VoteReservedNamesCall(req VoteReservedNamesRequest, out *VoteReservedNamesReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "tx.VoteReservedNames", Args: req, Reply: out, Idempotent: false}
+}
+
+// WithdrawReward calls tx.WithdrawReward
+func (c TxClient) WithdrawReward(ctx context.Context, req WithdrawRewardRequest) (out WithdrawRewardReply, err error) {
+	err = c.c.CallContext(ctx, "tx.WithdrawReward", req, &out)
+	return
+}
+
+// WithdrawRewardCall is tx.WithdrawReward in a batch, out gets the reply
+func (TxClient) WithdrawRewardCall(req WithdrawRewardRequest, out *WithdrawRewardReply) *rpc.BatchCall {
+	return &rpc.BatchCall{Method: "tx.WithdrawReward", Args: req, Reply: out, Idempotent: false}
+}
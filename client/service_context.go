@@ -23,12 +23,14 @@ import (
 	rpcclient "github.com/tendermint/tendermint/rpc/client"
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
 
+	"github.com/Oneledger/protocol/config"
 	"github.com/Oneledger/protocol/rpc"
 )
 
 var (
 	ErrEmptyQuery    = errors.New("empty query path")
 	ErrEmptyResponse = errors.New("empty response")
+	ErrNoRPCClient   = errors.New("rpc client isn't connected")
 )
 
 // ExtServiceContext holds clients for making requests to external services
@@ -43,6 +45,32 @@ type ExtServiceContext struct {
 	Generators
 */
 func NewExtServiceContext(rpcAddress, sdkAddress string) (cliCtx ExtServiceContext, err error) {
+	return NewExtServiceContextWithOptions(rpcAddress, sdkAddress, rpc.ClientOptions{})
+}
+
+// SDKOptions returns the options to call the SDK port of the node configured in cfg
+func SDKOptions(cfg config.Server) rpc.ClientOptions {
+	opts := rpc.ClientOptions{}
+	if cfg.Network == nil {
+		return opts
+	}
+	network := cfg.Network
+
+	ca := network.SDKTLSCAFile
+	if ca == "" {
+		// a certificate in the pool is trusted, self-signed ones included
+		ca = network.SDKTLSCertFile
+	}
+	opts.TLS = rpc.ClientTLS{
+		CAFile:   cfg.RootPath(ca),
+		CertFile: cfg.RootPath(network.SDKTLSClientCertFile),
+		KeyFile:  cfg.RootPath(network.SDKTLSClientKeyFile),
+	}
+	return opts
+}
+
+// NewExtServiceContextWithOptions is NewExtServiceContext calling the SDK port with the options
+func NewExtServiceContextWithOptions(rpcAddress, sdkAddress string, opts rpc.ClientOptions) (cliCtx ExtServiceContext, err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			logger.Debug("Ignoring rpcClient Panic", "r", r)
@@ -65,7 +93,7 @@ func NewExtServiceContext(rpcAddress, sdkAddress string) (cliCtx ExtServiceConte
 		return
 	}
 
-	rpcClient, err := rpc.NewClient(sdkAddress)
+	rpcClient, err := rpc.NewClientWithOptions(sdkAddress, opts)
 	if err != nil {
 		return
 	}
@@ -134,6 +162,22 @@ func (ctx ExtServiceContext) Search(query string, prove bool, page, perPage int)
 	return result
 }
 
+// Status returns the status of the tendermint node, whether it's catching up included
+func (ctx ExtServiceContext) Status() (*ctypes.ResultStatus, error) {
+	if ctx.rpcClient == nil {
+		return nil, ErrNoRPCClient
+	}
+	return ctx.rpcClient.Status()
+}
+
+// NetInfo returns the peers of the tendermint node
+func (ctx ExtServiceContext) NetInfo() (*ctypes.ResultNetInfo, error) {
+	if ctx.rpcClient == nil {
+		return nil, ErrNoRPCClient
+	}
+	return ctx.rpcClient.NetInfo()
+}
+
 // abciQuery is a thin wrapper over tendermint rpc client's abciQuery
 func (ctx ExtServiceContext) abciQuery(path string, packet []byte) (res *ctypes.ResultABCIQuery, err error) {
 
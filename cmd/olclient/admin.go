@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Oneledger/protocol/client"
+)
+
+var adminCmd = &cobra.Command{
+	Use:   "admin",
+	Short: "Manage the jobs, peers, services and logging of a running node, needs the admin role",
+}
+
+var (
+	adminPersistent bool
+	adminJobStatus  string
+)
+
+func init() {
+	RootCmd.AddCommand(adminCmd)
+
+	dialPeersCmd := &cobra.Command{
+		Use:   "dial_peers [id@host:port...]",
+		Short: "Connect to peers",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: adminCall(func(c client.AdminClient, args []string) (interface{}, error) {
+			return c.DialPeers(context.Background(), client.AdminDialPeersRequest{Peers: args, Persistent: adminPersistent})
+		}),
+	}
+	dialPeersCmd.Flags().BoolVar(&adminPersistent, "persistent", false, "redial the peers when they disconnect")
+
+	jobsCmd := &cobra.Command{
+		Use:   "jobs [chain]",
+		Short: "List the jobs of the job bus, of bitcoin or ethereum only when the chain is given",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: adminCall(func(c client.AdminClient, args []string) (interface{}, error) {
+			req := client.AdminListJobsRequest{Status: adminJobStatus}
+			if len(args) > 0 {
+				req.Chain = args[0]
+			}
+			return c.ListJobs(context.Background(), req)
+		}),
+	}
+	jobsCmd.Flags().StringVar(&adminJobStatus, "status", "",
+		"list only the jobs with the status: new, in_progress, completed, failed or dead")
+
+	commands := []*cobra.Command{
+		jobsCmd,
+		{
+			Use:   "retry_job [chain] [id]",
+			Short: "Start a job over, forgetting its status and attempts",
+			Args:  cobra.ExactArgs(2),
+			RunE: adminCall(func(c client.AdminClient, args []string) (interface{}, error) {
+				return c.RetryJob(context.Background(), client.AdminJobRequest{Chain: args[0], ID: args[1]})
+			}),
+		},
+		{
+			Use:   "cancel_job [chain] [id]",
+			Short: "Delete a job from the job store",
+			Args:  cobra.ExactArgs(2),
+			RunE: adminCall(func(c client.AdminClient, args []string) (interface{}, error) {
+				return c.CancelJob(context.Background(), client.AdminJobRequest{Chain: args[0], ID: args[1]})
+			}),
+		},
+		{
+			Use:   "run_jobs",
+			Short: "Run a cycle of the job bus now and list the jobs left",
+			Args:  cobra.NoArgs,
+			RunE: adminCall(func(c client.AdminClient, args []string) (interface{}, error) {
+				return c.RunJobs(context.Background(), client.AdminRunJobsRequest{})
+			}),
+		},
+		{
+			Use:   "log_level [levels]",
+			Short: "Set the log levels until the node restarts, e.g. debug or \"app=info, event=debug\"",
+			Args:  cobra.ExactArgs(1),
+			RunE: adminCall(func(c client.AdminClient, args []string) (interface{}, error) {
+				return c.SetLogLevel(context.Background(), client.AdminLogLevelRequest{Levels: args[0]})
+			}),
+		},
+		{
+			Use:   "set_option [key] [value]",
+			Short: "Change an option of the running node, like ABCI SetOption",
+			Args:  cobra.ExactArgs(2),
+			RunE: adminCall(func(c client.AdminClient, args []string) (interface{}, error) {
+				return c.SetOption(context.Background(), client.AdminSetOptionRequest{Key: args[0], Value: args[1]})
+			}),
+		},
+		{
+			Use:   "peers",
+			Short: "List the connected and the banned peers",
+			Args:  cobra.NoArgs,
+			RunE: adminCall(func(c client.AdminClient, args []string) (interface{}, error) {
+				return c.ListPeers(context.Background(), client.AdminListPeersRequest{})
+			}),
+		},
+		dialPeersCmd,
+		{
+			Use:   "ban_peer [id]",
+			Short: "Disconnect a peer and refuse its connections, the ban is saved in the config",
+			Args:  cobra.ExactArgs(1),
+			RunE: adminCall(func(c client.AdminClient, args []string) (interface{}, error) {
+				return c.BanPeer(context.Background(), client.AdminPeerRequest{ID: args[0]})
+			}),
+		},
+		{
+			Use:   "unban_peer [id]",
+			Short: "Let a banned peer connect again",
+			Args:  cobra.ExactArgs(1),
+			RunE: adminCall(func(c client.AdminClient, args []string) (interface{}, error) {
+				return c.UnbanPeer(context.Background(), client.AdminPeerRequest{ID: args[0]})
+			}),
+		},
+		{
+			Use:   "services",
+			Short: "List the services of the SDK port",
+			Args:  cobra.NoArgs,
+			RunE: adminCall(func(c client.AdminClient, args []string) (interface{}, error) {
+				return c.ListServices(context.Background(), client.AdminListServicesRequest{})
+			}),
+		},
+		{
+			Use:   "enable_service [name]",
+			Short: "Let a disabled service be called again",
+			Args:  cobra.ExactArgs(1),
+			RunE: adminCall(func(c client.AdminClient, args []string) (interface{}, error) {
+				return c.EnableService(context.Background(), client.AdminServiceRequest{Name: args[0]})
+			}),
+		},
+		{
+			Use:   "disable_service [name]",
+			Short: "Make the calls to a service fail until it's enabled again",
+			Args:  cobra.ExactArgs(1),
+			RunE: adminCall(func(c client.AdminClient, args []string) (interface{}, error) {
+				return c.DisableService(context.Background(), client.AdminServiceRequest{Name: args[0]})
+			}),
+		},
+	}
+	for _, c := range commands {
+		adminCmd.AddCommand(c)
+	}
+
+	adminCmd.AddCommand(&cobra.Command{
+		Use:   "config",
+		Short: "Print the config the node runs with, without its secrets",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			Ctx := NewContext()
+			reply, err := Ctx.clCtx.FullNodeClient().Admin().Config(context.Background(), client.AdminConfigRequest{})
+			if err != nil {
+				return err
+			}
+			fmt.Print(reply.Config)
+			return nil
+		},
+	})
+}
+
+// adminCall runs a call of the admin service and prints its reply as JSON
+func adminCall(fn func(c client.AdminClient, args []string) (interface{}, error)) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		Ctx := NewContext()
+		reply, err := fn(Ctx.clCtx.FullNodeClient().Admin(), args)
+		if err != nil {
+			return err
+		}
+
+		out, err := json.MarshalIndent(reply, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/Oneledger/protocol/client"
 	"github.com/Oneledger/protocol/rpc"
 )
 
@@ -49,7 +50,8 @@ func call(cmd *cobra.Command, args []string) error {
 	}
 
 	var reply map[string]interface{}
-	client, err := rpc.NewClient(fullnodeConn)
+	opts := client.SDKOptions(Ctx.cfg)
+	client, err := rpc.NewClientWithOptions(fullnodeConn, opts)
 	if err != nil {
 		return errors.New("failed to create rpc client")
 	}
@@ -46,7 +46,7 @@ func NewContext() *Context {
 		logger.Fatal("failed to read configuration", err)
 	}
 
-	clientContext, err := client.NewExtServiceContext(Ctx.cfg.Network.RPCAddress, Ctx.cfg.Network.SDKAddress)
+	clientContext, err := client.NewExtServiceContextWithOptions(Ctx.cfg.Network.RPCAddress, Ctx.cfg.Network.SDKAddress, client.SDKOptions(Ctx.cfg))
 	if err != nil {
 		Ctx.logger.Fatal("error starting rpc client", err)
 	}
@@ -5,7 +5,14 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+
+	"github.com/Oneledger/protocol/client"
 )
 
 var trackerCmd = &cobra.Command{
@@ -20,11 +27,22 @@ type TrackerReq struct {
 
 var trackerArgs *TrackerReq = &TrackerReq{}
 
+var trackerHistoryCmd = &cobra.Command{
+	Use:   "history [name]",
+	Short: "Print the state transitions of a tracker, with the block and the tx behind each",
+	Args:  cobra.ExactArgs(1),
+	RunE:  trackerHistory,
+}
+
+var trackerHistoryChain string
+
 func init() {
 	RootCmd.AddCommand(trackerCmd)
+	trackerCmd.AddCommand(trackerHistoryCmd)
 
 	// Transaction Parameters
 	trackerCmd.Flags().StringVar(&trackerArgs.name, "tracker_name", "tracker_0", "tracker name")
+	trackerHistoryCmd.Flags().StringVar(&trackerHistoryChain, "chain", "btc", "chain of the tracker, btc or eth")
 }
 
 // IssueRequest sends out a sendTx to all of the nodes in the chain
@@ -48,3 +66,36 @@ func TrackerNode(cmd *cobra.Command, args []string) {
 func printTracker(tracker string, nodeName string) {
 	logger.Infof("\n %s \n Node Name: %s \n", tracker, nodeName)
 }
+
+func trackerHistory(cmd *cobra.Command, args []string) error {
+	Ctx := NewContext()
+	fullnode := Ctx.clCtx.FullNodeClient()
+
+	req := client.TrackerHistoryRequest{Name: args[0]}
+	var reply client.TrackerHistoryReply
+	var err error
+	switch strings.ToLower(trackerHistoryChain) {
+	case "btc", "bitcoin":
+		reply, err = fullnode.BTC().GetTrackerHistory(context.Background(), req)
+	case "eth", "ethereum":
+		reply, err = fullnode.ETH().GetTrackerHistory(context.Background(), req)
+	default:
+		return errors.Errorf("unknown chain %s, expected btc or eth", trackerHistoryChain)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(reply.Entries) == 0 {
+		fmt.Println("no transitions recorded for tracker", reply.Name)
+		return nil
+	}
+	fmt.Println("Tracker:", reply.Name)
+	for _, e := range reply.Entries {
+		fmt.Printf("%4d  height %d  %s: %s -> %s\n", e.Index, e.Height, e.Transition, e.From, e.To)
+		if e.TxHash != "" {
+			fmt.Println("      tx:", e.TxHash)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+/*
+	Copyright 2017-2019 OneLedger
+
+	Cli to manage the API keys of the SDK RPC server and to hash owner passwords.
+*/
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/Oneledger/protocol/rpc"
+)
+
+type apiKeyArgs struct {
+	name     string
+	roles    []string
+	expires  time.Duration
+	password string
+}
+
+var apiKeyArg = &apiKeyArgs{}
+
+var apiKeyCmd = &cobra.Command{
+	Use:   "apikey",
+	Short: "Manage the API keys of the SDK RPC server, changes apply to a running node",
+}
+
+var apiKeyCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create an API key, it is only shown once",
+	RunE:  CreateAPIKey,
+}
+
+var apiKeyListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the API keys",
+	RunE:  ListAPIKeys,
+}
+
+var apiKeyRevokeCmd = &cobra.Command{
+	Use:   "revoke <id>",
+	Short: "Revoke an API key",
+	Args:  cobra.ExactArgs(1),
+	RunE:  RevokeAPIKey,
+}
+
+var hashPasswordCmd = &cobra.Command{
+	Use:   "hash_password",
+	Short: "Hash a password for owner_credentials, reads it from stdin unless --password is given",
+	RunE:  HashPassword,
+}
+
+func init() {
+	RootCmd.AddCommand(apiKeyCmd, hashPasswordCmd)
+	apiKeyCmd.AddCommand(apiKeyCreateCmd, apiKeyListCmd, apiKeyRevokeCmd)
+
+	apiKeyCreateCmd.Flags().StringVar(&apiKeyArg.name, "name", "", "name of the key, e.g. who uses it")
+	apiKeyCreateCmd.Flags().StringSliceVar(&apiKeyArg.roles, "roles", []string{"public"}, "roles of the key (public, owner, admin)")
+	apiKeyCreateCmd.Flags().DurationVar(&apiKeyArg.expires, "expires", 0, "time until the key expires, e.g. 720h, 0 for never")
+
+	hashPasswordCmd.Flags().StringVar(&apiKeyArg.password, "password", "", "password to hash")
+}
+
+func apiKeyStore() (*rpc.APIKeyStore, error) {
+	cfg, err := readServerConfig()
+	if err != nil {
+		return nil, err
+	}
+	return rpc.NewAPIKeyStore(cfg.APIKeysPath()), nil
+}
+
+func CreateAPIKey(cmd *cobra.Command, args []string) error {
+	if apiKeyArg.name == "" {
+		return errors.New("--name is required")
+	}
+	roles, err := rpc.ParseRoles(apiKeyArg.roles)
+	if err != nil {
+		return err
+	}
+	expires := time.Time{}
+	if apiKeyArg.expires > 0 {
+		expires = time.Now().UTC().Add(apiKeyArg.expires)
+	}
+
+	store, err := apiKeyStore()
+	if err != nil {
+		return err
+	}
+	key, info, err := store.Create(apiKeyArg.name, roles, expires)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("id:     ", info.ID)
+	fmt.Println("key:    ", key)
+	fmt.Println("Store the key now, it can't be shown again")
+	return nil
+}
+
+func ListAPIKeys(cmd *cobra.Command, args []string) error {
+	store, err := apiKeyStore()
+	if err != nil {
+		return err
+	}
+	list, err := store.List()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, k := range list {
+		status := "active"
+		switch {
+		case k.Revoked:
+			status = "revoked"
+		case !k.Expires.IsZero() && !now.Before(k.Expires):
+			status = "expired"
+		}
+		expires := "never"
+		if !k.Expires.IsZero() {
+			expires = k.Expires.Format(time.RFC3339)
+		}
+		roles := make([]string, 0, len(k.Roles))
+		for _, r := range k.Roles {
+			roles = append(roles, string(r))
+		}
+		fmt.Printf("%s\t%s\t%s\t%s\texpires %s\n", k.ID, k.Name, strings.Join(roles, ","), status, expires)
+	}
+	return nil
+}
+
+func RevokeAPIKey(cmd *cobra.Command, args []string) error {
+	store, err := apiKeyStore()
+	if err != nil {
+		return err
+	}
+	err = store.Revoke(args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Println("revoked", args[0])
+	return nil
+}
+
+func HashPassword(cmd *cobra.Command, args []string) error {
+	password := apiKeyArg.password
+	if password == "" {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return errors.Wrap(err, "failed to read password")
+		}
+		password = strings.TrimRight(line, "\r\n")
+	}
+	if password == "" {
+		return errors.New("empty password")
+	}
+
+	hash, err := rpc.HashPassword(password)
+	if err != nil {
+		return err
+	}
+	fmt.Println(hash)
+	return nil
+}
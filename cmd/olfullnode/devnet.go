@@ -33,10 +33,12 @@ import (
 	"github.com/Oneledger/protocol/data/chain"
 
 	"github.com/Oneledger/protocol/data/fees"
+	"github.com/Oneledger/protocol/data/governance"
 	"github.com/Oneledger/protocol/data/keys"
 
 	ethcontracts "github.com/Oneledger/protocol/chains/ethereum/contract"
 	"github.com/Oneledger/protocol/log"
+	"github.com/Oneledger/protocol/version"
 )
 
 type testnetConfig struct {
@@ -470,7 +472,9 @@ func initialState(args *testnetConfig, nodeList []node, option ethchain.ChainDri
 			})
 		}
 	}
+	upgrades := governance.NewChainUpgrades()
 	return consensus.AppState{
+		Version:     version.Protocol.String(),
 		Currencies:  currencies,
 		FeeOption:   feeOpt,
 		ETHCDOption: option,
@@ -478,6 +482,7 @@ func initialState(args *testnetConfig, nodeList []node, option ethchain.ChainDri
 		Staking:     staking,
 		Domains:     domains,
 		Fees:        fees_db,
+		Governance:  consensus.GovernanceState{Upgrades: &upgrades},
 	}
 }
 
@@ -0,0 +1,127 @@
+/*
+	Copyright 2017-2019 OneLedger
+
+	Cli to export the chain state as the genesis of a new chain, for hard-fork upgrades.
+*/
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	tmdb "github.com/tendermint/tendermint/libs/db"
+	sm "github.com/tendermint/tendermint/state"
+	"github.com/tendermint/tendermint/types"
+
+	"github.com/Oneledger/protocol/app"
+	olnode "github.com/Oneledger/protocol/app/node"
+	"github.com/Oneledger/protocol/consensus"
+	"github.com/Oneledger/protocol/serialize"
+)
+
+type exportArgs struct {
+	height  int64
+	chainID string
+	out     string
+}
+
+var exportArg = &exportArgs{}
+
+var exportCmd = &cobra.Command{
+	Use:     "export",
+	Aliases: []string{"save_state"},
+	Short:   "Export the chain state as a genesis file for a new chain, the node must be stopped",
+	RunE:    ExportGenesis,
+}
+
+func init() {
+	RootCmd.AddCommand(exportCmd)
+	exportCmd.Flags().Int64Var(&exportArg.height, "height", 0, "height to export, defaults to the latest committed height")
+	exportCmd.Flags().StringVar(&exportArg.chainID, "chain-id", "", "chain ID of the new chain, required")
+	exportCmd.Flags().StringVarP(&exportArg.out, "out", "o", "genesis.json", "file to write the genesis to")
+}
+
+func ExportGenesis(cmd *cobra.Command, args []string) error {
+	if exportArg.chainID == "" {
+		return errors.New("--chain-id is required")
+	}
+
+	cfg, err := readServerConfig()
+	if err != nil {
+		return err
+	}
+
+	oldGenesis, err := types.GenesisDocFromFile(cfg.TMConfig().GenesisFile())
+	if err != nil {
+		return errors.Wrap(err, "failed to read genesis")
+	}
+	if oldGenesis.ChainID == exportArg.chainID {
+		return errors.New("the new chain needs a different chain ID")
+	}
+
+	appNodeContext, err := olnode.NewNodeContext(cfg)
+	if err != nil {
+		return errors.Wrap(err, "failed to create app's node context")
+	}
+	application, err := app.NewApp(cfg, appNodeContext)
+	if err != nil {
+		return errors.Wrap(err, "failed to create new app")
+	}
+	defer application.Close()
+
+	appState, err := application.ExportState(exportArg.height)
+	if err != nil {
+		return errors.Wrap(err, "failed to export state")
+	}
+
+	// the new chain starts with the validators that would have signed the next block
+	tmcfg := cfg.TMConfig()
+	stateDB := tmdb.NewDB("state", tmdb.DBBackendType(tmcfg.DBBackend), tmcfg.DBDir())
+	defer stateDB.Close()
+	valSet, err := sm.LoadValidators(stateDB, appState.Chain.Version+1)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load validators for height %d", appState.Chain.Version+1)
+	}
+
+	stateBytes, err := serialize.GetSerializer(serialize.JSON).Serialize(appState)
+	if err != nil {
+		return errors.Wrap(err, "failed to serialize state")
+	}
+
+	genesis := &consensus.GenesisDoc{
+		GenesisTime:     time.Now(),
+		ChainID:         exportArg.chainID,
+		ConsensusParams: oldGenesis.ConsensusParams,
+		Validators:      make([]consensus.GenesisValidator, 0, valSet.Size()),
+		AppState:        stateBytes,
+	}
+	for _, v := range valSet.Validators {
+		name := ""
+		for _, gv := range oldGenesis.Validators {
+			if gv.Address.String() == v.Address.String() {
+				name = gv.Name
+			}
+		}
+		genesis.Validators = append(genesis.Validators, consensus.GenesisValidator{
+			Address: v.Address,
+			PubKey:  v.PubKey,
+			Power:   v.VotingPower,
+			Name:    name,
+		})
+	}
+
+	err = genesis.ValidateAndComplete()
+	if err != nil {
+		return errors.Wrap(err, "invalid genesis")
+	}
+	err = genesis.SaveAs(exportArg.out)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Exported height %d (app hash %X) of %s to %s as chain %s\n",
+		appState.Chain.Version, appState.Chain.Hash, oldGenesis.ChainID, exportArg.out, exportArg.chainID)
+	return nil
+}
@@ -0,0 +1,83 @@
+/*
+	Copyright 2017-2019 OneLedger
+
+	Cli to run the signing daemon holding validator and bridge keys.
+*/
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	olnode "github.com/Oneledger/protocol/app/node"
+	"github.com/Oneledger/protocol/config"
+	"github.com/Oneledger/protocol/log"
+	"github.com/Oneledger/protocol/signer"
+)
+
+var signerCmd = &cobra.Command{
+	Use:   "signer",
+	Short: "Run a signing daemon for the keys under --root, configured by its [signer] section",
+	RunE:  StartSigner,
+}
+
+func init() {
+	RootCmd.AddCommand(signerCmd)
+}
+
+func StartSigner(cmd *cobra.Command, args []string) error {
+	logger := log.NewLoggerWithPrefix(os.Stdout, "olfullnode signer")
+
+	rootPath, err := filepath.Abs(rootArgs.rootDir)
+	if err != nil {
+		return err
+	}
+
+	cfg := &config.Server{}
+	err = cfg.ReadFile(cfgPath(rootPath))
+	if err != nil {
+		return errors.Wrapf(err, "failed to read configuration file at %s", cfgPath(rootPath))
+	}
+	if cfg.Signer == nil {
+		return errors.New("missing [signer] section in configuration")
+	}
+
+	nodeContext, err := olnode.NewNodeContext(cfg)
+	if err != nil {
+		return errors.Wrap(err, "failed to read keys")
+	}
+
+	guard := signer.NewGuard(filepath.Join(rootPath, cfg.Node.DBDir), cfg.Node.DB)
+	ecdsaKey := nodeContext.ValidatorECDSAPrivateKey()
+	local, err := signer.NewLocalSigner(nodeContext.PrivVal(), ecdsaKey, ecdsaKey, guard)
+	if err != nil {
+		return err
+	}
+	if cfg.Signer.TrackerRPC != "" {
+		trackers, err := signer.NewNodeTrackers(cfg.Signer.TrackerRPC)
+		if err != nil {
+			return err
+		}
+		local.WithTrackers(trackers)
+	}
+
+	server := signer.NewServer(local, cfg.Signer, logger)
+	err = server.Start()
+	if err != nil {
+		return errors.Wrap(err, "failed to start signer")
+	}
+
+	waiter := sync.WaitGroup{}
+	waiter.Add(1)
+	catchSigTerm(logger, func() {
+		server.Close()
+		guard.Close()
+	}, &waiter)
+
+	waiter.Wait()
+	return nil
+}
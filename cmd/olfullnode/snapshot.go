@@ -0,0 +1,229 @@
+/*
+	Copyright 2017-2019 OneLedger
+
+	Cli to create snapshots of the chain state and to start a node from one.
+*/
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	tmdb "github.com/tendermint/tendermint/libs/db"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	"github.com/tendermint/tendermint/types"
+
+	"github.com/Oneledger/protocol/config"
+	"github.com/Oneledger/protocol/snapshot"
+	"github.com/Oneledger/protocol/storage"
+)
+
+type snapshotArgs struct {
+	height      int64
+	chunkSize   int
+	from        string
+	trustedHash string
+	trustedRPC  string
+}
+
+var snapshotArg = &snapshotArgs{}
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Create and restore chain state snapshots",
+}
+
+var snapshotCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Write a snapshot of the chain state to <root>/snapshots, the node must be stopped",
+	RunE:  CreateSnapshot,
+}
+
+var snapshotRestoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Initialize an empty node from a snapshot verified against a trusted app hash",
+	RunE:  RestoreSnapshot,
+}
+
+func init() {
+	RootCmd.AddCommand(snapshotCmd)
+	snapshotCmd.AddCommand(snapshotCreateCmd, snapshotRestoreCmd)
+
+	snapshotCreateCmd.Flags().Int64Var(&snapshotArg.height, "height", 0, "height to snapshot, defaults to the latest committed height")
+	snapshotCreateCmd.Flags().IntVar(&snapshotArg.chunkSize, "chunk-size", snapshot.DefaultChunkSize, "approximate size of each chunk in bytes")
+
+	snapshotRestoreCmd.Flags().Int64Var(&snapshotArg.height, "height", 0, "height of the snapshot to restore")
+	snapshotRestoreCmd.Flags().StringVar(&snapshotArg.from, "from", "", "snapshot directory or SDK address of a serving node, e.g. http://127.0.0.1:26631")
+	snapshotRestoreCmd.Flags().StringVar(&snapshotArg.trustedHash, "trusted-hash", "", fmt.Sprintf("comma separated hex app hashes of the chain state at --height and the %d heights before it, newest first; snapshots with tendermint data need --trusted-rpc", snapshot.History))
+	snapshotRestoreCmd.Flags().StringVar(&snapshotArg.trustedRPC, "trusted-rpc", "", "tendermint RPC address of a trusted node to read the app hash from")
+}
+
+func readServerConfig() (*config.Server, error) {
+	rootPath, err := filepath.Abs(rootArgs.rootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &config.Server{}
+	err = cfg.ReadFile(cfgPath(rootPath))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read configuration file at %s", cfgPath(rootPath))
+	}
+	return cfg, nil
+}
+
+func snapshotDir(cfg *config.Server) string {
+	return filepath.Join(cfg.RootDir(), "snapshots")
+}
+
+// openNodeDBs opens the chain state and the tendermint state and block store of the node
+func openNodeDBs(cfg *config.Server) (appDB, stateDB, blockDB tmdb.DB, err error) {
+	appDB, err = storage.GetDatabase("chainstate", filepath.Join(cfg.RootDir(), cfg.Node.DBDir), cfg.Node.DB)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "failed to open chainstate")
+	}
+
+	tmcfg := cfg.TMConfig()
+	backend := tmdb.DBBackendType(tmcfg.DBBackend)
+	stateDB = tmdb.NewDB("state", backend, tmcfg.DBDir())
+	blockDB = tmdb.NewDB("blockstore", backend, tmcfg.DBDir())
+	return appDB, stateDB, blockDB, nil
+}
+
+func CreateSnapshot(cmd *cobra.Command, args []string) error {
+	cfg, err := readServerConfig()
+	if err != nil {
+		return err
+	}
+
+	appDB, stateDB, blockDB, err := openNodeDBs(cfg)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		appDB.Close()
+		stateDB.Close()
+		blockDB.Close()
+	}()
+
+	chainState := storage.NewChainState("chainstate", appDB)
+	height := snapshotArg.height
+	if height == 0 {
+		height = chainState.Version
+	}
+
+	genesis, err := types.GenesisDocFromFile(cfg.TMConfig().GenesisFile())
+	if err != nil {
+		return errors.Wrap(err, "failed to read genesis")
+	}
+
+	consensus, err := snapshot.ExportConsensus(stateDB, blockDB, height)
+	if err != nil {
+		return err
+	}
+
+	m, err := snapshot.NewStore(snapshotDir(cfg)).Create(chainState, genesis.ChainID, height, snapshotArg.chunkSize, consensus)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Created snapshot at height %d with %d chunks, app hash %X\n", m.Height, len(m.Chunks), m.AppHash)
+	if consensus == nil {
+		fmt.Println("Tendermint has moved past this height, the snapshot can only restore the chain state")
+	}
+	return nil
+}
+
+func RestoreSnapshot(cmd *cobra.Command, args []string) error {
+	if snapshotArg.height <= 0 {
+		return errors.New("--height is required")
+	}
+	if snapshotArg.from == "" {
+		return errors.New("--from is required")
+	}
+
+	cfg, err := readServerConfig()
+	if err != nil {
+		return err
+	}
+
+	trusted, header, err := trustedAppHashes(snapshotArg.height)
+	if err != nil {
+		return err
+	}
+
+	genesis, err := types.GenesisDocFromFile(cfg.TMConfig().GenesisFile())
+	if err != nil {
+		return errors.Wrap(err, "failed to read genesis")
+	}
+
+	var src snapshot.Source
+	if strings.HasPrefix(snapshotArg.from, "http://") || strings.HasPrefix(snapshotArg.from, "https://") {
+		src = snapshot.NewHTTPSource(snapshotArg.from)
+	} else {
+		src = snapshot.NewStore(snapshotArg.from)
+	}
+
+	appDB, stateDB, blockDB, err := openNodeDBs(cfg)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		appDB.Close()
+		stateDB.Close()
+		blockDB.Close()
+	}()
+
+	m, err := snapshot.Restore(src, snapshotArg.height, genesis.ChainID, trusted, header, appDB, stateDB, blockDB)
+	if err != nil {
+		return errors.Wrap(err, "failed to restore snapshot")
+	}
+
+	fmt.Printf("Restored snapshot at height %d, app hash %X\n", m.Height, m.AppHash)
+	if len(m.ConsensusHash) == 0 {
+		fmt.Println("Snapshot has no tendermint data, the node has to replay blocks from its own block store")
+	}
+	return nil
+}
+
+// trustedAppHashes returns the app hashes from --trusted-hash, or from the headers of the
+// blocks after height and after each of the snapshot.History heights before it on the
+// --trusted-rpc node, newest first. The header of the block after height is returned too,
+// it's needed to verify the tendermint data of the snapshot.
+func trustedAppHashes(height int64) ([][]byte, *types.Header, error) {
+	if snapshotArg.trustedHash != "" {
+		hashes := make([][]byte, 0)
+		for _, h := range strings.Split(snapshotArg.trustedHash, ",") {
+			hash, err := hex.DecodeString(strings.TrimSpace(h))
+			if err != nil {
+				return nil, nil, errors.Wrap(err, "invalid --trusted-hash")
+			}
+			hashes = append(hashes, hash)
+		}
+		return hashes, nil, nil
+	}
+
+	if snapshotArg.trustedRPC == "" {
+		return nil, nil, errors.New("one of --trusted-hash or --trusted-rpc is required")
+	}
+
+	client := rpcclient.NewHTTP(snapshotArg.trustedRPC, "/websocket")
+	var header *types.Header
+	hashes := make([][]byte, 0, snapshot.History+1)
+	for h := height; h > 0 && h >= height-snapshot.History; h-- {
+		next := h + 1
+		commit, err := client.Commit(&next)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to get commit %d from trusted node", next)
+		}
+		if header == nil {
+			header = commit.SignedHeader.Header
+		}
+		hashes = append(hashes, commit.SignedHeader.Header.AppHash)
+	}
+	return hashes, header, nil
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/Oneledger/protocol/event"
+)
+
+var transitionsCmd = &cobra.Command{
+	Use:   "transitions [engine...]",
+	Short: "Print the tracker state transitions as Graphviz DOT, of btc, eth_lock and eth_redeem or the engines given",
+	RunE:  Transitions,
+}
+
+func init() {
+	RootCmd.AddCommand(transitionsCmd)
+}
+
+func Transitions(cmd *cobra.Command, args []string) error {
+	graphs := event.Graphs()
+	names := make([]string, 0, len(graphs))
+	for name := range graphs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if len(args) == 0 {
+		args = names
+	}
+	for _, name := range args {
+		graph, ok := graphs[name]
+		if !ok {
+			return errors.Errorf("unknown engine %s, expected one of %s", name, strings.Join(names, ", "))
+		}
+		fmt.Print(graph)
+	}
+	return nil
+}
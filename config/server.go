@@ -4,6 +4,7 @@ import (
 	"bytes"
 	//"crypto/ecdsa"
 	"io/ioutil"
+	"net/url"
 	"path/filepath"
 	"strings"
 	"time"
@@ -14,6 +15,7 @@ import (
 	"github.com/pkg/errors"
 	tmconfig "github.com/tendermint/tendermint/config"
 
+	"github.com/Oneledger/protocol/data/fees"
 	"github.com/Oneledger/protocol/log"
 )
 
@@ -49,6 +51,8 @@ type Server struct {
 	Consensus      *ConsensusConfig           `toml:"consensus"`
 	ChainDriver    *ChainDriverConfig         `toml:"chain_driver"`
 	EthChainDriver *EthereumChainDriverConfig `toml:"ethereum_chain_driver"`
+	Signer         *SignerConfig              `toml:"signer"`
+	RPC            *RPCConfig                 `toml:"rpc"`
 
 	chainID string
 	rootDir string
@@ -62,6 +66,37 @@ func (cfg *Server) ChainID() string {
 	return cfg.chainID
 }
 
+// APIKeysPath returns the path of the API keys file
+func (cfg *Server) APIKeysPath() string {
+	name := cfg.Node.APIKeysFile
+	if name == "" {
+		name = DefaultAPIKeysFile
+	}
+	return filepath.Join(cfg.rootDir, name)
+}
+
+// SDKTLSEnabled tells if the SDK port serves TLS
+func (cfg *Server) SDKTLSEnabled() bool {
+	return cfg.Network != nil && cfg.Network.SDKTLSCertFile != ""
+}
+
+// RootPath resolves a file named in the config against the root directory
+func (cfg *Server) RootPath(name string) string {
+	if name == "" || filepath.IsAbs(name) {
+		return name
+	}
+	return filepath.Join(cfg.rootDir, name)
+}
+
+// AuditLogPath returns the path of the RPC audit log
+func (cfg *Server) AuditLogPath() string {
+	name := cfg.Node.AuditLog
+	if name == "" {
+		name = DefaultAuditLog
+	}
+	return filepath.Join(cfg.rootDir, name)
+}
+
 func (cfg *Server) setChainID(doc GenesisDoc) {
 	cfg.chainID = doc.ChainID
 }
@@ -79,6 +114,8 @@ func (cfg *Server) TMConfig() tmconfig.Config {
 	baseConfig.DBBackend = leveldb
 	baseConfig.DBPath = "data"
 	baseConfig.LogLevel = cfg.Consensus.LogLevel
+	// peers are checked against banned_peers through ABCI queries
+	baseConfig.FilterPeers = true
 
 	p2pConfig := cfg.P2P.TMConfig()
 	p2pConfig.ListenAddress = cfg.Network.P2PAddress
@@ -158,6 +195,61 @@ func (cfg *Server) SaveFile(filepath string) error {
 	return ioutil.WriteFile(filepath, bz, FilePerms)
 }
 
+const redacted = "<redacted>"
+
+// Redacted returns a copy of the config without the passwords, keys and tokens in it, to
+// show the config of a running node
+func (cfg *Server) Redacted() *Server {
+	out := *cfg
+	if cfg.Node != nil {
+		node := *cfg.Node
+		if node.RPCPrivateKey != "" {
+			node.RPCPrivateKey = redacted
+		}
+		node.OwnerCredentials = make([]string, len(cfg.Node.OwnerCredentials))
+		for i, c := range cfg.Node.OwnerCredentials {
+			parts := strings.Split(c, ":")
+			if len(parts) > 1 {
+				parts[1] = redacted
+			}
+			node.OwnerCredentials[i] = strings.Join(parts, ":")
+		}
+		out.Node = &node
+	}
+	if cfg.ChainDriver != nil {
+		driver := *cfg.ChainDriver
+		if driver.BitcoinRPCPassword != "" {
+			driver.BitcoinRPCPassword = redacted
+		}
+		if driver.BlockCypherToken != "" {
+			driver.BlockCypherToken = redacted
+		}
+		out.ChainDriver = &driver
+	}
+	if cfg.EthChainDriver != nil {
+		driver := *cfg.EthChainDriver
+		driver.Connection = redactURL(driver.Connection)
+		out.EthChainDriver = &driver
+	}
+	return &out
+}
+
+// redactURL keeps the scheme and the host of a node url, providers put the keys of their
+// users in the credentials, the path or the query
+func redactURL(raw string) string {
+	if raw == "" {
+		return raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return redacted
+	}
+	if u.User == nil && strings.Trim(u.Path, "/") == "" && u.RawQuery == "" && u.Fragment == "" {
+		return raw
+	}
+	return u.Scheme + "://" + u.Host + "/" + redacted
+}
+
 func DefaultServerConfig() *Server {
 	return &Server{
 		Node:           DefaultNodeConfig(),
@@ -167,6 +259,8 @@ func DefaultServerConfig() *Server {
 		Consensus:      DefaultConsensusConfig(),
 		ChainDriver:    DefaultChainDriverConfig(),
 		EthChainDriver: DefaultEthConfig(),
+		Signer:         DefaultSignerConfig(),
+		RPC:            DefaultRPCConfig(),
 	}
 }
 
@@ -178,6 +272,9 @@ type NodeConfig struct {
 	DBDir    string `toml:"db_dir" desc:"Specify the application database directory. This is always relative to the root directory of the app."`
 
 	LogLevel int `toml:"loglevel" desc:"Specify the log level for olfullnode. 0: Fatal, 1: Error, 2: Warning, 3: Info, 4: Debug, 5: Detail"`
+	// Levels of single modules, they can be changed at runtime with the log_level option
+	LogLevels string `toml:"log_levels" desc:"Log levels of single modules overriding loglevel, e.g. \"app=info, event=debug, storage=warn\". Levels are fatal, error, warn, info, debug and detail"`
+	LogFormat string `toml:"log_format" desc:"Format of the olfullnode logs (text|json)"`
 	// List of transaction tags to index in the db, allows them to be searched
 	// by this parameter
 	IndexTags []string `toml:"index_tags" desc:"List of transaction tags to index in the database, allows them to be searched by the specified tags"`
@@ -186,13 +283,102 @@ type NodeConfig struct {
 	IndexAllTags bool `toml:"index_all_tags" desc:"Tells the indexer to index all available tags, IndexTags has precedence over IndexAllTags"`
 
 	//rpc package
-	Services []string `toml:"services" desc:"List of services used by the current Node. Possible valued [broadcast, node, owner, query, tx]"`
+	Services []string `toml:"services" desc:"List of services used by the current Node. Possible valued [broadcast, node, owner, query, tx, btc, eth, admin]"`
 
 	//owner's password
-	OwnerCredentials []string `toml:"owner_credentials" desc:"Username and Password required to access owner services. Format [\"Username:Password\", \"Username:Password\"...]"`
+	OwnerCredentials []string `toml:"owner_credentials" desc:"Users allowed to get RPC tokens from /token, the role defaults to owner. Hash passwords with olfullnode hash_password. Format [\"Username:PasswordHash[:Role]\"...]"`
 
 	//Private Key for RPC Authentication
-	RPCPrivateKey string `toml:"rpc_private_key" desc:"(ED25519 key) This private key will be used to generate a token for authentication through RPC Port."`
+	RPCPrivateKey string `toml:"rpc_private_key" desc:"(ED25519 key) This private key will be used to generate a token for authentication through RPC Port. When set, every RPC call needs a token or an API key."`
+
+	// RPC authorization
+	ServiceRoles []string `toml:"service_roles" desc:"Role needed to call each service, overrides the defaults (public for broadcast, node and query, admin for admin, owner for the others). Roles are public, owner and admin. Format [\"service:role\"...]"`
+	LocalRoles   []string `toml:"rpc_local_roles" desc:"Roles of RPC clients on this host that send no token or API key, defaults to [\"owner\", \"admin\"] when not set. Requests through a proxy setting X-Forwarded-For, and requests from a browser for a page of another host, are never local."`
+	APIKeysFile  string   `toml:"api_keys_file" desc:"File holding the RPC API keys, relative to the root directory. Manage them with olfullnode apikey."`
+	AuditLog     string   `toml:"audit_log" desc:"File every call to a non public RPC service is logged to, relative to the root directory"`
+	// Web pages allowed to use the SDK port from a browser
+	AllowedOrigins []string `toml:"rpc_allowed_origins" desc:"Origins of the web pages allowed to use the RPC port from a browser, e.g. [\"https://wallet.example.com\"], \"*\" allows any. Pages from other origins can't open websockets or call the REST gateway."`
+
+	Metrics bool `toml:"metrics" desc:"Serve prometheus metrics at /metrics on the SDK port"`
+
+	// Which versions of the chain state to keep
+	Pruning           string `toml:"pruning" desc:"Which chain state versions to keep (default|nothing|everything|custom). default: the last 10 and every 100th of the last 1000, nothing: every version (archive nodes), everything: only the versions consensus needs, custom: set by the pruning_* options"`
+	PruningKeepRecent int64  `toml:"pruning_keep_recent" desc:"Number of recent versions to keep, only used with pruning = \"custom\""`
+	PruningKeepEvery  int64  `toml:"pruning_keep_every" desc:"Also keep every Nth version, 0 for none, only used with pruning = \"custom\""`
+	PruningCycles     int64  `toml:"pruning_cycles" desc:"Number of pruning_keep_every versions to keep, 0 for all of them, only used with pruning = \"custom\""`
+}
+
+const (
+	PruningDefault    = "default"
+	PruningNothing    = "nothing"
+	PruningEverything = "everything"
+	PruningCustom     = "custom"
+)
+
+// PruningOptions resolves the pruning preset into the number of recent versions to keep,
+// the interval of older versions to keep and how many of those intervals to keep. Fee
+// withdrawals read the state FEE_LOCK_BLOCKS back, so at least that many recent versions
+// are kept whatever the preset.
+func (cfg *NodeConfig) PruningOptions() (recent, every, cycles int64, err error) {
+	recent, every, cycles, err = cfg.pruningPreset()
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	if recent < fees.FEE_LOCK_BLOCKS {
+		recent = fees.FEE_LOCK_BLOCKS
+	}
+	return recent, every, cycles, nil
+}
+
+func (cfg *NodeConfig) pruningPreset() (recent, every, cycles int64, err error) {
+	switch cfg.Pruning {
+	case "", PruningDefault:
+		return 10, 100, 10, nil
+	case PruningNothing:
+		return 0, 1, 0, nil
+	case PruningEverything:
+		return 0, 0, 0, nil
+	case PruningCustom:
+		if cfg.PruningKeepRecent < 0 || cfg.PruningKeepEvery < 0 || cfg.PruningCycles < 0 {
+			return 0, 0, 0, errors.New("pruning options can not be negative")
+		}
+		if cfg.PruningKeepEvery == 0 && cfg.PruningCycles != 0 {
+			return 0, 0, 0, errors.New("pruning_cycles needs pruning_keep_every")
+		}
+		return cfg.PruningKeepRecent, cfg.PruningKeepEvery, cfg.PruningCycles, nil
+	}
+	return 0, 0, 0, errors.Errorf("unknown pruning strategy %q", cfg.Pruning)
+}
+
+var DefaultLocalRoles = []string{"owner", "admin"}
+
+const (
+	DefaultAPIKeysFile = "apikeys.json"
+	DefaultAuditLog    = "audit.log"
+)
+
+// RPCLocalRoles returns the roles of local clients, configs written before the option
+// existed keep local clients working as before
+func (cfg *NodeConfig) RPCLocalRoles() []string {
+	if cfg.LocalRoles == nil {
+		return DefaultLocalRoles
+	}
+	return cfg.LocalRoles
+}
+
+// SetupLogging applies the log format and the module levels to every logger
+func (cfg *NodeConfig) SetupLogging() error {
+	format, err := log.ParseFormat(cfg.LogFormat)
+	if err != nil {
+		return err
+	}
+	levels, err := log.ParseLevels(cfg.LogLevels)
+	if err != nil {
+		return errors.Wrap(err, "invalid log_levels")
+	}
+	log.SetFormat(format)
+	log.SetLevels(levels)
+	return nil
 }
 
 func DefaultNodeConfig() *NodeConfig {
@@ -202,9 +388,15 @@ func DefaultNodeConfig() *NodeConfig {
 		DB:           "goleveldb",
 		DBDir:        "nodedata",
 		LogLevel:     int(log.Info),
+		LogFormat:    "text",
 		IndexTags:    []string{"tx.owner", "tx.type"},
 		IndexAllTags: false,
-		Services:     []string{"broadcast", "node", "owner", "query", "tx", "btc", "eth"},
+		Services:     []string{"broadcast", "node", "owner", "query", "tx", "btc", "eth", "admin"},
+		LocalRoles:   DefaultLocalRoles,
+		APIKeysFile:  DefaultAPIKeysFile,
+		AuditLog:     DefaultAuditLog,
+		Metrics:      true,
+		Pruning:      PruningDefault,
 	}
 }
 
@@ -217,6 +409,16 @@ type NetworkConfig struct {
 
 	SDKAddress string `toml:"sdk_address"`
 
+	// TLS of the SDK listener, sdk_address has to be https:// when it is on
+	SDKTLSCertFile          string `toml:"sdk_tls_cert_file" desc:"Certificate the SDK port serves TLS with, relative to the root directory. Plain http when empty. Send SIGHUP to read renewed certificates."`
+	SDKTLSKeyFile           string `toml:"sdk_tls_key_file" desc:"Private key of sdk_tls_cert_file"`
+	SDKTLSClientCAFile      string `toml:"sdk_tls_client_ca_file" desc:"CA that client certificates are verified with, clients without a certificate are still accepted unless sdk_tls_require_client_cert is set"`
+	SDKTLSRequireClientCert bool   `toml:"sdk_tls_require_client_cert" desc:"Only accept clients with a certificate signed by sdk_tls_client_ca_file (mutual TLS)"`
+	// How olclient and the node itself call an https sdk_address
+	SDKTLSCAFile         string `toml:"sdk_tls_ca_file" desc:"CA that olclient verifies the SDK port with, sdk_tls_cert_file when empty and set, else the system roots"`
+	SDKTLSClientCertFile string `toml:"sdk_tls_client_cert_file" desc:"Client certificate olclient presents to the SDK port"`
+	SDKTLSClientKeyFile  string `toml:"sdk_tls_client_key_file" desc:"Private key of sdk_tls_client_cert_file"`
+
 	BTCAddress string `toml:"btc_address"`
 	ETHAddress string `toml:"eth_address"`
 
@@ -245,6 +447,8 @@ type P2PConfig struct {
 
 	PersistentPeers []string `toml:"persistent_peers" desc:"List of peers to maintain a persistent connection to"`
 
+	BannedPeers []string `toml:"banned_peers" desc:"IDs of peers the node refuses to connect to, managed with olclient admin ban_peer"`
+
 	UPNP bool `toml:"upnp" desc:"Enable UPNP port forwarding"`
 
 	AddrBookStrict bool `toml:"addr_book_strict" desc:"Set true for strict address routability rules. If true, the node will fail to start if the given P2P address isn't routable'"`
@@ -303,6 +507,7 @@ func DefaultP2PConfig() *P2PConfig {
 	tmDefaults := tmconfig.DefaultP2PConfig()
 	cfg.Seeds = make([]string, 0)
 	cfg.PersistentPeers = make([]string, 0)
+	cfg.BannedPeers = make([]string, 0)
 	cfg.PrivatePeerIDs = make([]string, 0)
 	cfg.UPNP = tmDefaults.UPNP
 	cfg.AddrBookStrict = false
@@ -453,3 +658,69 @@ func DefaultEthConfig() *EthereumChainDriverConfig {
 func (cfg *EthereumChainDriverConfig) Client() (*ethclient.Client, error) {
 	return ethclient.Dial(cfg.Connection)
 }
+
+// SignerConfig selects where the validator and bridge keys are held. The same section configures
+// the signing daemon, in which case Address is the address it listens on.
+type SignerConfig struct {
+	Mode    string `toml:"mode" desc:"Where validator and bridge keys are held (local|remote)"`
+	Address string `toml:"address" desc:"Address of the signing daemon, e.g. unix:///var/run/olsigner.sock or tcp://127.0.0.1:26651"`
+
+	CertFile   string `toml:"cert_file" desc:"Certificate presented to the other side of the signer connection"`
+	KeyFile    string `toml:"key_file" desc:"Private key of cert_file"`
+	CAFile     string `toml:"ca_file" desc:"CA certificate the other side of the signer connection must be signed by"`
+	ServerName string `toml:"server_name" desc:"Name expected in the signing daemon's certificate"`
+
+	Timeout Duration `toml:"timeout" desc:"Timeout for a single signing request in milliseconds"`
+
+	TrackerRPC string `toml:"tracker_rpc" desc:"SDK RPC address the signing daemon reads committed bitcoin trackers from, to sign a new lock for an outpoint after its tracker was reset. Best a node other than the one it signs for. Empty never signs an outpoint twice"`
+}
+
+func DefaultSignerConfig() *SignerConfig {
+	return &SignerConfig{
+		Mode:       "local",
+		Address:    "unix://olsigner.sock",
+		ServerName: "olsigner",
+		Timeout:    toConfigDuration(5 * time.Second),
+	}
+}
+
+// RPCConfig limits what a single client can ask of the SDK RPC server. Rates are in cost units,
+// every method costs 1 unless method_costs says otherwise.
+type RPCConfig struct {
+	RateLimit       float64  `toml:"rate_limit" desc:"Cost units per second a single IP address may spend, 0 for no limit"`
+	RateBurst       int      `toml:"rate_burst" desc:"Cost units an IP address may spend at once"`
+	APIKeyRateLimit float64  `toml:"api_key_rate_limit" desc:"Cost units per second a single API key may spend, it replaces the IP limit for requests with an API key. 0 for no limit"`
+	APIKeyRateBurst int      `toml:"api_key_rate_burst" desc:"Cost units an API key may spend at once"`
+	MethodCosts     []string `toml:"method_costs" desc:"Cost of the methods, and of the paths of other requests like /health, that are more expensive than others. A request costing more than the burst is refused. Format [\"method:cost\"...]"`
+
+	MaxBodyBytes           int64 `toml:"max_body_bytes" desc:"Largest request body the server reads, 0 for no limit"`
+	MaxConcurrent          int   `toml:"max_concurrent" desc:"Requests served at once, 0 for no limit"`
+	MaxConcurrentPerClient int   `toml:"max_concurrent_per_client" desc:"Requests served at once for a single IP address or API key, 0 for no limit"`
+	MaxBatchSize           int   `toml:"max_batch_size" desc:"Calls in a single JSON-RPC batch request, 0 for no limit"`
+
+	LimitLocal bool `toml:"limit_local" desc:"Apply the rate and concurrency limits to clients on this host too"`
+}
+
+func DefaultRPCConfig() *RPCConfig {
+	return &RPCConfig{
+		RateLimit:       20,
+		RateBurst:       40,
+		APIKeyRateLimit: 100,
+		APIKeyRateBurst: 200,
+		MethodCosts: []string{
+			"broadcast.TxSync:5",
+			"broadcast.TxCommit:10",
+			"query.SimulateTx:5",
+			"query.ONS_GetDomainByOwner:10",
+			"query.ONS_GetDomainOnSale:10",
+			"query.ONS_GetDomainByBeneficiary:10",
+			"query.ONS_GetExpiringDomains:10",
+			"/health:5",
+		},
+		MaxBodyBytes:           1 << 20,
+		MaxConcurrent:          100,
+		MaxConcurrentPerClient: 10,
+		MaxBatchSize:           1000,
+		LimitLocal:             false,
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"path/filepath"
 	"strings"
+
+	"github.com/Oneledger/protocol/log"
 )
 
 var setup map[string]func(cfg *Server, value string) error
@@ -11,6 +13,7 @@ var setup map[string]func(cfg *Server, value string) error
 func init() {
 	setup = make(map[string]func(cfg *Server, value string) error)
 	setup["persistent_peers"] = persistent
+	setup["log_level"] = logLevel
 
 }
 
@@ -27,3 +30,15 @@ func persistent(cfg *Server, value string) error {
 	err := cfg.SaveFile(filepath.Join(cfg.rootDir, FileName))
 	return err
 }
+
+// logLevel changes the log levels of a running node, e.g. "debug" or "app=info, event=debug",
+// the change isn't saved to the config file
+func logLevel(cfg *Server, value string) error {
+	levels, err := log.ParseLevels(value)
+	if err != nil {
+		return err
+	}
+	log.SetLevels(levels)
+	cfg.Node.LogLevels = log.FormatLevels(log.Levels())
+	return nil
+}
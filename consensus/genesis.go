@@ -7,12 +7,19 @@ import (
 	"github.com/pkg/errors"
 	"github.com/tendermint/tendermint/types"
 
+	ethchain "github.com/Oneledger/protocol/chains/ethereum"
 	"github.com/Oneledger/protocol/data/balance"
+	"github.com/Oneledger/protocol/data/bitcoin"
+	"github.com/Oneledger/protocol/data/ethereum"
 	"github.com/Oneledger/protocol/data/fees"
+	"github.com/Oneledger/protocol/data/governance"
+	"github.com/Oneledger/protocol/data/history"
 	"github.com/Oneledger/protocol/data/keys"
+	"github.com/Oneledger/protocol/data/ons"
 	"github.com/Oneledger/protocol/identity"
 	"github.com/Oneledger/protocol/serialize"
-	ethchain "github.com/Oneledger/protocol/chains/ethereum"
+	"github.com/Oneledger/protocol/storage"
+	"github.com/Oneledger/protocol/version"
 )
 
 type GenesisDoc = types.GenesisDoc
@@ -44,6 +51,45 @@ type DomainState struct {
 	OwnerAddress   keys.Address `json:"ownerAddress"`
 	AccountAddress keys.Address `json:"accountAddress"`
 	Name           string       `json:"name"`
+
+	// Optional, so that hand written genesis files only need the fields above
+	Inactive  bool          `json:"inactive,omitempty"`
+	OnSale    bool          `json:"onSale,omitempty"`
+	SalePrice *balance.Coin `json:"salePrice,omitempty"`
+	// Blocks left until the domain expires, heights restart with a new chain. Negative while
+	// the domain is in its grace period, 0 for domains that never expire.
+	ExpiresIn int64        `json:"expiresIn,omitempty"`
+	Records   *ons.Records `json:"records,omitempty"`
+	// The domain is the primary name of AccountAddress
+	Primary bool `json:"primary,omitempty"`
+}
+
+// AuctionState is a running name auction with its bids. Heights restart with a new chain, so
+// they are relative to the export height.
+type AuctionState struct {
+	Auction ons.Auction `json:"auction"`
+	Bids    []ons.Bid   `json:"bids"`
+}
+
+// LockScript is a bitcoin lock script kept by the node for a tracker address
+type LockScript struct {
+	Address []byte `json:"address"`
+	Script  []byte `json:"script"`
+}
+
+// BTCTrackerState is a bitcoin tracker with the lock scripts of its addresses
+type BTCTrackerState struct {
+	Tracker     bitcoin.Tracker `json:"tracker"`
+	LockScripts []LockScript    `json:"lockScripts"`
+}
+
+// GovernanceState holds governance options that have no field of their own in AppState
+type GovernanceState struct {
+	Epoch         int64                `json:"epoch,omitempty"`
+	GasSchedule   *storage.GasSchedule `json:"gasSchedule,omitempty"`
+	ReservedNames []string             `json:"reservedNames,omitempty"`
+	// Missing in the genesis of chains started before the upgrades, which then stay off
+	Upgrades *governance.Upgrades `json:"upgrades,omitempty"`
 }
 
 type ChainState struct {
@@ -54,14 +100,35 @@ type ChainState struct {
 type Stake identity.Stake
 
 type AppState struct {
-	Currencies balance.Currencies `json:"currencies"`
-	FeeOption  fees.FeeOption     `json:"feeOption"`
-	ETHCDOption   ethchain.ChainDriverOption `json:"ethchaindriverOption"`
-	Chain      ChainState         `json:"state"`
-	Balances   []BalanceState     `json:"balances"`
-	Staking    []Stake            `json:"staking"`
-	Domains    []DomainState      `json:"domains"`
-	Fees       []BalanceState     `json:"fees"`
+	// Protocol version the state was written with, see MigrateAppState
+	Version     string                     `json:"version,omitempty"`
+	Currencies  balance.Currencies         `json:"currencies"`
+	FeeOption   fees.FeeOption             `json:"feeOption"`
+	ETHCDOption ethchain.ChainDriverOption `json:"ethchaindriverOption"`
+	Governance  GovernanceState            `json:"governance"`
+	Chain       ChainState                 `json:"state"`
+	Balances    []BalanceState             `json:"balances"`
+	Staking     []Stake                    `json:"staking"`
+	Domains     []DomainState              `json:"domains"`
+	Fees        []BalanceState             `json:"fees"`
+
+	// Part of Fees that had already passed the fee lock and can be withdrawn right away
+	FeeWithdrawals []BalanceState `json:"feeWithdrawals,omitempty"`
+
+	// Empty in a new chain, the initial bitcoin trackers are then created from the validators
+	BTCTrackers []BTCTrackerState  `json:"btcTrackers,omitempty"`
+	ETHTrackers []ethereum.Tracker `json:"ethTrackers,omitempty"`
+
+	// Transition logs of the trackers, their heights and tx hashes are those of the chain the
+	// transitions happened on
+	BTCHistory []history.Entry `json:"btcHistory,omitempty"`
+	ETHHistory []history.Entry `json:"ethHistory,omitempty"`
+
+	// Running name auctions, their deposits are part of the escrow balance in Balances
+	Auctions []AuctionState `json:"auctions,omitempty"`
+	// Open domain offers with heights relative to the export height, their amounts are part of
+	// the offer escrow balance in Balances
+	Offers []ons.Offer `json:"offers,omitempty"`
 }
 
 func NewAppState(currencies balance.Currencies,
@@ -73,16 +140,23 @@ func NewAppState(currencies balance.Currencies,
 	ethoptions ethchain.ChainDriverOption,
 ) *AppState {
 	return &AppState{
-		Currencies: currencies,
-		FeeOption:  feeOpt,
-		Balances:   balances,
-		Staking:    staking,
-		Domains:    domains,
-		Fees:       fees,
-		ETHCDOption:ethoptions,
+		Version:     version.Protocol.String(),
+		Currencies:  currencies,
+		FeeOption:   feeOpt,
+		Balances:    balances,
+		Staking:     staking,
+		Domains:     domains,
+		Fees:        fees,
+		ETHCDOption: ethoptions,
+		Governance:  GovernanceState{Upgrades: newChainUpgrades()},
 	}
 }
 
+func newChainUpgrades() *governance.Upgrades {
+	upgrades := governance.NewChainUpgrades()
+	return &upgrades
+}
+
 func (a AppState) RawJSON() ([]byte, error) {
 	szr := serialize.GetSerializer(serialize.JSON)
 	return szr.Serialize(a)
@@ -0,0 +1,170 @@
+package consensus
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ethchain "github.com/Oneledger/protocol/chains/ethereum"
+	"github.com/Oneledger/protocol/data/balance"
+	"github.com/Oneledger/protocol/data/chain"
+	"github.com/Oneledger/protocol/data/fees"
+	"github.com/Oneledger/protocol/data/governance"
+	"github.com/Oneledger/protocol/data/history"
+	"github.com/Oneledger/protocol/data/keys"
+	"github.com/Oneledger/protocol/data/ons"
+	"github.com/Oneledger/protocol/storage"
+)
+
+func testAppState() AppState {
+	olt := balance.Currency{Id: 0, Name: "OLT", Chain: chain.ONELEDGER, Decimal: 18, Unit: "nue"}
+	addr := keys.Address("01234567890123456789")
+	return AppState{
+		Version:    "v0.0.1",
+		Currencies: balance.Currencies{olt},
+		FeeOption:  fees.FeeOption{FeeCurrency: olt, MinFeeDecimal: 9},
+		Balances:   []BalanceState{{Address: addr, Currency: "OLT", Amount: *balance.NewAmount(10)}},
+		Domains:    []DomainState{{OwnerAddress: addr, Name: "alice"}},
+		Fees:       []BalanceState{{Address: addr, Currency: "OLT", Amount: *balance.NewAmount(5)}},
+	}
+}
+
+func TestMigrateAppState(t *testing.T) {
+	defer func() { migrations = map[string]migrationStep{} }()
+
+	RegisterMigration("v0.0.1", "v0.0.2", func(state map[string]json.RawMessage) error {
+		state["domains"] = json.RawMessage("[]")
+		return nil
+	})
+	RegisterMigration("v0.0.2", "v0.0.3", func(state map[string]json.RawMessage) error {
+		delete(state, "fees")
+		return nil
+	})
+
+	raw, err := json.Marshal(testAppState())
+	require.NoError(t, err)
+
+	out, err := migrateAppState(raw, "v0.0.3")
+	require.NoError(t, err)
+	migrated := AppState{}
+	require.NoError(t, json.Unmarshal(out, &migrated))
+	assert.Equal(t, "v0.0.3", migrated.Version)
+	assert.Empty(t, migrated.Domains)
+	assert.Empty(t, migrated.Fees)
+	assert.Len(t, migrated.Balances, 1)
+
+	// already current
+	out, err = migrateAppState(raw, "v0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, raw, out)
+
+	_, err = migrateAppState(raw, "v0.0.4")
+	assert.Equal(t, ErrNoMigration, errors.Cause(err))
+}
+
+func TestAppState_CheckInvariants(t *testing.T) {
+	assert.NoError(t, testAppState().CheckInvariants())
+
+	s := testAppState()
+	s.Balances[0].Currency = "BTC"
+	assert.Equal(t, ErrInvariant, errors.Cause(s.CheckInvariants()))
+
+	s = testAppState()
+	s.Domains = append(s.Domains, DomainState{OwnerAddress: s.Domains[0].OwnerAddress, Name: "ALICE"})
+	assert.Equal(t, ErrInvariant, errors.Cause(s.CheckInvariants()))
+
+	s = testAppState()
+	s.Domains = append(s.Domains, DomainState{OwnerAddress: s.Domains[0].OwnerAddress, Name: "pay.bob"})
+	assert.Equal(t, ErrInvariant, errors.Cause(s.CheckInvariants()))
+	s.Domains[1].Name = "pay.alice"
+	assert.NoError(t, s.CheckInvariants())
+
+	s = testAppState()
+	olt := s.Currencies[0]
+	bid := ons.Bid{Name: "bob", Bidder: s.Balances[0].Address, Deposit: olt.NewCoinFromInt(2)}
+	s.Auctions = []AuctionState{{Auction: ons.Auction{Name: "bob"}, Bids: []ons.Bid{bid}}}
+	assert.Equal(t, ErrInvariant, errors.Cause(s.CheckInvariants()))
+	s.Balances = append(s.Balances, BalanceState{Address: ons.AuctionEscrowAddress(), Currency: "OLT", Amount: *bid.Deposit.Amount})
+	assert.NoError(t, s.CheckInvariants())
+	s.Auctions[0].Auction.Name = "alice"
+	assert.Equal(t, ErrInvariant, errors.Cause(s.CheckInvariants()))
+
+	// deposits are held in the fee currency of the chain
+	s = testAppState()
+	one := balance.Currency{Id: 1, Name: "ONE", Chain: chain.ONELEDGER, Decimal: 18, Unit: "wei"}
+	s.Currencies = append(s.Currencies, one)
+	s.FeeOption.FeeCurrency = one
+	s.Fees = nil
+	bid = ons.Bid{Name: "bob", Bidder: s.Balances[0].Address, Deposit: one.NewCoinFromInt(2)}
+	s.Auctions = []AuctionState{{Auction: ons.Auction{Name: "bob"}, Bids: []ons.Bid{bid}}}
+	s.Balances = append(s.Balances, BalanceState{Address: ons.AuctionEscrowAddress(), Currency: "ONE", Amount: *bid.Deposit.Amount})
+	assert.NoError(t, s.CheckInvariants())
+	s.Auctions[0].Bids[0].Deposit = olt.NewCoinFromInt(2)
+	assert.Equal(t, ErrInvariant, errors.Cause(s.CheckInvariants()))
+
+	s = testAppState()
+	offer := ons.Offer{Name: "alice", Buyer: keys.Address("buyer"), Amount: olt.NewCoinFromInt(3)}
+	s.Offers = []ons.Offer{offer}
+	assert.Equal(t, ErrInvariant, errors.Cause(s.CheckInvariants()))
+	s.Balances = append(s.Balances, BalanceState{Address: ons.OfferEscrowAddress(), Currency: "OLT", Amount: *offer.Amount.Amount})
+	assert.NoError(t, s.CheckInvariants())
+	s.Offers[0].Name = "bob"
+	assert.Equal(t, ErrInvariant, errors.Cause(s.CheckInvariants()))
+
+	s = testAppState()
+	s.FeeWithdrawals = []BalanceState{{Address: s.Fees[0].Address, Currency: "OLT", Amount: *balance.NewAmount(6)}}
+	assert.Equal(t, ErrInvariant, errors.Cause(s.CheckInvariants()))
+	s.FeeWithdrawals[0].Amount = *balance.NewAmount(5)
+	assert.NoError(t, s.CheckInvariants())
+
+	s = testAppState()
+	s.Domains[0].OnSale = true
+	assert.Equal(t, ErrInvariant, errors.Cause(s.CheckInvariants()))
+
+	s = testAppState()
+	schedule := storage.DefaultGasSchedule()
+	s.Governance.GasSchedule = &schedule
+	assert.NoError(t, s.CheckInvariants())
+	schedule.VerifySig = -1
+	assert.Equal(t, ErrInvariant, errors.Cause(s.CheckInvariants()))
+
+	s = testAppState()
+	s.Domains[0].Primary = true
+	assert.NoError(t, s.CheckInvariants())
+	s.Domains = append(s.Domains, DomainState{OwnerAddress: s.Domains[0].OwnerAddress, Name: "bob", Primary: true})
+	assert.Equal(t, ErrInvariant, errors.Cause(s.CheckInvariants()))
+	s.Domains[1].AccountAddress = keys.Address("98765432109876543210")
+	assert.NoError(t, s.CheckInvariants())
+
+	s = testAppState()
+	s.Governance.ReservedNames = []string{"oneledger", "xn--bcher-kva"}
+	assert.NoError(t, s.CheckInvariants())
+	s.Governance.ReservedNames = []string{"OneLedger"}
+	assert.Equal(t, ErrInvariant, errors.Cause(s.CheckInvariants()))
+	s.Governance.ReservedNames = []string{"pay.oneledger"}
+	assert.Equal(t, ErrInvariant, errors.Cause(s.CheckInvariants()))
+
+	s = testAppState()
+	s.BTCHistory = []history.Entry{{Tracker: "a", Index: 0}, {Tracker: "b", Index: 0}, {Tracker: "a", Index: 1}}
+	assert.NoError(t, s.CheckInvariants())
+	s.ETHHistory = []history.Entry{{Tracker: "0x1", Index: 1}}
+	assert.Equal(t, ErrInvariant, errors.Cause(s.CheckInvariants()))
+}
+
+func TestNewAppState_Upgrades(t *testing.T) {
+	base := testAppState()
+	s := NewAppState(base.Currencies, base.FeeOption, base.Balances, nil, base.Domains, base.Fees, ethchain.ChainDriverOption{})
+	require.NotNil(t, s.Governance.Upgrades)
+	assert.True(t, s.Governance.Upgrades.DeleteTrackingAt(1))
+
+	// chains started before the upgrade carry no heights
+	raw, err := json.Marshal(testAppState())
+	require.NoError(t, err)
+	old := AppState{}
+	require.NoError(t, json.Unmarshal(raw, &old))
+	assert.Nil(t, old.Governance.Upgrades)
+	assert.False(t, governance.Upgrades{}.DeleteTrackingAt(100))
+}
@@ -0,0 +1,239 @@
+package consensus
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/Oneledger/protocol/data/balance"
+	"github.com/Oneledger/protocol/data/history"
+	"github.com/Oneledger/protocol/data/ons"
+)
+
+var ErrInvariant = errors.New("app state invariant violated")
+
+func invariantf(format string, args ...interface{}) error {
+	return errors.Wrapf(ErrInvariant, format, args...)
+}
+
+func isNegative(amt balance.Amount) bool {
+	return amt.BigInt().Sign() < 0
+}
+
+// CheckInvariants verifies that an app state is consistent in itself. It runs on every
+// export and again before InitChain loads a state.
+func (a AppState) CheckInvariants() error {
+	currencies := make(map[string]bool)
+	ids := make(map[int64]bool)
+	for _, c := range a.Currencies {
+		if currencies[c.Name] || ids[c.Id] {
+			return invariantf("currency %s registered twice", c.Name)
+		}
+		currencies[c.Name] = true
+		ids[c.Id] = true
+	}
+	if len(a.Currencies) > 0 && !currencies[a.FeeOption.FeeCurrency.Name] {
+		return invariantf("fee currency %s not registered", a.FeeOption.FeeCurrency.Name)
+	}
+
+	if a.Governance.GasSchedule != nil && a.Governance.GasSchedule.Validate() != nil {
+		return invariantf("gas schedule has a negative cost")
+	}
+
+	for _, name := range a.Governance.ReservedNames {
+		if ons.ValidateName(name) != nil || ons.IsSubdomain(name) {
+			return invariantf("reserved name %s is not a normalized top level name", name)
+		}
+	}
+
+	balances := make(map[string]bool)
+	for _, b := range a.Balances {
+		if !currencies[b.Currency] {
+			return invariantf("balance of %s in unregistered currency %s", b.Address, b.Currency)
+		}
+		if isNegative(b.Amount) {
+			return invariantf("negative balance of %s", b.Address)
+		}
+		key := b.Address.String() + "/" + b.Currency
+		if balances[key] {
+			return invariantf("balance of %s in %s listed twice", b.Address, b.Currency)
+		}
+		balances[key] = true
+	}
+
+	validators := make(map[string]bool)
+	for _, s := range a.Staking {
+		if len(s.ValidatorAddress) == 0 {
+			return invariantf("stake without validator address")
+		}
+		if isNegative(s.Amount) {
+			return invariantf("negative stake of %s", s.ValidatorAddress)
+		}
+		if validators[s.ValidatorAddress.String()] {
+			return invariantf("validator %s staked twice", s.ValidatorAddress)
+		}
+		validators[s.ValidatorAddress.String()] = true
+	}
+
+	domains := make(map[string]bool)
+	primaries := make(map[string]bool)
+	for _, d := range a.Domains {
+		name := strings.ToLower(d.Name)
+		if name == "" || len(d.OwnerAddress) == 0 {
+			return invariantf("domain %q without name or owner", d.Name)
+		}
+		if domains[name] {
+			return invariantf("domain %s listed twice", d.Name)
+		}
+		if d.OnSale != (d.SalePrice != nil) {
+			return invariantf("domain %s sale price does not match its sale flag", d.Name)
+		}
+		if d.Records != nil {
+			if err := d.Records.Validate(); err != nil {
+				return invariantf("domain %s: %s", d.Name, err)
+			}
+		}
+		if d.Primary {
+			account := d.AccountAddress
+			if len(account) == 0 {
+				account = d.OwnerAddress
+			}
+			if d.Inactive || primaries[account.String()] {
+				return invariantf("domain %s is primary name of an inactive domain or a second one", d.Name)
+			}
+			primaries[account.String()] = true
+		}
+		domains[name] = true
+	}
+	for _, d := range a.Domains {
+		parent := ons.ParentName(strings.ToLower(d.Name))
+		if parent != "" && !domains[parent] {
+			return invariantf("subdomain %s without its parent", d.Name)
+		}
+	}
+
+	deposits := big.NewInt(0)
+	for _, auction := range a.Auctions {
+		name := strings.ToLower(auction.Auction.Name)
+		if name == "" || domains[name] {
+			return invariantf("auction for %q, which is missing or registered", auction.Auction.Name)
+		}
+		bidders := make(map[string]bool)
+		for _, b := range auction.Bids {
+			if strings.ToLower(b.Name) != name || bidders[b.Bidder.String()] {
+				return invariantf("bid of %s not in auction %s or listed twice", b.Bidder, auction.Auction.Name)
+			}
+			bidders[b.Bidder.String()] = true
+			if b.Deposit.Currency.Name != a.FeeOption.FeeCurrency.Name {
+				return invariantf("deposit of %s for %s not in fee currency", b.Bidder, auction.Auction.Name)
+			}
+			if b.Deposit.Amount != nil {
+				deposits.Add(deposits, b.Deposit.Amount.BigInt())
+			}
+		}
+	}
+	offered := make(map[string]*big.Int)
+	offers := make(map[string]bool)
+	for _, o := range a.Offers {
+		key := strings.ToLower(o.Name) + "/" + o.Buyer.String()
+		if !domains[strings.ToLower(o.Name)] || offers[key] {
+			return invariantf("offer of %s for %s without domain or listed twice", o.Buyer, o.Name)
+		}
+		offers[key] = true
+		if !currencies[o.Amount.Currency.Name] || o.Amount.Amount == nil {
+			return invariantf("offer of %s for %s in unregistered currency", o.Buyer, o.Name)
+		}
+		if offered[o.Amount.Currency.Name] == nil {
+			offered[o.Amount.Currency.Name] = big.NewInt(0)
+		}
+		offered[o.Amount.Currency.Name].Add(offered[o.Amount.Currency.Name], o.Amount.Amount.BigInt())
+	}
+
+	escrow := big.NewInt(0)
+	for _, b := range a.Balances {
+		amount := b.Amount
+		if b.Address.Equal(ons.AuctionEscrowAddress()) && b.Currency == a.FeeOption.FeeCurrency.Name {
+			escrow = amount.BigInt()
+		}
+		if b.Address.Equal(ons.OfferEscrowAddress()) {
+			if offered[b.Currency] == nil || offered[b.Currency].Cmp(amount.BigInt()) != 0 {
+				return invariantf("offer escrow of %s %s does not match the offers", amount.BigInt(), b.Currency)
+			}
+			delete(offered, b.Currency)
+		}
+	}
+	if escrow.Cmp(deposits) != 0 {
+		return invariantf("auction escrow of %s does not match the deposits of %s", escrow, deposits)
+	}
+	for c, amt := range offered {
+		if amt.Sign() != 0 {
+			return invariantf("offers of %s %s missing from the offer escrow", amt, c)
+		}
+	}
+
+	fees := make(map[string]balance.Amount)
+	for _, f := range a.Fees {
+		if f.Currency != a.FeeOption.FeeCurrency.Name {
+			return invariantf("fee of %s not in fee currency", f.Address)
+		}
+		if isNegative(f.Amount) {
+			return invariantf("negative fee of %s", f.Address)
+		}
+		if _, ok := fees[f.Address.String()]; ok {
+			return invariantf("fee of %s listed twice", f.Address)
+		}
+		fees[f.Address.String()] = f.Amount
+	}
+	for _, w := range a.FeeWithdrawals {
+		fee, ok := fees[w.Address.String()]
+		if !ok || isNegative(w.Amount) || fee.BigInt().Cmp(w.Amount.BigInt()) < 0 {
+			return invariantf("fee withdrawal of %s exceeds its fees", w.Address)
+		}
+	}
+
+	trackers := make(map[string]bool)
+	for _, t := range a.BTCTrackers {
+		if t.Tracker.Name == "" || trackers[t.Tracker.Name] {
+			return invariantf("bitcoin tracker %q missing name or listed twice", t.Tracker.Name)
+		}
+		trackers[t.Tracker.Name] = true
+		if t.Tracker.Multisig == nil {
+			return invariantf("bitcoin tracker %s has no multisig", t.Tracker.Name)
+		}
+
+		scripts := make(map[string]bool)
+		for _, s := range t.LockScripts {
+			scripts[string(s.Address)] = true
+		}
+		for _, addr := range [][]byte{t.Tracker.CurrentLockScriptAddress, t.Tracker.ProcessLockScriptAddress} {
+			if len(addr) > 0 && !scripts[string(addr)] {
+				return invariantf("bitcoin tracker %s missing lock script %X", t.Tracker.Name, addr)
+			}
+		}
+	}
+
+	names := make(map[string]bool)
+	for _, t := range a.ETHTrackers {
+		if names[t.TrackerName.String()] {
+			return invariantf("ethereum tracker %s listed twice", t.TrackerName.String())
+		}
+		names[t.TrackerName.String()] = true
+		if len(t.FinalityVotes) != len(t.Validators) {
+			return invariantf("ethereum tracker %s votes do not match its validators", t.TrackerName.String())
+		}
+	}
+
+	for chain, entries := range map[string][]history.Entry{"bitcoin": a.BTCHistory, "ethereum": a.ETHHistory} {
+		// the entries of a tracker are numbered from 0, in order
+		next := make(map[string]int64)
+		for _, e := range entries {
+			if e.Index != next[e.Tracker] {
+				return invariantf("%s history of %s out of order at %d", chain, e.Tracker, e.Index)
+			}
+			next[e.Tracker]++
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package consensus
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/Oneledger/protocol/version"
+)
+
+var ErrNoMigration = errors.New("no migration path for app state version")
+
+// Migration rewrites an exported app state from one protocol version to the next. It works
+// on the raw JSON fields, so it can read fields the current AppState no longer has.
+type Migration func(state map[string]json.RawMessage) error
+
+type migrationStep struct {
+	to      string
+	migrate Migration
+}
+
+// migrations are keyed by the version they upgrade from
+var migrations = map[string]migrationStep{}
+
+// RegisterMigration adds the migration of app states written by protocol version from to
+// protocol version to. Call it from an init function of the release that changes the schema.
+func RegisterMigration(from, to string, fn Migration) {
+	if _, ok := migrations[from]; ok {
+		panic("duplicate app state migration from " + from)
+	}
+	migrations[from] = migrationStep{to: to, migrate: fn}
+}
+
+// MigrateAppState applies the registered migrations to bring an app state up to the
+// current protocol version. States without a version are assumed to be current.
+func MigrateAppState(raw []byte) ([]byte, error) {
+	return migrateAppState(raw, version.Protocol.String())
+}
+
+func migrateAppState(raw []byte, target string) ([]byte, error) {
+	state := make(map[string]json.RawMessage)
+	err := json.Unmarshal(raw, &state)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid app state")
+	}
+
+	current := ""
+	if v, ok := state["version"]; ok {
+		err = json.Unmarshal(v, &current)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid app state version")
+		}
+	}
+	if current == "" || current == target {
+		return raw, nil
+	}
+
+	seen := make(map[string]bool)
+	for current != target {
+		step, ok := migrations[current]
+		if !ok || seen[current] {
+			return nil, errors.Wrapf(ErrNoMigration, "%s to %s", current, target)
+		}
+		seen[current] = true
+
+		err = step.migrate(state)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to migrate app state from %s to %s", current, step.to)
+		}
+		current = step.to
+	}
+
+	state["version"], err = json.Marshal(current)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(state)
+}
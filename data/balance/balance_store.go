@@ -186,3 +186,21 @@ func (st *Store) GetBalanceForCurr(address keys.Address, curr *Currency) (coin C
 
 	return
 }
+
+// IterateUpdated walks the balances changed since the last commit
+func (st *Store) IterateUpdated(fn func(addr keys.Address, c string, amt Amount) bool) bool {
+	return st.State.IterateUpdates(st.prefix, func(key, value []byte) bool {
+		amt := NewAmount(0)
+		err := serialize.GetSerializer(serialize.PERSISTENT).Deserialize(value, amt)
+		if err != nil {
+			return false
+		}
+		// addresses are raw bytes, the currency comes after the last separator
+		key = key[len(st.prefix):]
+		i := strings.LastIndex(string(key), storage.DB_PREFIX)
+		if i < 0 {
+			return false
+		}
+		return fn(keys.Address(key[:i]), string(key[i+len(storage.DB_PREFIX):]), *amt)
+	})
+}
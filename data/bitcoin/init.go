@@ -15,6 +15,24 @@ const (
 	Finalized
 )
 
+var trackerStateNames = map[TrackerState]string{
+	Available:                "available",
+	Requested:                "requested",
+	BusySigning:              "busySigning",
+	BusyScheduleBroadcasting: "busyScheduleBroadcasting",
+	BusyBroadcasting:         "busyBroadcasting",
+	BusyScheduleFinalizing:   "busyScheduleFinalizing",
+	BusyFinalizing:           "busyFinalizing",
+	Finalized:                "finalized",
+}
+
+func (s TrackerState) String() string {
+	if name, ok := trackerStateNames[s]; ok {
+		return name
+	}
+	return "unknown"
+}
+
 const (
 	RESERVE              = "reserveTracker"
 	FREEZE_FOR_BROADCAST = "freezeForBroadcast"
@@ -158,3 +158,25 @@ func (ts *TrackerStore) GetLockScript(lockAddress []byte) ([]byte, error) {
 func keyFromName(name string) []byte {
 	return []byte(strings.ToLower(name))
 }
+
+// IterateUpdated walks the trackers changed since the last commit, prev is the tracker as it
+// was committed and nil for new ones
+func (ts *TrackerStore) IterateUpdated(fn func(prev, tracker *Tracker) bool) bool {
+	return ts.State.IterateUpdates(ts.prefix, func(key, value []byte) bool {
+		d := &Tracker{}
+		err := ts.szlr.Deserialize(value, d)
+		if err != nil {
+			return false
+		}
+
+		var prev *Tracker
+		data, err := ts.State.GetPrevious(0, key)
+		if err == nil && len(data) > 0 {
+			prev = &Tracker{}
+			if ts.szlr.Deserialize(data, prev) != nil {
+				prev = nil
+			}
+		}
+		return fn(prev, d)
+	})
+}
@@ -30,6 +30,22 @@ var (
 	errTrackerInvalidVote = errors.New("vote information is invalid")
 )
 
+var trackerStateNames = map[TrackerState]string{
+	New:              "new",
+	BusyBroadcasting: "busyBroadcasting",
+	BroadcastSuccess: "broadcastSuccess",
+	BusyFinalizing:   "busyFinalizing",
+	Finalized:        "finalized",
+	Released:         "released",
+}
+
+func (s TrackerState) String() string {
+	if name, ok := trackerStateNames[s]; ok {
+		return name
+	}
+	return "unknown"
+}
+
 type ProcessType int8
 
 type Vote uint8
@@ -93,3 +93,25 @@ func (ts *TrackerStore) SetupOption(opt *ethereum.ChainDriverOption) {
 func (ts *TrackerStore) GetOption() *ethereum.ChainDriverOption {
 	return ts.cdOpt
 }
+
+// IterateUpdated walks the trackers changed since the last commit, prev is the tracker as it
+// was committed and nil for new ones
+func (ts *TrackerStore) IterateUpdated(fn func(prev, tracker *Tracker) bool) bool {
+	return ts.state.IterateUpdates(ts.prefix, func(key, value []byte) bool {
+		tracker := &Tracker{}
+		err := ts.szlr.Deserialize(value, tracker)
+		if err != nil {
+			return false
+		}
+
+		var prev *Tracker
+		data, err := ts.state.GetPrevious(0, key)
+		if err == nil && len(data) > 0 {
+			prev = &Tracker{}
+			if ts.szlr.Deserialize(data, prev) != nil {
+				prev = nil
+			}
+		}
+		return fn(prev, tracker)
+	})
+}
@@ -13,12 +13,16 @@ type Store struct {
 	state  *storage.State
 	prefix []byte
 	feeOpt *FeeOption
+
+	// fees that can be withdrawn without waiting for FEE_LOCK_BLOCKS, set from genesis
+	unlockedPrefix []byte
 }
 
 func NewStore(prefix string, state *storage.State) *Store {
 	return &Store{
-		state:  state,
-		prefix: storage.Prefix(prefix),
+		state:          state,
+		prefix:         storage.Prefix(prefix),
+		unlockedPrefix: storage.Prefix("unlocked" + prefix),
 	}
 }
 
@@ -112,7 +116,11 @@ func (st *Store) MinusFromAddress(addr keys.Address, coin balance.Coin) error {
 		return err
 	}
 
-	return st.Set(addr, newCoin)
+	err = st.Set(addr, newCoin)
+	if err != nil {
+		return err
+	}
+	return st.reduceUnlocked(addr, coin)
 }
 
 func (st *Store) AddToPool(coin balance.Coin) error {
@@ -123,19 +131,82 @@ func (st *Store) MinusFromPool(coin balance.Coin) error {
 	return st.MinusFromAddress(keys.Address(POOL_KEY), coin)
 }
 
-func (st *Store) GetAllowedWithdraw(addr keys.Address) balance.Coin {
+// GetAllowedWithdraw returns the fees of addr that are older than FEE_LOCK_BLOCKS. It fails
+// if that version of the chainstate has been pruned.
+func (st *Store) GetAllowedWithdraw(addr keys.Address) (balance.Coin, error) {
 	prefixed := append(st.prefix, addr...)
 
-	data := st.state.GetPrevious(FEE_LOCK_BLOCKS, prefixed)
+	unlocked := st.getUnlocked(addr)
+	data, err := st.state.GetPrevious(FEE_LOCK_BLOCKS, prefixed)
+	if err != nil {
+		return unlocked, errors.Wrap(err, "failed to read locked fees")
+	}
 	amt := balance.Amount{}
-	coin := balance.Coin{}
 	if len(data) == 0 {
-		coin = st.feeOpt.FeeCurrency.NewCoinFromInt(0)
-		return coin
+		return unlocked, nil
+	}
+	err = serialize.GetSerializer(serialize.PERSISTENT).Deserialize(data, &amt)
+	if err != nil {
+		return unlocked, nil
+	}
+
+	coin := st.feeOpt.FeeCurrency.NewCoinFromAmount(amt)
+	if coin.LessThanCoin(unlocked) {
+		return unlocked, nil
+	}
+	return coin, nil
+}
+
+// SetUnlocked marks coin of the fees of addr as withdrawable right away. Used when a chain
+// starts from an exported state, which has no history for the fee lock to look back at.
+func (st *Store) SetUnlocked(addr keys.Address, coin balance.Coin) error {
+	dat, err := serialize.GetSerializer(serialize.PERSISTENT).Serialize(coin.Amount)
+	if err != nil {
+		return err
+	}
+	return st.state.Set(append(st.unlockedPrefix, addr...), dat)
+}
+
+func (st *Store) getUnlocked(addr keys.Address) balance.Coin {
+	dat, _ := st.state.Get(append(st.unlockedPrefix, addr...))
+	amt := balance.NewAmount(0)
+	if len(dat) > 0 {
+		err := serialize.GetSerializer(serialize.PERSISTENT).Deserialize(dat, amt)
+		if err != nil {
+			amt = balance.NewAmount(0)
+		}
 	}
-	err := serialize.GetSerializer(serialize.PERSISTENT).Deserialize(data, &amt)
+	return st.feeOpt.FeeCurrency.NewCoinFromAmount(*amt)
+}
+
+func (st *Store) reduceUnlocked(addr keys.Address, coin balance.Coin) error {
+	key := append(st.unlockedPrefix, addr...)
+	if !st.state.Exists(key) {
+		return nil
+	}
+
+	left, err := st.getUnlocked(addr).Minus(coin)
 	if err != nil {
-		coin = st.feeOpt.FeeCurrency.NewCoinFromInt(0)
+		_, err = st.state.Delete(key)
+		return err
 	}
-	return st.feeOpt.FeeCurrency.NewCoinFromAmount(amt)
+	return st.SetUnlocked(addr, left)
+}
+
+// IterateUnlocked goes through the fees marked by SetUnlocked
+func (st *Store) IterateUnlocked(fn func(addr keys.Address, coin balance.Coin) (stop bool)) bool {
+	return st.state.IterateRange(
+		st.unlockedPrefix,
+		storage.Rangefix(string(st.unlockedPrefix)),
+		true,
+		func(key, value []byte) bool {
+			amt := &balance.Amount{}
+			err := serialize.GetSerializer(serialize.PERSISTENT).Deserialize(value, amt)
+			if err != nil {
+				return false
+			}
+			addr := key[len(st.unlockedPrefix):]
+			return fn(addr, st.feeOpt.FeeCurrency.NewCoinFromAmount(*amt))
+		},
+	)
 }
@@ -2,12 +2,15 @@ package governance
 
 import (
 	"encoding/binary"
+	"sort"
 
 	"github.com/pkg/errors"
 
 	ethchain "github.com/Oneledger/protocol/chains/ethereum"
 	"github.com/Oneledger/protocol/data/balance"
 	"github.com/Oneledger/protocol/data/fees"
+	"github.com/Oneledger/protocol/data/keys"
+	"github.com/Oneledger/protocol/data/ons"
 	"github.com/Oneledger/protocol/serialize"
 	"github.com/Oneledger/protocol/storage"
 )
@@ -20,6 +23,14 @@ const (
 	ADMIN_EPOCH_BLOCK_INTERVAL string = "epoch"
 
 	ADMIN_ETH_CHAINDRIVER_OPTION string = "ethcdopt"
+
+	ADMIN_GAS_SCHEDULE       string = "gasschedule"
+	ADMIN_GAS_SCHEDULE_VOTES string = "gasschedulevotes"
+
+	ADMIN_ONS_RESERVED_NAMES       string = "onsreserved"
+	ADMIN_ONS_RESERVED_NAMES_VOTES string = "onsreservedvotes"
+
+	ADMIN_UPGRADES string = "upgrades"
 )
 
 type Store struct {
@@ -169,3 +180,219 @@ func (st *Store) SetETHChainDriverOption(opt ethchain.ChainDriverOption) error {
 	}
 	return nil
 }
+
+// GetGasSchedule returns the gas schedule set through governance, chains that never set one
+// run on the default schedule
+func (st *Store) GetGasSchedule() (storage.GasSchedule, error) {
+	schedule := storage.DefaultGasSchedule()
+	bytes, err := st.Get([]byte(ADMIN_GAS_SCHEDULE))
+	if err != nil {
+		return schedule, errors.Wrap(err, "failed to get the gas schedule")
+	}
+	if len(bytes) == 0 {
+		return schedule, nil
+	}
+	err = serialize.GetSerializer(serialize.PERSISTENT).Deserialize(bytes, &schedule)
+	if err != nil {
+		return schedule, errors.Wrap(err, "failed to deserialize gas schedule stored")
+	}
+	return schedule, nil
+}
+
+func (st *Store) SetGasSchedule(schedule storage.GasSchedule) error {
+	err := schedule.Validate()
+	if err != nil {
+		return err
+	}
+	bytes, err := serialize.GetSerializer(serialize.PERSISTENT).Serialize(schedule)
+	if err != nil {
+		return errors.Wrap(err, "failed to serialize gas schedule")
+	}
+	err = st.Set([]byte(ADMIN_GAS_SCHEDULE), bytes)
+	if err != nil {
+		return errors.Wrap(err, "failed to set the gas schedule")
+	}
+	return nil
+}
+
+// GasScheduleVote is the gas schedule a validator voted for
+type GasScheduleVote struct {
+	Validator keys.Address        `json:"validator"`
+	Schedule  storage.GasSchedule `json:"schedule"`
+}
+
+// GetGasScheduleVotes returns the votes for a new gas schedule that haven't reached the majority yet
+func (st *Store) GetGasScheduleVotes() ([]GasScheduleVote, error) {
+	votes := make([]GasScheduleVote, 0)
+	bytes, err := st.Get([]byte(ADMIN_GAS_SCHEDULE_VOTES))
+	if err != nil {
+		return votes, errors.Wrap(err, "failed to get the gas schedule votes")
+	}
+	if len(bytes) == 0 {
+		return votes, nil
+	}
+	err = serialize.GetSerializer(serialize.PERSISTENT).Deserialize(bytes, &votes)
+	if err != nil {
+		return votes, errors.Wrap(err, "failed to deserialize gas schedule votes stored")
+	}
+	return votes, nil
+}
+
+func (st *Store) SetGasScheduleVotes(votes []GasScheduleVote) error {
+	bytes, err := serialize.GetSerializer(serialize.PERSISTENT).Serialize(votes)
+	if err != nil {
+		return errors.Wrap(err, "failed to serialize gas schedule votes")
+	}
+	err = st.Set([]byte(ADMIN_GAS_SCHEDULE_VOTES), bytes)
+	if err != nil {
+		return errors.Wrap(err, "failed to set the gas schedule votes")
+	}
+	return nil
+}
+
+// Upgrades holds the heights consensus changes take effect at. A change at height 0 is off, chains
+// that started before a change keep running without it until they are exported to a new chain.
+type Upgrades struct {
+	// reads of the state skip keys deleted in the same block, see storage.State.WithDeleteTracking
+	DeleteTracking int64 `json:"deleteTracking,omitempty"`
+}
+
+// NewChainUpgrades turns every change on from the first block, for chains started with this version
+func NewChainUpgrades() Upgrades {
+	return Upgrades{DeleteTracking: 1}
+}
+
+func (u Upgrades) DeleteTrackingAt(height int64) bool {
+	return u.DeleteTracking > 0 && height >= u.DeleteTracking
+}
+
+// GetUpgrades returns the upgrade heights of the chain, none are set on chains that never set them
+func (st *Store) GetUpgrades() (Upgrades, error) {
+	upgrades := Upgrades{}
+	bytes, err := st.Get([]byte(ADMIN_UPGRADES))
+	if err != nil {
+		return upgrades, errors.Wrap(err, "failed to get the upgrades")
+	}
+	if len(bytes) == 0 {
+		return upgrades, nil
+	}
+	err = serialize.GetSerializer(serialize.PERSISTENT).Deserialize(bytes, &upgrades)
+	if err != nil {
+		return upgrades, errors.Wrap(err, "failed to deserialize upgrades stored")
+	}
+	return upgrades, nil
+}
+
+func (st *Store) SetUpgrades(upgrades Upgrades) error {
+	bytes, err := serialize.GetSerializer(serialize.PERSISTENT).Serialize(upgrades)
+	if err != nil {
+		return errors.Wrap(err, "failed to serialize upgrades")
+	}
+	err = st.Set([]byte(ADMIN_UPGRADES), bytes)
+	if err != nil {
+		return errors.Wrap(err, "failed to set the upgrades")
+	}
+	return nil
+}
+
+// GetReservedNames returns the top level ONS names nobody can register
+func (st *Store) GetReservedNames() ([]string, error) {
+	names := make([]string, 0)
+	bytes, err := st.Get([]byte(ADMIN_ONS_RESERVED_NAMES))
+	if err != nil {
+		return names, errors.Wrap(err, "failed to get the reserved names")
+	}
+	if len(bytes) == 0 {
+		return names, nil
+	}
+	err = serialize.GetSerializer(serialize.PERSISTENT).Deserialize(bytes, &names)
+	if err != nil {
+		return names, errors.Wrap(err, "failed to deserialize reserved names stored")
+	}
+	return names, nil
+}
+
+// NormalizeReservedNames returns the normalized form of the names, sorted and without duplicates.
+// Only top level names can be reserved.
+func NormalizeReservedNames(names []string) ([]string, error) {
+	reserved := make([]string, 0, len(names))
+	seen := make(map[string]bool)
+	for _, name := range names {
+		normalized, err := ons.NormalizeName(name)
+		if err != nil {
+			return nil, errors.Wrap(err, name)
+		}
+		if ons.IsSubdomain(normalized) {
+			return nil, errors.Errorf("only top level names can be reserved: %s", name)
+		}
+		if !seen[normalized] {
+			seen[normalized] = true
+			reserved = append(reserved, normalized)
+		}
+	}
+	sort.Strings(reserved)
+	return reserved, nil
+}
+
+// SetReservedNames stores the normalized form of the names, sorted and without duplicates
+func (st *Store) SetReservedNames(names []string) error {
+	reserved, err := NormalizeReservedNames(names)
+	if err != nil {
+		return err
+	}
+
+	bytes, err := serialize.GetSerializer(serialize.PERSISTENT).Serialize(reserved)
+	if err != nil {
+		return errors.Wrap(err, "failed to serialize reserved names")
+	}
+	err = st.Set([]byte(ADMIN_ONS_RESERVED_NAMES), bytes)
+	if err != nil {
+		return errors.Wrap(err, "failed to set the reserved names")
+	}
+	return nil
+}
+
+// ReservedNamesVote is the list of reserved names a validator voted for
+type ReservedNamesVote struct {
+	Validator keys.Address `json:"validator"`
+	Names     []string     `json:"names"`
+}
+
+// GetReservedNamesVotes returns the votes for new reserved names that haven't reached the majority yet
+func (st *Store) GetReservedNamesVotes() ([]ReservedNamesVote, error) {
+	votes := make([]ReservedNamesVote, 0)
+	bytes, err := st.Get([]byte(ADMIN_ONS_RESERVED_NAMES_VOTES))
+	if err != nil {
+		return votes, errors.Wrap(err, "failed to get the reserved names votes")
+	}
+	if len(bytes) == 0 {
+		return votes, nil
+	}
+	err = serialize.GetSerializer(serialize.PERSISTENT).Deserialize(bytes, &votes)
+	if err != nil {
+		return votes, errors.Wrap(err, "failed to deserialize reserved names votes stored")
+	}
+	return votes, nil
+}
+
+func (st *Store) SetReservedNamesVotes(votes []ReservedNamesVote) error {
+	bytes, err := serialize.GetSerializer(serialize.PERSISTENT).Serialize(votes)
+	if err != nil {
+		return errors.Wrap(err, "failed to serialize reserved names votes")
+	}
+	err = st.Set([]byte(ADMIN_ONS_RESERVED_NAMES_VOTES), bytes)
+	if err != nil {
+		return errors.Wrap(err, "failed to set the reserved names votes")
+	}
+	return nil
+}
+
+// IsReservedName tells whether a normalized top level name is reserved
+func (st *Store) IsReservedName(name string) (bool, error) {
+	names, err := st.GetReservedNames()
+	if err != nil {
+		return false, err
+	}
+	i := sort.SearchStrings(names, name)
+	return i < len(names) && names[i] == name, nil
+}
@@ -0,0 +1,159 @@
+/*
+
+ */
+
+// Package history keeps the log of the state transitions of the bridge trackers
+package history
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/Oneledger/protocol/serialize"
+	"github.com/Oneledger/protocol/storage"
+)
+
+// Entry is a transition of a tracker, from and to are the names of its states. Only the
+// transitions committed to the tracker are kept, a failure can come from the local job store of
+// a validator and would differ between the nodes.
+type Entry struct {
+	Tracker    string `json:"tracker"`
+	Index      int64  `json:"index"`
+	Transition string `json:"transition"`
+	From       string `json:"from"`
+	To         string `json:"to"`
+	Height     int64  `json:"height"`
+	// the last tx of the block that changed the tracker, empty when none did
+	TxHash string `json:"txHash,omitempty"`
+}
+
+// Store is the append-only transition log of the trackers of a chain, the entries of a
+// tracker are numbered from 0 in the order they were added
+type Store struct {
+	State       *storage.State
+	szlr        serialize.Serializer
+	prefix      []byte
+	countPrefix []byte
+}
+
+func NewStore(prefix string, state *storage.State) *Store {
+	return &Store{
+		State:       state,
+		szlr:        serialize.GetSerializer(serialize.PERSISTENT),
+		prefix:      storage.Prefix(prefix + "e"),
+		countPrefix: storage.Prefix(prefix + "n"),
+	}
+}
+
+// WithState updates the storage state of the store and returns the store back
+func (s *Store) WithState(state *storage.State) *Store {
+	s.State = state
+	return s
+}
+
+func (s *Store) trackerKey(tracker string) []byte {
+	return append(append([]byte{}, s.prefix...), tracker+storage.DB_PREFIX...)
+}
+
+func (s *Store) key(tracker string, index int64) storage.StoreKey {
+	return append(s.trackerKey(tracker), fmt.Sprintf("%016x", index)...)
+}
+
+func (s *Store) countKey(tracker string) storage.StoreKey {
+	return append(append([]byte{}, s.countPrefix...), tracker...)
+}
+
+// Count is the number of entries of the tracker
+func (s *Store) Count(tracker string) (int64, error) {
+	data, err := s.State.Get(s.countKey(tracker))
+	if err != nil || len(data) == 0 {
+		return 0, err
+	}
+	n, err := strconv.ParseInt(string(data), 10, 64)
+	return n, errors.Wrap(err, "error reading the entry count")
+}
+
+// Append adds an entry to the log of its tracker and returns it with its index set
+func (s *Store) Append(e Entry) (Entry, error) {
+	n, err := s.Count(e.Tracker)
+	if err != nil {
+		return e, err
+	}
+	e.Index = n
+
+	data, err := s.szlr.Serialize(&e)
+	if err != nil {
+		return e, errors.Wrap(err, "error serializing history entry")
+	}
+	err = s.State.Set(s.key(e.Tracker, n), data)
+	if err != nil {
+		return e, err
+	}
+	return e, s.State.Set(s.countKey(e.Tracker), []byte(strconv.FormatInt(n+1, 10)))
+}
+
+// Last returns the latest entry of the tracker, nil when it has none
+func (s *Store) Last(tracker string) (*Entry, error) {
+	n, err := s.Count(tracker)
+	if err != nil || n == 0 {
+		return nil, err
+	}
+	data, err := s.State.Get(s.key(tracker, n-1))
+	if err != nil {
+		return nil, err
+	}
+	e := &Entry{}
+	err = s.szlr.Deserialize(data, e)
+	if err != nil {
+		return nil, errors.Wrap(err, "error de-serializing history entry")
+	}
+	return e, nil
+}
+
+// Iterate walks the committed entries of the tracker from the oldest
+func (s *Store) Iterate(tracker string, fn func(e *Entry) bool) (stopped bool) {
+	start := s.trackerKey(tracker)
+	return s.State.IterateRange(
+		start,
+		storage.Rangefix(string(start)),
+		true,
+		func(key, value []byte) bool {
+			e := &Entry{}
+			err := s.szlr.Deserialize(value, e)
+			// names may contain the separator, so the range can hold other trackers' entries
+			if err != nil || e.Tracker != tracker {
+				return false
+			}
+			return fn(e)
+		},
+	)
+}
+
+// IterateAll walks the committed entries of all trackers, each tracker from its oldest entry
+func (s *Store) IterateAll(fn func(e *Entry) bool) (stopped bool) {
+	return s.State.IterateRange(
+		s.prefix,
+		storage.Rangefix(string(s.prefix)),
+		true,
+		func(key, value []byte) bool {
+			e := &Entry{}
+			err := s.szlr.Deserialize(value, e)
+			if err != nil {
+				return false
+			}
+			return fn(e)
+		},
+	)
+}
+
+// List returns the committed entries of the tracker from the oldest
+func (s *Store) List(tracker string) []Entry {
+	list := make([]Entry, 0)
+	s.Iterate(tracker, func(e *Entry) bool {
+		list = append(list, *e)
+		return false
+	})
+	return list
+}
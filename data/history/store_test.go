@@ -0,0 +1,76 @@
+package history
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/libs/db"
+
+	"github.com/Oneledger/protocol/storage"
+)
+
+func newTestStore() (*Store, *storage.State) {
+	state := storage.NewState(storage.NewChainState("chainstate", db.NewDB("test", db.MemDBBackend, "")))
+	return NewStore("h", state), state
+}
+
+func TestStore_Append(t *testing.T) {
+	store, state := newTestStore()
+
+	last, err := store.Last("a")
+	require.NoError(t, err)
+	assert.Nil(t, last)
+
+	for i := 0; i < 18; i++ {
+		e, err := store.Append(Entry{Tracker: "a", Transition: "step", Height: int64(i)})
+		require.NoError(t, err)
+		assert.EqualValues(t, i, e.Index)
+	}
+	_, err = store.Append(Entry{Tracker: "a_b", Transition: "other"})
+	require.NoError(t, err)
+
+	// the entries are counted before they're committed
+	last, err = store.Last("a")
+	require.NoError(t, err)
+	assert.EqualValues(t, 17, last.Index)
+	assert.Empty(t, store.List("a"))
+
+	state.Commit()
+	list := store.List("a")
+	require.Len(t, list, 18)
+	for i, e := range list {
+		assert.EqualValues(t, i, e.Index)
+		assert.EqualValues(t, i, e.Height)
+	}
+	assert.Equal(t, []Entry{{Tracker: "a_b", Transition: "other"}}, store.List("a_b"))
+
+	n, err := store.Count("a")
+	require.NoError(t, err)
+	assert.EqualValues(t, 18, n)
+}
+
+func TestStore_IterateAll(t *testing.T) {
+	store, state := newTestStore()
+	for _, tracker := range []string{"b", "a", "b"} {
+		_, err := store.Append(Entry{Tracker: tracker})
+		require.NoError(t, err)
+	}
+	state.Commit()
+
+	all := make([]Entry, 0)
+	store.IterateAll(func(e *Entry) bool {
+		all = append(all, *e)
+		return false
+	})
+	assert.Equal(t, []Entry{{Tracker: "a"}, {Tracker: "b"}, {Tracker: "b", Index: 1}}, all)
+
+	// an exported log appended in order to a new store comes out the same
+	imported, state := newTestStore()
+	for _, e := range all {
+		_, err := imported.Append(e)
+		require.NoError(t, err)
+	}
+	state.Commit()
+	assert.Equal(t, store.List("b"), imported.List("b"))
+}
@@ -4,12 +4,22 @@
 
 package jobs
 
+import (
+	"time"
+)
+
+// Job is run by the job bus until it's done. A run that fails sets the status to Failed and is
+// retried as the retry policy says, a run that neither completes nor fails waits for the next
+// cycle of its chain.
 type Job interface {
 	DoMyJob(ctx interface{})
 	IsDone() bool
 
 	GetType() string
 	GetJobID() string
+
+	// JobState returns the status and the schedule of the job, jobs embed State for it
+	JobState() *State
 }
 
 type Status int
@@ -19,6 +29,90 @@ const (
 	InProgress
 	Completed
 	Failed
-
-	Max_Retry_Count int = 3
+	// out of retries, the job isn't run anymore until it's reset
+	Dead
 )
+
+func (s Status) String() string {
+	switch s {
+	case New:
+		return "new"
+	case InProgress:
+		return "in_progress"
+	case Completed:
+		return "completed"
+	case Failed:
+		return "failed"
+	case Dead:
+		return "dead"
+	}
+	return "unknown"
+}
+
+// State is the status and the schedule every job stores along with its own data
+type State struct {
+	Status Status
+	// failed runs so far
+	Attempts int
+	// unix time in nanoseconds the job runs again at, at the next cycle when 0
+	NextRun   int64
+	LastError string
+}
+
+func (s *State) JobState() *State {
+	return s
+}
+
+func (s *State) IsDone() bool {
+	return s.Status == Completed
+}
+
+// Fail marks the run as failed
+func (s *State) Fail(err error) {
+	s.Status = Failed
+	if err != nil {
+		s.LastError = err.Error()
+	}
+}
+
+// Due tells if the job is to be run at now
+func (s *State) Due(now time.Time) bool {
+	return s.Status != Completed && s.Status != Dead && s.NextRun <= now.UnixNano()
+}
+
+// Reset starts the job over, forgetting its failures
+func (s *State) Reset() {
+	*s = State{}
+}
+
+// RetryPolicy says how often a failed job is retried, the wait between the runs starts at
+// Backoff and doubles up to MaxBackoff
+type RetryPolicy struct {
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+}
+
+// Delay is how long to wait after the attempt-th failed run
+func (p RetryPolicy) Delay(attempt int) time.Duration {
+	delay := p.Backoff
+	for i := 1; i < attempt && delay < p.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	return delay
+}
+
+// Retrier is a job with a retry policy of its own
+type Retrier interface {
+	RetryPolicy(defaults RetryPolicy) RetryPolicy
+}
+
+// Expirer is a job with something to do once it's out of retries, instead of being dead the
+// job is completed once Expire succeeds. Until then it stays failed and runs again after
+// MaxBackoff.
+type Expirer interface {
+	Expire(ctx interface{}) error
+}
@@ -17,11 +17,15 @@ import (
 
 const rootkey = "rootkey"
 
+var ErrNotFound = errors.New("job not found")
+
+// JobStore holds the jobs of every chain, WithChain returns the view of one of them. The
+// views share a lock, the underlying tree can't be read while a session commits.
 type JobStore struct {
 	storage.SessionedStorage
 	chain chain.Type
 	ser   serialize.Serializer
-	lock  sync.Mutex
+	lock  *sync.RWMutex
 }
 
 func NewJobStore(config config.Server, dbDir string) *JobStore {
@@ -32,31 +36,70 @@ func NewJobStore(config config.Server, dbDir string) *JobStore {
 		SessionedStorage: store,
 		chain:            chain.Type(-1),
 		ser:              serialize.GetSerializer(serialize.LOCAL),
-		lock:             sync.Mutex{},
+		lock:             &sync.RWMutex{},
 	}
 }
+
+// WithChain returns the jobs of the chain, the store itself is left as it is
 func (js *JobStore) WithChain(chain chain.Type) *JobStore {
-	js.chain = chain
-	return js
+	return &JobStore{
+		SessionedStorage: js.SessionedStorage,
+		chain:            chain,
+		ser:              js.ser,
+		lock:             js.lock,
+	}
+}
+
+func (js *JobStore) key(jobID string) storage.StoreKey {
+	return storage.StoreKey("job:" + js.chain.String() + ":" + jobID)
 }
 
 func (js *JobStore) SaveJob(job Job) error {
+	dat, err := js.ser.Serialize(job)
+	if err != nil {
+		return err
+	}
 
-	key := storage.StoreKey("job:" + js.chain.String() + ":" + job.GetJobID())
+	js.lock.Lock()
+	defer js.lock.Unlock()
+	return js.set(js.key(job.GetJobID()), dat)
+}
+
+// UpdateJob saves a job only if it's still in the store, ErrNotFound tells it was deleted
+// in the meantime
+func (js *JobStore) UpdateJob(job Job) error {
 	dat, err := js.ser.Serialize(job)
 	if err != nil {
 		return err
 	}
 
+	key := js.key(job.GetJobID())
 	js.lock.Lock()
+	defer js.lock.Unlock()
+	if _, err := js.get(key); err != nil {
+		return errors.Wrap(err, job.GetJobID())
+	}
+	return js.set(key, dat)
+}
+
+// get reads a key, ErrNotFound when the store doesn't have it. The store itself returns no
+// value and no error for keys it never had.
+func (js *JobStore) get(key storage.StoreKey) ([]byte, error) {
+	dat, err := js.Get(key)
+	if err == storage.ErrNotFound || (err == nil && len(dat) == 0) {
+		return nil, ErrNotFound
+	}
+	return dat, err
+}
+
+func (js *JobStore) set(key storage.StoreKey, dat []byte) error {
 	session := js.BeginSession()
-	err = session.Set(key, dat)
+	err := session.Set(key, dat)
 	if err != nil {
 		return err
 	}
 
 	ok := session.Commit()
-	js.lock.Unlock()
 	if !ok {
 		return errors.New("err commiting to job store")
 	}
@@ -64,13 +107,15 @@ func (js *JobStore) SaveJob(job Job) error {
 }
 
 func (js *JobStore) GetJob(jobID string) (Job, error) {
-	key := storage.StoreKey("job:" + js.chain.String() + ":" + jobID)
-	dat, err := js.Get(key)
+	key := js.key(jobID)
+	js.lock.RLock()
+	dat, err := js.get(key)
+	js.lock.RUnlock()
 	if err != nil {
 		return nil, errors.Wrap(err, key.String())
 	}
 	var job Job
-	err = js.ser.Deserialize(dat,&job)
+	err = js.ser.Deserialize(dat, &job)
 	if err != nil {
 		return nil, err
 	}
@@ -79,9 +124,10 @@ func (js *JobStore) GetJob(jobID string) (Job, error) {
 
 func (js *JobStore) DeleteJob(job Job) error {
 
-	key := storage.StoreKey("job:" + js.chain.String() + ":" + job.GetJobID())
+	key := js.key(job.GetJobID())
 
 	js.lock.Lock()
+	defer js.lock.Unlock()
 	session := js.BeginSession()
 
 	_, err := session.Delete(key)
@@ -90,18 +136,20 @@ func (js *JobStore) DeleteJob(job Job) error {
 	}
 
 	ok := session.Commit()
-	js.lock.Unlock()
 	if !ok {
 		return errors.New("error committing to job store")
 	}
 	return nil
 }
 
+// Iterate calls fn with every job of the chain, fn can't use the store
 func (js *JobStore) Iterate(fn func(job Job)) {
 	start := []byte("job:" + js.chain.String() + ":")
 	end := []byte("job:" + js.chain.String() + storage.DB_RANGEFIX)
 	isAsc := true
 
+	js.lock.RLock()
+	defer js.lock.RUnlock()
 	session := js.BeginSession()
 	iter := session.GetIterator()
 
@@ -0,0 +1,73 @@
+package jobs
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Oneledger/protocol/config"
+	"github.com/Oneledger/protocol/data/chain"
+	"github.com/Oneledger/protocol/serialize"
+)
+
+type testJob struct {
+	ID string
+	State
+}
+
+func (j *testJob) DoMyJob(ctx interface{}) {}
+func (j *testJob) GetType() string         { return "test" }
+func (j *testJob) GetJobID() string        { return j.ID }
+
+func init() {
+	serialize.RegisterConcrete(new(testJob), "jobs_test")
+}
+
+func newTestJobStore(t *testing.T) (*JobStore, func()) {
+	dir, err := ioutil.TempDir("", "jobs")
+	require.NoError(t, err)
+	js := NewJobStore(*config.DefaultServerConfig(), dir)
+	return js, func() {
+		js.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestJobStore(t *testing.T) {
+	js, done := newTestJobStore(t)
+	defer done()
+	btc, eth := js.WithChain(chain.BITCOIN), js.WithChain(chain.ETHEREUM)
+
+	_, err := btc.GetJob("a")
+	assert.Equal(t, ErrNotFound, errors.Cause(err))
+
+	// a job deleted while it ran isn't saved back
+	err = btc.UpdateJob(&testJob{ID: "a"})
+	assert.Equal(t, ErrNotFound, errors.Cause(err))
+	_, err = btc.GetJob("a")
+	assert.Equal(t, ErrNotFound, errors.Cause(err))
+
+	require.NoError(t, btc.SaveJob(&testJob{ID: "a"}))
+	require.NoError(t, btc.UpdateJob(&testJob{ID: "a", State: State{Status: Failed, Attempts: 1}}))
+	job, err := btc.GetJob("a")
+	require.NoError(t, err)
+	assert.Equal(t, State{Status: Failed, Attempts: 1}, *job.JobState())
+
+	// the chains don't see each other's jobs
+	_, err = eth.GetJob("a")
+	assert.Equal(t, ErrNotFound, errors.Cause(err))
+	require.NoError(t, eth.SaveJob(&testJob{ID: "b"}))
+	ids := make([]string, 0)
+	btc.Iterate(func(job Job) {
+		ids = append(ids, job.GetJobID())
+	})
+	assert.Equal(t, []string{"a"}, ids)
+
+	require.NoError(t, btc.DeleteJob(job))
+	err = btc.UpdateJob(job)
+	assert.Equal(t, ErrNotFound, errors.Cause(err))
+}
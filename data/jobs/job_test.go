@@ -0,0 +1,42 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 10, Backoff: time.Second, MaxBackoff: 10 * time.Second}
+	for attempt, delay := range []time.Duration{time.Second, time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second} {
+		assert.Equal(t, delay, p.Delay(attempt), "attempt %d", attempt)
+	}
+
+	// without a MaxBackoff the delay stays at Backoff
+	p.MaxBackoff = 0
+	assert.Equal(t, time.Second, p.Delay(5))
+}
+
+func TestState(t *testing.T) {
+	now := time.Now()
+	s := &State{}
+	assert.True(t, s.Due(now))
+
+	s.Fail(errors.New("boom"))
+	assert.Equal(t, Failed, s.Status)
+	assert.Equal(t, "boom", s.LastError)
+	s.NextRun = now.Add(time.Second).UnixNano()
+	assert.False(t, s.Due(now))
+	assert.True(t, s.Due(now.Add(time.Second)))
+
+	s.Status = Dead
+	assert.False(t, s.Due(now.Add(time.Hour)))
+	s.Reset()
+	assert.Equal(t, State{}, *s)
+
+	s.Status = Completed
+	assert.True(t, s.IsDone())
+	assert.False(t, s.Due(now))
+}
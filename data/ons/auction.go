@@ -0,0 +1,88 @@
+/*
+
+ */
+
+package ons
+
+import (
+	"crypto/sha256"
+	"strings"
+
+	"github.com/Oneledger/protocol/data/balance"
+	"github.com/Oneledger/protocol/data/keys"
+)
+
+const (
+	// blocks an auction takes bids for, counted from the first bid
+	AUCTION_COMMIT_BLOCKS = 100
+
+	// blocks after the commit phase during which the bids are revealed
+	AUCTION_REVEAL_BLOCKS = 100
+)
+
+// Auction is a sealed-bid auction for a name nobody holds. Bidders commit to a hidden amount
+// and escrow a deposit covering it, then reveal the amount once bidding is over. The highest
+// revealed bid takes the name and pays its bid.
+type Auction struct {
+	Name        string `json:"name"`
+	StartHeight int64  `json:"startHeight"`
+
+	// last heights bids are committed and revealed at, the auction closes after RevealEnd
+	CommitEnd int64 `json:"commitEnd"`
+	RevealEnd int64 `json:"revealEnd"`
+
+	// highest revealed bid so far
+	Winner     keys.Address `json:"winner"`
+	WinningBid balance.Coin `json:"winningBid"`
+}
+
+func NewAuction(name string, height int64) *Auction {
+	return &Auction{
+		Name:        name,
+		StartHeight: height,
+		CommitEnd:   height + AUCTION_COMMIT_BLOCKS,
+		RevealEnd:   height + AUCTION_COMMIT_BLOCKS + AUCTION_REVEAL_BLOCKS,
+	}
+}
+
+func (a *Auction) IsCommitPhase(height int64) bool {
+	return height <= a.CommitEnd
+}
+
+func (a *Auction) IsRevealPhase(height int64) bool {
+	return height > a.CommitEnd && height <= a.RevealEnd
+}
+
+// IsClosed tells whether the auction is over and waits to be settled
+func (a *Auction) IsClosed(height int64) bool {
+	return height > a.RevealEnd
+}
+
+// Bid is a bidder's sealed bid in an auction, the deposit is held in escrow until the auction
+// is settled
+type Bid struct {
+	Name       string       `json:"name"`
+	Bidder     keys.Address `json:"bidder"`
+	Commitment []byte       `json:"commitment"`
+	Deposit    balance.Coin `json:"deposit"`
+
+	// set once the bid is revealed
+	Revealed bool         `json:"revealed"`
+	Amount   balance.Coin `json:"amount"`
+}
+
+// BidCommitment is the hash a bidder commits to, the salt keeps equal bids from hashing the same
+func BidCommitment(name string, bidder keys.Address, amount balance.Amount, salt []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte(strings.ToLower(name)))
+	h.Write(bidder)
+	h.Write([]byte(amount.String()))
+	h.Write(salt)
+	return h.Sum(nil)
+}
+
+// AuctionEscrowAddress holds the deposits of all running auctions
+func AuctionEscrowAddress() keys.Address {
+	h := sha256.Sum256([]byte("ons_auction_escrow"))
+	return keys.Address(h[:20])
+}
@@ -0,0 +1,156 @@
+/*
+
+ */
+
+package ons
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/Oneledger/protocol/data/keys"
+	"github.com/Oneledger/protocol/storage"
+)
+
+func (ds *DomainStore) auctionKey(name string) storage.StoreKey {
+	return append(append([]byte{}, ds.auctionPrefix...), keyFromName(name)...)
+}
+
+// bidKey groups the bids of an auction together
+func (ds *DomainStore) bidKey(name string, bidder keys.Address) storage.StoreKey {
+	key := string(keyFromName(name)) + storage.DB_PREFIX + bidder.String()
+	return append(append([]byte{}, ds.bidPrefix...), key...)
+}
+
+// auctionEndKey orders auctions by the height they close at
+func (ds *DomainStore) auctionEndKey(height int64, name string) storage.StoreKey {
+	key := fmt.Sprintf("%016x%s%s", uint64(height)^(1<<63), storage.DB_PREFIX, keyFromName(name))
+	return append(append([]byte{}, ds.auctionEndPrefix...), key...)
+}
+
+func (ds *DomainStore) GetAuction(name string) (*Auction, error) {
+	data, err := ds.State.Get(ds.auctionKey(name))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, ErrAuctionNotFound
+	}
+
+	a := &Auction{}
+	err = ds.szlr.Deserialize(data, a)
+	if err != nil {
+		return nil, errors.Wrap(err, "error de-serializing auction")
+	}
+	return a, nil
+}
+
+// SetAuction stores an auction and indexes it by the height it closes at
+func (ds *DomainStore) SetAuction(a *Auction) error {
+	data, err := ds.szlr.Serialize(a)
+	if err != nil {
+		return err
+	}
+
+	err = ds.State.Set(ds.auctionKey(a.Name), data)
+	if err != nil {
+		return err
+	}
+	return ds.State.Set(ds.auctionEndKey(a.RevealEnd+1, a.Name), keyFromName(a.Name))
+}
+
+// DeleteAuction removes a settled auction, its bids are deleted one by one while settling
+func (ds *DomainStore) DeleteAuction(a *Auction) error {
+	_, err := ds.State.Delete(ds.auctionKey(a.Name))
+	if err != nil {
+		return err
+	}
+	_, err = ds.State.Delete(ds.auctionEndKey(a.RevealEnd+1, a.Name))
+	return err
+}
+
+func (ds *DomainStore) GetBid(name string, bidder keys.Address) (*Bid, error) {
+	data, err := ds.State.Get(ds.bidKey(name, bidder))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, ErrBidNotFound
+	}
+
+	b := &Bid{}
+	err = ds.szlr.Deserialize(data, b)
+	if err != nil {
+		return nil, errors.Wrap(err, "error de-serializing bid")
+	}
+	return b, nil
+}
+
+func (ds *DomainStore) SetBid(b *Bid) error {
+	data, err := ds.szlr.Serialize(b)
+	if err != nil {
+		return err
+	}
+	return ds.State.Set(ds.bidKey(b.Name, b.Bidder), data)
+}
+
+func (ds *DomainStore) DeleteBid(b *Bid) error {
+	_, err := ds.State.Delete(ds.bidKey(b.Name, b.Bidder))
+	return err
+}
+
+// IterateBids walks the bids of an auction in order of the bidder address
+func (ds *DomainStore) IterateBids(name string, fn func(bid *Bid) bool) (stopped bool) {
+	start := append(append([]byte{}, ds.bidPrefix...), keyFromName(name)...)
+	return ds.State.IterateRange(
+		append(start, storage.DB_PREFIX...),
+		storage.Rangefix(string(start)),
+		true,
+		func(key, value []byte) bool {
+			b := &Bid{}
+			err := ds.szlr.Deserialize(value, b)
+			if err != nil {
+				return false
+			}
+			// names may contain the separator, so the range can hold other auctions' bids
+			if string(keyFromName(b.Name)) != string(keyFromName(name)) {
+				return false
+			}
+			return fn(b)
+		},
+	)
+}
+
+// IterateAuctions walks all running auctions in order of their names
+func (ds *DomainStore) IterateAuctions(fn func(auction *Auction) bool) (stopped bool) {
+	return ds.State.IterateRange(
+		ds.auctionPrefix,
+		storage.Rangefix(string(ds.auctionPrefix)),
+		true,
+		func(key, value []byte) bool {
+			a := &Auction{}
+			err := ds.szlr.Deserialize(value, a)
+			if err != nil {
+				return false
+			}
+			return fn(a)
+		},
+	)
+}
+
+// IterateClosedAuctions walks the auctions that are closed at height, in order of closing
+func (ds *DomainStore) IterateClosedAuctions(height int64, fn func(auction *Auction) bool) (stopped bool) {
+	return ds.State.IterateRange(
+		ds.auctionEndPrefix,
+		ds.auctionEndKey(height+1, ""),
+		true,
+		func(key, value []byte) bool {
+			a, err := ds.GetAuction(string(value))
+			if err != nil {
+				return false
+			}
+			return fn(a)
+		},
+	)
+}
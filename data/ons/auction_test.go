@@ -0,0 +1,71 @@
+package ons
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Oneledger/protocol/data/balance"
+	"github.com/Oneledger/protocol/data/keys"
+)
+
+func TestAuction_Phases(t *testing.T) {
+	a := NewAuction("alice", 10)
+	assert.True(t, a.IsCommitPhase(10+AUCTION_COMMIT_BLOCKS))
+	assert.False(t, a.IsRevealPhase(10+AUCTION_COMMIT_BLOCKS))
+	assert.True(t, a.IsRevealPhase(11+AUCTION_COMMIT_BLOCKS))
+	assert.True(t, a.IsRevealPhase(a.RevealEnd))
+	assert.True(t, a.IsClosed(a.RevealEnd+1))
+
+	amt := balance.NewAmount(500)
+	c := BidCommitment("Alice", keys.Address("bidder"), *amt, []byte("salt"))
+	assert.Equal(t, c, BidCommitment("alice", keys.Address("bidder"), *amt, []byte("salt")))
+	assert.NotEqual(t, c, BidCommitment("alice", keys.Address("bidder"), *amt, []byte("other")))
+}
+
+func TestDomainStore_Auctions(t *testing.T) {
+	store, state := newTestStore()
+	require.NoError(t, store.SetAuction(NewAuction("alice", 10)))
+	require.NoError(t, store.SetAuction(NewAuction("alice_x", 20)))
+	for _, bidder := range []string{"b1", "b2"} {
+		require.NoError(t, store.SetBid(&Bid{Name: "alice", Bidder: keys.Address(bidder)}))
+	}
+	require.NoError(t, store.SetBid(&Bid{Name: "alice_x", Bidder: keys.Address("b1")}))
+	state.Commit()
+
+	_, err := store.GetAuction("bob")
+	assert.Equal(t, ErrAuctionNotFound, err)
+	_, err = store.GetBid("alice", keys.Address("b3"))
+	assert.Equal(t, ErrBidNotFound, err)
+	b, err := store.GetBid("ALICE", keys.Address("b2"))
+	require.NoError(t, err)
+	assert.Equal(t, keys.Address("b2"), b.Bidder)
+
+	count := 0
+	store.IterateBids("alice", func(b *Bid) bool {
+		assert.Equal(t, "alice", b.Name)
+		count++
+		return false
+	})
+	assert.Equal(t, 2, count)
+
+	closed := func(height int64) []string {
+		names := make([]string, 0)
+		store.IterateClosedAuctions(height, func(a *Auction) bool {
+			names = append(names, a.Name)
+			return false
+		})
+		return names
+	}
+	end := 10 + AUCTION_COMMIT_BLOCKS + AUCTION_REVEAL_BLOCKS
+	assert.Empty(t, closed(int64(end)))
+	assert.Equal(t, []string{"alice"}, closed(int64(end+1)))
+	assert.Equal(t, []string{"alice", "alice_x"}, closed(int64(end+11)))
+
+	a, err := store.GetAuction("alice")
+	require.NoError(t, err)
+	require.NoError(t, store.DeleteAuction(a))
+	state.Commit()
+	assert.Equal(t, []string{"alice_x"}, closed(int64(end+11)))
+}
@@ -5,11 +5,24 @@
 package ons
 
 import (
+	"math"
+	"math/big"
+	"strings"
+
 	"github.com/Oneledger/protocol/data/balance"
 	"github.com/Oneledger/protocol/data/keys"
 )
 
-const HEIGHT_INTERVAL = 1
+const (
+	HEIGHT_INTERVAL = 1
+
+	// blocks a domain is registered for per OLT paid on create or renew
+	BLOCKS_PER_OLT = 1000
+
+	// blocks after expiry during which only the owner can renew a domain, after that the
+	// domain is released and can be created by anyone
+	GRACE_PERIOD_BLOCKS = 100000
+)
 
 type Domain struct {
 	// addresses of the owner and the account the domain points to
@@ -32,6 +45,12 @@ type Domain struct {
 
 	// the asking price in OLT set by the owner
 	SalePrice balance.Coin `json:"salePrice"`
+
+	// last block height the domain is registered for, 0 for domains that never expire
+	ExpireHeight int64 `json:"expireHeight"`
+
+	// addresses on other chains and text records the domain resolves to
+	Records Records `json:"records"`
 }
 
 func NewDomain(ownerAddress, accountAddress keys.Address,
@@ -94,3 +113,50 @@ func (d *Domain) CancelSale() {
 	d.OnSaleFlag = false
 	d.SalePrice = balance.Coin{}
 }
+
+// IsExpired tells whether the registration has run out, the domain stops resolving then
+func (d *Domain) IsExpired(currentHeight int64) bool {
+	return d.ExpireHeight != 0 && currentHeight > d.ExpireHeight
+}
+
+// IsReleased tells whether the grace period after expiry is over as well
+func (d *Domain) IsReleased(currentHeight int64) bool {
+	return d.ExpireHeight != 0 && currentHeight > d.ExpireHeight+GRACE_PERIOD_BLOCKS
+}
+
+func (d *Domain) SetExpireHeight(height int64) {
+	d.ExpireHeight = height
+}
+
+// Renew extends the registration by the blocks paid for, counting from the old expiry
+func (d *Domain) Renew(blocks int64) {
+	d.ExpireHeight += blocks
+}
+
+// ParentName returns the name a subdomain sits under, "pay.alice" for "x.pay.alice". Top level
+// names have no parent and return ""
+func ParentName(name string) string {
+	i := strings.Index(name, ".")
+	if i < 0 {
+		return ""
+	}
+	return name[i+1:]
+}
+
+// IsSubdomain tells whether name is created under a parent domain
+func IsSubdomain(name string) bool {
+	return strings.Contains(name, ".")
+}
+
+// BlocksForPrice returns the number of blocks a domain is registered for with price
+func BlocksForPrice(price balance.Coin) int64 {
+	if price.Amount == nil {
+		return 0
+	}
+	blocks := new(big.Int).Mul(price.Amount.BigInt(), big.NewInt(BLOCKS_PER_OLT))
+	blocks.Div(blocks, price.Currency.Base())
+	if !blocks.IsInt64() {
+		return math.MaxInt64 / 2
+	}
+	return blocks.Int64()
+}
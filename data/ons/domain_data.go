@@ -21,6 +21,10 @@ type domainData struct {
 	OnSaleFlag       bool
 
 	SalePriceData *balance.CoinData
+
+	ExpireHeight int64
+
+	Records Records
 }
 
 func (d *Domain) NewDataInstance() serialize.Data {
@@ -35,6 +39,8 @@ func (d *Domain) Data() serialize.Data {
 		LastUpdateHeight: d.LastUpdateHeight,
 		ActiveFlag:       d.ActiveFlag,
 		OnSaleFlag:       d.OnSaleFlag,
+		ExpireHeight:     d.ExpireHeight,
+		Records:          d.Records,
 	}
 	if d.SalePrice.Amount != nil {
 		dd.SalePriceData = d.SalePrice.Data().(*balance.CoinData)
@@ -56,6 +62,8 @@ func (d *Domain) SetData(a interface{}) error {
 	d.LastUpdateHeight = cd.LastUpdateHeight
 	d.ActiveFlag = cd.ActiveFlag
 	d.OnSaleFlag = cd.OnSaleFlag
+	d.ExpireHeight = cd.ExpireHeight
+	d.Records = cd.Records
 
 	if cd.SalePriceData != nil {
 		err := d.SalePrice.SetData(cd.SalePriceData)
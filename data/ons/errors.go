@@ -7,5 +7,12 @@ package ons
 import "github.com/pkg/errors"
 
 var (
-	ErrDomainNotFound = errors.New("Domain doesn't exist")
+	ErrDomainNotFound  = errors.New("Domain doesn't exist")
+	ErrDomainExpired   = errors.New("Domain expired")
+	ErrInvalidRecords  = errors.New("invalid domain records")
+	ErrAuctionNotFound = errors.New("auction doesn't exist")
+	ErrBidNotFound     = errors.New("bid doesn't exist")
+	ErrOfferNotFound   = errors.New("offer doesn't exist")
+	ErrInvalidName     = errors.New("invalid domain name")
+	ErrPrimaryNotFound = errors.New("no primary name set")
 )
@@ -0,0 +1,135 @@
+/*
+
+ */
+
+package ons
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/idna"
+)
+
+const (
+	// length range of a top level name, counted in characters of its unicode form
+	MIN_NAME_LENGTH = 3
+	MAX_NAME_LENGTH = 63
+
+	// length limit of a full name in its ascii (punycode) form, as for DNS names
+	MAX_FULL_NAME_LENGTH = 253
+)
+
+// names are mapped like a lookup (case folding, width and unicode normalization) and then
+// have to pass the registration rules: letters, digits and hyphens only, valid punycode,
+// bidi rule and DNS label lengths
+var nameProfile = idna.New(
+	idna.MapForLookup(),
+	idna.BidiRule(),
+	idna.VerifyDNSLength(true),
+)
+
+// NormalizeName returns the canonical form of a name, the one domains are stored and
+// looked up under. Unicode labels are converted to punycode, so the canonical form is
+// always lower case ascii.
+func NormalizeName(name string) (string, error) {
+	if name == "" {
+		return "", errors.Wrap(ErrInvalidName, "empty name")
+	}
+
+	normalized, err := nameProfile.ToASCII(name)
+	if err != nil {
+		return "", errors.Wrap(ErrInvalidName, err.Error())
+	}
+	if len(normalized) > MAX_FULL_NAME_LENGTH {
+		return "", errors.Wrapf(ErrInvalidName, "longer than %d characters", MAX_FULL_NAME_LENGTH)
+	}
+
+	display, err := nameProfile.ToUnicode(normalized)
+	if err != nil {
+		return "", errors.Wrap(ErrInvalidName, err.Error())
+	}
+	labels := strings.Split(display, ".")
+	for _, label := range labels {
+		if !singleScript(label) {
+			return "", errors.Wrapf(ErrInvalidName, "label %s mixes scripts", label)
+		}
+	}
+
+	top := []rune(labels[len(labels)-1])
+	if len(top) < MIN_NAME_LENGTH || len(top) > MAX_NAME_LENGTH {
+		return "", errors.Wrapf(ErrInvalidName, "top level names have %d to %d characters",
+			MIN_NAME_LENGTH, MAX_NAME_LENGTH)
+	}
+
+	return normalized, nil
+}
+
+// ValidateName accepts only names that are already in their canonical form, so that two
+// spellings of a name can never refer to different domains
+func ValidateName(name string) error {
+	normalized, err := NormalizeName(name)
+	if err != nil {
+		return err
+	}
+	if normalized != name {
+		return errors.Wrapf(ErrInvalidName, "name is not normalized, use %s", normalized)
+	}
+	return nil
+}
+
+// DisplayName returns the unicode form of a canonical name
+func DisplayName(name string) string {
+	display, err := nameProfile.ToUnicode(name)
+	if err != nil {
+		return name
+	}
+	return display
+}
+
+// scripts that are commonly written together and are not confused with each other
+var compatibleScripts = map[string]string{
+	"Han":      "CJK",
+	"Hiragana": "CJK",
+	"Katakana": "CJK",
+	"Bopomofo": "CJK",
+	"Hangul":   "CJK",
+}
+
+// singleScript rejects labels that mix letters of different scripts, like a latin "a" next
+// to a cyrillic "о", which is how most homoglyph names are made up. Digits and hyphens
+// belong to every script.
+func singleScript(label string) bool {
+	script := ""
+	for _, r := range label {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		s := scriptOf(r)
+		if group, ok := compatibleScripts[s]; ok {
+			s = group
+		}
+		if script == "" {
+			script = s
+		} else if script != s {
+			return false
+		}
+	}
+	return true
+}
+
+func scriptOf(r rune) string {
+	if r < unicode.MaxASCII {
+		return "Latin"
+	}
+	for name, table := range unicode.Scripts {
+		if name == "Common" || name == "Inherited" {
+			continue
+		}
+		if unicode.Is(table, r) {
+			return name
+		}
+	}
+	return "Common"
+}
@@ -0,0 +1,56 @@
+package ons
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeName(t *testing.T) {
+	valid := map[string]string{
+		"alice":         "alice",
+		"ALICE":         "alice",
+		"Pay.Alice":     "pay.alice",
+		"ａｌｉｃｅ":         "alice",
+		"a-1.bob":       "a-1.bob",
+		"bücher":        "xn--bcher-kva",
+		"xn--bcher-kva": "xn--bcher-kva",
+		"пример":        "xn--e1afmkfd",
+		"東京都":           "xn--1lqs71dym3a",
+	}
+	for name, want := range valid {
+		got, err := NormalizeName(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, want, got, name)
+	}
+
+	invalid := []string{
+		"",
+		"al",
+		"東京",
+		"al ice",
+		"alice\n",
+		"alice_x",
+		"-alice",
+		"pay..alice",
+		"alice.",
+		strings.Repeat("a", MAX_NAME_LENGTH+1),
+		strings.Repeat("a", 64) + ".alice",
+		// latin "a" with a cyrillic "о"
+		"bоb",
+	}
+	for _, name := range invalid {
+		_, err := NormalizeName(name)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestValidateName(t *testing.T) {
+	assert.NoError(t, ValidateName("alice"))
+	assert.NoError(t, ValidateName("xn--bcher-kva"))
+	assert.Error(t, ValidateName("Alice"))
+	assert.Error(t, ValidateName("bücher"))
+	assert.Equal(t, "bücher", DisplayName("xn--bcher-kva"))
+}
@@ -0,0 +1,36 @@
+/*
+
+ */
+
+package ons
+
+import (
+	"crypto/sha256"
+
+	"github.com/Oneledger/protocol/data/balance"
+	"github.com/Oneledger/protocol/data/keys"
+)
+
+// Offer is a buyer's standing offer for a registered domain. The amount is held in escrow
+// until the owner accepts the offer, the buyer cancels it or it expires.
+type Offer struct {
+	Name  string       `json:"name"`
+	Buyer keys.Address `json:"buyer"`
+	// the account the domain points to once bought, the buyer if empty
+	Account keys.Address `json:"account"`
+	Amount  balance.Coin `json:"amount"`
+
+	CreationHeight int64 `json:"creationHeight"`
+	// last height the offer can be accepted at
+	ExpireHeight int64 `json:"expireHeight"`
+}
+
+func (o *Offer) IsExpired(height int64) bool {
+	return height > o.ExpireHeight
+}
+
+// OfferEscrowAddress holds the amounts of all open offers
+func OfferEscrowAddress() keys.Address {
+	h := sha256.Sum256([]byte("ons_offer_escrow"))
+	return keys.Address(h[:20])
+}
@@ -0,0 +1,126 @@
+/*
+
+ */
+
+package ons
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/Oneledger/protocol/data/keys"
+	"github.com/Oneledger/protocol/storage"
+)
+
+// offerKey groups the offers for a domain together
+func (ds *DomainStore) offerKey(name string, buyer keys.Address) storage.StoreKey {
+	key := string(keyFromName(name)) + storage.DB_PREFIX + buyer.String()
+	return append(append([]byte{}, ds.offerPrefix...), key...)
+}
+
+// offerEndKey orders offers by their expire height
+func (ds *DomainStore) offerEndKey(height int64, name string, buyer keys.Address) storage.StoreKey {
+	key := fmt.Sprintf("%016x%s%s%s%s", uint64(height)^(1<<63), storage.DB_PREFIX, keyFromName(name), storage.DB_PREFIX, buyer)
+	return append(append([]byte{}, ds.offerEndPrefix...), key...)
+}
+
+func (ds *DomainStore) GetOffer(name string, buyer keys.Address) (*Offer, error) {
+	data, err := ds.State.Get(ds.offerKey(name, buyer))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, ErrOfferNotFound
+	}
+
+	o := &Offer{}
+	err = ds.szlr.Deserialize(data, o)
+	if err != nil {
+		return nil, errors.Wrap(err, "error de-serializing offer")
+	}
+	return o, nil
+}
+
+// SetOffer stores an offer and indexes it by its expire height
+func (ds *DomainStore) SetOffer(o *Offer) error {
+	data, err := ds.szlr.Serialize(o)
+	if err != nil {
+		return err
+	}
+
+	err = ds.State.Set(ds.offerKey(o.Name, o.Buyer), data)
+	if err != nil {
+		return err
+	}
+	return ds.State.Set(ds.offerEndKey(o.ExpireHeight, o.Name, o.Buyer), ds.offerKey(o.Name, o.Buyer))
+}
+
+func (ds *DomainStore) DeleteOffer(o *Offer) error {
+	_, err := ds.State.Delete(ds.offerKey(o.Name, o.Buyer))
+	if err != nil {
+		return err
+	}
+	_, err = ds.State.Delete(ds.offerEndKey(o.ExpireHeight, o.Name, o.Buyer))
+	return err
+}
+
+func (ds *DomainStore) iterateOffers(start, end []byte, fn func(offer *Offer) bool) bool {
+	return ds.State.IterateRange(
+		start,
+		end,
+		true,
+		func(key, value []byte) bool {
+			o := &Offer{}
+			err := ds.szlr.Deserialize(value, o)
+			if err != nil {
+				return false
+			}
+			return fn(o)
+		},
+	)
+}
+
+// IterateOffers walks all open offers in order of the domain name
+func (ds *DomainStore) IterateOffers(fn func(offer *Offer) bool) (stopped bool) {
+	return ds.iterateOffers(ds.offerPrefix, storage.Rangefix(string(ds.offerPrefix)), fn)
+}
+
+// IterateDomainOffers walks the open offers for a domain in order of the buyer address
+func (ds *DomainStore) IterateDomainOffers(name string, fn func(offer *Offer) bool) (stopped bool) {
+	start := append(append([]byte{}, ds.offerPrefix...), keyFromName(name)...)
+	return ds.iterateOffers(
+		append(start, storage.DB_PREFIX...),
+		storage.Rangefix(string(start)),
+		func(o *Offer) bool {
+			// names may contain the separator, so the range can hold other domains' offers
+			if string(keyFromName(o.Name)) != string(keyFromName(name)) {
+				return false
+			}
+			return fn(o)
+		},
+	)
+}
+
+// IterateExpiredOffers walks the offers expired at height, in order of their expire height
+func (ds *DomainStore) IterateExpiredOffers(height int64, fn func(offer *Offer) bool) (stopped bool) {
+	// offers expiring at height sort after the bare height
+	end := fmt.Sprintf("%016x", uint64(height)^(1<<63))
+	return ds.State.IterateRange(
+		ds.offerEndPrefix,
+		append(append([]byte{}, ds.offerEndPrefix...), end...),
+		true,
+		func(key, value []byte) bool {
+			data, err := ds.State.Get(value)
+			if err != nil || len(data) == 0 {
+				return false
+			}
+			o := &Offer{}
+			err = ds.szlr.Deserialize(data, o)
+			if err != nil {
+				return false
+			}
+			return fn(o)
+		},
+	)
+}
@@ -0,0 +1,65 @@
+package ons
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Oneledger/protocol/data/keys"
+)
+
+func TestDomainStore_Offers(t *testing.T) {
+	store, state := newTestStore()
+	offer := func(name, buyer string, expire int64) *Offer {
+		return &Offer{Name: name, Buyer: keys.Address(buyer), ExpireHeight: expire}
+	}
+	require.NoError(t, store.SetOffer(offer("alice", "b1", 10)))
+	require.NoError(t, store.SetOffer(offer("alice", "b2", 20)))
+	require.NoError(t, store.SetOffer(offer("alice_x", "b1", 5)))
+	require.NoError(t, store.SetOffer(offer("0bob", "b1", 10)))
+	state.Commit()
+
+	names := func(iterate func(fn func(*Offer) bool) bool) []string {
+		list := make([]string, 0)
+		iterate(func(o *Offer) bool {
+			list = append(list, o.Name+"/"+string(o.Buyer))
+			return false
+		})
+		return list
+	}
+	assert.Equal(t, []string{"alice/b1", "alice/b2"}, names(func(fn func(*Offer) bool) bool {
+		return store.IterateDomainOffers("alice", fn)
+	}))
+	assert.Equal(t, []string{"alice_x/b1"}, names(func(fn func(*Offer) bool) bool {
+		return store.IterateExpiredOffers(10, fn)
+	}))
+	assert.Equal(t, []string{"alice_x/b1", "0bob/b1", "alice/b1"}, names(func(fn func(*Offer) bool) bool {
+		return store.IterateExpiredOffers(11, fn)
+	}))
+
+	o, err := store.GetOffer("alice", keys.Address("b1"))
+	require.NoError(t, err)
+	require.NoError(t, store.DeleteOffer(o))
+	state.Commit()
+	_, err = store.GetOffer("alice", keys.Address("b1"))
+	assert.Equal(t, ErrOfferNotFound, err)
+	assert.Len(t, names(store.IterateOffers), 3)
+}
+
+func TestDomainStore_DeleteOfferThenRead(t *testing.T) {
+	store, state := newTestStore()
+	o := &Offer{Name: "alice", Buyer: keys.Address("b1"), ExpireHeight: 10}
+	require.NoError(t, store.SetOffer(o))
+	state.Commit()
+
+	// a refunded offer is gone for the rest of the block, before any commit
+	require.NoError(t, store.DeleteOffer(o))
+	_, err := store.GetOffer("alice", keys.Address("b1"))
+	assert.Equal(t, ErrOfferNotFound, err)
+	count := 0
+	store.IterateExpiredOffers(11, func(*Offer) bool { count++; return false })
+	store.IterateDomainOffers("alice", func(*Offer) bool { count++; return false })
+	store.IterateOffers(func(*Offer) bool { count++; return false })
+	assert.Zero(t, count)
+}
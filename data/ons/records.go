@@ -0,0 +1,144 @@
+/*
+
+ */
+
+package ons
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/Oneledger/protocol/data/chain"
+	"github.com/Oneledger/protocol/data/keys"
+)
+
+const (
+	// keys of the text records wallets know how to show
+	TEXT_URL    = "url"
+	TEXT_EMAIL  = "email"
+	TEXT_PUBKEY = "pubkey"
+
+	// bounds of a record set, records live in state so they are kept small
+	MAX_TEXT_RECORDS        = 16
+	MAX_RECORD_LENGTH       = 256
+	MAX_CONTENT_HASH_LENGTH = 64
+)
+
+// AddressRecord is the address a domain resolves to on another chain
+type AddressRecord struct {
+	Chain   chain.Type `json:"chain"`
+	Address string     `json:"address"`
+}
+
+// TextRecord is a free form key value attached to a domain
+type TextRecord struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// Records is what a domain resolves to besides its OneLedger account address
+type Records struct {
+	Addresses   []AddressRecord `json:"addresses"`
+	Texts       []TextRecord    `json:"texts"`
+	ContentHash []byte          `json:"contentHash"`
+}
+
+// Validate checks the shape of a record set, the address formats are left to the caller as
+// they depend on the chain
+func (r Records) Validate() error {
+	chains := make(map[chain.Type]bool)
+	for _, a := range r.Addresses {
+		if a.Chain.String() == "INVALID" {
+			return errors.Wrapf(ErrInvalidRecords, "unknown chain %d", a.Chain)
+		}
+		if chains[a.Chain] {
+			return errors.Wrapf(ErrInvalidRecords, "chain %s listed twice", a.Chain)
+		}
+		if len(a.Address) == 0 || len(a.Address) > MAX_RECORD_LENGTH {
+			return errors.Wrapf(ErrInvalidRecords, "bad %s address length", a.Chain)
+		}
+		chains[a.Chain] = true
+	}
+
+	if len(r.Texts) > MAX_TEXT_RECORDS {
+		return errors.Wrapf(ErrInvalidRecords, "more than %d text records", MAX_TEXT_RECORDS)
+	}
+	texts := make(map[string]bool)
+	for _, t := range r.Texts {
+		if len(t.Key) == 0 || len(t.Key) > MAX_RECORD_LENGTH || len(t.Value) > MAX_RECORD_LENGTH {
+			return errors.Wrapf(ErrInvalidRecords, "bad text record %q", t.Key)
+		}
+		if texts[t.Key] {
+			return errors.Wrapf(ErrInvalidRecords, "text record %q listed twice", t.Key)
+		}
+		texts[t.Key] = true
+	}
+
+	if len(r.ContentHash) > MAX_CONTENT_HASH_LENGTH {
+		return errors.Wrap(ErrInvalidRecords, "content hash too long")
+	}
+	return nil
+}
+
+// Address returns the address record of a chain
+func (r Records) Address(c chain.Type) (string, bool) {
+	for _, a := range r.Addresses {
+		if a.Chain == c {
+			return a.Address, true
+		}
+	}
+	return "", false
+}
+
+// Text returns the text record under key
+func (r Records) Text(key string) (string, bool) {
+	for _, t := range r.Texts {
+		if t.Key == key {
+			return t.Value, true
+		}
+	}
+	return "", false
+}
+
+// SetRecords replaces the record set of a domain. A OneLedger address record points the
+// domain to that account, it is kept as the account address rather than a record.
+func (d *Domain) SetRecords(r Records) error {
+	addresses := make([]AddressRecord, 0, len(r.Addresses))
+	for _, a := range r.Addresses {
+		if a.Chain != chain.ONELEDGER {
+			addresses = append(addresses, a)
+			continue
+		}
+
+		addr := keys.Address{}
+		err := addr.UnmarshalText([]byte(a.Address))
+		if err == nil {
+			err = addr.Err()
+		}
+		if err != nil {
+			return errors.Wrap(ErrInvalidRecords, err.Error())
+		}
+		d.SetAccountAddress(addr)
+	}
+
+	// records are kept sorted so that equal sets serialize the same
+	sort.Slice(addresses, func(i, j int) bool { return addresses[i].Chain < addresses[j].Chain })
+	texts := append([]TextRecord{}, r.Texts...)
+	sort.Slice(texts, func(i, j int) bool { return texts[i].Key < texts[j].Key })
+
+	d.Records = Records{
+		Addresses:   addresses,
+		Texts:       texts,
+		ContentHash: r.ContentHash,
+	}
+	return nil
+}
+
+// Resolve returns the address the domain points to on a chain
+func (d *Domain) Resolve(c chain.Type) (string, bool) {
+	if c == chain.ONELEDGER {
+		return d.AccountAddress.String(), len(d.AccountAddress) > 0
+	}
+	return d.Records.Address(c)
+}
@@ -0,0 +1,61 @@
+package ons
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Oneledger/protocol/data/chain"
+	"github.com/Oneledger/protocol/data/keys"
+)
+
+func TestRecords_Validate(t *testing.T) {
+	r := Records{
+		Addresses: []AddressRecord{{Chain: chain.BITCOIN, Address: "1BoatSLRHtKNngkdXEeobR76b53LETtpyT"}},
+		Texts:     []TextRecord{{Key: TEXT_URL, Value: "https://oneledger.io"}},
+	}
+	assert.NoError(t, r.Validate())
+
+	r.Addresses = append(r.Addresses, AddressRecord{Chain: chain.BITCOIN, Address: "x"})
+	assert.Equal(t, ErrInvalidRecords, errors.Cause(r.Validate()))
+
+	r.Addresses = []AddressRecord{{Chain: chain.Type(9), Address: "x"}}
+	assert.Equal(t, ErrInvalidRecords, errors.Cause(r.Validate()))
+
+	r.Addresses = nil
+	r.ContentHash = make([]byte, MAX_CONTENT_HASH_LENGTH+1)
+	assert.Equal(t, ErrInvalidRecords, errors.Cause(r.Validate()))
+}
+
+func TestDomain_Resolve(t *testing.T) {
+	d := NewDomain(keys.Address("owner"), nil, "alice", 1)
+	eth := "0x1111111111111111111111111111111111111111"
+	account := "0x2222222222222222222222222222222222222222"
+
+	err := d.SetRecords(Records{
+		Addresses: []AddressRecord{
+			{Chain: chain.ETHEREUM, Address: eth},
+			{Chain: chain.ONELEDGER, Address: account},
+		},
+		Texts: []TextRecord{{Key: TEXT_URL, Value: "b"}, {Key: TEXT_EMAIL, Value: "a"}},
+	})
+	require.NoError(t, err)
+
+	addr, ok := d.Resolve(chain.ETHEREUM)
+	assert.True(t, ok)
+	assert.Equal(t, eth, addr)
+	addr, ok = d.Resolve(chain.ONELEDGER)
+	assert.True(t, ok)
+	assert.Equal(t, account, addr)
+	_, ok = d.Resolve(chain.BITCOIN)
+	assert.False(t, ok)
+
+	// the OneLedger address is kept as the account address, texts are sorted
+	assert.Len(t, d.Records.Addresses, 1)
+	assert.Equal(t, TEXT_EMAIL, d.Records.Texts[0].Key)
+
+	err = d.SetRecords(Records{Addresses: []AddressRecord{{Chain: chain.ONELEDGER, Address: "0x12"}}})
+	assert.Equal(t, ErrInvalidRecords, errors.Cause(err))
+}
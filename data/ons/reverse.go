@@ -0,0 +1,189 @@
+/*
+
+ */
+
+package ons
+
+import (
+	"bytes"
+	"encoding/hex"
+
+	"github.com/pkg/errors"
+
+	"github.com/Oneledger/protocol/data/keys"
+	"github.com/Oneledger/protocol/storage"
+)
+
+// addressKey indexes a domain under one of its addresses
+func addressKey(prefix []byte, addr keys.Address, name string) storage.StoreKey {
+	key := hex.EncodeToString(addr) + storage.DB_PREFIX + string(keyFromName(name))
+	return append(append([]byte{}, prefix...), key...)
+}
+
+// indexAddresses moves a domain in the owner and account indexes when its addresses change,
+// the primary name of the account it pointed to is dropped with it
+func (ds *DomainStore) indexAddresses(old, d *Domain) error {
+	if old == nil || !bytes.Equal(old.OwnerAddress, d.OwnerAddress) {
+		if old != nil {
+			_, err := ds.State.Delete(addressKey(ds.ownerPrefix, old.OwnerAddress, old.Name))
+			if err != nil {
+				return errors.Wrap(err, "failed to update owner index")
+			}
+		}
+		err := ds.State.Set(addressKey(ds.ownerPrefix, d.OwnerAddress, d.Name), keyFromName(d.Name))
+		if err != nil {
+			return errors.Wrap(err, "failed to update owner index")
+		}
+	}
+
+	if old == nil || !bytes.Equal(old.AccountAddress, d.AccountAddress) {
+		if old != nil {
+			err := ds.unindexAccount(old)
+			if err != nil {
+				return err
+			}
+		}
+		err := ds.State.Set(addressKey(ds.accountPrefix, d.AccountAddress, d.Name), keyFromName(d.Name))
+		if err != nil {
+			return errors.Wrap(err, "failed to update account index")
+		}
+	}
+	return nil
+}
+
+// unindexAddresses removes a deleted domain from the address indexes
+func (ds *DomainStore) unindexAddresses(d *Domain) error {
+	_, err := ds.State.Delete(addressKey(ds.ownerPrefix, d.OwnerAddress, d.Name))
+	if err != nil {
+		return errors.Wrap(err, "failed to update owner index")
+	}
+	return ds.unindexAccount(d)
+}
+
+func (ds *DomainStore) unindexAccount(d *Domain) error {
+	_, err := ds.State.Delete(addressKey(ds.accountPrefix, d.AccountAddress, d.Name))
+	if err != nil {
+		return errors.Wrap(err, "failed to update account index")
+	}
+
+	primary, err := ds.GetPrimary(d.AccountAddress)
+	if err == ErrPrimaryNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if primary == string(keyFromName(d.Name)) {
+		return ds.DeletePrimary(d.AccountAddress)
+	}
+	return nil
+}
+
+// iterateAddress walks the domains indexed under addr, entries are checked against the
+// domain itself with match
+func (ds *DomainStore) iterateAddress(prefix []byte, addr keys.Address, match func(d *Domain) bool,
+	fn func(domain *Domain) bool) (stopped bool) {
+
+	start := addressKey(prefix, addr, "")
+	return ds.State.IterateRange(
+		start,
+		storage.Rangefix(string(start)),
+		true,
+		func(key, value []byte) bool {
+			// longer addresses sort into the same range
+			if !bytes.HasPrefix(key, start) {
+				return false
+			}
+			d, err := ds.Get(string(value))
+			if err != nil || !match(d) {
+				return false
+			}
+			return fn(d)
+		},
+	)
+}
+
+// IterateOwnerDomains walks the domains owned by owner in order of their names
+func (ds *DomainStore) IterateOwnerDomains(owner keys.Address, fn func(domain *Domain) bool) (stopped bool) {
+	return ds.iterateAddress(ds.ownerPrefix, owner, func(d *Domain) bool {
+		return bytes.Equal(d.OwnerAddress, owner)
+	}, fn)
+}
+
+// IterateAccountDomains walks the domains pointing to account in order of their names
+func (ds *DomainStore) IterateAccountDomains(account keys.Address, fn func(domain *Domain) bool) (stopped bool) {
+	return ds.iterateAddress(ds.accountPrefix, account, func(d *Domain) bool {
+		return bytes.Equal(d.AccountAddress, account)
+	}, fn)
+}
+
+func (ds *DomainStore) primaryKey(addr keys.Address) storage.StoreKey {
+	return append(append([]byte{}, ds.primaryPrefix...), hex.EncodeToString(addr)...)
+}
+
+// GetPrimary returns the name set as primary name of an address
+func (ds *DomainStore) GetPrimary(addr keys.Address) (string, error) {
+	data, err := ds.State.Get(ds.primaryKey(addr))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get primary name")
+	}
+	if len(data) == 0 {
+		return "", ErrPrimaryNotFound
+	}
+	return string(data), nil
+}
+
+// SetPrimary sets the primary name of an address, the caller checks that the name points
+// to the address
+func (ds *DomainStore) SetPrimary(addr keys.Address, name string) error {
+	err := ds.State.Set(ds.primaryKey(addr), keyFromName(name))
+	if err != nil {
+		return errors.Wrap(err, "failed to set primary name")
+	}
+	return nil
+}
+
+func (ds *DomainStore) DeletePrimary(addr keys.Address) error {
+	_, err := ds.State.Delete(ds.primaryKey(addr))
+	if err != nil {
+		return errors.Wrap(err, "failed to delete primary name")
+	}
+	return nil
+}
+
+// IteratePrimaries walks all primary names in order of their addresses
+func (ds *DomainStore) IteratePrimaries(fn func(addr keys.Address, name string) bool) (stopped bool) {
+	return ds.State.IterateRange(
+		ds.primaryPrefix,
+		storage.Rangefix(string(ds.primaryPrefix)),
+		true,
+		func(key, value []byte) bool {
+			addr, err := hex.DecodeString(string(key[len(ds.primaryPrefix):]))
+			if err != nil {
+				return false
+			}
+			return fn(addr, string(value))
+		},
+	)
+}
+
+// ReverseLookup returns the domain set as primary name of an address, as long as the domain
+// is active and still resolves to the address at height
+func (ds *DomainStore) ReverseLookup(addr keys.Address, height int64) (*Domain, error) {
+	name, err := ds.GetPrimary(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := ds.Get(name)
+	if err == ErrDomainNotFound {
+		return nil, ErrPrimaryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !d.ActiveFlag || d.IsExpired(height) || !bytes.Equal(d.AccountAddress, addr) {
+		return nil, ErrPrimaryNotFound
+	}
+	return d, nil
+}
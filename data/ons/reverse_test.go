@@ -0,0 +1,86 @@
+package ons
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Oneledger/protocol/data/keys"
+)
+
+func addressNames(iterate func(addr keys.Address, fn func(*Domain) bool) bool, addr string) []string {
+	names := make([]string, 0)
+	iterate(keys.Address(addr), func(d *Domain) bool {
+		names = append(names, d.Name)
+		return false
+	})
+	return names
+}
+
+func TestDomainStore_AddressIndexes(t *testing.T) {
+	store, state := newTestStore()
+	require.NoError(t, store.Set(NewDomain(keys.Address("a"), nil, "alice", 1)))
+	require.NoError(t, store.Set(NewDomain(keys.Address("a"), keys.Address("b"), "carol", 1)))
+	require.NoError(t, store.Set(NewDomain(keys.Address("ab"), nil, "bob", 1)))
+	state.Commit()
+
+	assert.Equal(t, []string{"alice", "carol"}, addressNames(store.IterateOwnerDomains, "a"))
+	assert.Equal(t, []string{"alice"}, addressNames(store.IterateAccountDomains, "a"))
+	assert.Equal(t, []string{"carol"}, addressNames(store.IterateAccountDomains, "b"))
+
+	// transfers move the domain, also when it comes back in the same block
+	d, err := store.Get("alice")
+	require.NoError(t, err)
+	d.ChangeOwner(keys.Address("b"))
+	require.NoError(t, store.Set(d))
+	d.ChangeOwner(keys.Address("a"))
+	require.NoError(t, store.Set(d))
+	d, err = store.Get("carol")
+	require.NoError(t, err)
+	d.ChangeOwner(keys.Address("b"))
+	require.NoError(t, store.Set(d))
+	state.Commit()
+
+	assert.Equal(t, []string{"alice"}, addressNames(store.IterateOwnerDomains, "a"))
+	assert.Equal(t, []string{"carol"}, addressNames(store.IterateOwnerDomains, "b"))
+
+	require.NoError(t, store.Delete("bob"))
+	state.Commit()
+	assert.Empty(t, addressNames(store.IterateOwnerDomains, "ab"))
+}
+
+func TestDomainStore_Primary(t *testing.T) {
+	store, state := newTestStore()
+	require.NoError(t, store.Set(NewDomain(keys.Address("a"), nil, "alice", 1)))
+	require.NoError(t, store.Set(newExpiringDomain("carol", 10)))
+	require.NoError(t, store.SetPrimary(keys.Address("a"), "alice"))
+	require.NoError(t, store.SetPrimary(keys.Address("owner"), "carol"))
+	state.Commit()
+
+	d, err := store.ReverseLookup(keys.Address("a"), 5)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", d.Name)
+	d, err = store.ReverseLookup(keys.Address("owner"), 10)
+	require.NoError(t, err)
+	assert.Equal(t, "carol", d.Name)
+	_, err = store.ReverseLookup(keys.Address("owner"), 11)
+	assert.Equal(t, ErrPrimaryNotFound, err)
+
+	// pointing the domain elsewhere drops the primary name
+	d, err = store.Get("alice")
+	require.NoError(t, err)
+	d.SetAccountAddress(keys.Address("b"))
+	require.NoError(t, store.Set(d))
+	state.Commit()
+	_, err = store.GetPrimary(keys.Address("a"))
+	assert.Equal(t, ErrPrimaryNotFound, err)
+
+	// so does releasing it
+	released, err := store.ReleaseExpired(11 + GRACE_PERIOD_BLOCKS)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"carol"}, released)
+	state.Commit()
+	_, err = store.GetPrimary(keys.Address("owner"))
+	assert.Equal(t, ErrPrimaryNotFound, err)
+}
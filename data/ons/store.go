@@ -5,6 +5,8 @@
 package ons
 
 import (
+	"bytes"
+	"fmt"
 	"strings"
 
 	"github.com/Oneledger/protocol/serialize"
@@ -18,6 +20,27 @@ type DomainStore struct {
 	State  *storage.State
 	szlr   serialize.Serializer
 	prefix []byte
+
+	// index of domain names by expire height
+	expirePrefix []byte
+
+	// index of subdomains by their parent
+	subPrefix []byte
+
+	// running auctions, their bids and an index of auctions by closing height
+	auctionPrefix    []byte
+	bidPrefix        []byte
+	auctionEndPrefix []byte
+
+	// open offers and an index of offers by expire height
+	offerPrefix    []byte
+	offerEndPrefix []byte
+
+	// indexes of domains by owner and by the account they point to, and the primary name
+	// of an account
+	ownerPrefix   []byte
+	accountPrefix []byte
+	primaryPrefix []byte
 }
 
 // NewDomainStore creates a new storage object from filepath and other configurations
@@ -27,6 +50,20 @@ func NewDomainStore(prefix string, state *storage.State) *DomainStore {
 		State:  state,
 		szlr:   serialize.GetSerializer(serialize.PERSISTENT),
 		prefix: storage.Prefix(prefix),
+
+		expirePrefix: storage.Prefix("expire" + prefix),
+		subPrefix:    storage.Prefix("sub" + prefix),
+
+		auctionPrefix:    storage.Prefix("auction" + prefix),
+		bidPrefix:        storage.Prefix("auctionbid" + prefix),
+		auctionEndPrefix: storage.Prefix("auctionend" + prefix),
+
+		offerPrefix:    storage.Prefix("offer" + prefix),
+		offerEndPrefix: storage.Prefix("offerend" + prefix),
+
+		ownerPrefix:   storage.Prefix("owner" + prefix),
+		accountPrefix: storage.Prefix("account" + prefix),
+		primaryPrefix: storage.Prefix("primary" + prefix),
 	}
 }
 
@@ -35,8 +72,23 @@ func (ds *DomainStore) WithState(state *storage.State) *DomainStore {
 	return ds
 }
 
-// Get is used to retrieve the domain object from the domain name
+// Get is used to retrieve the domain object from the domain name. Subdomains are returned with
+// the expire height of their top level domain.
 func (ds *DomainStore) Get(name string) (*Domain, error) {
+	d, err := ds.get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ds.inheritExpiry(d)
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// get reads a domain as it is stored
+func (ds *DomainStore) get(name string) (*Domain, error) {
 	key := keyFromName(name)
 	key = append(ds.prefix, key...)
 	exists := ds.State.Exists(key)
@@ -55,9 +107,60 @@ func (ds *DomainStore) Get(name string) (*Domain, error) {
 	return d, nil
 }
 
+// inheritExpiry sets the expire height of a subdomain to the one of its top level domain,
+// subdomains whose parent is gone are not found
+func (ds *DomainStore) inheritExpiry(d *Domain) error {
+	if !IsSubdomain(d.Name) {
+		return nil
+	}
+
+	parent := ParentName(d.Name)
+	for IsSubdomain(parent) {
+		parent = ParentName(parent)
+	}
+	root, err := ds.get(parent)
+	if err != nil {
+		return err
+	}
+	d.ExpireHeight = root.ExpireHeight
+	return nil
+}
+
 func (ds *DomainStore) Set(d *Domain) error {
 	key := keyFromName(d.Name)
 
+	if IsSubdomain(d.Name) {
+		// subdomains follow the expiry of their parent, they are indexed under it instead
+		sub := *d
+		sub.ExpireHeight = 0
+		d = &sub
+
+		err := ds.State.Set(ds.subKey(ParentName(d.Name), d.Name), keyFromName(d.Name))
+		if err != nil {
+			return errors.Wrap(err, "failed to update subdomain index")
+		}
+	}
+
+	var old *Domain
+	if ds.Exists(d.Name) {
+		var err error
+		old, err = ds.get(d.Name)
+		if err != nil {
+			return err
+		}
+		if old.ExpireHeight != 0 && old.ExpireHeight != d.ExpireHeight {
+			_, err = ds.State.Delete(ds.expireKey(old.ExpireHeight, old.Name))
+			if err != nil {
+				return errors.Wrap(err, "failed to update expire index")
+			}
+		}
+	}
+
+	err := ds.indexAddresses(old, d)
+	if err != nil {
+		return err
+	}
+
 	data, err := ds.szlr.Serialize(d)
 	if err != nil {
 		return err
@@ -69,9 +172,43 @@ func (ds *DomainStore) Set(d *Domain) error {
 		return err
 	}
 
+	if d.ExpireHeight != 0 {
+		err = ds.State.Set(ds.expireKey(d.ExpireHeight, d.Name), keyFromName(d.Name))
+		if err != nil {
+			return errors.Wrap(err, "failed to update expire index")
+		}
+	}
+
 	return nil
 }
 
+// Delete removes a domain together with its entries in the expire, subdomain and address
+// indexes. The subdomains and open offers of the domain are left to the caller.
+func (ds *DomainStore) Delete(name string) error {
+	d, err := ds.get(name)
+	if err != nil {
+		return err
+	}
+
+	_, err = ds.State.Delete(append(ds.prefix, keyFromName(name)...))
+	if err != nil {
+		return err
+	}
+	if d.ExpireHeight != 0 {
+		_, err = ds.State.Delete(ds.expireKey(d.ExpireHeight, d.Name))
+		if err != nil {
+			return err
+		}
+	}
+	if IsSubdomain(d.Name) {
+		_, err = ds.State.Delete(ds.subKey(ParentName(d.Name), d.Name))
+		if err != nil {
+			return err
+		}
+	}
+	return ds.unindexAddresses(d)
+}
+
 func (ds *DomainStore) Exists(name string) bool {
 	key := keyFromName(name)
 	key = append(ds.prefix, key...)
@@ -94,7 +231,139 @@ func (ds *DomainStore) Iterate(fn func(name string, domain *Domain) bool) (stopp
 			if err != nil {
 				return false
 			}
+			if ds.inheritExpiry(domain) != nil {
+				// left over from a released parent
+				return false
+			}
 			return fn(name, domain)
 		},
 	)
 }
+
+// subKey indexes a subdomain under its direct parent
+func (ds *DomainStore) subKey(parent, name string) storage.StoreKey {
+	key := string(keyFromName(parent)) + storage.DB_PREFIX + string(keyFromName(name))
+	return append(append([]byte{}, ds.subPrefix...), key...)
+}
+
+// IterateSubdomains walks the direct subdomains of parent in order of their names
+func (ds *DomainStore) IterateSubdomains(parent string, fn func(domain *Domain) bool) (stopped bool) {
+	start := ds.subKey(parent, "")
+	return ds.State.IterateRange(
+		start,
+		storage.Rangefix(string(start[:len(start)-len(storage.DB_PREFIX)])),
+		true,
+		func(key, value []byte) bool {
+			// names may contain the separator, so the range can hold other parents' entries
+			if ParentName(string(value)) != string(keyFromName(parent)) {
+				return false
+			}
+			d, err := ds.Get(string(value))
+			if err != nil {
+				return false
+			}
+			return fn(d)
+		},
+	)
+}
+
+// expireKey orders the index by height, heights are offset so that negative ones, which
+// domains imported in their grace period have, sort first
+func (ds *DomainStore) expireKey(height int64, name string) storage.StoreKey {
+	key := fmt.Sprintf("%016x%s%s", uint64(height)^(1<<63), storage.DB_PREFIX, keyFromName(name))
+	return append(append([]byte{}, ds.expirePrefix...), key...)
+}
+
+// IterateExpiring walks the domains expiring from height from to height to, both included, in
+// order of their expire height
+func (ds *DomainStore) IterateExpiring(from, to int64, fn func(domain *Domain) bool) (stopped bool) {
+	if to < from {
+		return false
+	}
+	return ds.State.IterateRange(
+		ds.expireKey(from, ""),
+		ds.expireKey(to+1, ""),
+		true,
+		func(key, value []byte) bool {
+			d, err := ds.Get(string(value))
+			if err != nil {
+				return false
+			}
+			return fn(d)
+		},
+	)
+}
+
+// ReleaseExpired deletes the domains whose grace period is over at height and returns their names.
+// Offers for them are left to the caller, which refunds them.
+func (ds *DomainStore) ReleaseExpired(height int64) ([]string, error) {
+	// the index is read from the last commit, domains changed in this block are checked below
+	names := make([]string, 0)
+	keys := make([]storage.StoreKey, 0)
+	ds.State.IterateRange(
+		ds.expirePrefix,
+		ds.expireKey(height-GRACE_PERIOD_BLOCKS, ""),
+		true,
+		func(key, value []byte) bool {
+			names = append(names, string(value))
+			keys = append(keys, append([]byte{}, key...))
+			return false
+		},
+	)
+
+	released := make([]string, 0, len(names))
+	for i, name := range names {
+		d, err := ds.Get(name)
+		if err != nil && err != ErrDomainNotFound {
+			return released, err
+		}
+
+		if err == nil && d.IsReleased(height) {
+			subs, err := ds.releaseSubdomains(name)
+			released = append(released, subs...)
+			if err != nil {
+				return released, err
+			}
+
+			err = ds.Delete(name)
+			if err != nil {
+				return released, errors.Wrapf(err, "failed to release domain %s", name)
+			}
+			released = append(released, name)
+		}
+
+		// drop entries left behind by domains that were renewed or deleted
+		if err != nil || !bytes.Equal(keys[i], ds.expireKey(d.ExpireHeight, d.Name)) {
+			_, err = ds.State.Delete(keys[i])
+			if err != nil {
+				return released, err
+			}
+		}
+	}
+	return released, nil
+}
+
+// releaseSubdomains deletes all subdomains below parent, deepest first
+func (ds *DomainStore) releaseSubdomains(parent string) ([]string, error) {
+	names := make([]string, 0)
+	ds.IterateSubdomains(parent, func(d *Domain) bool {
+		names = append(names, d.Name)
+		return false
+	})
+
+	released := make([]string, 0)
+	for _, name := range names {
+		subs, err := ds.releaseSubdomains(name)
+		released = append(released, subs...)
+		if err != nil {
+			return released, err
+		}
+
+		err = ds.Delete(name)
+		if err != nil {
+			return released, errors.Wrapf(err, "failed to release subdomain %s", name)
+		}
+		released = append(released, name)
+	}
+	return released, nil
+}
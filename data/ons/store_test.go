@@ -0,0 +1,155 @@
+package ons
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/libs/db"
+
+	"github.com/Oneledger/protocol/data/keys"
+	"github.com/Oneledger/protocol/storage"
+)
+
+func newTestStore() (*DomainStore, *storage.State) {
+	state := storage.NewState(storage.NewChainState("chainstate", db.NewDB("test", db.MemDBBackend, ""))).WithDeleteTracking(true)
+	return NewDomainStore("d", state), state
+}
+
+func newExpiringDomain(name string, expire int64) *Domain {
+	d := NewDomain(keys.Address("owner"), nil, name, 1)
+	d.SetExpireHeight(expire)
+	return d
+}
+
+func expiringNames(store *DomainStore, from, to int64) []string {
+	names := make([]string, 0)
+	store.IterateExpiring(from, to, func(d *Domain) bool {
+		names = append(names, d.Name)
+		return false
+	})
+	return names
+}
+
+func TestDomain_Expiry(t *testing.T) {
+	d := newExpiringDomain("alice", 100)
+	assert.False(t, d.IsExpired(100))
+	assert.True(t, d.IsExpired(101))
+	assert.False(t, d.IsReleased(100+GRACE_PERIOD_BLOCKS))
+	assert.True(t, d.IsReleased(101+GRACE_PERIOD_BLOCKS))
+
+	d.Renew(50)
+	assert.Equal(t, int64(150), d.ExpireHeight)
+
+	// domains without an expire height are held forever
+	d = NewDomain(keys.Address("owner"), nil, "bob", 1)
+	assert.False(t, d.IsExpired(1<<40))
+	assert.False(t, d.IsReleased(1<<40))
+}
+
+func TestDomainStore_Expiring(t *testing.T) {
+	store, state := newTestStore()
+	require.NoError(t, store.Set(newExpiringDomain("a", 10)))
+	require.NoError(t, store.Set(newExpiringDomain("b", 20)))
+	require.NoError(t, store.Set(newExpiringDomain("c", -5)))
+	require.NoError(t, store.Set(NewDomain(keys.Address("owner"), nil, "forever", 1)))
+	state.Commit()
+
+	assert.Equal(t, []string{"c", "a", "b"}, expiringNames(store, -10, 20))
+	assert.Equal(t, []string{"a"}, expiringNames(store, 10, 19))
+
+	// renewing moves the domain in the index
+	d, err := store.Get("a")
+	require.NoError(t, err)
+	d.Renew(100)
+	require.NoError(t, store.Set(d))
+	state.Commit()
+	assert.Equal(t, []string{"b"}, expiringNames(store, 0, 100))
+	assert.Equal(t, []string{"a"}, expiringNames(store, 110, 110))
+}
+
+func TestDomainStore_ReleaseExpired(t *testing.T) {
+	store, state := newTestStore()
+	require.NoError(t, store.Set(newExpiringDomain("a", 10)))
+	require.NoError(t, store.Set(newExpiringDomain("b", 20)))
+	state.Commit()
+
+	released, err := store.ReleaseExpired(10 + GRACE_PERIOD_BLOCKS)
+	require.NoError(t, err)
+	assert.Empty(t, released)
+
+	// renewed in the same block as it would be released
+	d, err := store.Get("b")
+	require.NoError(t, err)
+	d.Renew(1000)
+	require.NoError(t, store.Set(d))
+
+	released, err = store.ReleaseExpired(21 + GRACE_PERIOD_BLOCKS)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, released)
+	state.Commit()
+
+	assert.False(t, store.Exists("a"))
+	assert.True(t, store.Exists("b"))
+	assert.Equal(t, []string{"b"}, expiringNames(store, 0, 2000))
+}
+
+func subdomainNames(store *DomainStore, parent string) []string {
+	names := make([]string, 0)
+	store.IterateSubdomains(parent, func(d *Domain) bool {
+		names = append(names, d.Name)
+		return false
+	})
+	return names
+}
+
+func TestParentName(t *testing.T) {
+	assert.Equal(t, "", ParentName("alice"))
+	assert.Equal(t, "alice", ParentName("pay.alice"))
+	assert.Equal(t, "pay.alice", ParentName("x.pay.alice"))
+	assert.False(t, IsSubdomain("alice"))
+	assert.True(t, IsSubdomain("pay.alice"))
+}
+
+func TestDomainStore_Subdomains(t *testing.T) {
+	store, state := newTestStore()
+	require.NoError(t, store.Set(newExpiringDomain("alice", 10)))
+	require.NoError(t, store.Set(newExpiringDomain("alice_x", 10)))
+	require.NoError(t, store.Set(NewDomain(keys.Address("owner"), nil, "pay.alice", 1)))
+	require.NoError(t, store.Set(NewDomain(keys.Address("owner"), nil, "hr.alice", 1)))
+	require.NoError(t, store.Set(NewDomain(keys.Address("owner"), nil, "x.pay.alice", 1)))
+	require.NoError(t, store.Set(NewDomain(keys.Address("owner"), nil, "pay.alice_x", 1)))
+	state.Commit()
+
+	assert.Equal(t, []string{"hr.alice", "pay.alice"}, subdomainNames(store, "alice"))
+	assert.Equal(t, []string{"x.pay.alice"}, subdomainNames(store, "pay.alice"))
+	assert.Empty(t, subdomainNames(store, "hr.alice"))
+
+	// subdomains follow the expiry of the top level domain and are not indexed by it
+	d, err := store.Get("x.pay.alice")
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), d.ExpireHeight)
+	assert.Equal(t, []string{"alice", "alice_x"}, expiringNames(store, 0, 100))
+
+	parent, err := store.Get("alice")
+	require.NoError(t, err)
+	parent.Renew(100)
+	require.NoError(t, store.Set(parent))
+	d, err = store.Get("pay.alice")
+	require.NoError(t, err)
+	assert.Equal(t, int64(110), d.ExpireHeight)
+	state.Commit()
+
+	// releasing a domain releases everything below it
+	released, err := store.ReleaseExpired(11 + GRACE_PERIOD_BLOCKS)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pay.alice_x", "alice_x"}, released)
+	state.Commit()
+	released, err = store.ReleaseExpired(111 + GRACE_PERIOD_BLOCKS)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"hr.alice", "x.pay.alice", "pay.alice", "alice"}, released)
+	state.Commit()
+
+	assert.False(t, store.Exists("x.pay.alice"))
+	assert.Empty(t, subdomainNames(store, "alice"))
+}
@@ -7,9 +7,8 @@ package event
 import (
 	"bytes"
 	"encoding/hex"
+	"errors"
 
-	"github.com/btcsuite/btcd/btcec"
-	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcutil"
 
@@ -25,7 +24,7 @@ type JobAddSignature struct {
 
 	JobID string
 
-	Status jobs.Status
+	jobs.State
 }
 
 func NewAddSignatureJob(trackerName, id string) jobs.Job {
@@ -34,7 +33,6 @@ func NewAddSignatureJob(trackerName, id string) jobs.Job {
 		Type:        JobTypeAddSignature,
 		TrackerName: trackerName,
 		JobID:       id,
-		Status:      jobs.New,
 	}
 }
 
@@ -48,16 +46,18 @@ func (j *JobAddSignature) DoMyJob(ctxI interface{}) {
 	tracker, err := ctx.Trackers.Get(j.TrackerName)
 	if err != nil {
 		ctx.Logger.Error("error while getting tracker ", err, j.TrackerName)
+		j.Fail(err)
 		return
 	}
 
 	ctx.Logger.Info(hex.EncodeToString(tracker.ProcessUnsignedTx))
 
-	pk, _ := btcec.PrivKeyFromBytes(btcec.S256(), ctx.BTCPrivKey.Data)
+	pubKey := ctx.Signer.BTCPubKey()
 
-	addressPubKey, err := btcutil.NewAddressPubKey(pk.PubKey().SerializeCompressed(), ctx.BTCParams)
+	addressPubKey, err := btcutil.NewAddressPubKey(pubKey.Data, ctx.BTCParams)
 	if err != nil {
 		ctx.Logger.Error("error while generating btc address", err)
+		j.Fail(err)
 		return
 	}
 
@@ -71,30 +71,34 @@ func (j *JobAddSignature) DoMyJob(ctxI interface{}) {
 	err = lockTx.Deserialize(bytes.NewReader(tracker.ProcessUnsignedTx))
 	if err != nil {
 		ctx.Logger.Error("error while deserializing lock", err)
+		j.Fail(err)
 		return
 	}
 
 	lockScript, err := ctx.LockScripts.GetLockScript(tracker.CurrentLockScriptAddress)
 	if err != nil {
 		ctx.Logger.Error("error in reading lockscript", err)
+		j.Fail(err)
 		return
 	}
 
 	if len(lockScript) == 0 && tracker.CurrentTxId != nil {
 		ctx.Logger.Error("error in reading lockscript", err)
+		j.Fail(errors.New("lock script not found"))
 		return
 	}
 
-	sig, err := txscript.RawTxInSignature(lockTx, 0, lockScript, txscript.SigHashAll, pk)
+	sig, err := ctx.Signer.SignBTCInput(j.TrackerName, lockTx, 0, lockScript)
 	if err != nil {
-		ctx.Logger.Error(err, "RawTxInSignature")
+		ctx.Logger.Error(err, "SignBTCInput")
 		ctx.Logger.Error(hex.EncodeToString(lockScript), hex.EncodeToString(tracker.CurrentLockScriptAddress))
+		j.Fail(err)
 		return
 	}
 
 	addSigData := btc.AddSignature{
 		TrackerName:      j.TrackerName,
-		ValidatorPubKey:  pk.PubKey().SerializeCompressed(),
+		ValidatorPubKey:  pubKey.Data,
 		BTCSignature:     sig,
 		ValidatorAddress: ctx.ValidatorAddress,
 		Memo:             j.JobID,
@@ -103,6 +107,7 @@ func (j *JobAddSignature) DoMyJob(ctxI interface{}) {
 	txData, err := addSigData.Marshal()
 	if err != nil {
 		ctx.Logger.Error("error in marshalling txn", err)
+		j.Fail(err)
 		return
 	}
 
@@ -121,6 +126,7 @@ func (j *JobAddSignature) DoMyJob(ctxI interface{}) {
 	err = ctx.Service.InternalBroadcast(req, &rep)
 	if err != nil || !rep.OK {
 		ctx.Logger.Error("error in broadcasting internal addsignature", err, rep.Log)
+		j.Fail(broadcastError(err, rep))
 		return
 	}
 }
@@ -128,7 +134,3 @@ func (j *JobAddSignature) DoMyJob(ctxI interface{}) {
 func (j *JobAddSignature) GetJobID() string {
 	return j.JobID
 }
-
-func (j JobAddSignature) IsDone() bool {
-	return j.Status == jobs.Completed
-}
@@ -12,6 +12,7 @@ import (
 	"github.com/btcsuite/btcd/rpcclient"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
+	"github.com/pkg/errors"
 
 	"github.com/Oneledger/protocol/action"
 	"github.com/Oneledger/protocol/action/btc"
@@ -31,9 +32,7 @@ type JobBTCBroadcast struct {
 
 	JobID string
 
-	Status jobs.Status
-
-	RetryCount int
+	jobs.State
 }
 
 func NewBTCBroadcastJob(trackerName, id string) jobs.Job {
@@ -42,24 +41,18 @@ func NewBTCBroadcastJob(trackerName, id string) jobs.Job {
 		Type:        JobTypeBTCBroadcast,
 		TrackerName: trackerName,
 		JobID:       id,
-		Status:      jobs.New,
 	}
 }
 
 func (j *JobBTCBroadcast) DoMyJob(ctxI interface{}) {
 
-	j.RetryCount += 1
-	ctx, ok := ctxI.(*JobsContext)
-	if !ok &&
-		j.RetryCount > MAX_BROADCAST_RETRY {
-		j.Status = jobs.Completed
-		return
-	}
+	ctx, _ := ctxI.(*JobsContext)
 
 	tracker, err := ctx.Trackers.Get(j.TrackerName)
 	if err != nil {
 
 		ctx.Logger.Error("err trying to deserialize tracker: ", j.TrackerName, err)
+		j.Fail(err)
 		return
 	}
 
@@ -68,18 +61,11 @@ func (j *JobBTCBroadcast) DoMyJob(ctxI interface{}) {
 		return
 	}
 
-	if j.RetryCount > MAX_BROADCAST_RETRY {
-		ok := resetCall(tracker, ctx, j.JobID)
-		if ok {
-			j.Status = jobs.Completed
-		}
-		return
-	}
-
 	lockTx := wire.NewMsgTx(wire.TxVersion)
 	err = lockTx.Deserialize(bytes.NewReader(tracker.ProcessUnsignedTx))
 	if err != nil {
 		ctx.Logger.Error("err trying to deserialize btc txn: ", err, j.TrackerName)
+		j.Fail(err)
 		return
 	}
 
@@ -97,6 +83,7 @@ func (j *JobBTCBroadcast) DoMyJob(ctxI interface{}) {
 	lockScript, err := ctx.LockScripts.GetLockScript(tracker.CurrentLockScriptAddress)
 	if err != nil {
 		ctx.Logger.Error("err trying to get lockscript ", err, j.TrackerName)
+		j.Fail(err)
 		return
 	}
 
@@ -113,6 +100,7 @@ func (j *JobBTCBroadcast) DoMyJob(ctxI interface{}) {
 	err = lockTx.Serialize(buf)
 	if err != nil {
 		ctx.Logger.Error("err trying to serialize btc final txn ", err, j.TrackerName)
+		j.Fail(err)
 		return
 	}
 
@@ -129,11 +117,13 @@ func (j *JobBTCBroadcast) DoMyJob(ctxI interface{}) {
 		if err != nil {
 			fmt.Println("new engine", err)
 			ctx.Logger.Error("error in test engine")
+			j.Fail(err)
 			return
 		}
 		if err := vm.Execute(); err != nil {
 			fmt.Println("vm Execute", err)
 			ctx.Logger.Error("error in vm execute")
+			j.Fail(err)
 			return
 		}
 	}
@@ -148,6 +138,7 @@ func (j *JobBTCBroadcast) DoMyJob(ctxI interface{}) {
 	clt, err := rpcclient.New(connCfg, nil)
 	if err != nil {
 		ctx.Logger.Error("err trying to connect to bitcoin node", j.TrackerName)
+		j.Fail(err)
 		return
 	}
 
@@ -168,6 +159,7 @@ func (j *JobBTCBroadcast) DoMyJob(ctxI interface{}) {
 		txData, err := bs.Marshal()
 		if err != nil {
 			ctx.Logger.Error("error while preparing mint txn ", err, j.TrackerName)
+			j.Fail(err)
 			return
 		}
 		tx := action.RawTx{
@@ -185,6 +177,7 @@ func (j *JobBTCBroadcast) DoMyJob(ctxI interface{}) {
 		err = ctx.Service.InternalBroadcast(req, &rep)
 		if err != nil || !rep.OK {
 			ctx.Logger.Error("error while broadcasting finality vote and mint txn ", err, j.TrackerName)
+			j.Fail(broadcastError(err, rep))
 			return
 		}
 
@@ -194,6 +187,7 @@ func (j *JobBTCBroadcast) DoMyJob(ctxI interface{}) {
 
 	} else {
 		ctx.Logger.Error("broadcast failed err: ", err, " tracker: ", j.TrackerName)
+		j.Fail(err)
 	}
 
 }
@@ -209,8 +203,28 @@ func (j *JobBTCBroadcast) GetJobID() string {
 	return j.JobID
 }
 
-func (j JobBTCBroadcast) IsDone() bool {
-	return j.Status == jobs.Completed
+// RetryPolicy gives the broadcast more tries than other jobs, the tracker is reset when they
+// run out
+func (j *JobBTCBroadcast) RetryPolicy(defaults jobs.RetryPolicy) jobs.RetryPolicy {
+	defaults.MaxAttempts = MAX_BROADCAST_RETRY
+	return defaults
+}
+
+// Expire votes to reset the tracker once the broadcast is out of retries
+func (j *JobBTCBroadcast) Expire(ctxI interface{}) error {
+	ctx, _ := ctxI.(*JobsContext)
+
+	tracker, err := ctx.Trackers.Get(j.TrackerName)
+	if err != nil {
+		return err
+	}
+	if tracker.State != bitcoin2.BusyBroadcasting {
+		return nil
+	}
+	if !resetCall(tracker, ctx, j.JobID) {
+		return errors.New("failed to broadcast the tracker reset")
+	}
+	return nil
 }
 
 func resetCall(tracker *bitcoin2.Tracker, ctx *JobsContext, jobID string) bool {
@@ -30,7 +30,7 @@ type JobBTCCheckFinality struct {
 	JobID       string
 	CheckAfter  int64
 
-	Status jobs.Status
+	jobs.State
 }
 
 func NewBTCCheckFinalityJob(trackerName, id string) jobs.Job {
@@ -40,7 +40,6 @@ func NewBTCCheckFinalityJob(trackerName, id string) jobs.Job {
 		TrackerName: trackerName,
 		JobID:       id,
 		CheckAfter:  time.Now().Unix() + SixtyMinutes,
-		Status:      jobs.New,
 	}
 }
 
@@ -55,6 +54,7 @@ func (cf *JobBTCCheckFinality) DoMyJob(ctxI interface{}) {
 	tracker, err := ctx.Trackers.Get(cf.TrackerName)
 	if err != nil {
 		ctx.Logger.Error("err trying to deserialize tracker: ", cf.TrackerName, err)
+		cf.Fail(err)
 		return
 	}
 
@@ -73,6 +73,7 @@ func (cf *JobBTCCheckFinality) DoMyJob(ctxI interface{}) {
 	ok, err := cd.CheckFinality(tracker.ProcessTxId, ctx.BTCData.BlockCypherToken, chain)
 	if err != nil {
 		ctx.Logger.Error("error while checking finality", err, cf.TrackerName)
+		cf.Fail(err)
 		return
 	}
 
@@ -86,6 +87,7 @@ func (cf *JobBTCCheckFinality) DoMyJob(ctxI interface{}) {
 	_, err = io.ReadFull(rand.Reader, data[:])
 	if err != nil {
 		ctx.Logger.Error("error while reading random bytes for minting", err, cf.TrackerName)
+		cf.Fail(err)
 		return
 	}
 
@@ -99,6 +101,7 @@ func (cf *JobBTCCheckFinality) DoMyJob(ctxI interface{}) {
 	txData, err := reportFinalityMint.Marshal()
 	if err != nil {
 		ctx.Logger.Error("error while preparing mint txn ", err, cf.TrackerName)
+		cf.Fail(err)
 		return
 	}
 
@@ -117,6 +120,7 @@ func (cf *JobBTCCheckFinality) DoMyJob(ctxI interface{}) {
 	err = ctx.Service.InternalBroadcast(req, &rep)
 	if err != nil || !rep.OK {
 		ctx.Logger.Error("error while broadcasting finality vote and mint txn ", err, cf.TrackerName)
+		cf.Fail(broadcastError(err, rep))
 		return
 	}
 
@@ -130,7 +134,3 @@ func (cf *JobBTCCheckFinality) GetType() string {
 func (cf *JobBTCCheckFinality) GetJobID() string {
 	return cf.JobID
 }
-
-func (cf *JobBTCCheckFinality) IsDone() bool {
-	return cf.Status == jobs.Completed
-}
@@ -5,8 +5,6 @@
 package event
 
 import (
-	"github.com/pkg/errors"
-
 	"github.com/Oneledger/protocol/data/bitcoin"
 	"github.com/Oneledger/protocol/utils/transition"
 )
@@ -46,10 +44,11 @@ func init() {
 	}
 
 	err = BtcEngine.Register(transition.Transition{
-		Name: bitcoin.FREEZE_FOR_BROADCAST,
-		Fn:   FreezeForBroadcast,
-		From: transition.Status(bitcoin.BusyScheduleBroadcasting),
-		To:   transition.Status(bitcoin.BusyBroadcasting),
+		Name:  bitcoin.FREEZE_FOR_BROADCAST,
+		Fn:    FreezeForBroadcast,
+		Guard: HasValidMultisig,
+		From:  transition.Status(bitcoin.BusyScheduleBroadcasting),
+		To:    transition.Status(bitcoin.BusyBroadcasting),
 	})
 	if err != nil {
 		panic(err)
@@ -79,14 +78,7 @@ func MakeAvailable(input interface{}) error {
 
 		if data.Validators.IsValidator() {
 			//Delete Add Signature Job
-			fjob, err := data.JobStore.GetJob(t.GetJobID(states[i]))
-			if err != nil {
-				continue
-			}
-			err = data.JobStore.DeleteJob(fjob)
-			if err != nil {
-				return errors.Wrap(err, "failed to delete job")
-			}
+			deleteJob(data.JobStore, t.GetJobID(states[i]))
 		}
 	}
 
@@ -106,11 +98,7 @@ func ReserveTracker(inp interface{}) error {
 	if data.Validators.IsValidator() {
 
 		job := NewAddSignatureJob(t.Name, t.GetJobID(t.State))
-
-		err := data.JobStore.SaveJob(job)
-		if err != nil {
-			return err
-		}
+		saveJob(data.JobStore, job)
 	}
 
 	return nil
@@ -128,10 +116,7 @@ func FreezeForBroadcast(inp interface{}) error {
 		data.Tracker.State = bitcoin.BusyBroadcasting
 		if data.Validators.IsValidator() {
 			job := NewBTCBroadcastJob(t.Name, t.GetJobID(t.State))
-			err := data.JobStore.SaveJob(job)
-			if err != nil {
-				return err
-			}
+			saveJob(data.JobStore, job)
 		}
 
 	} else if t.Multisig.IsCancel() {
@@ -141,6 +126,15 @@ func FreezeForBroadcast(inp interface{}) error {
 	return nil
 }
 
+// HasValidMultisig tells if the tracker has the signatures for its transaction to be broadcast
+func HasValidMultisig(inp interface{}) bool {
+	data, ok := inp.(bitcoin.BTCTransitionContext)
+	if !ok {
+		return false
+	}
+	return data.Tracker.Multisig.IsValid()
+}
+
 func ReportBroadcastSuccess(inp interface{}) error {
 	data, ok := inp.(bitcoin.BTCTransitionContext)
 	if !ok {
@@ -152,10 +146,7 @@ func ReportBroadcastSuccess(inp interface{}) error {
 
 	if data.Validators.IsValidator() {
 		job := NewBTCCheckFinalityJob(t.Name, t.GetJobID(t.State))
-		err := data.JobStore.SaveJob(job)
-		if err != nil {
-			return err
-		}
+		saveJob(data.JobStore, job)
 	}
 
 	return nil
@@ -5,12 +5,10 @@
 package event
 
 import (
-	"crypto/ecdsa"
 	"os"
 
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/crypto"
 
 	"github.com/Oneledger/protocol/action"
 	bitcoin2 "github.com/Oneledger/protocol/chains/bitcoin"
@@ -20,6 +18,7 @@ import (
 	"github.com/Oneledger/protocol/data/keys"
 	"github.com/Oneledger/protocol/identity"
 	"github.com/Oneledger/protocol/log"
+	"github.com/Oneledger/protocol/signer"
 )
 
 type JobsContext struct {
@@ -30,9 +29,8 @@ type JobsContext struct {
 	Trackers   *bitcoin.TrackerStore
 	Validators *identity.ValidatorStore
 
-	BTCPrivKey *keys.PrivateKey
-	ETHPrivKey *keys.PrivateKey
-	BTCParams  *chaincfg.Params
+	Signer    signer.Signer
+	BTCParams *chaincfg.Params
 
 	ValidatorAddress action.Address
 	LockScripts      *bitcoin.LockScriptStore
@@ -52,7 +50,7 @@ type BTCData struct {
 
 func NewJobsContext(cfg config.Server, btcChainType string, svc *Service,
 	trackers *bitcoin.TrackerStore, validators *identity.ValidatorStore,
-	privKey *keys.PrivateKey, ethprivKey *keys.PrivateKey,
+	signer signer.Signer,
 	valAddress keys.Address, bcyToken string, lStore *bitcoin.LockScriptStore,
 	btcAddress, btcRPCPort, BTCRPCUsername, BTCRPCPassword string,
 	ethTracker *ethereum.TrackerStore,
@@ -68,8 +66,7 @@ func NewJobsContext(cfg config.Server, btcChainType string, svc *Service,
 		Logger:           log.NewLoggerWithPrefix(w, "internal_jobs"),
 		Trackers:         trackers,
 		Validators:       validators,
-		BTCPrivKey:       privKey,
-		ETHPrivKey:       ethprivKey,
+		Signer:           signer,
 		BTCParams:        params,
 		ValidatorAddress: valAddress,
 		LockScripts:      lStore,
@@ -88,20 +85,5 @@ func NewJobsContext(cfg config.Server, btcChainType string, svc *Service,
 }
 
 func (jc *JobsContext) GetValidatorETHAddress() common.Address {
-	privkey := keys.ETHSECP256K1TOECDSA(jc.ETHPrivKey.Data)
-
-	pubkey := privkey.Public()
-	ecdsapubkey, ok := pubkey.(*ecdsa.PublicKey)
-	if !ok {
-		jc.Logger.Error("failed to cast pubkey", pubkey)
-		return common.Address{}
-	}
-	addr := crypto.PubkeyToAddress(*ecdsapubkey)
-	return addr
-}
-
-func (jc *JobsContext) GetValidatorETHPrivKey() *ecdsa.PrivateKey {
-	privkey := keys.ETHSECP256K1TOECDSA(jc.ETHPrivKey.Data)
-
-	return privkey
+	return jc.Signer.ETHAddress()
 }
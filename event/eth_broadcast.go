@@ -14,8 +14,8 @@ var _ jobs.Job = &JobETHBroadcast{}
 type JobETHBroadcast struct {
 	TrackerName ethereum.TrackerName
 	JobID       string
-	RetryCount  int
-	Status      jobs.Status
+
+	jobs.State
 }
 
 func NewETHBroadcast(name ethereum.TrackerName, state ethereum2.TrackerState) *JobETHBroadcast {
@@ -23,27 +23,18 @@ func NewETHBroadcast(name ethereum.TrackerName, state ethereum2.TrackerState) *J
 	return &JobETHBroadcast{
 		TrackerName: name,
 		JobID:       name.String() + storage.DB_PREFIX + strconv.Itoa(int(state)),
-		RetryCount:  0,
-		Status:      0,
 	}
 }
 
 func (job *JobETHBroadcast) DoMyJob(ctx interface{}) {
 
-	job.RetryCount += 1
-	if job.RetryCount > jobs.Max_Retry_Count {
-		job.Status = jobs.Failed
-	}
-	if job.Status == jobs.New {
-		job.Status = jobs.InProgress
-	}
-
 	ethCtx, _ := ctx.(*JobsContext)
 	trackerStore := ethCtx.EthereumTrackers
 
 	tracker, err := trackerStore.Get(job.TrackerName)
 	if err != nil {
 		ethCtx.Logger.Error("err trying to deserialize tracker: ", job.TrackerName, err)
+		job.Fail(err)
 		return
 	}
 	ethconfig := ethCtx.cfg.EthChainDriver
@@ -52,7 +43,7 @@ func (job *JobETHBroadcast) DoMyJob(ctx interface{}) {
 	cd, err := ethereum.NewChainDriver(ethconfig, ethCtx.Logger, trackerStore.GetOption())
 	if err != nil {
 		ethCtx.Logger.Error("err trying to get ChainDriver : ", job.GetJobID(), err)
-
+		job.Fail(err)
 		return
 	}
 
@@ -60,12 +51,14 @@ func (job *JobETHBroadcast) DoMyJob(ctx interface{}) {
 	tx, err := cd.DecodeTransaction(rawTx)
 	if err != nil {
 		ethCtx.Logger.Error("Error Decoding Bytes from RaxTX :", job.GetJobID(), err)
+		job.Fail(err)
 		return
 	}
 	//check if tx already broadcasted, if yest, job.Status = jobs.Completed
 	_, err = cd.BroadcastTx(tx)
 	if err != nil {
 		ethCtx.Logger.Error("Error in transaction broadcast : ", job.GetJobID(), err)
+		job.Fail(err)
 		return
 	}
 
@@ -79,7 +72,3 @@ func (job *JobETHBroadcast) GetType() string {
 func (job *JobETHBroadcast) GetJobID() string {
 	return job.JobID
 }
-
-func (job *JobETHBroadcast) IsDone() bool {
-	return job.Status == jobs.Completed
-}
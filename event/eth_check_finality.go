@@ -16,16 +16,14 @@ var _ jobs.Job = &JobETHCheckFinality{}
 type JobETHCheckFinality struct {
 	TrackerName ethereum.TrackerName
 	JobID       string
-	RetryCount  int
-	Status      jobs.Status
+
+	jobs.State
 }
 
 func NewETHCheckFinality(name ethereum.TrackerName, state ethtracker.TrackerState) *JobETHCheckFinality {
 	return &JobETHCheckFinality{
 		TrackerName: name,
 		JobID:       name.String() + storage.DB_PREFIX + strconv.Itoa(int(state)),
-		RetryCount:  0,
-		Status:      0,
 	}
 }
 
@@ -33,20 +31,13 @@ func (job *JobETHCheckFinality) DoMyJob(ctx interface{}) {
 
 	// get tracker
 
-	job.RetryCount += 1
-	if job.RetryCount > jobs.Max_Retry_Count {
-		job.Status = jobs.Failed
-	}
-	if job.Status == jobs.New {
-		job.Status = jobs.InProgress
-	}
 	ethCtx, _ := ctx.(*JobsContext)
 
 	trackerStore := ethCtx.EthereumTrackers
 	tracker, err := trackerStore.Get(job.TrackerName)
 	if err != nil {
 		ethCtx.Logger.Error("err trying to deserialize tracker: ", job.TrackerName, err)
-		job.RetryCount += 1
+		job.Fail(err)
 		return
 	}
 
@@ -54,7 +45,7 @@ func (job *JobETHCheckFinality) DoMyJob(ctx interface{}) {
 	cd, err := ethereum.NewChainDriver(ethconfig, ethCtx.Logger, trackerStore.GetOption())
 	if err != nil {
 		ethCtx.Logger.Error("err trying to get ChainDriver : ", job.GetJobID(), err)
-		job.RetryCount += 1
+		job.Fail(err)
 		return
 	}
 
@@ -62,12 +53,14 @@ func (job *JobETHCheckFinality) DoMyJob(ctx interface{}) {
 	tx, err := cd.DecodeTransaction(rawTx)
 	if err != nil {
 		ethCtx.Logger.Error("Error Decoding Bytes from RaxTX :", job.GetJobID(), err)
+		job.Fail(err)
 		return
 	}
 
 	receipt, err := cd.CheckFinality(tx.Hash())
 	if err != nil {
 		ethCtx.Logger.Error("Error in Receiving TX receipt : ", job.GetJobID(), err)
+		job.Fail(err)
 		return
 	}
 	if receipt == nil {
@@ -89,6 +82,7 @@ func (job *JobETHCheckFinality) DoMyJob(ctx interface{}) {
 	txData, err := reportFinalityMint.Marshal()
 	if err != nil {
 		ethCtx.Logger.Error("Error while preparing mint txn ", job.GetJobID(), err)
+		job.Fail(err)
 		return
 	}
 
@@ -107,6 +101,7 @@ func (job *JobETHCheckFinality) DoMyJob(ctx interface{}) {
 
 	if err != nil || !rep.OK {
 		ethCtx.Logger.Error("error while broadcasting finality vote and mint txn ", job.GetJobID(), err, rep.Log)
+		job.Fail(broadcastError(err, rep))
 		return
 	}
 
@@ -120,7 +115,3 @@ func (job *JobETHCheckFinality) GetType() string {
 func (job *JobETHCheckFinality) GetJobID() string {
 	return job.JobID
 }
-
-func (job *JobETHCheckFinality) IsDone() bool {
-	return job.Status == jobs.Completed
-}
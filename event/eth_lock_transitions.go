@@ -79,7 +79,7 @@ func Broadcasting(ctx interface{}) error {
 
 	if tracker.State != ethereum.New {
 		err := errors.New("Cannot Broadcast from the current state")
-		return errors.Wrap(err, string((*tracker).State))
+		return errors.Wrap(err, tracker.State.String())
 	}
 
 	tracker.State = ethereum.BusyBroadcasting
@@ -88,11 +88,7 @@ func Broadcasting(ctx interface{}) error {
 	if context.Validators.IsValidator() {
 
 		job := NewETHBroadcast((*tracker).TrackerName, tracker.State)
-		err := context.JobStore.SaveJob(job)
-		if err != nil {
-
-			return errors.Wrap(errors.New("job serialization failed err: "), err.Error())
-		}
+		saveJob(context.JobStore, job)
 	}
 	context.Tracker = tracker
 	return nil
@@ -108,24 +104,16 @@ func Finalizing(ctx interface{}) error {
 
 	if tracker.State != ethereum.BusyBroadcasting {
 		err := errors.New("Cannot start Finalizing from the current state")
-		return errors.Wrap(err, string(tracker.State))
+		return errors.Wrap(err, tracker.State.String())
 	}
 
 	if context.Validators.IsValidator() {
 		_, voted := tracker.CheckIfVoted(context.CurrNodeAddr)
 		if !voted {
-			bjob, err := context.JobStore.GetJob(tracker.GetJobID(ethereum.BusyBroadcasting))
-			if err != nil {
-				return errors.Wrap(err, "failed to get job")
-			}
-
-			if bjob.IsDone() {
-
+			bjob := getJob(context.JobStore, tracker.GetJobID(ethereum.BusyBroadcasting))
+			if bjob != nil && bjob.IsDone() {
 				job := NewETHCheckFinality(tracker.TrackerName, ethereum.BusyFinalizing)
-				err := context.JobStore.SaveJob(job)
-				if err != nil {
-					return errors.Wrap(errors.New("job serialization failed err: "), err.Error())
-				}
+				saveJob(context.JobStore, job)
 			}
 		}
 	}
@@ -151,7 +139,7 @@ func Finalization(ctx interface{}) error {
 
 	if tracker.State != ethereum.BusyFinalizing {
 		err := errors.New("cannot finalize from the current state")
-		return errors.Wrap(err, string(tracker.State))
+		return errors.Wrap(err, tracker.State.String())
 	}
 
 	if tracker.Finalized() {
@@ -167,11 +155,7 @@ func Finalization(ctx interface{}) error {
 			//Create job to check finality
 
 			job := NewETHCheckFinality(tracker.TrackerName, tracker.State)
-
-			err := context.JobStore.SaveJob(job)
-			if err != nil {
-				return errors.Wrap(errors.New("job serialization failed err: "), err.Error())
-			}
+			saveJob(context.JobStore, job)
 			//} else {
 			//	job, err := context.JobStore.GetJob(tracker.GetJobID(tracker.State))
 			//	if err != nil {
@@ -194,7 +178,7 @@ func Minting(ctx interface{}) error {
 
 	if tracker.State != ethereum.Finalized {
 		err := errors.New("Cannot Mint from the current state")
-		return errors.Wrap(err, string(tracker.State))
+		return errors.Wrap(err, tracker.State.String())
 	}
 	//todo: create a job to mint
 
@@ -213,25 +197,12 @@ func Cleanup(ctx interface{}) error {
 	tracker := context.Tracker
 	//todo: delete the tracker and jobs related
 
-	//Delete Broadcasting Job
-	bjob, err := context.JobStore.GetJob(tracker.GetJobID(ethereum.BusyBroadcasting))
-	if err != nil {
-		return errors.Wrap(err, "failed to get job")
-	}
-
-	err = context.JobStore.DeleteJob(bjob)
-	if err != nil {
-		return err
-	}
+	if context.Validators.IsValidator() {
+		//Delete Broadcasting Job
+		deleteJob(context.JobStore, tracker.GetJobID(ethereum.BusyBroadcasting))
 
-	//Delete CheckFinality Job
-	fjob, err := context.JobStore.GetJob(tracker.GetJobID(ethereum.BusyFinalizing))
-	if err != nil {
-		return errors.Wrap(err, "failed to get job")
-	}
-	err = context.JobStore.DeleteJob(fjob)
-	if err != nil {
-		return err
+		//Delete CheckFinality Job
+		deleteJob(context.JobStore, tracker.GetJobID(ethereum.BusyFinalizing))
 	}
 
 	//Delete Tracker
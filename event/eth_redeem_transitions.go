@@ -1,8 +1,6 @@
 package event
 
 import (
-	"fmt"
-
 	"github.com/pkg/errors"
 
 	"github.com/Oneledger/protocol/data/ethereum"
@@ -82,7 +80,7 @@ func Signing(ctx interface{}) error {
 
 	if tracker.State != ethereum.New {
 		err := errors.New("Cannot Start Sign and Broadcast from Current State")
-		return errors.Wrap(err, string((*tracker).State))
+		return errors.Wrap(err, tracker.State.String())
 	}
 
 	tracker.State = ethereum.BusyBroadcasting
@@ -90,11 +88,7 @@ func Signing(ctx interface{}) error {
 	if context.Validators.IsValidator() {
 
 		job := NewETHSignRedeem(tracker.TrackerName, tracker.State)
-		err := context.JobStore.SaveJob(job)
-		if err != nil {
-
-			return errors.Wrap(errors.New("job serialization failed err: "), err.Error())
-		}
+		saveJob(context.JobStore, job)
 
 	}
 	context.Tracker = tracker
@@ -110,11 +104,10 @@ func VerifyRedeem(ctx interface{}) error {
 	tracker := context.Tracker
 
 	// create verify job for the first time from the state of broadcasting
-	if tracker.State == ethereum.BusyBroadcasting && context.Validators.IsValidator() {
-		job := NewETHVerifyRedeem(tracker.TrackerName, ethereum.BusyFinalizing)
-		err := context.JobStore.SaveJob(job)
-		if err != nil {
-			return errors.Wrap(err, "Failed to save job")
+	if tracker.State == ethereum.BusyBroadcasting {
+		if context.Validators.IsValidator() {
+			job := NewETHVerifyRedeem(tracker.TrackerName, ethereum.BusyFinalizing)
+			saveJob(context.JobStore, job)
 		}
 		tracker.State = ethereum.BusyFinalizing
 	}
@@ -149,15 +142,7 @@ func redeemCleanup(ctx interface{}) error {
 	//delete the tracker related jobs
 	if context.Validators.IsValidator() {
 		for state := ethereum.BusyBroadcasting; state <= ethereum.Released; state++ {
-			job, err := context.JobStore.GetJob(tracker.GetJobID(state))
-			if err != nil {
-				fmt.Println(err, "failed to get job from state: ", state)
-				continue
-			}
-			err = context.JobStore.DeleteJob(job)
-			if err != nil {
-				return errors.Wrap(err, "error deleting job from store")
-			}
+			deleteJob(context.JobStore, tracker.GetJobID(state))
 		}
 	}
 	//Delete Tracker
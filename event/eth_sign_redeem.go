@@ -5,7 +5,6 @@ import (
 	"strconv"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/core/types"
 
 	"github.com/Oneledger/protocol/chains/ethereum"
 	trackerlib "github.com/Oneledger/protocol/data/ethereum"
@@ -18,38 +17,25 @@ var _ jobs.Job = &JobETHSignRedeem{}
 type JobETHSignRedeem struct {
 	TrackerName ethereum.TrackerName
 	JobID       string
-	RetryCount  int
-	Status      jobs.Status
 	TxHash      *ethereum.TransactionHash
+
+	jobs.State
 }
 
 func NewETHSignRedeem(name ethereum.TrackerName, state trackerlib.TrackerState) *JobETHSignRedeem {
 	return &JobETHSignRedeem{
 		TrackerName: name,
 		JobID:       name.String() + storage.DB_PREFIX + strconv.Itoa(int(state)),
-		RetryCount:  0,
-		Status:      0,
 	}
 }
 
 func (j *JobETHSignRedeem) DoMyJob(ctx interface{}) {
-	j.RetryCount += 1
-	if j.RetryCount > jobs.Max_Retry_Count {
-		j.Status = jobs.Failed
-	}
-	if j.Status == jobs.New {
-		j.Status = jobs.InProgress
-	}
-
-	if j.Status == jobs.Completed {
-		return
-	}
-
 	ethCtx, _ := ctx.(*JobsContext)
 	trackerStore := ethCtx.EthereumTrackers
 	tracker, err := trackerStore.Get(j.TrackerName)
 	if err != nil {
 		ethCtx.Logger.Error("err trying to deserialize tracker: ", j.TrackerName, err)
+		j.Fail(err)
 		return
 	}
 	ethconfig := ethCtx.cfg.EthChainDriver
@@ -57,6 +43,7 @@ func (j *JobETHSignRedeem) DoMyJob(ctx interface{}) {
 	cd, err := ethereum.NewChainDriver(ethconfig, ethCtx.Logger, trackerStore.GetOption())
 	if err != nil {
 		ethCtx.Logger.Error("err trying to get ChainDriver : ", j.GetJobID(), err)
+		j.Fail(err)
 		return
 	}
 
@@ -64,12 +51,14 @@ func (j *JobETHSignRedeem) DoMyJob(ctx interface{}) {
 	tx, err := cd.DecodeTransaction(rawTx)
 	if err != nil {
 		ethCtx.Logger.Error("Error Decoding Bytes from RaxTX :", j.GetJobID(), err)
+		j.Fail(err)
 		return
 	}
 
 	msg, err := cd.GetTransactionMessage(tx)
 	if err != nil {
 		ethCtx.Logger.Error("Error in decoding transaction as message : ", j.GetJobID(), err)
+		j.Fail(err)
 		return
 	}
 
@@ -88,6 +77,7 @@ func (j *JobETHSignRedeem) DoMyJob(ctx interface{}) {
 	req, err := cd.ParseRedeem(rawTx)
 	if err != nil {
 		ethCtx.Logger.Error("Error in Parsing amount from rawTx ", j.GetJobID(), err)
+		j.Fail(err)
 		return
 	}
 
@@ -97,21 +87,27 @@ func (j *JobETHSignRedeem) DoMyJob(ctx interface{}) {
 	tx, err = cd.SignRedeem(addr, redeemAmount, redeemAddr)
 	if err != nil {
 		ethCtx.Logger.Error("Error in creating signing trasanction : ", j.GetJobID(), err)
+		j.Fail(err)
 		return
 	}
 
-	privkey := ethCtx.GetValidatorETHPrivKey()
 	chainid, err := cd.ChainId()
 	if err != nil {
 		ethCtx.Logger.Error("Failed to get chain id ", err)
+		j.Fail(err)
 		return
 	}
 
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainid), privkey)
-	privkey = nil
+	signedTx, err := ethCtx.Signer.SignETHTx(j.TrackerName.String(), tx, chainid)
+	if err != nil {
+		ethCtx.Logger.Error("Failed to sign redeem transaction : ", j.GetJobID(), err)
+		j.Fail(err)
+		return
+	}
 	txHash, err := cd.BroadcastTx(signedTx)
 	if err != nil {
 		ethCtx.Logger.Error("Unable to broadcast transaction :", j.GetJobID(), err)
+		j.Fail(err)
 		return
 	}
 
@@ -121,10 +117,6 @@ func (j *JobETHSignRedeem) DoMyJob(ctx interface{}) {
 	// j.Status = jobs.Completed
 }
 
-func (j *JobETHSignRedeem) IsDone() bool {
-	return j.Status == jobs.Completed
-}
-
 func (j *JobETHSignRedeem) GetType() string {
 	return JobTypeETHSignRedeem
 }
@@ -16,49 +16,44 @@ var _ jobs.Job = &JobETHVerifyRedeem{}
 type JobETHVerifyRedeem struct {
 	TrackerName ethereum.TrackerName
 	JobID       string
-	RetryCount  int
-	Status      jobs.Status
+
+	jobs.State
 }
 
 func NewETHVerifyRedeem(name ethereum.TrackerName, state trackerlib.TrackerState) *JobETHVerifyRedeem {
 	return &JobETHVerifyRedeem{
 		TrackerName: name,
 		JobID:       name.String() + storage.DB_PREFIX + strconv.Itoa(int(state)),
-		RetryCount:  0,
-		Status:      0,
 	}
 }
 
 func (job *JobETHVerifyRedeem) DoMyJob(ctx interface{}) {
-	job.RetryCount += 1
-	if job.RetryCount > jobs.Max_Retry_Count {
-		job.Status = jobs.Failed
-	}
-	if job.Status == jobs.New {
-		job.Status = jobs.InProgress
-	}
 	ethCtx, _ := ctx.(*JobsContext)
 	trackerStore := ethCtx.EthereumTrackers
 	tracker, err := trackerStore.Get(job.TrackerName)
 	if err != nil {
 		ethCtx.Logger.Error("Unable to get Tracker", job.JobID)
+		job.Fail(err)
 		return
 	}
 
 	cd, err := ethereum.NewChainDriver(ethCtx.cfg.EthChainDriver, ethCtx.Logger, trackerStore.GetOption())
 	if err != nil {
 		ethCtx.Logger.Error("Unable to get Chain Driver", job.JobID)
+		job.Fail(err)
 		return
 	}
 	tx, err := cd.DecodeTransaction(tracker.SignedETHTx)
 	if err != nil {
 		ethCtx.Logger.Error("Unable to decode transaction")
+		job.Fail(err)
 		return
 	}
 
 	msg, err := cd.GetTransactionMessage(tx)
 	if err != nil {
 		ethCtx.Logger.Error("Error in decoding transaction as message : ", job.GetJobID(), err)
+		job.Fail(err)
 		return
 	}
 
@@ -85,6 +80,7 @@ func (job *JobETHVerifyRedeem) DoMyJob(ctx interface{}) {
 		txData, err := cf.Marshal()
 		if err != nil {
 			ethCtx.Logger.Error("Error while preparing mint txn ", job.GetJobID(), err)
+			job.Fail(err)
 			return
 		}
 
@@ -103,16 +99,13 @@ func (job *JobETHVerifyRedeem) DoMyJob(ctx interface{}) {
 
 		if err != nil || !rep.OK {
 			ethCtx.Logger.Error("error while broadcasting finality vote and mint txn ", job.GetJobID(), err, rep.Log)
+			job.Fail(broadcastError(err, rep))
 			return
 		}
 		job.Status = jobs.Completed
 	}
 }
 
-func (job *JobETHVerifyRedeem) IsDone() bool {
-	return job.Status == jobs.Completed
-}
-
 func (job *JobETHVerifyRedeem) GetType() string {
 	return JobTypeETHVerifyRedeem
 }
@@ -1,6 +1,11 @@
 package event
 
 import (
+	"os"
+
+	"github.com/Oneledger/protocol/data/bitcoin"
+	"github.com/Oneledger/protocol/data/ethereum"
+	"github.com/Oneledger/protocol/log"
 	"github.com/Oneledger/protocol/serialize"
 	"github.com/Oneledger/protocol/utils/transition"
 )
@@ -9,6 +14,8 @@ var (
 	EthLockEngine   transition.Engine
 	EthRedeemEngine transition.Engine
 	BtcEngine       transition.Engine
+
+	logger = log.NewDefaultLogger(os.Stdout).WithPrefix("event")
 )
 
 const (
@@ -32,3 +39,18 @@ func init() {
 	serialize.RegisterConcrete(new(JobETHSignRedeem), "eth_sign")
 	serialize.RegisterConcrete(new(JobETHVerifyRedeem), "eth_verify")
 }
+
+// Graphs returns the transitions of the tracker engines as Graphviz DOT, by engine name
+func Graphs() map[string]string {
+	btcState := func(s transition.Status) string {
+		return bitcoin.TrackerState(s).String()
+	}
+	ethState := func(s transition.Status) string {
+		return ethereum.TrackerState(s).String()
+	}
+	return map[string]string{
+		"btc":        transition.Dot("btc", BtcEngine, btcState),
+		"eth_lock":   transition.Dot("eth_lock", EthLockEngine, ethState),
+		"eth_redeem": transition.Dot("eth_redeem", EthRedeemEngine, ethState),
+	}
+}
@@ -6,16 +6,16 @@ package event
 
 import (
 	"github.com/Oneledger/protocol/action"
-	"github.com/Oneledger/protocol/app/node"
 	"github.com/Oneledger/protocol/consensus"
 	"github.com/Oneledger/protocol/log"
+	"github.com/Oneledger/protocol/signer"
 	"github.com/pkg/errors"
 	tmclient "github.com/tendermint/tendermint/rpc/client"
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
 )
 
 type Service struct {
-	nodeCtx node.Context
+	signer signer.Signer
 
 	logger *log.Logger
 	router action.Router
@@ -24,12 +24,12 @@ type Service struct {
 	tmrpc *tmclient.Local
 }
 
-func NewService(ctx node.Context, logger *log.Logger, router action.Router, tmnode *consensus.Node) *Service {
+func NewService(signer signer.Signer, logger *log.Logger, router action.Router, tmnode *consensus.Node) *Service {
 	return &Service{
-		nodeCtx: ctx,
-		logger:  logger,
-		router:  router,
-		tmrpc:   tmclient.NewLocal(tmnode),
+		signer: signer,
+		logger: logger,
+		router: router,
+		tmrpc:  tmclient.NewLocal(tmnode),
 	}
 }
 
@@ -70,26 +70,28 @@ func (reply *BroadcastReply) FromResultBroadcastTxCommit(result *ctypes.ResultBr
 	reply.Log = "check: " + result.CheckTx.Log + ", deliver: " + result.DeliverTx.Log
 }
 
+// broadcastError is why an internal broadcast failed
+func broadcastError(err error, rep BroadcastReply) error {
+	if err != nil {
+		return err
+	}
+	return errors.New(rep.Log)
+}
+
 func (svc Service) InternalBroadcast(request InternalBroadcastRequest, reply *BroadcastReply) error {
 
 	if err := svc.allowedTx(request.RawTx); err != nil {
 		return err
 	}
 
-	priKey := svc.nodeCtx.PrivVal()
-
-	h, err := priKey.GetHandler()
-	if err != nil {
-		return errors.Wrap(err, "wrong node private validator key")
-	}
-	signed, err := h.Sign(request.RawTx.RawBytes())
+	signed, err := svc.signer.SignValidator(request.RawTx.RawBytes())
 	if err != nil {
 		return errors.Wrap(err, "signing failed")
 	}
 	rawSignedTx := action.SignedTx{
 		RawTx: request.RawTx,
 		Signatures: []action.Signature{{
-			Signer: h.PubKey(),
+			Signer: svc.signer.ValidatorPubKey(),
 			Signed: signed,
 		}},
 	}
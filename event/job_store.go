@@ -0,0 +1,39 @@
+package event
+
+import (
+	"github.com/Oneledger/protocol/data/jobs"
+)
+
+// The job store is local to the node, only validators keep jobs in it. The transitions schedule
+// and drop their jobs through these, which log a failure instead of returning it, so a tracker
+// comes out of a transition the same on every node.
+
+// saveJob schedules the job
+func saveJob(js *jobs.JobStore, job jobs.Job) {
+	err := js.SaveJob(job)
+	if err != nil {
+		logger.Error("failed to save job", job.GetJobID(), err)
+	}
+}
+
+// getJob returns the job, nil when the store doesn't have it
+func getJob(js *jobs.JobStore, jobID string) jobs.Job {
+	job, err := js.GetJob(jobID)
+	if err != nil {
+		logger.Debug("failed to get job", jobID, err)
+		return nil
+	}
+	return job
+}
+
+// deleteJob drops the job if the store has it
+func deleteJob(js *jobs.JobStore, jobID string) {
+	job := getJob(js, jobID)
+	if job == nil {
+		return
+	}
+	err := js.DeleteJob(job)
+	if err != nil {
+		logger.Error("failed to delete job", jobID, err)
+	}
+}
@@ -7,108 +7,415 @@ package event
 import (
 	"fmt"
 	"runtime/debug"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/pkg/errors"
+
 	"github.com/Oneledger/protocol/data/chain"
 	"github.com/Oneledger/protocol/data/jobs"
+	"github.com/Oneledger/protocol/metrics"
 )
 
+// JobBus runs the jobs of every chain. Each chain has a scheduler of its own, every cycle it
+// hands the jobs that are due to a pool of workers and waits for them.
 type JobBus struct {
-	store *jobs.JobStore
-	ctx   *JobsContext
-	opt   Option
-	quit  chan struct{}
+	store  *jobs.JobStore
+	ctx    *JobsContext
+	opt    Option
+	chains map[chain.Type]*chainWorkers
+
+	quit      chan struct{}
+	closeOnce sync.Once
+	// the running schedulers
+	wg sync.WaitGroup
+
+	// the unix time the last cycle ended, and set while the schedulers run
+	lastCycle int64
+	running   int32
+}
+
+var (
+	ErrJobNotFound   = jobs.ErrNotFound
+	ErrJobRunning    = errors.New("job is running")
+	ErrBusNotRunning = errors.New("job bus isn't running")
+)
+
+// DefaultRetryPolicy is the policy of the jobs without one of their own
+var DefaultRetryPolicy = jobs.RetryPolicy{
+	MaxAttempts: MaxJobRetries,
+	Backoff:     10 * time.Second,
+	MaxBackoff:  10 * time.Minute,
 }
 
+const (
+	defaultWorkers      = 4
+	defaultDrainTimeout = 30 * time.Second
+)
+
 type Option struct {
 	BtcInterval time.Duration
 	EthInterval time.Duration
+	// jobs of the chain run at once, 4 when 0
+	BtcWorkers int
+	EthWorkers int
+	// Retry is the policy of the jobs without one of their own, DefaultRetryPolicy when zero
+	Retry jobs.RetryPolicy
+	// how long Close waits for the running jobs, 30 seconds when 0
+	DrainTimeout time.Duration
+	// Metrics the job counts and retries are recorded to, none when nil
+	Metrics *metrics.Metrics
+}
+
+// chainWorkers schedules the jobs of a chain
+type chainWorkers struct {
+	chain    chain.Type
+	store    *jobs.JobStore
+	interval time.Duration
+	workers  int
+	// asks for a cycle now, the channel is closed once it's over
+	trigger chan chan struct{}
+
+	// ids of the jobs being run, admin changes to them are refused
+	sync.Mutex
+	running map[string]bool
+
+	// job counts last set in the metrics
+	counted map[jobCount]float64
+}
+
+type jobCount struct {
+	typ    string
+	status string
 }
 
 func NewJobBus(opt Option, store *jobs.JobStore) *JobBus {
-	return &JobBus{
-		store: store,
-		opt:   opt,
-		quit:  make(chan struct{}),
+	if opt.Metrics == nil {
+		opt.Metrics = metrics.NopMetrics()
+	}
+	if opt.Retry == (jobs.RetryPolicy{}) {
+		opt.Retry = DefaultRetryPolicy
+	}
+	if opt.DrainTimeout == 0 {
+		opt.DrainTimeout = defaultDrainTimeout
+	}
+
+	j := &JobBus{
+		store:  store,
+		opt:    opt,
+		chains: make(map[chain.Type]*chainWorkers),
+		quit:   make(chan struct{}),
+	}
+	j.addChain(chain.BITCOIN, opt.BtcInterval, opt.BtcWorkers)
+	j.addChain(chain.ETHEREUM, opt.EthInterval, opt.EthWorkers)
+	return j
+}
+
+func (j *JobBus) addChain(c chain.Type, interval time.Duration, workers int) {
+	if workers <= 0 {
+		workers = defaultWorkers
+	}
+	j.chains[c] = &chainWorkers{
+		chain:    c,
+		store:    j.store.WithChain(c),
+		interval: interval,
+		workers:  workers,
+		trigger:  make(chan chan struct{}),
+		running:  make(map[string]bool),
+		counted:  make(map[jobCount]float64),
 	}
 }
 
 func (j *JobBus) Start(ctx *JobsContext) error {
 	j.ctx = ctx
-	tickerBtc := time.NewTicker(j.opt.BtcInterval)
-	tickerEth := time.NewTicker(j.opt.EthInterval)
-	go func() {
-		for {
-			select {
-			case <-tickerBtc.C:
-				ProcessAllJobs(j.ctx, j.store.WithChain(chain.BITCOIN))
-				DeleteCompletedJobs(j.ctx, j.store.WithChain(chain.BITCOIN))
-			case <-tickerEth.C:
-				ProcessAllJobs(j.ctx, j.store.WithChain(chain.ETHEREUM))
-			case <-j.quit:
-				tickerBtc.Stop()
-				tickerEth.Stop()
-				return
-			}
+	atomic.StoreInt32(&j.running, 1)
+	atomic.StoreInt64(&j.lastCycle, time.Now().Unix())
+	for _, w := range j.chains {
+		j.wg.Add(1)
+		go j.schedule(w)
+	}
+	return nil
+}
+
+func (j *JobBus) schedule(w *chainWorkers) {
+	defer j.wg.Done()
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			j.cycle(w)
+		case done := <-w.trigger:
+			j.cycle(w)
+			close(done)
+		case <-j.quit:
+			return
 		}
+	}
+}
+
+// Close stops the schedulers, the jobs running are given DrainTimeout to finish
+func (j *JobBus) Close() {
+	j.closeOnce.Do(func() {
+		close(j.quit)
+	})
+	defer atomic.StoreInt32(&j.running, 0)
+
+	done := make(chan struct{})
+	go func() {
+		j.wg.Wait()
+		close(done)
 	}()
+	select {
+	case <-done:
+	case <-time.After(j.opt.DrainTimeout):
+		if j.ctx != nil {
+			j.ctx.Logger.Error("job bus closed with jobs still running after", j.opt.DrainTimeout)
+		}
+	}
+}
 
-	return nil
+// Alive tells if the schedulers of the bus are running
+func (j *JobBus) Alive() bool {
+	return atomic.LoadInt32(&j.running) == 1
 }
 
-func (j *JobBus) Close() {
-	close(j.quit)
+// LastCycle is when the bus last finished processing the jobs of a chain, or when it started
+func (j *JobBus) LastCycle() time.Time {
+	return time.Unix(atomic.LoadInt64(&j.lastCycle), 0)
+}
+
+// Interval is the longer of the chain intervals of the bus
+func (j *JobBus) Interval() time.Duration {
+	if j.opt.BtcInterval > j.opt.EthInterval {
+		return j.opt.BtcInterval
+	}
+	return j.opt.EthInterval
+}
+
+// cycle runs the jobs of the chain that are due, completed bitcoin jobs are deleted after.
+// No more jobs are handed to the workers once the bus is closing.
+func (j *JobBus) cycle(w *chainWorkers) {
+	due := j.dueJobs(w, time.Now())
+
+	queue := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < w.workers && i < len(due); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range queue {
+				j.run(w, id)
+			}
+		}()
+	}
+
+dispatch:
+	for _, id := range due {
+		select {
+		case queue <- id:
+		case <-j.quit:
+			break dispatch
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	if w.chain == chain.BITCOIN {
+		DeleteCompletedJobs(j.ctx, w.store)
+	}
+	j.count(w)
+	atomic.StoreInt64(&j.lastCycle, time.Now().Unix())
 }
 
-type JobProcess func(job jobs.Job) jobs.Job
+// dueJobs returns the ids of the jobs of the chain to be run at now
+func (j *JobBus) dueJobs(w *chainWorkers, now time.Time) []string {
+	ids := make([]string, 0)
+	w.store.Iterate(func(job jobs.Job) {
+		if job.JobState().Due(now) {
+			ids = append(ids, job.GetJobID())
+		}
+	})
+	return ids
+}
 
-func ProcessAllJobs(ctx *JobsContext, js *jobs.JobStore) {
+// run does a job once and saves how it went, unless the job was cancelled in the meantime
+func (j *JobBus) run(w *chainWorkers, id string) {
+	w.Lock()
+	w.running[id] = true
+	w.Unlock()
+	defer func() {
+		w.Lock()
+		delete(w.running, id)
+		w.Unlock()
+	}()
 
-	RangeJobs(js, func(job jobs.Job) jobs.Job {
+	job, err := w.store.GetJob(id)
+	if err != nil {
+		// cancelled since the cycle started
+		return
+	}
 
-		fmt.Println("trying to do job:", job.GetType(), job.GetJobID(), job.IsDone())
+	logger := j.ctx.Logger.With("job", id, "type", job.GetType())
+	state := job.JobState()
+	state.Status = jobs.InProgress
+	logger.Debug("trying to do job, attempts:", state.Attempts)
 
-		func() {
-			defer func() {
-				if r := recover(); r != nil {
-					fmt.Println("panic in job: ", job.GetJobID())
-					fmt.Println(r)
-					fmt.Println(string(debug.Stack()))
-				}
-			}()
-			job.DoMyJob(ctx)
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Error("panic in job:", r, string(debug.Stack()))
+				state.Fail(errors.Errorf("panic: %v", r))
+			}
 		}()
+		job.DoMyJob(j.ctx)
+	}()
+
+	if !job.IsDone() {
+		j.opt.Metrics.JobRetries.WithLabelValues(job.GetType()).Inc()
+	}
+	j.settle(job, time.Now())
+	if state.Status == jobs.Dead {
+		logger.Error("job out of retries:", state.LastError)
+	}
+
+	err = w.store.UpdateJob(job)
+	if err != nil && errors.Cause(err) != jobs.ErrNotFound {
+		logger.Error("failed to save job", err)
+	}
+}
+
+// settle schedules the next run of a job after it ran. A failed job waits as its retry
+// policy says and is dead once out of attempts, a job that neither failed nor completed runs
+// again in the next cycle.
+func (j *JobBus) settle(job jobs.Job, now time.Time) {
+	state := job.JobState()
+	state.NextRun = 0
+	if state.Status != jobs.Failed {
+		return
+	}
+
+	state.Attempts++
+	policy := j.opt.Retry
+	if r, ok := job.(jobs.Retrier); ok {
+		policy = r.RetryPolicy(policy)
+	}
+	if state.Attempts < policy.MaxAttempts {
+		state.NextRun = now.Add(policy.Delay(state.Attempts)).UnixNano()
+		return
+	}
+
+	e, ok := job.(jobs.Expirer)
+	if !ok {
+		state.Status = jobs.Dead
+		return
+	}
+	if err := e.Expire(j.ctx); err != nil {
+		state.LastError = err.Error()
+		state.NextRun = now.Add(policy.MaxBackoff).UnixNano()
+		return
+	}
+	state.Status = jobs.Completed
+}
+
+func (j *JobBus) chainWorkers(c chain.Type) (*chainWorkers, error) {
+	w, ok := j.chains[c]
+	if !ok {
+		return nil, errors.Errorf("no jobs for chain %s", c)
+	}
+	return w, nil
+}
 
-		return job
+// Jobs returns the jobs of the chain in the store, done or not
+func (j *JobBus) Jobs(c chain.Type) ([]jobs.Job, error) {
+	w, err := j.chainWorkers(c)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]jobs.Job, 0)
+	w.store.Iterate(func(job jobs.Job) {
+		list = append(list, job)
 	})
+	return list, nil
 }
 
-func RangeJobs(js *jobs.JobStore, pro JobProcess) {
+// RetryJob starts a job over, one that failed or is dead runs again in the next cycle
+func (j *JobBus) RetryJob(c chain.Type, id string) (jobs.Job, error) {
+	return j.changeJob(c, id, func(w *chainWorkers, job jobs.Job) error {
+		job.JobState().Reset()
+		return w.store.SaveJob(job)
+	})
+}
 
-	jobkeys := make([]string, 0, 20)
-	js.Iterate(func(job jobs.Job) {
-		//fmt.Println("Searching Jobstore",job.GetType())
-		if !job.IsDone() {
-			jobkeys = append(jobkeys, job.GetJobID())
-		}
+// CancelJob deletes a job from the store, it isn't run again
+func (j *JobBus) CancelJob(c chain.Type, id string) (jobs.Job, error) {
+	return j.changeJob(c, id, func(w *chainWorkers, job jobs.Job) error {
+		return w.store.DeleteJob(job)
 	})
-	for _, key := range jobkeys {
+}
 
-		job, err := js.GetJob(key)
-		if err != nil {
-			fmt.Println("err get job by key", key)
-			continue
-		}
+// changeJob runs fn with a job that isn't running, the workers wait for it
+func (j *JobBus) changeJob(c chain.Type, id string, fn func(w *chainWorkers, job jobs.Job) error) (jobs.Job, error) {
+	w, err := j.chainWorkers(c)
+	if err != nil {
+		return nil, err
+	}
 
-		fmt.Println("processing job", job)
-		job = pro(job)
+	w.Lock()
+	defer w.Unlock()
+	if w.running[id] {
+		return nil, errors.Wrap(ErrJobRunning, id)
+	}
+	job, err := w.store.GetJob(id)
+	if err != nil {
+		return nil, errors.Wrap(ErrJobNotFound, id)
+	}
+	return job, fn(w, job)
+}
 
-		err = js.SaveJob(job)
-		if err != nil {
-			fmt.Println("range job; err saving job", err)
+// Cycle runs the due jobs of every chain now, instead of waiting for their intervals. It
+// returns once the cycles are over.
+func (j *JobBus) Cycle() error {
+	if !j.Alive() {
+		return ErrBusNotRunning
+	}
+
+	dones := make([]chan struct{}, 0, len(j.chains))
+	for _, w := range j.chains {
+		done := make(chan struct{})
+		select {
+		case w.trigger <- done:
+			dones = append(dones, done)
+		case <-j.quit:
+			return ErrBusNotRunning
 		}
+	}
+	for _, done := range dones {
+		<-done
+	}
+	return nil
+}
+
+// count sets the job counts of the chain in the metrics, counts of types and statuses gone
+// from the store drop to zero
+func (j *JobBus) count(w *chainWorkers) {
+	counts := make(map[jobCount]float64)
+	w.store.Iterate(func(job jobs.Job) {
+		counts[jobCount{job.GetType(), job.JobState().Status.String()}]++
+	})
 
+	c := w.chain.String()
+	for k := range w.counted {
+		if _, ok := counts[k]; !ok {
+			j.opt.Metrics.Jobs.WithLabelValues(c, k.typ, k.status).Set(0)
+		}
+	}
+	for k, n := range counts {
+		j.opt.Metrics.Jobs.WithLabelValues(c, k.typ, k.status).Set(n)
 	}
+	w.counted = counts
 }
 
 func DeleteCompletedJobs(ctx *JobsContext, js *jobs.JobStore) {
@@ -125,6 +432,7 @@ func DeleteCompletedJobs(ctx *JobsContext, js *jobs.JobStore) {
 		job, err := js.GetJob(key)
 		if err != nil {
 			fmt.Println("err getting job by key", err)
+			continue
 		}
 		if job.IsDone() {
 			err = js.DeleteJob(job)
@@ -0,0 +1,268 @@
+package event
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Oneledger/protocol/config"
+	"github.com/Oneledger/protocol/data/chain"
+	"github.com/Oneledger/protocol/data/jobs"
+	"github.com/Oneledger/protocol/log"
+	"github.com/Oneledger/protocol/metrics"
+	"github.com/Oneledger/protocol/serialize"
+)
+
+// busJob does what its fields say, the jobs are read from the store for every run so what
+// they share goes through the package variables below
+type busJob struct {
+	ID        string
+	Fails     bool
+	Panics    bool
+	Expirable bool
+	ExpireErr bool
+	Wait      bool
+
+	jobs.State
+}
+
+var (
+	busRunning, busMaxRunning int32
+	busRelease                chan struct{}
+	busStarted                chan string
+)
+
+func (j *busJob) DoMyJob(ctx interface{}) {
+	n := atomic.AddInt32(&busRunning, 1)
+	defer atomic.AddInt32(&busRunning, -1)
+	for {
+		max := atomic.LoadInt32(&busMaxRunning)
+		if n <= max || atomic.CompareAndSwapInt32(&busMaxRunning, max, n) {
+			break
+		}
+	}
+
+	if j.Wait {
+		busStarted <- j.ID
+		<-busRelease
+	}
+	if j.Panics {
+		panic("boom")
+	}
+	if j.Fails {
+		j.Fail(errors.New("failed"))
+		return
+	}
+	j.Status = jobs.Completed
+}
+
+func (j *busJob) GetType() string  { return "bus" }
+func (j *busJob) GetJobID() string { return j.ID }
+
+type expiringJob struct {
+	busJob
+}
+
+func (j *expiringJob) Expire(ctx interface{}) error {
+	if j.ExpireErr {
+		return errors.New("expire failed")
+	}
+	return nil
+}
+
+func init() {
+	serialize.RegisterConcrete(new(busJob), "event_test_bus")
+	serialize.RegisterConcrete(new(expiringJob), "event_test_expiring")
+}
+
+func newTestBus(t *testing.T, opt Option) (*JobBus, *jobs.JobStore, func()) {
+	dir, err := ioutil.TempDir("", "jobbus")
+	require.NoError(t, err)
+	store := jobs.NewJobStore(*config.DefaultServerConfig(), dir)
+	bus := NewJobBus(opt, store)
+	bus.ctx = &JobsContext{Logger: log.NewLoggerWithPrefix(ioutil.Discard, "test")}
+	return bus, store.WithChain(chain.ETHEREUM), func() {
+		store.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func jobState(t *testing.T, store *jobs.JobStore, id string) jobs.State {
+	job, err := store.GetJob(id)
+	require.NoError(t, err)
+	return *job.JobState()
+}
+
+func TestJobBus_Retries(t *testing.T) {
+	policy := jobs.RetryPolicy{MaxAttempts: 3, Backoff: time.Minute, MaxBackoff: time.Hour}
+	bus, store, done := newTestBus(t, Option{Retry: policy})
+	defer done()
+	w := bus.chains[chain.ETHEREUM]
+
+	require.NoError(t, store.SaveJob(&busJob{ID: "fails", Fails: true}))
+	require.NoError(t, store.SaveJob(&busJob{ID: "panics", Panics: true}))
+
+	// a failed run waits as the policy says, the wait doubles
+	start := time.Now()
+	for attempt := 1; attempt < policy.MaxAttempts; attempt++ {
+		for _, id := range []string{"fails", "panics"} {
+			bus.run(w, id)
+			s := jobState(t, store, id)
+			assert.Equal(t, jobs.Failed, s.Status)
+			assert.Equal(t, attempt, s.Attempts)
+			assert.True(t, s.NextRun >= start.Add(policy.Delay(attempt)).UnixNano())
+			assert.False(t, s.Due(start))
+		}
+	}
+	assert.Contains(t, jobState(t, store, "panics").LastError, "boom")
+	assert.Empty(t, bus.dueJobs(w, start))
+	assert.Len(t, bus.dueJobs(w, start.Add(2*policy.Backoff+time.Second)), 2)
+
+	// out of attempts the jobs are dead and not run anymore
+	bus.run(w, "fails")
+	bus.run(w, "panics")
+	for _, id := range []string{"fails", "panics"} {
+		s := jobState(t, store, id)
+		assert.Equal(t, jobs.Dead, s.Status)
+		assert.Equal(t, policy.MaxAttempts, s.Attempts)
+	}
+	assert.Empty(t, bus.dueJobs(w, start.Add(24*time.Hour)))
+
+	// until they're retried
+	_, err := bus.RetryJob(chain.ETHEREUM, "fails")
+	require.NoError(t, err)
+	assert.Equal(t, jobs.State{}, jobState(t, store, "fails"))
+	assert.Equal(t, []string{"fails"}, bus.dueJobs(w, start))
+
+	_, err = bus.CancelJob(chain.ETHEREUM, "panics")
+	require.NoError(t, err)
+	_, err = bus.RetryJob(chain.ETHEREUM, "panics")
+	assert.Equal(t, ErrJobNotFound, errors.Cause(err))
+}
+
+func TestJobBus_Expire(t *testing.T) {
+	policy := jobs.RetryPolicy{MaxAttempts: 1, Backoff: time.Minute, MaxBackoff: time.Hour}
+	bus, store, done := newTestBus(t, Option{Retry: policy})
+	defer done()
+	w := bus.chains[chain.ETHEREUM]
+
+	require.NoError(t, store.SaveJob(&expiringJob{busJob{ID: "expires", Fails: true}}))
+	require.NoError(t, store.SaveJob(&expiringJob{busJob{ID: "stuck", Fails: true, ExpireErr: true}}))
+
+	start := time.Now()
+	bus.run(w, "expires")
+	assert.Equal(t, jobs.Completed, jobState(t, store, "expires").Status)
+
+	// a failed expiry is tried again after MaxBackoff
+	bus.run(w, "stuck")
+	s := jobState(t, store, "stuck")
+	assert.Equal(t, jobs.Failed, s.Status)
+	assert.Equal(t, "expire failed", s.LastError)
+	assert.True(t, s.NextRun >= start.Add(policy.MaxBackoff).UnixNano())
+}
+
+func TestJobBus_Workers(t *testing.T) {
+	bus, store, done := newTestBus(t, Option{EthWorkers: 2})
+	defer done()
+	w := bus.chains[chain.ETHEREUM]
+
+	busRelease = make(chan struct{})
+	busStarted = make(chan string, 5)
+	atomic.StoreInt32(&busMaxRunning, 0)
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		require.NoError(t, store.SaveJob(&busJob{ID: id, Wait: true}))
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		bus.cycle(w)
+	}()
+
+	// two run at once, the running ones can't be changed
+	running := []string{<-busStarted, <-busStarted}
+	select {
+	case id := <-busStarted:
+		t.Fatalf("%s started with two jobs running", id)
+	case <-time.After(50 * time.Millisecond):
+	}
+	_, err := bus.CancelJob(chain.ETHEREUM, running[0])
+	assert.Equal(t, ErrJobRunning, errors.Cause(err))
+
+	close(busRelease)
+	wg.Wait()
+	assert.EqualValues(t, 2, atomic.LoadInt32(&busMaxRunning))
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		assert.Equal(t, jobs.Completed, jobState(t, store, id).Status)
+	}
+}
+
+func TestJobBus_CancelledWhileRunning(t *testing.T) {
+	bus, store, done := newTestBus(t, Option{})
+	defer done()
+	w := bus.chains[chain.ETHEREUM]
+
+	busRelease = make(chan struct{})
+	busStarted = make(chan string, 1)
+	require.NoError(t, store.SaveJob(&busJob{ID: "a", Wait: true}))
+
+	ran := make(chan struct{})
+	go func() {
+		bus.run(w, "a")
+		close(ran)
+	}()
+	<-busStarted
+	job, err := store.GetJob("a")
+	require.NoError(t, err)
+	require.NoError(t, store.DeleteJob(job))
+	close(busRelease)
+	<-ran
+
+	// the run doesn't bring the job back
+	_, err = store.GetJob("a")
+	assert.Equal(t, jobs.ErrNotFound, errors.Cause(err))
+}
+
+func TestJobBus_Metrics(t *testing.T) {
+	m := metrics.PrometheusMetrics()
+	policy := jobs.RetryPolicy{MaxAttempts: 3, Backoff: time.Minute, MaxBackoff: time.Hour}
+	bus, store, done := newTestBus(t, Option{Retry: policy, Metrics: m})
+	defer done()
+	w := bus.chains[chain.ETHEREUM]
+
+	scrape := func() string {
+		rec := httptest.NewRecorder()
+		m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, metrics.Path, nil))
+		return rec.Body.String()
+	}
+
+	require.NoError(t, store.SaveJob(&busJob{ID: "done"}))
+	require.NoError(t, store.SaveJob(&busJob{ID: "fails", Fails: true}))
+	bus.cycle(w)
+
+	body := scrape()
+	assert.Contains(t, body, `oneledger_jobs_jobs{chain="Ethereum",status="completed",type="bus"} 1`)
+	assert.Contains(t, body, `oneledger_jobs_jobs{chain="Ethereum",status="failed",type="bus"} 1`)
+	assert.Contains(t, body, `oneledger_jobs_retries_total{type="bus"} 1`)
+
+	// counts of jobs gone from the store drop to zero
+	job, err := store.GetJob("fails")
+	require.NoError(t, err)
+	require.NoError(t, store.DeleteJob(job))
+	bus.cycle(w)
+
+	body = scrape()
+	assert.Contains(t, body, `oneledger_jobs_jobs{chain="Ethereum",status="completed",type="bus"} 1`)
+	assert.Contains(t, body, `oneledger_jobs_jobs{chain="Ethereum",status="failed",type="bus"} 0`)
+	assert.Contains(t, body, `oneledger_jobs_retries_total{type="bus"} 1`)
+}
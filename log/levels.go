@@ -0,0 +1,174 @@
+package log
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+)
+
+// AllModules names every module when setting a level, modules with a level of their own
+// keep it
+const AllModules = "*"
+
+// Format of the log lines
+type Format int32
+
+const (
+	TextFormat Format = iota
+	JSONFormat
+)
+
+var format int32
+
+// SetFormat changes the format of every logger
+func SetFormat(f Format) {
+	atomic.StoreInt32(&format, int32(f))
+}
+
+func currentFormat() Format {
+	return Format(atomic.LoadInt32(&format))
+}
+
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "text":
+		return TextFormat, nil
+	case "json":
+		return JSONFormat, nil
+	}
+	return TextFormat, errors.Errorf("unknown log format %q", s)
+}
+
+var levelNames = map[Level]string{
+	Fatal:   "fatal",
+	Error:   "error",
+	Warning: "warn",
+	Info:    "info",
+	Debug:   "debug",
+	Detail:  "detail",
+}
+
+// Name is the level as it is written in the config and in json logs
+func (l Level) Name() string {
+	if name, ok := levelNames[l]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// ParseLevel reads a level by its name or its number
+func ParseLevel(s string) (Level, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "warning" {
+		return Warning, nil
+	}
+	for l, name := range levelNames {
+		if s == name {
+			return l, nil
+		}
+	}
+	n, err := strconv.Atoi(s)
+	if err == nil && n >= int(Fatal) && n <= int(Detail) {
+		return Level(n), nil
+	}
+	return Fatal, errors.Errorf("unknown log level %q", s)
+}
+
+// ParseLevels reads module levels like "app=info, event=debug, storage=warn". A level
+// without a module applies to all modules.
+func ParseLevels(s string) (map[string]Level, error) {
+	levels := make(map[string]Level)
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		module, value := AllModules, part
+		if i := strings.Index(part, "="); i >= 0 {
+			module, value = strings.TrimSpace(part[:i]), part[i+1:]
+		}
+		if module == "" {
+			return nil, errors.Errorf("missing module in %q", part)
+		}
+		level, err := ParseLevel(value)
+		if err != nil {
+			return nil, err
+		}
+		levels[module] = level
+	}
+	return levels, nil
+}
+
+// FormatLevels writes module levels the way ParseLevels reads them
+func FormatLevels(levels map[string]Level) string {
+	parts := make([]string, 0, len(levels))
+	for module, level := range levels {
+		parts = append(parts, module+"="+level.Name())
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ", ")
+}
+
+// levelSet holds the levels set in the config or at runtime, they take precedence over the
+// level a logger was created with
+type levelSet struct {
+	sync.RWMutex
+	modules map[string]Level
+}
+
+var levels = &levelSet{modules: make(map[string]Level)}
+
+func (s *levelSet) get(module string, level Level) Level {
+	s.RLock()
+	defer s.RUnlock()
+
+	if l, ok := s.modules[module]; ok {
+		return l
+	}
+	if l, ok := s.modules[AllModules]; ok {
+		return l
+	}
+	return level
+}
+
+// SetLevel sets the level of a module, AllModules for the modules without one
+func SetLevel(module string, level Level) {
+	levels.Lock()
+	defer levels.Unlock()
+
+	levels.modules[module] = level
+}
+
+// SetLevels adds the module levels to the ones already set
+func SetLevels(modules map[string]Level) {
+	levels.Lock()
+	defer levels.Unlock()
+
+	for module, level := range modules {
+		levels.modules[module] = level
+	}
+}
+
+// ResetLevels drops the module levels, loggers use their own level again
+func ResetLevels() {
+	levels.Lock()
+	defer levels.Unlock()
+
+	levels.modules = make(map[string]Level)
+}
+
+// Levels returns the module levels in use
+func Levels() map[string]Level {
+	levels.RLock()
+	defer levels.RUnlock()
+
+	modules := make(map[string]Level, len(levels.modules))
+	for module, level := range levels.modules {
+		modules[module] = level
+	}
+	return modules
+}
@@ -1,6 +1,7 @@
 package log
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -70,8 +71,12 @@ func DefaultOptions() Options {
 type Logger struct {
 	w      io.Writer
 	prefix string
-	// Basic log-level filtering
+	// Basic log-level filtering, a level set for the module takes precedence
 	level Level
+	// module is the first word of the prefix
+	module string
+	// key/value pairs written with every line
+	fields []interface{}
 }
 
 func NewDefaultLogger(w io.Writer) *Logger {
@@ -82,7 +87,20 @@ func NewLoggerWithOpts(w io.Writer, opts Options) *Logger {
 	if opts.Sync {
 		w = newSyncWriter(w)
 	}
-	return &Logger{w, opts.Prefix, opts.Level}
+	return &Logger{
+		w:      w,
+		prefix: opts.Prefix,
+		level:  opts.Level,
+		module: moduleOf(opts.Prefix),
+	}
+}
+
+func moduleOf(prefix string) string {
+	fields := strings.Fields(prefix)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
 }
 
 // NewLoggerWithPrefix returns a brand new Logger with the prefix attached
@@ -106,11 +124,30 @@ func (l *Logger) WithLevel(level Level) *Logger {
 // WithPrefix returns a new logger with the prefix appended to the current logger's prefix
 func (l Logger) WithPrefix(prefix string) *Logger {
 	nextPrefix := strings.Trim(l.prefix+" "+prefix, " ")
-	return NewLoggerWithOpts(l.w, Options{
+	next := NewLoggerWithOpts(l.w, Options{
 		Prefix: nextPrefix,
 		Sync:   false,
 		Level:  l.level,
 	})
+	next.fields = l.fields
+	return next
+}
+
+// With returns a new logger writing the key/value pairs with every line, e.g.
+// logger.With("height", height, "tx", hash)
+func (l Logger) With(keyvals ...interface{}) *Logger {
+	if len(keyvals)%2 != 0 {
+		keyvals = append(keyvals, nil)
+	}
+	fields := make([]interface{}, 0, len(l.fields)+len(keyvals))
+	fields = append(fields, l.fields...)
+	l.fields = append(fields, keyvals...)
+	return &l
+}
+
+// Enabled tells if lines of the level are written
+func (l *Logger) Enabled(level Level) bool {
+	return level <= levels.get(l.module, l.level)
 }
 
 func (l *Logger) Info(args ...interface{}) {
@@ -170,17 +207,62 @@ func (l *Logger) Dump(msg string, args ...interface{}) {
 }
 
 func (l *Logger) fprintln(level Level, now time.Time, args ...interface{}) {
-	if level > l.level {
+	if !l.Enabled(level) {
 		return
 	}
 
-	args = append([]interface{}{l.completePrefix(level, now)}, args...)
-	_, err := fmt.Fprintln(l.w, args...)
-	if err != nil {
+	var err error
+	switch currentFormat() {
+	case JSONFormat:
+		_, err = l.w.Write(l.jsonLine(level, now, args))
+	default:
+		args = append([]interface{}{l.completePrefix(level, now)}, args...)
+		for i := 0; i < len(l.fields); i += 2 {
+			args = append(args, fmt.Sprintf("%v=%v", l.fields[i], l.fields[i+1]))
+		}
+		_, err = fmt.Fprintln(l.w, args...)
+	}
+	if err != nil && level != Error {
 		l.Error("LoggingError", err)
 	}
 }
 
+// jsonLine writes the line as a json object holding the time, level, module, message and
+// the fields of the logger
+func (l *Logger) jsonLine(level Level, now time.Time, args []interface{}) []byte {
+	line := make(map[string]interface{}, 4+len(l.fields)/2)
+	for i := 0; i < len(l.fields); i += 2 {
+		line[fmt.Sprint(l.fields[i])] = jsonValue(l.fields[i+1])
+	}
+	line["time"] = now.Format(time.RFC3339Nano)
+	line["level"] = level.Name()
+	line["module"] = l.prefix
+	line["msg"] = strings.TrimSuffix(fmt.Sprintln(args...), "\n")
+
+	data, err := json.Marshal(line)
+	if err != nil {
+		data, _ = json.Marshal(map[string]string{
+			"time":  line["time"].(string),
+			"level": level.Name(),
+			"msg":   line["msg"].(string),
+			"error": err.Error(),
+		})
+	}
+	return append(data, '\n')
+}
+
+func jsonValue(v interface{}) interface{} {
+	// fmt survives nil receivers of String and Error
+	switch t := v.(type) {
+	case error, fmt.Stringer:
+		return fmt.Sprint(t)
+	case json.Marshaler, string, bool, nil,
+		int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return t
+	}
+	return fmt.Sprint(v)
+}
+
 func (l *Logger) fprintf(level Level, now time.Time, format string, args ...interface{}) {
 	l.fprintln(level, now, fmt.Sprintf(format, args...))
 }
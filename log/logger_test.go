@@ -0,0 +1,74 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLevels(t *testing.T) {
+	levels, err := ParseLevels("app=info, event=debug,storage=warn, 1")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]Level{
+		"app":      Info,
+		"event":    Debug,
+		"storage":  Warning,
+		AllModules: Error,
+	}, levels)
+	assert.Equal(t, "*=error, app=info, event=debug, storage=warn", FormatLevels(levels))
+
+	_, err = ParseLevels("app=loud")
+	assert.Error(t, err)
+	_, err = ParseLevels("=info")
+	assert.Error(t, err)
+}
+
+func TestModuleLevels(t *testing.T) {
+	defer ResetLevels()
+
+	buf := &bytes.Buffer{}
+	app := NewLoggerWithPrefix(buf, "app").WithLevel(Info)
+	event := NewLoggerWithPrefix(buf, "event").WithLevel(Info)
+
+	app.Debug("hidden")
+	assert.Empty(t, buf.String())
+
+	SetLevel("app", Debug)
+	app.Debug("shown")
+	event.Debug("hidden")
+	assert.Contains(t, buf.String(), "shown")
+	assert.NotContains(t, buf.String(), "hidden")
+
+	SetLevel(AllModules, Error)
+	buf.Reset()
+	event.Info("hidden")
+	app.Debug("shown")
+	assert.NotContains(t, buf.String(), "hidden")
+	assert.Contains(t, buf.String(), "shown")
+}
+
+func TestJSONFormat(t *testing.T) {
+	SetFormat(JSONFormat)
+	defer SetFormat(TextFormat)
+
+	buf := &bytes.Buffer{}
+	logger := NewLoggerWithPrefix(buf, "app").With("height", 10, "tx", "abcd")
+	logger.Info("delivered", 2, "txs")
+
+	line := make(map[string]interface{})
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, "info", line["level"])
+	assert.Equal(t, "app", line["module"])
+	assert.Equal(t, "delivered 2 txs", line["msg"])
+	assert.Equal(t, float64(10), line["height"])
+	assert.Equal(t, "abcd", line["tx"])
+
+	SetFormat(TextFormat)
+	buf.Reset()
+	logger.Info("delivered")
+	assert.True(t, strings.HasSuffix(buf.String(), "delivered height=10 tx=abcd\n"))
+}
@@ -0,0 +1,127 @@
+/*
+
+ */
+
+// Package metrics holds the prometheus metrics of the node, served at /metrics on the SDK port
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	Namespace = "oneledger"
+	Path      = "/metrics"
+)
+
+// Metrics are updated by the app at every commit, by the job bus after every cycle and by
+// the rpc server for every request. Gauges describing a set, like the validators, are reset
+// before they are set again so nothing stale stays behind.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	Height   prometheus.Gauge
+	BlockTxs prometheus.Gauge
+	BlockGas prometheus.Gauge
+	// delivered txs by action type and result code
+	Txs *prometheus.CounterVec
+
+	// fee pool balance by currency, in the smallest unit of the currency
+	FeePool        *prometheus.GaugeVec
+	ValidatorPower *prometheus.GaugeVec
+
+	// jobs in the job store by chain, type and status
+	Jobs *prometheus.GaugeVec
+	// runs of jobs that didn't finish them, by type
+	JobRetries *prometheus.CounterVec
+	// bridge trackers by chain and state
+	Trackers *prometheus.GaugeVec
+
+	// rpc request duration by method
+	RPCLatency *prometheus.HistogramVec
+}
+
+// PrometheusMetrics returns metrics registered to a registry of their own
+func PrometheusMetrics() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+
+		Height: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: Namespace, Subsystem: "app", Name: "height",
+			Help: "Height of the last committed block.",
+		}),
+		BlockTxs: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: Namespace, Subsystem: "app", Name: "block_txs",
+			Help: "Number of txs in the last committed block.",
+		}),
+		BlockGas: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: Namespace, Subsystem: "app", Name: "block_gas_used",
+			Help: "Gas used by the last committed block.",
+		}),
+		Txs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace, Subsystem: "app", Name: "txs_total",
+			Help: "Delivered txs by action type and result code.",
+		}, []string{"type", "code"}),
+
+		FeePool: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: Namespace, Subsystem: "app", Name: "fee_pool",
+			Help: "Balance of the fee pool in the smallest unit of the currency.",
+		}, []string{"currency"}),
+		ValidatorPower: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: Namespace, Subsystem: "app", Name: "validator_power",
+			Help: "Voting power of the validators.",
+		}, []string{"address", "name"}),
+
+		Jobs: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: Namespace, Subsystem: "jobs", Name: "jobs",
+			Help: "Jobs in the job store by chain, type and status.",
+		}, []string{"chain", "type", "status"}),
+		JobRetries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace, Subsystem: "jobs", Name: "retries_total",
+			Help: "Runs of jobs that did not finish them, by type.",
+		}, []string{"type"}),
+		Trackers: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: Namespace, Subsystem: "bridge", Name: "trackers",
+			Help: "Bridge trackers by chain and state.",
+		}, []string{"chain", "state"}),
+
+		RPCLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace, Subsystem: "rpc", Name: "request_duration_seconds",
+			Help:    "Duration of the rpc requests by method.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method"}),
+	}
+
+	m.registry.MustRegister(
+		m.Height, m.BlockTxs, m.BlockGas, m.Txs,
+		m.FeePool, m.ValidatorPower,
+		m.Jobs, m.JobRetries, m.Trackers,
+		m.RPCLatency,
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// NopMetrics returns metrics that are updated like the others but never served
+func NopMetrics() *Metrics {
+	m := PrometheusMetrics()
+	m.registry = nil
+	return m
+}
+
+// Enabled tells if the metrics are served
+func (m *Metrics) Enabled() bool {
+	return m.registry != nil
+}
+
+// Handler serves the metrics in the prometheus text format
+func (m *Metrics) Handler() http.Handler {
+	if m.registry == nil {
+		return http.NotFoundHandler()
+	}
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
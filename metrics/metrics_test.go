@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func scrape(t *testing.T, m *Metrics) (int, string) {
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, Path, nil))
+	body, err := ioutil.ReadAll(rec.Body)
+	require.NoError(t, err)
+	return rec.Code, string(body)
+}
+
+func TestMetrics_Handler(t *testing.T) {
+	m := PrometheusMetrics()
+	assert.True(t, m.Enabled())
+
+	m.Height.Set(7)
+	m.BlockTxs.Set(2)
+	m.BlockGas.Set(1500)
+	m.Txs.WithLabelValues("SEND", "0").Inc()
+	m.FeePool.WithLabelValues("OLT").Set(100)
+	m.ValidatorPower.WithLabelValues("0lt01", "node-0").Set(10)
+	m.Jobs.WithLabelValues("Bitcoin", "addSignature", "new").Set(3)
+	m.JobRetries.WithLabelValues("addSignature").Inc()
+	m.Trackers.WithLabelValues("Ethereum", "New").Set(1)
+	m.RPCLatency.WithLabelValues("query.Balance").Observe(0.01)
+
+	code, body := scrape(t, m)
+	require.Equal(t, http.StatusOK, code)
+	for _, line := range []string{
+		`oneledger_app_height 7`,
+		`oneledger_app_block_txs 2`,
+		`oneledger_app_block_gas_used 1500`,
+		`oneledger_app_txs_total{code="0",type="SEND"} 1`,
+		`oneledger_app_fee_pool{currency="OLT"} 100`,
+		`oneledger_app_validator_power{address="0lt01",name="node-0"} 10`,
+		`oneledger_jobs_jobs{chain="Bitcoin",status="new",type="addSignature"} 3`,
+		`oneledger_jobs_retries_total{type="addSignature"} 1`,
+		`oneledger_bridge_trackers{chain="Ethereum",state="New"} 1`,
+		`oneledger_rpc_request_duration_seconds_count{method="query.Balance"} 1`,
+		`go_goroutines`,
+	} {
+		assert.Contains(t, body, line)
+	}
+}
+
+func TestMetrics_Nop(t *testing.T) {
+	m := NopMetrics()
+	assert.False(t, m.Enabled())
+
+	// still updated, never served
+	m.Height.Set(7)
+	code, _ := scrape(t, m)
+	assert.Equal(t, http.StatusNotFound, code)
+}
@@ -0,0 +1,238 @@
+package rpc
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// APIKeyPrefix starts every API key, so they can't be mistaken for tokens
+const APIKeyPrefix = "olk_"
+
+var (
+	ErrAPIKeyInvalid  = errors.New("invalid api key")
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	ErrAPIKeyRevoked  = errors.New("api key revoked")
+	ErrAPIKeyExpired  = errors.New("api key expired")
+)
+
+// APIKey gives the roles to whoever holds its secret, only the hash of the secret is kept
+type APIKey struct {
+	ID      string    `json:"id"`
+	Name    string    `json:"name"`
+	Roles   []Role    `json:"roles"`
+	Hash    string    `json:"hash"`
+	Created time.Time `json:"created"`
+	// zero for keys that don't expire
+	Expires time.Time `json:"expires"`
+	Revoked bool      `json:"revoked"`
+}
+
+func (k *APIKey) check(now time.Time) error {
+	if k.Revoked {
+		return ErrAPIKeyRevoked
+	}
+	if !k.Expires.IsZero() && !now.Before(k.Expires) {
+		return ErrAPIKeyExpired
+	}
+	return nil
+}
+
+func IsAPIKey(s string) bool {
+	return strings.HasPrefix(s, APIKeyPrefix)
+}
+
+// APIKeyStore keeps the API keys in a json file. The file is read again whenever it changes,
+// so keys created or revoked with olfullnode apikey apply to a running node.
+type APIKeyStore struct {
+	sync.Mutex
+
+	path string
+	info os.FileInfo
+	keys []*APIKey
+}
+
+func NewAPIKeyStore(path string) *APIKeyStore {
+	return &APIKeyStore{
+		path: path,
+		keys: make([]*APIKey, 0),
+	}
+}
+
+// load reads the file if it changed since the last time, a missing file holds no keys
+func (s *APIKeyStore) load() error {
+	info, err := os.Stat(s.path)
+	if os.IsNotExist(err) {
+		s.keys = make([]*APIKey, 0)
+		s.info = nil
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to read api keys")
+	}
+	// the file is replaced on every change, so it is another file once it changed
+	if s.info != nil && os.SameFile(info, s.info) && info.ModTime().Equal(s.info.ModTime()) {
+		return nil
+	}
+
+	data, err := ioutil.ReadFile(s.path)
+	if err != nil {
+		return errors.Wrap(err, "failed to read api keys")
+	}
+	keys := make([]*APIKey, 0)
+	err = json.Unmarshal(data, &keys)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse api keys")
+	}
+
+	s.keys = keys
+	s.info = info
+	return nil
+}
+
+func (s *APIKeyStore) save() error {
+	data, err := json.MarshalIndent(s.keys, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// replace the file at once, a node may be reading it
+	tmp := s.path + ".tmp"
+	err = ioutil.WriteFile(tmp, data, 0600)
+	if err != nil {
+		return errors.Wrap(err, "failed to write api keys")
+	}
+	err = os.Rename(tmp, s.path)
+	if err != nil {
+		return errors.Wrap(err, "failed to write api keys")
+	}
+	s.info = nil
+	return nil
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func hashSecret(secret string) string {
+	h := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(h[:])
+}
+
+// Create adds a key and returns it, the key can't be read back later. A zero expiry never
+// expires.
+func (s *APIKeyStore) Create(name string, roles []Role, expires time.Time) (string, *APIKey, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	err := s.load()
+	if err != nil {
+		return "", nil, err
+	}
+
+	id, err := randomHex(8)
+	if err != nil {
+		return "", nil, err
+	}
+	secret, err := randomHex(32)
+	if err != nil {
+		return "", nil, err
+	}
+
+	key := &APIKey{
+		ID:      id,
+		Name:    name,
+		Roles:   roles,
+		Hash:    hashSecret(secret),
+		Created: time.Now().UTC(),
+		Expires: expires,
+	}
+	s.keys = append(s.keys, key)
+	err = s.save()
+	if err != nil {
+		return "", nil, err
+	}
+	return APIKeyPrefix + id + "_" + secret, key, nil
+}
+
+func (s *APIKeyStore) Revoke(id string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	err := s.load()
+	if err != nil {
+		return err
+	}
+	for _, k := range s.keys {
+		if k.ID == id {
+			k.Revoked = true
+			return s.save()
+		}
+	}
+	return ErrAPIKeyNotFound
+}
+
+// List returns all keys, revoked and expired ones included, in order of creation
+func (s *APIKeyStore) List() ([]APIKey, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	list := make([]APIKey, 0, len(s.keys))
+	for _, k := range s.keys {
+		list = append(list, *k)
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].Created.Before(list[j].Created)
+	})
+	return list, nil
+}
+
+// Verify returns the key matching the secret if it is still valid at now
+func (s *APIKeyStore) Verify(key string, now time.Time) (*APIKey, error) {
+	parts := strings.Split(strings.TrimPrefix(key, APIKeyPrefix), "_")
+	if !IsAPIKey(key) || len(parts) != 2 {
+		return nil, ErrAPIKeyInvalid
+	}
+	id, hash := parts[0], hashSecret(parts[1])
+
+	s.Lock()
+	defer s.Unlock()
+
+	err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	for _, k := range s.keys {
+		if k.ID != id {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(k.Hash), []byte(hash)) != 1 {
+			return nil, ErrAPIKeyInvalid
+		}
+		if err := k.check(now); err != nil {
+			return nil, err
+		}
+		found := *k
+		return &found, nil
+	}
+	return nil, ErrAPIKeyNotFound
+}
@@ -0,0 +1,59 @@
+package rpc
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// AuditEntry records a call to a privileged method, whether it was allowed or not
+type AuditEntry struct {
+	Time    time.Time `json:"time"`
+	Remote  string    `json:"remote"`
+	User    string    `json:"user"`
+	Auth    string    `json:"auth"`
+	Method  string    `json:"method"`
+	Role    Role      `json:"role"`
+	Allowed bool      `json:"allowed"`
+}
+
+// AuditLog appends entries to a file as json lines. A nil AuditLog records nothing.
+type AuditLog struct {
+	sync.Mutex
+
+	file *os.File
+}
+
+func OpenAuditLog(path string) (*AuditLog, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open audit log")
+	}
+	return &AuditLog{file: f}, nil
+}
+
+func (a *AuditLog) Record(e AuditEntry) {
+	if a == nil {
+		return
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+
+	a.Lock()
+	defer a.Unlock()
+	_, _ = a.file.Write(append(data, '\n'))
+}
+
+func (a *AuditLog) Close() error {
+	if a == nil {
+		return nil
+	}
+	a.Lock()
+	defer a.Unlock()
+	return a.file.Close()
+}
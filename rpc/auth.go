@@ -0,0 +1,521 @@
+package rpc
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/Oneledger/protocol/config"
+	"github.com/Oneledger/protocol/data/keys"
+	"github.com/Oneledger/protocol/metrics"
+)
+
+// Role is what a caller needs to be allowed to call the methods of a service
+type Role string
+
+const (
+	// anyone who can reach the port
+	RolePublic Role = "public"
+	// services using the accounts and keys of the node
+	RoleOwner Role = "owner"
+	// node management, has every other role too
+	RoleAdmin Role = "admin"
+)
+
+var ErrUnknownRole = errors.New("unknown role")
+
+func ParseRole(s string) (Role, error) {
+	switch r := Role(strings.ToLower(strings.TrimSpace(s))); r {
+	case RolePublic, RoleOwner, RoleAdmin:
+		return r, nil
+	}
+	return "", errors.Wrap(ErrUnknownRole, s)
+}
+
+func ParseRoles(list []string) ([]Role, error) {
+	roles := make([]Role, 0, len(list))
+	for _, s := range list {
+		r, err := ParseRole(s)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, r)
+	}
+	return roles, nil
+}
+
+// how a caller was authenticated
+const (
+	AuthAnonymous = "anonymous"
+	AuthLocal     = "local"
+	AuthToken     = "token"
+	AuthAPIKey    = "apikey"
+)
+
+// Principal is the caller of a request
+type Principal struct {
+	Name string
+	Auth string
+	// ID of the API key
+	Key   string
+	Roles []Role
+}
+
+func (p *Principal) Has(role Role) bool {
+	if role == RolePublic {
+		return true
+	}
+	for _, r := range p.Roles {
+		if r == role || r == RoleAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+	Credentials
+*/
+
+// Credential is a user allowed to get tokens from /token, configured as "user:hash[:role]"
+// in owner_credentials. The role defaults to owner.
+type Credential struct {
+	User string
+	Hash string
+	Role Role
+}
+
+// HashPassword returns the hash of a password to put in owner_credentials
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to hash password")
+	}
+	return string(hash), nil
+}
+
+func ParseCredentials(list []string) ([]Credential, error) {
+	creds := make([]Credential, 0, len(list))
+	for _, s := range list {
+		parts := strings.Split(s, ":")
+		if len(parts) < 2 || len(parts) > 3 || parts[0] == "" {
+			return nil, errors.Errorf("invalid owner credential %q, expected user:hash[:role]", parts[0])
+		}
+
+		c := Credential{User: parts[0], Hash: parts[1], Role: RoleOwner}
+		if len(parts) == 3 {
+			r, err := ParseRole(parts[2])
+			if err != nil {
+				return nil, err
+			}
+			c.Role = r
+		}
+		creds = append(creds, c)
+	}
+	return creds, nil
+}
+
+// Hashed is false for credentials still holding a plaintext password
+func (c Credential) Hashed() bool {
+	_, err := bcrypt.Cost([]byte(c.Hash))
+	return err == nil
+}
+
+func (c Credential) Verify(password string) bool {
+	if !c.Hashed() {
+		return subtle.ConstantTimeCompare([]byte(c.Hash), []byte(password)) == 1
+	}
+	return bcrypt.CompareHashAndPassword([]byte(c.Hash), []byte(password)) == nil
+}
+
+// CheckCredentials returns the credential of user if password matches it
+func CheckCredentials(creds []Credential, user, password string) (*Credential, bool) {
+	for i := range creds {
+		if creds[i].User == user {
+			return &creds[i], creds[i].Verify(password)
+		}
+	}
+	return nil, false
+}
+
+/*
+	Tokens
+*/
+
+// TokenTTL is how long the tokens handed out by /token are valid
+const TokenTTL = 24 * time.Hour
+
+const tokenSignatureSize = 64
+
+var ErrInvalidToken = errors.New("invalid token")
+
+// TokenClaims is what a token says about its holder, signed with the rpc private key
+type TokenClaims struct {
+	Client  string `json:"client"`
+	User    string `json:"user,omitempty"`
+	Roles   []Role `json:"roles"`
+	Expires int64  `json:"exp"`
+}
+
+func tokenKey(rpcPrivateKey string) (keys.PrivateKeyHandler, error) {
+	keyData, err := base64.StdEncoding.DecodeString(rpcPrivateKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode rpc private key")
+	}
+	privateKey, err := keys.GetPrivateKeyFromBytes(keyData, keys.ED25519)
+	if err != nil {
+		return nil, err
+	}
+	return privateKey.GetHandler()
+}
+
+// NewToken signs the claims with the rpc private key
+func NewToken(rpcPrivateKey string, claims TokenClaims) (string, error) {
+	handler, err := tokenKey(rpcPrivateKey)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signature, err := handler.Sign(data)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to sign token")
+	}
+	return base58.Encode(append(data, signature...)), nil
+}
+
+// VerifyToken checks the signature and expiry of a token and returns its claims
+func VerifyToken(rpcPrivateKey, token string, now time.Time) (*TokenClaims, error) {
+	handler, err := tokenKey(rpcPrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	pubKeyHandler, err := handler.PubKey().GetHandler()
+	if err != nil {
+		return nil, err
+	}
+
+	data := base58.Decode(token)
+	if len(data) <= tokenSignatureSize {
+		return nil, ErrInvalidToken
+	}
+	signed, signature := data[:len(data)-tokenSignatureSize], data[len(data)-tokenSignatureSize:]
+	if !pubKeyHandler.VerifyBytes(signed, signature) {
+		return nil, ErrInvalidToken
+	}
+
+	claims := &TokenClaims{}
+	err = json.Unmarshal(signed, claims)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	if now.Unix() >= claims.Expires {
+		return nil, errors.Wrap(ErrInvalidToken, "token expired")
+	}
+	return claims, nil
+}
+
+/*
+	HTTP authorization
+*/
+
+// APIKeyHeader carries an API key, it can also be sent as a bearer token
+const APIKeyHeader = "X-API-Key"
+
+type authHandler interface {
+	http.Handler
+	// Authorized authenticates the request for the public methods and applies the rate limit
+	Authorized(respW http.ResponseWriter, req *http.Request) bool
+}
+
+var _ authHandler = &rpcAuthHandler{}
+
+// rpcAuthHandler authenticates the callers of the rpc handler and checks they have the role
+// of every service they call. Calls needing more than the public role are audited.
+type rpcAuthHandler struct {
+	rpcHandler http.Handler
+	cfg        *config.Server
+
+	roles      map[string]Role
+	disabled   *disabledServices
+	methods    map[string]reflect.Type
+	localRoles []Role
+	apiKeys    *APIKeyStore
+	audit      *AuditLog
+	metrics    *metrics.Metrics
+	limits     *limits
+}
+
+func (r *rpcAuthHandler) ServeHTTP(respW http.ResponseWriter, req *http.Request) {
+	p, ok := r.authenticate(respW, req)
+	if !ok {
+		return
+	}
+
+	limited := !r.limits.exempt(p)
+	c := client(p, req)
+	if limited {
+		if !r.limits.running.acquire(c) {
+			tooManyRequests(respW, "too many concurrent requests", time.Second)
+			return
+		}
+		defer r.limits.running.release(c)
+	}
+
+	r.limits.limitBody(respW, req)
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		status := http.StatusBadRequest
+		if strings.Contains(err.Error(), "request body too large") {
+			status = http.StatusRequestEntityTooLarge
+		}
+		http.Error(respW, err.Error(), status)
+		return
+	}
+	_ = req.Body.Close()
+
+	methods := requestMethods(body)
+	for _, method := range methods {
+		// the codec runs batches through this method, called directly its calls would
+		// skip the role checks
+		if method == batchMethod {
+			http.Error(respW, batchMethod+" can't be called", http.StatusBadRequest)
+			return
+		}
+	}
+	if max := r.limits.maxBatch(); max > 0 && len(methods) > max {
+		http.Error(respW, fmt.Sprintf("batch of %d calls, at most %d are allowed", len(methods), max),
+			http.StatusRequestEntityTooLarge)
+		return
+	}
+	if limited {
+		ok, wait, err := r.limits.allow(p, c, methods, time.Now())
+		if err != nil {
+			http.Error(respW, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		if !ok {
+			tooManyRequests(respW, "rate limit exceeded", wait)
+			return
+		}
+	}
+
+	for _, method := range methods {
+		if service := serviceOf(method); r.disabled.has(service) {
+			http.Error(respW, fmt.Sprintf("service %s is disabled", service), http.StatusForbidden)
+			return
+		}
+
+		role := r.roleOf(method)
+		if role == RolePublic {
+			continue
+		}
+
+		allowed := p.Has(role)
+		r.audit.Record(AuditEntry{
+			Time:    time.Now().UTC(),
+			Remote:  req.RemoteAddr,
+			User:    p.Name,
+			Auth:    p.Auth,
+			Method:  method,
+			Role:    role,
+			Allowed: allowed,
+		})
+		if !allowed {
+			http.Error(respW, fmt.Sprintf("%s needs the %s role", method, role), http.StatusForbidden)
+			return
+		}
+	}
+
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	start := time.Now()
+	r.rpcHandler.ServeHTTP(respW, req)
+	if r.metrics != nil {
+		r.metrics.RPCLatency.WithLabelValues(r.metricsMethod(methods)).Observe(time.Since(start).Seconds())
+	}
+}
+
+// metricsMethod names a request in the metrics, batches are counted together and unknown
+// methods too, so callers can't make up labels
+func (r *rpcAuthHandler) metricsMethod(methods []string) string {
+	switch {
+	case len(methods) > 1:
+		return "batch"
+	case len(methods) == 0:
+		return "invalid"
+	}
+	if _, ok := r.methods[methods[0]]; !ok {
+		return "unknown"
+	}
+	return methods[0]
+}
+
+// publicHandler serves to any authenticated caller
+type publicHandler struct {
+	auth    authHandler
+	handler http.Handler
+}
+
+func (h publicHandler) ServeHTTP(respW http.ResponseWriter, req *http.Request) {
+	if h.auth.Authorized(respW, req) {
+		h.handler.ServeHTTP(respW, req)
+	}
+}
+
+func (r *rpcAuthHandler) Authorized(respW http.ResponseWriter, req *http.Request) bool {
+	p, ok := r.authenticate(respW, req)
+	return ok && r.limits.limitRequest(respW, req, p)
+}
+
+// authenticate finds out who sent the request from its API key or token. Requests without
+// either are anonymous, unless the rpc private key is set, and get the local roles when they
+// come from localhost and not from a web page.
+func (r *rpcAuthHandler) authenticate(respW http.ResponseWriter, req *http.Request) (*Principal, bool) {
+	fail := func(msg string) (*Principal, bool) {
+		respW.Header().Set("WWW-Authenticate", `Bearer realm="Restricted"`)
+		http.Error(respW, msg, http.StatusUnauthorized)
+		return nil, false
+	}
+
+	privateKey := ""
+	if r.cfg != nil && r.cfg.Node != nil {
+		privateKey = r.cfg.Node.RPCPrivateKey
+	}
+
+	token := strings.TrimSpace(strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer "))
+	if key := req.Header.Get(APIKeyHeader); key != "" {
+		token = key
+	}
+
+	switch {
+	case token == "":
+		if privateKey != "" {
+			return fail("missing token")
+		}
+		p := &Principal{Name: req.RemoteAddr, Auth: AuthAnonymous, Roles: []Role{RolePublic}}
+		if isLocal(req) && !fromPage(req) {
+			p.Auth = AuthLocal
+			p.Roles = append(p.Roles, r.localRoles...)
+		}
+		return p, true
+
+	case IsAPIKey(token):
+		if r.apiKeys == nil {
+			return fail("api keys are not enabled")
+		}
+		key, err := r.apiKeys.Verify(token, time.Now())
+		if err != nil {
+			return fail(err.Error())
+		}
+		return &Principal{Name: key.ID + " " + key.Name, Auth: AuthAPIKey, Key: key.ID, Roles: key.Roles}, true
+
+	default:
+		if privateKey == "" {
+			return fail("tokens are not enabled")
+		}
+		claims, err := VerifyToken(privateKey, token, time.Now())
+		if err != nil {
+			return fail(err.Error())
+		}
+		return &Principal{Name: claims.User + " " + claims.Client, Auth: AuthToken, Roles: claims.Roles}, true
+	}
+}
+
+// roleOf returns the role needed by a method, unknown services are left for the rpc server
+// to reject
+func (r *rpcAuthHandler) roleOf(method string) Role {
+	if role, ok := r.roles[serviceOf(method)]; ok {
+		return role
+	}
+	return RolePublic
+}
+
+func serviceOf(method string) string {
+	if i := strings.Index(method, "."); i >= 0 {
+		return method[:i]
+	}
+	return method
+}
+
+// batchMethod is the method jsonrpc2 serves batch requests with
+const batchMethod = "JSONRPC2.Batch"
+
+// requestMethods returns the methods called by a single or batch JSON-RPC request
+func requestMethods(body []byte) []string {
+	type call struct {
+		Method string `json:"method"`
+	}
+
+	calls := make([]call, 0)
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		_ = json.Unmarshal(body, &calls)
+	} else {
+		c := call{}
+		if json.Unmarshal(body, &c) == nil {
+			calls = append(calls, c)
+		}
+	}
+
+	methods := make([]string, 0, len(calls))
+	for _, c := range calls {
+		methods = append(methods, c.Method)
+	}
+	return methods
+}
+
+// isLocal tells if the request comes from this host. Requests forwarded by a proxy never are.
+// A browser on this host can still send them for any page, see fromPage.
+func isLocal(req *http.Request) bool {
+	if req.Header.Get("X-Forwarded-For") != "" || req.Header.Get("Forwarded") != "" {
+		return false
+	}
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return false
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// fromPage tells if a local request may come from a web page the browser runs for another
+// site: it has an Origin that isn't local, or a Host that isn't, as when a site rebinds its
+// name to 127.0.0.1.
+func fromPage(req *http.Request) bool {
+	if origin := req.Header.Get("Origin"); origin != "" {
+		u, err := url.Parse(origin)
+		if err != nil || !isLoopbackHost(u.Host) {
+			return true
+		}
+	}
+	return !isLoopbackHost(req.Host)
+}
+
+// isLoopbackHost tells if a host, with or without a port, names this host
+func isLoopbackHost(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,281 @@
+package rpc
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Oneledger/protocol/config"
+	"github.com/Oneledger/protocol/data/keys"
+	"github.com/Oneledger/protocol/metrics"
+)
+
+func newRPCPrivateKey(t *testing.T) string {
+	_, priv, err := keys.NewKeyPairFromTendermint()
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(priv.Data)
+}
+
+// newTestAuth returns an auth handler in front of a handler answering every call, tx needs
+// the owner role and admin the admin role
+func newTestAuth(t *testing.T, privateKey string) (*rpcAuthHandler, func()) {
+	dir, err := ioutil.TempDir("", "rpcauth")
+	require.NoError(t, err)
+
+	cfg := config.DefaultServerConfig()
+	cfg.Node.RPCPrivateKey = privateKey
+	h := &rpcAuthHandler{
+		rpcHandler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("ok"))
+		}),
+		cfg:        cfg,
+		roles:      map[string]Role{"query": RolePublic, "tx": RoleOwner, "admin": RoleAdmin},
+		disabled:   newDisabledServices(),
+		localRoles: []Role{RoleOwner, RoleAdmin},
+		apiKeys:    NewAPIKeyStore(filepath.Join(dir, "apikeys.json")),
+	}
+	return h, func() { os.RemoveAll(dir) }
+}
+
+func rpcRequest(method string) *http.Request {
+	body := `{"jsonrpc":"2.0","id":1,"method":"` + method + `","params":{}}`
+	req := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:26631"+Path, strings.NewReader(body))
+	req.Header.Set("Content-Type", ContentType)
+	return req
+}
+
+func serve(h http.Handler, req *http.Request) int {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestParseRoles(t *testing.T) {
+	roles, err := ParseRoles([]string{"public", " Owner ", "ADMIN"})
+	require.NoError(t, err)
+	assert.Equal(t, []Role{RolePublic, RoleOwner, RoleAdmin}, roles)
+
+	_, err = ParseRoles([]string{"root"})
+	assert.Error(t, err)
+
+	owner := &Principal{Roles: []Role{RoleOwner}}
+	assert.True(t, owner.Has(RolePublic))
+	assert.True(t, owner.Has(RoleOwner))
+	assert.False(t, owner.Has(RoleAdmin))
+
+	admin := &Principal{Roles: []Role{RoleAdmin}}
+	assert.True(t, admin.Has(RoleOwner))
+}
+
+func TestCredentials(t *testing.T) {
+	hash, err := HashPassword("secret")
+	require.NoError(t, err)
+
+	creds, err := ParseCredentials([]string{"alice:" + hash, "bob:plain:admin"})
+	require.NoError(t, err)
+	assert.Equal(t, RoleOwner, creds[0].Role)
+	assert.Equal(t, RoleAdmin, creds[1].Role)
+	assert.True(t, creds[0].Hashed())
+	assert.False(t, creds[1].Hashed())
+
+	_, ok := CheckCredentials(creds, "alice", "secret")
+	assert.True(t, ok)
+	_, ok = CheckCredentials(creds, "alice", "wrong")
+	assert.False(t, ok)
+	_, ok = CheckCredentials(creds, "bob", "plain")
+	assert.True(t, ok)
+	c, ok := CheckCredentials(creds, "carol", "secret")
+	assert.Nil(t, c)
+	assert.False(t, ok)
+
+	_, err = ParseCredentials([]string{"alice"})
+	assert.Error(t, err)
+	_, err = ParseCredentials([]string{"alice:hash:root"})
+	assert.Error(t, err)
+}
+
+func TestTokens(t *testing.T) {
+	key := newRPCPrivateKey(t)
+	now := time.Now()
+	claims := TokenClaims{Client: "c", User: "alice", Roles: []Role{RoleOwner}, Expires: now.Add(time.Hour).Unix()}
+
+	token, err := NewToken(key, claims)
+	require.NoError(t, err)
+	got, err := VerifyToken(key, token, now)
+	require.NoError(t, err)
+	assert.Equal(t, claims, *got)
+
+	_, err = VerifyToken(key, token, now.Add(2*time.Hour))
+	assert.Error(t, err)
+
+	// signed with another key
+	_, err = VerifyToken(newRPCPrivateKey(t), token, now)
+	assert.Error(t, err)
+
+	_, err = VerifyToken(key, token[:len(token)-2]+"11", now)
+	assert.Error(t, err)
+}
+
+func TestAuth_Tokens(t *testing.T) {
+	key := newRPCPrivateKey(t)
+	h, cleanup := newTestAuth(t, key)
+	defer cleanup()
+
+	// with a private key every call needs a token, even from localhost
+	req := rpcRequest("query.Balance")
+	req.RemoteAddr = "127.0.0.1:5000"
+	assert.Equal(t, http.StatusUnauthorized, serve(h, req))
+
+	token, err := NewToken(key, TokenClaims{Roles: []Role{RoleOwner}, Expires: time.Now().Add(time.Hour).Unix()})
+	require.NoError(t, err)
+	withToken := func(method string) *http.Request {
+		req := rpcRequest(method)
+		req.Header.Set("Authorization", "Bearer "+token)
+		return req
+	}
+	assert.Equal(t, http.StatusOK, serve(h, withToken("query.Balance")))
+	assert.Equal(t, http.StatusOK, serve(h, withToken("tx.SendTx")))
+	assert.Equal(t, http.StatusForbidden, serve(h, withToken("admin.ListJobs")))
+
+	req = rpcRequest("query.Balance")
+	req.Header.Set("Authorization", "Bearer nonsense")
+	assert.Equal(t, http.StatusUnauthorized, serve(h, req))
+}
+
+func TestAuth_APIKeys(t *testing.T) {
+	h, cleanup := newTestAuth(t, "")
+	defer cleanup()
+
+	secret, key, err := h.apiKeys.Create("bot", []Role{RoleOwner}, time.Time{})
+	require.NoError(t, err)
+	withKey := func(method, secret string) *http.Request {
+		req := rpcRequest(method)
+		req.Header.Set(APIKeyHeader, secret)
+		return req
+	}
+
+	assert.Equal(t, http.StatusOK, serve(h, withKey("tx.SendTx", secret)))
+	assert.Equal(t, http.StatusForbidden, serve(h, withKey("admin.ListJobs", secret)))
+	assert.Equal(t, http.StatusUnauthorized, serve(h, withKey("tx.SendTx", secret+"0")))
+
+	// the bearer header works too
+	req := rpcRequest("tx.SendTx")
+	req.Header.Set("Authorization", "Bearer "+secret)
+	assert.Equal(t, http.StatusOK, serve(h, req))
+
+	require.NoError(t, h.apiKeys.Revoke(key.ID))
+	assert.Equal(t, http.StatusUnauthorized, serve(h, withKey("tx.SendTx", secret)))
+
+	expiring, _, err := h.apiKeys.Create("old", []Role{RoleOwner}, time.Now().Add(-time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, serve(h, withKey("tx.SendTx", expiring)))
+}
+
+func TestAuth_LocalRoles(t *testing.T) {
+	h, cleanup := newTestAuth(t, "")
+	defer cleanup()
+
+	local := func(method string) *http.Request {
+		req := rpcRequest(method)
+		req.RemoteAddr = "127.0.0.1:5000"
+		return req
+	}
+
+	assert.Equal(t, http.StatusOK, serve(h, local("tx.SendTx")))
+	assert.Equal(t, http.StatusOK, serve(h, local("admin.ListJobs")))
+
+	// remote clients only get the public role
+	req := rpcRequest("tx.SendTx")
+	req.RemoteAddr = "10.0.0.2:5000"
+	assert.Equal(t, http.StatusForbidden, serve(h, req))
+	req = rpcRequest("query.Balance")
+	req.RemoteAddr = "10.0.0.2:5000"
+	assert.Equal(t, http.StatusOK, serve(h, req))
+
+	// nor do requests forwarded by a proxy
+	req = local("tx.SendTx")
+	req.Header.Set("X-Forwarded-For", "10.0.0.2")
+	assert.Equal(t, http.StatusForbidden, serve(h, req))
+
+	// a page of another site posting to the node
+	req = local("tx.SendTx")
+	req.Header.Set("Origin", "https://evil.example.com")
+	assert.Equal(t, http.StatusForbidden, serve(h, req))
+
+	// a site rebinding its name to 127.0.0.1
+	req = local("tx.SendTx")
+	req.Host = "evil.example.com:26631"
+	assert.Equal(t, http.StatusForbidden, serve(h, req))
+
+	// pages served from this host are local
+	req = local("tx.SendTx")
+	req.Host = "localhost:26631"
+	req.Header.Set("Origin", "http://localhost:3000")
+	assert.Equal(t, http.StatusOK, serve(h, req))
+	req = local("tx.SendTx")
+	req.Host = "[::1]:26631"
+	assert.Equal(t, http.StatusOK, serve(h, req))
+}
+
+func TestAuth_Batches(t *testing.T) {
+	h, cleanup := newTestAuth(t, "")
+	defer cleanup()
+
+	remote := func(methods ...string) *http.Request {
+		calls := make([]string, len(methods))
+		for i, m := range methods {
+			calls[i] = `{"jsonrpc":"2.0","id":` + strconv.Itoa(i) + `,"method":"` + m + `","params":{}}`
+		}
+		req := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:26631"+Path, strings.NewReader("["+strings.Join(calls, ",")+"]"))
+		req.Header.Set("Content-Type", ContentType)
+		req.RemoteAddr = "10.0.0.2:5000"
+		return req
+	}
+
+	// every call of a batch needs its role
+	assert.Equal(t, http.StatusOK, serve(h, remote("query.Balance", "query.Balance")))
+	assert.Equal(t, http.StatusForbidden, serve(h, remote("query.Balance", "tx.SendTx")))
+
+	// the method the codec runs batches with can't be called directly
+	assert.Equal(t, http.StatusBadRequest, serve(h, remote(batchMethod)))
+	assert.Equal(t, http.StatusBadRequest, serve(h, rpcRequest(batchMethod)))
+
+	assert.Equal(t, []string{"a", "b"}, requestMethods([]byte(` [{"method":"a"},{"method":"b"}]`)))
+	assert.Equal(t, []string{"a"}, requestMethods([]byte(`{"method":"a"}`)))
+	assert.Empty(t, requestMethods([]byte(`nonsense`)))
+}
+
+func TestAuth_MetricsMethod(t *testing.T) {
+	h, cleanup := newTestAuth(t, "")
+	defer cleanup()
+	h.methods = map[string]reflect.Type{"query.Balance": nil}
+	h.metrics = metrics.PrometheusMetrics()
+
+	assert.Equal(t, "query.Balance", h.metricsMethod([]string{"query.Balance"}))
+	assert.Equal(t, "unknown", h.metricsMethod([]string{"query.MadeUp"}))
+	assert.Equal(t, "batch", h.metricsMethod([]string{"query.Balance", "query.Balance"}))
+	assert.Equal(t, "invalid", h.metricsMethod(nil))
+
+	// callers can't add labels of their own
+	assert.Equal(t, http.StatusOK, serve(h, rpcRequest("query.Balance")))
+	assert.Equal(t, http.StatusOK, serve(h, rpcRequest("query.MadeUp1")))
+	assert.Equal(t, http.StatusOK, serve(h, rpcRequest("query.MadeUp2")))
+
+	rec := httptest.NewRecorder()
+	h.metrics.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, metrics.Path, nil))
+	body := rec.Body.String()
+	assert.Contains(t, body, `oneledger_rpc_request_duration_seconds_count{method="query.Balance"} 1`)
+	assert.Contains(t, body, `oneledger_rpc_request_duration_seconds_count{method="unknown"} 2`)
+	assert.NotContains(t, body, "MadeUp")
+}
@@ -1,21 +1,291 @@
 package rpc
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
 	"net/url"
+	"strconv"
+	"sync/atomic"
+	"time"
 
+	"github.com/pkg/errors"
 	"github.com/powerman/rpc-codec/jsonrpc2"
 )
 
 type Client struct {
 	*jsonrpc2.Client
+
+	endpoint string
+	doer     jsonrpc2.Doer
+	retry    RetryPolicy
+	seq      uint64
+}
+
+// ClientOptions configure how a client reaches the SDK port
+type ClientOptions struct {
+	// API key or token from /token sent with every call
+	Auth string
+	// used for https addresses
+	TLS ClientTLS
+	// how idempotent calls are retried, DefaultRetryPolicy when left empty
+	Retry RetryPolicy
+}
+
+// RetryPolicy says how calls that are safe to repeat are tried again when the node can't be
+// reached or is busy. The wait starts at Backoff and doubles up to MaxBackoff, a Retry-After
+// sent by the node takes precedence.
+type RetryPolicy struct {
+	// tries of a call, the first one included, 1 for no retries
+	Attempts   int
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		Attempts:   3,
+		Backoff:    200 * time.Millisecond,
+		MaxBackoff: 5 * time.Second,
+	}
 }
 
 func NewClient(addr string) (*Client, error) {
+	return NewClientWithOptions(addr, ClientOptions{})
+}
+
+// NewClientWithAuth returns a client sending an API key or a token from /token with every call
+func NewClientWithAuth(addr, auth string) (*Client, error) {
+	return NewClientWithOptions(addr, ClientOptions{Auth: auth})
+}
+
+// NewClientWithOptions returns a client for the address, https addresses are called over
+// TLS with the certificates in the options
+func NewClientWithOptions(addr string, opts ClientOptions) (*Client, error) {
 	u, err := url.Parse(addr)
 	if err != nil {
 		return nil, err
 	}
 
-	client := jsonrpc2.NewHTTPClient("http://" + u.Host + Path)
-	return &Client{client}, nil
+	scheme := "http"
+	httpClient := &http.Client{}
+	if u.Scheme == "https" {
+		scheme = "https"
+		tlsConfig, err := opts.TLS.config()
+		if err != nil {
+			return nil, err
+		}
+		httpClient.Transport = &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+		}
+	}
+	endpoint := scheme + "://" + u.Host + Path
+
+	doer := jsonrpc2.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		if opts.Auth != "" {
+			req.Header.Set("Authorization", "Bearer "+opts.Auth)
+		}
+		return httpClient.Do(req)
+	})
+
+	retry := opts.Retry
+	if retry.Attempts == 0 {
+		retry = DefaultRetryPolicy()
+	}
+
+	return &Client{
+		Client:   jsonrpc2.NewCustomHTTPClient(endpoint, doer),
+		endpoint: endpoint,
+		doer:     doer,
+		retry:    retry,
+	}, nil
+}
+
+// BatchCall is a call sent in a batch. Once the batch is done Reply holds the result of the
+// call, or Error why it failed.
+type BatchCall struct {
+	Method string
+	Args   interface{}
+	Reply  interface{}
+	// the call can be repeated without harm, batches of such calls only are retried
+	Idempotent bool
+
+	Error error
+}
+
+type clientRequest struct {
+	Version string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+	ID      uint64      `json:"id"`
+}
+
+type clientResponse struct {
+	ID     *uint64          `json:"id"`
+	Result *json.RawMessage `json:"result"`
+	Error  *jsonrpc2.Error  `json:"error"`
+}
+
+// CallContext calls the method once, it gives up when ctx is done
+func (c *Client) CallContext(ctx context.Context, method string, args, reply interface{}) error {
+	return c.call(ctx, &BatchCall{Method: method, Args: args, Reply: reply})
+}
+
+// CallIdempotent calls a method that can be repeated without harm, it's retried as the retry
+// policy of the client says
+func (c *Client) CallIdempotent(ctx context.Context, method string, args, reply interface{}) error {
+	return c.call(ctx, &BatchCall{Method: method, Args: args, Reply: reply, Idempotent: true})
+}
+
+func (c *Client) call(ctx context.Context, call *BatchCall) error {
+	req := c.request(call)
+	body, err := json.Marshal(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal request")
+	}
+
+	resp := clientResponse{}
+	if err := c.post(ctx, body, call.Idempotent, &resp); err != nil {
+		return err
+	}
+	c.finish(call, &resp)
+	return call.Error
+}
+
+// Batch sends the calls in a single request. The error tells why the request failed, the
+// errors of single calls are in their Error. The batch is retried only when all of its calls
+// are idempotent.
+func (c *Client) Batch(ctx context.Context, calls ...*BatchCall) error {
+	if len(calls) == 0 {
+		return nil
+	}
+
+	idempotent := true
+	reqs := make([]clientRequest, len(calls))
+	byID := make(map[uint64]*BatchCall, len(calls))
+	for i, call := range calls {
+		reqs[i] = c.request(call)
+		byID[reqs[i].ID] = call
+		idempotent = idempotent && call.Idempotent
+	}
+	body, err := json.Marshal(reqs)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal batch")
+	}
+
+	resps := make([]clientResponse, 0, len(calls))
+	if err := c.post(ctx, body, idempotent, &resps); err != nil {
+		return err
+	}
+	for i := range resps {
+		if resps[i].ID == nil {
+			continue
+		}
+		if call, ok := byID[*resps[i].ID]; ok {
+			c.finish(call, &resps[i])
+			delete(byID, *resps[i].ID)
+		}
+	}
+	for _, call := range byID {
+		call.Error = errors.New("no response to the call in the batch")
+	}
+	return nil
+}
+
+func (c *Client) request(call *BatchCall) clientRequest {
+	return clientRequest{
+		Version: "2.0",
+		Method:  call.Method,
+		Params:  call.Args,
+		ID:      atomic.AddUint64(&c.seq, 1),
+	}
+}
+
+func (c *Client) finish(call *BatchCall, resp *clientResponse) {
+	switch {
+	case resp.Error != nil:
+		call.Error = resp.Error
+	case resp.Result == nil:
+		call.Error = errors.New("response without result")
+	case call.Reply != nil:
+		call.Error = errors.Wrap(json.Unmarshal(*resp.Result, call.Reply), "failed to unmarshal result")
+	}
+}
+
+// post sends the body to the node and reads the response into out, it retries idempotent
+// requests the node didn't get or was too busy to serve
+func (c *Client) post(ctx context.Context, body []byte, idempotent bool, out interface{}) error {
+	attempts := 1
+	if idempotent && c.retry.Attempts > 1 {
+		attempts = c.retry.Attempts
+	}
+	backoff := c.retry.Backoff
+
+	for attempt := 1; ; attempt++ {
+		wait, err := c.postOnce(ctx, body, out)
+		if err == nil || wait < 0 || attempt >= attempts {
+			return err
+		}
+
+		if wait == 0 {
+			wait = backoff
+			backoff *= 2
+			if c.retry.MaxBackoff > 0 && backoff > c.retry.MaxBackoff {
+				backoff = c.retry.MaxBackoff
+			}
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Wrap(ctx.Err(), err.Error())
+		case <-timer.C:
+		}
+	}
+}
+
+// postOnce returns how long to wait before a retry along with the error, or a negative wait
+// for errors a retry won't fix
+func (c *Client) postOnce(ctx context.Context, body []byte, out interface{}) (time.Duration, error) {
+	req, err := http.NewRequest(http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return -1, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", ContentType)
+	req.Header.Set("Accept", ContentType)
+
+	resp, err := c.doer.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return -1, ctx.Err()
+		}
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to read response")
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		wait := time.Duration(0)
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			wait = time.Duration(seconds) * time.Second
+		}
+		return wait, errors.Errorf("bad HTTP Status: %s: %s", resp.Status, bytes.TrimSpace(data))
+	default:
+		return -1, errors.Errorf("bad HTTP Status: %s: %s", resp.Status, bytes.TrimSpace(data))
+	}
+
+	if err := json.Unmarshal(data, out); err != nil {
+		return -1, errors.Errorf("bad response: %s", data)
+	}
+	return 0, nil
 }
@@ -0,0 +1,160 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testNode answers Arith.Add calls, in reverse order for batches, after failing the first
+// requests with the status in fail
+type testNode struct {
+	requests int32
+	failures int32
+	fail     int
+	auth     string
+}
+
+func (n *testNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	atomic.AddInt32(&n.requests, 1)
+	if atomic.AddInt32(&n.failures, -1) >= 0 {
+		w.WriteHeader(n.fail)
+		return
+	}
+	n.auth = r.Header.Get("Authorization")
+
+	type request struct {
+		ID     uint64
+		Method string
+		Params struct{ A, B int }
+	}
+	answer := func(req request) map[string]interface{} {
+		resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		switch req.Method {
+		case "Arith.Add":
+			resp["result"] = map[string]int{"C": req.Params.A + req.Params.B}
+		case "Arith.Lost":
+			return nil
+		default:
+			resp["error"] = map[string]interface{}{"code": -32601, "message": "method not found"}
+		}
+		return resp
+	}
+
+	body, _ := ioutil.ReadAll(r.Body)
+	if body[0] != '[' {
+		req := request{}
+		_ = json.Unmarshal(body, &req)
+		_ = json.NewEncoder(w).Encode(answer(req))
+		return
+	}
+	reqs := make([]request, 0)
+	_ = json.Unmarshal(body, &reqs)
+	resps := make([]map[string]interface{}, 0)
+	for i := len(reqs) - 1; i >= 0; i-- {
+		if resp := answer(reqs[i]); resp != nil {
+			resps = append(resps, resp)
+		}
+	}
+	_ = json.NewEncoder(w).Encode(resps)
+}
+
+func newTestClient(t *testing.T, node *testNode) (*Client, func()) {
+	srv := httptest.NewServer(node)
+	c, err := NewClientWithOptions(srv.URL, ClientOptions{
+		Auth:  "secret",
+		Retry: RetryPolicy{Attempts: 3, Backoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond},
+	})
+	require.NoError(t, err)
+	return c, srv.Close
+}
+
+type sum struct{ C int }
+
+func TestClient_Batch(t *testing.T) {
+	node := &testNode{}
+	c, stop := newTestClient(t, node)
+	defer stop()
+
+	one, two, lost, unknown := &sum{}, &sum{}, &sum{}, &sum{}
+	calls := []*BatchCall{
+		{Method: "Arith.Add", Args: map[string]int{"A": 1, "B": 2}, Reply: one},
+		{Method: "Arith.Add", Args: map[string]int{"A": 3, "B": 4}, Reply: two},
+		{Method: "Arith.Lost", Reply: lost},
+		{Method: "Arith.Unknown", Reply: unknown},
+	}
+	require.NoError(t, c.Batch(context.Background(), calls...))
+
+	// the replies are matched by id, whatever their order
+	assert.NoError(t, calls[0].Error)
+	assert.Equal(t, 3, one.C)
+	assert.NoError(t, calls[1].Error)
+	assert.Equal(t, 7, two.C)
+	assert.Error(t, calls[2].Error)
+	assert.Contains(t, calls[3].Error.Error(), "method not found")
+	assert.EqualValues(t, 1, node.requests)
+	assert.Equal(t, "Bearer secret", node.auth)
+
+	reply := &sum{}
+	require.NoError(t, c.CallContext(context.Background(), "Arith.Add", map[string]int{"A": 5, "B": 5}, reply))
+	assert.Equal(t, 10, reply.C)
+	assert.Error(t, c.CallContext(context.Background(), "Arith.Unknown", nil, reply))
+
+	assert.NoError(t, c.Batch(context.Background()))
+}
+
+func TestClient_Retry(t *testing.T) {
+	add := map[string]int{"A": 1, "B": 1}
+
+	// idempotent calls are tried again while the node is busy
+	node := &testNode{failures: 2, fail: http.StatusServiceUnavailable}
+	c, stop := newTestClient(t, node)
+	defer stop()
+	reply := &sum{}
+	require.NoError(t, c.CallIdempotent(context.Background(), "Arith.Add", add, reply))
+	assert.Equal(t, 2, reply.C)
+	assert.EqualValues(t, 3, node.requests)
+
+	// up to the attempts of the policy
+	node.requests, node.failures = 0, 3
+	assert.Error(t, c.CallIdempotent(context.Background(), "Arith.Add", add, reply))
+	assert.EqualValues(t, 3, node.requests)
+
+	// the others aren't, nor are batches with any of them
+	node.requests, node.failures = 0, 1
+	assert.Error(t, c.CallContext(context.Background(), "Arith.Add", add, reply))
+	assert.EqualValues(t, 1, node.requests)
+	node.requests, node.failures = 0, 1
+	err := c.Batch(context.Background(),
+		&BatchCall{Method: "Arith.Add", Args: add, Reply: &sum{}, Idempotent: true},
+		&BatchCall{Method: "Arith.Add", Args: add, Reply: &sum{}})
+	assert.Error(t, err)
+	assert.EqualValues(t, 1, node.requests)
+
+	// errors a retry won't fix aren't retried
+	node.requests, node.failures, node.fail = 0, 1, http.StatusForbidden
+	assert.Error(t, c.CallIdempotent(context.Background(), "Arith.Add", add, reply))
+	assert.EqualValues(t, 1, node.requests)
+}
+
+func TestClient_RetryStopsWithContext(t *testing.T) {
+	node := &testNode{failures: 10, fail: http.StatusTooManyRequests}
+	c, stop := newTestClient(t, node)
+	defer stop()
+	c.retry = RetryPolicy{Attempts: 10, Backoff: time.Hour, MaxBackoff: time.Hour}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := c.CallIdempotent(ctx, "Arith.Add", nil, &sum{})
+	assert.Equal(t, context.DeadlineExceeded, errors.Cause(err))
+	assert.EqualValues(t, 1, node.requests)
+}
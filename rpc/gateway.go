@@ -0,0 +1,351 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// PathREST prefixes the REST routes of the gateway
+	PathREST    = "/v1/"
+	PathOpenAPI = "/v1/openapi.json"
+)
+
+// Route maps a REST path onto a JSON-RPC method. Path parameters like {address} and, for GET
+// routes, the query parameters fill the fields of the request with the same json name. The
+// body of other routes is the request itself.
+type Route struct {
+	Method  string
+	Path    string
+	RPC     string
+	Summary string
+}
+
+// route is a Route of a registered method, split for matching
+type route struct {
+	Route
+	segments  []string
+	reqType   reflect.Type
+	replyType reflect.Type
+}
+
+// literals counts the segments that aren't parameters, routes with more of them win
+func (rt *route) literals() int {
+	n := 0
+	for _, s := range rt.segments {
+		if !isParam(s) {
+			n++
+		}
+	}
+	return n
+}
+
+func (rt *route) match(method string, segments []string) (map[string]string, bool) {
+	if rt.Method != method || len(rt.segments) != len(segments) {
+		return nil, false
+	}
+	params := make(map[string]string)
+	for i, s := range rt.segments {
+		if isParam(s) {
+			params[strings.Trim(s, "{}")] = segments[i]
+		} else if s != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+func isParam(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+// RegisterRoutes serves the routes of registered methods under PathREST and describes them
+// at PathOpenAPI. Routes of methods that weren't registered, like those of disabled services,
+// are left out.
+func (srv *Server) RegisterRoutes(routes ...Route) error {
+	for _, r := range routes {
+		typ, ok := srv.methods[r.RPC]
+		if !ok {
+			continue
+		}
+		if !strings.HasPrefix(r.Path, PathREST) {
+			return errors.Errorf("route %s %s is not under %s", r.Method, r.Path, PathREST)
+		}
+
+		rt := &route{
+			Route:     r,
+			segments:  splitPath(r.Path),
+			reqType:   typ.In(1),
+			replyType: typ.In(2).Elem(),
+		}
+		for _, s := range rt.segments {
+			if isParam(s) && fieldType(rt.reqType, strings.Trim(s, "{}")) == nil {
+				return errors.Errorf("route %s %s: %s has no field %s", r.Method, r.Path, rt.reqType, s)
+			}
+		}
+		srv.routes = append(srv.routes, rt)
+	}
+	return nil
+}
+
+// gateway serves the REST routes by passing them on as JSON-RPC requests, through the
+// authorization of the rpc handler
+type gateway struct {
+	srv *Server
+}
+
+func (g gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// the calls are passed on as JSON-RPC, which browsers only let other sites send after a
+	// CORS preflight. Pages can post forms and text/plain anywhere, so those are refused here.
+	if !originAllowed(r, g.srv.allowedOrigins()) {
+		writeJSON(w, http.StatusForbidden, NotAllowedError("origin not allowed"))
+		return
+	}
+	if r.Method != http.MethodGet && !isJSON(r) {
+		writeJSON(w, http.StatusUnsupportedMediaType, InvalidRequestError("content type must be "+ContentType))
+		return
+	}
+
+	if r.URL.Path == PathOpenAPI && r.Method == http.MethodGet {
+		writeJSON(w, http.StatusOK, openAPI(g.srv.routes))
+		return
+	}
+
+	segments := splitPath(r.URL.Path)
+	var (
+		found  *route
+		params map[string]string
+	)
+	for _, rt := range g.srv.routes {
+		p, ok := rt.match(r.Method, segments)
+		if ok && (found == nil || rt.literals() > found.literals()) {
+			found, params = rt, p
+		}
+	}
+	if found == nil {
+		writeJSON(w, http.StatusNotFound, NewError(CodeMethodNotFound, "no route for "+r.Method+" "+r.URL.Path))
+		return
+	}
+
+	if auth, ok := g.srv.authenticator.(*rpcAuthHandler); ok {
+		auth.limits.limitBody(w, r)
+	}
+	request, err := found.buildRequest(r, params)
+	if err != nil {
+		status := http.StatusBadRequest
+		if strings.Contains(err.Error(), "request body too large") {
+			status = http.StatusRequestEntityTooLarge
+		}
+		writeJSON(w, status, InvalidParamsError(err.Error()))
+		return
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  found.RPC,
+		"params":  request,
+	})
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, InternalError(err.Error()))
+		return
+	}
+
+	u := *r.URL
+	u.Path, u.RawQuery = Path, ""
+	call := r.WithContext(r.Context())
+	call.Method = http.MethodPost
+	call.URL = &u
+	call.Header = r.Header.Clone()
+	call.Header.Set("Content-Type", ContentType)
+	call.Header.Set("Accept", ContentType)
+	call.Body = ioutil.NopCloser(bytes.NewReader(body))
+	call.ContentLength = int64(len(body))
+
+	rec := newRecorder()
+	g.srv.authenticator.ServeHTTP(rec, call)
+	if rec.status != http.StatusOK {
+		// refused before the call, e.g. unauthorized
+		copyHeader(w.Header(), rec.Header())
+		w.WriteHeader(rec.status)
+		_, _ = w.Write(rec.body.Bytes())
+		return
+	}
+
+	resp := struct {
+		Result json.RawMessage `json:"result"`
+		Error  *Error          `json:"error"`
+	}{}
+	err = json.Unmarshal(rec.body.Bytes(), &resp)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, InternalError("invalid rpc response"))
+		return
+	}
+	if resp.Error != nil {
+		writeJSON(w, errorStatus(resp.Error), resp.Error)
+		return
+	}
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(resp.Result)
+}
+
+// isJSON tells if the request says its body is json
+func isJSON(r *http.Request) bool {
+	typ, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && typ == ContentType
+}
+
+// buildRequest builds the params of the call from the body and the parameters of the path and
+// query, parameters are converted to the type of their field
+func (rt *route) buildRequest(r *http.Request, params map[string]string) (map[string]interface{}, error) {
+	request := make(map[string]interface{})
+	if r.Method != http.MethodGet && r.Body != nil {
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read body")
+		}
+		if len(bytes.TrimSpace(data)) > 0 {
+			// numbers are kept as they are, int64 fields don't fit a float64
+			dec := json.NewDecoder(bytes.NewReader(data))
+			dec.UseNumber()
+			err = dec.Decode(&request)
+			if err != nil {
+				return nil, errors.Wrap(err, "invalid body")
+			}
+		}
+	}
+
+	if r.Method == http.MethodGet {
+		for name, values := range r.URL.Query() {
+			if len(values) == 0 {
+				continue
+			}
+			v, err := paramValue(rt.reqType, name, values[0])
+			if err != nil {
+				return nil, err
+			}
+			request[name] = v
+		}
+	}
+	for name, value := range params {
+		v, err := paramValue(rt.reqType, name, value)
+		if err != nil {
+			return nil, err
+		}
+		request[name] = v
+	}
+	return request, nil
+}
+
+func paramValue(request reflect.Type, name, value string) (interface{}, error) {
+	typ := fieldType(request, name)
+	if typ == nil {
+		return nil, errors.Errorf("unknown parameter %s", name)
+	}
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if isStringLike(typ) {
+		return value, nil
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, errors.Errorf("%s must be true or false", name)
+		}
+		return b, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		_, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, errors.Errorf("%s must be a number", name)
+		}
+		return json.Number(value), nil
+	}
+	return value, nil
+}
+
+// fieldType finds the field of a struct by its json name, the way encoding/json does
+func fieldType(typ reflect.Type, name string) reflect.Type {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return nil
+	}
+	for _, f := range jsonFields(typ) {
+		if strings.EqualFold(f.name, name) {
+			return f.typ
+		}
+	}
+	return nil
+}
+
+func errorStatus(e *Error) int {
+	switch e.Code {
+	case CodeParseError, CodeInvalidRequest, CodeInvalidParams:
+		return http.StatusBadRequest
+	case CodeMethodNotFound:
+		return http.StatusNotFound
+	case CodeInternalError:
+		return http.StatusInternalServerError
+	case CodeNotAllowed:
+		return http.StatusForbidden
+	}
+	// errors of the services
+	return http.StatusBadRequest
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func copyHeader(dst, src http.Header) {
+	for k, v := range src {
+		dst[k] = v
+	}
+}
+
+// recorder keeps the response of the rpc handler so the gateway can rewrite it
+type recorder struct {
+	header http.Header
+	status int
+	body   *bytes.Buffer
+}
+
+func newRecorder() *recorder {
+	return &recorder{
+		header: make(http.Header),
+		status: http.StatusOK,
+		body:   &bytes.Buffer{},
+	}
+}
+
+func (r *recorder) Header() http.Header {
+	return r.header
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	return r.body.Write(b)
+}
+
+func (r *recorder) WriteHeader(status int) {
+	r.status = status
+}
@@ -0,0 +1,97 @@
+package rpc
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Oneledger/protocol/config"
+)
+
+func newTestGateway(t *testing.T) (gateway, *[]string) {
+	cfg := config.DefaultServerConfig()
+	cfg.Node.AllowedOrigins = []string{"https://wallet.example.com"}
+	srv := NewServer(ioutil.Discard, cfg)
+
+	service := Arith(0)
+	require.NoError(t, srv.Register("Arith", &service))
+	require.NoError(t, srv.RegisterRoutes(
+		Route{Method: http.MethodPost, Path: "/v1/arith/multiply", RPC: "Arith.Multiply"},
+		Route{Method: http.MethodGet, Path: "/v1/arith/multiply/{A}/{B}", RPC: "Arith.Multiply"},
+	))
+
+	// the calls the gateway passes on
+	calls := make([]string, 0)
+	srv.authenticator = &rpcAuthHandler{
+		rpcHandler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			calls = append(calls, string(body))
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":15}`))
+		}),
+		disabled: newDisabledServices(),
+	}
+	return gateway{srv}, &calls
+}
+
+func TestGateway(t *testing.T) {
+	g, calls := newTestGateway(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/arith/multiply", strings.NewReader(`{"A":3,"B":5}`))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	rec := httptest.NewRecorder()
+	g.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "15", rec.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/v1/arith/multiply/3/5", nil)
+	rec = httptest.NewRecorder()
+	g.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	require.Len(t, *calls, 2)
+	assert.Contains(t, (*calls)[0], `"method":"Arith.Multiply"`)
+	assert.Contains(t, (*calls)[1], `"A":3`)
+}
+
+func TestGateway_RejectsCrossSiteRequests(t *testing.T) {
+	g, calls := newTestGateway(t)
+
+	// what a page of any site can send without a CORS preflight
+	for _, typ := range []string{"", "text/plain", "application/x-www-form-urlencoded", "multipart/form-data"} {
+		req := httptest.NewRequest(http.MethodPost, "/v1/arith/multiply", strings.NewReader(`{"A":3,"B":5}`))
+		if typ != "" {
+			req.Header.Set("Content-Type", typ)
+		}
+		rec := httptest.NewRecorder()
+		g.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code, typ)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/arith/multiply", strings.NewReader(`{"A":3,"B":5}`))
+	req.Header.Set("Content-Type", ContentType)
+	req.Header.Set("Origin", "https://evil.example.com")
+	rec := httptest.NewRecorder()
+	g.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/v1/arith/multiply/3/5", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	rec = httptest.NewRecorder()
+	g.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	assert.Empty(t, *calls)
+
+	// allowed origins pass
+	req = httptest.NewRequest(http.MethodPost, "/v1/arith/multiply", strings.NewReader(`{"A":3,"B":5}`))
+	req.Header.Set("Content-Type", ContentType)
+	req.Header.Set("Origin", "https://wallet.example.com")
+	rec = httptest.NewRecorder()
+	g.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
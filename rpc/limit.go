@@ -0,0 +1,293 @@
+package rpc
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/Oneledger/protocol/config"
+)
+
+// ParseMethodCosts reads method costs configured as "method:cost"
+func ParseMethodCosts(list []string) (map[string]float64, error) {
+	costs := make(map[string]float64)
+	for _, s := range list {
+		i := strings.LastIndex(s, ":")
+		if i <= 0 {
+			return nil, errors.Errorf("invalid method cost %q, expected method:cost", s)
+		}
+		cost, err := strconv.ParseFloat(s[i+1:], 64)
+		if err != nil || cost < 0 {
+			return nil, errors.Errorf("invalid method cost %q, expected method:cost", s)
+		}
+		costs[s[:i]] = cost
+	}
+	return costs, nil
+}
+
+// RateLimiter keeps a token bucket per client. A bucket holds up to burst tokens and gets
+// rate tokens a second.
+type RateLimiter struct {
+	sync.Mutex
+
+	rate    float64
+	burst   float64
+	buckets map[string]*bucket
+	swept   time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter returns nil for a rate of 0, a nil limiter allows everything
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	if rate <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:    rate,
+		burst:   float64(burst),
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// Fits tells if a request of the cost can ever be allowed, the bucket never holds more than
+// the burst
+func (l *RateLimiter) Fits(cost float64) bool {
+	return l == nil || cost <= l.burst
+}
+
+// Allow takes cost tokens from the bucket of the client. When there aren't enough it takes
+// none and returns how long until there are. Costs that don't fit the burst are never allowed.
+func (l *RateLimiter) Allow(client string, cost float64, now time.Time) (bool, time.Duration) {
+	if l == nil {
+		return true, 0
+	}
+	if !l.Fits(cost) {
+		return false, 0
+	}
+	l.Lock()
+	defer l.Unlock()
+
+	l.sweep(now)
+
+	b, ok := l.buckets[client]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[client] = b
+	}
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+
+	if b.tokens < cost {
+		wait := time.Duration((cost - b.tokens) / l.rate * float64(time.Second))
+		return false, wait
+	}
+	b.tokens -= cost
+	return true, 0
+}
+
+// sweep drops the buckets that filled up again, once a minute
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.swept) < time.Minute {
+		return
+	}
+	l.swept = now
+	full := time.Duration(l.burst / l.rate * float64(time.Second))
+	for client, b := range l.buckets {
+		if now.Sub(b.last) >= full {
+			delete(l.buckets, client)
+		}
+	}
+}
+
+// concurrency caps the requests served at once, in total and for every client
+type concurrency struct {
+	sync.Mutex
+
+	max       int
+	perClient int
+	total     int
+	clients   map[string]int
+}
+
+func newConcurrency(max, perClient int) *concurrency {
+	return &concurrency{
+		max:       max,
+		perClient: perClient,
+		clients:   make(map[string]int),
+	}
+}
+
+func (c *concurrency) acquire(client string) bool {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.max > 0 && c.total >= c.max {
+		return false
+	}
+	if c.perClient > 0 && c.clients[client] >= c.perClient {
+		return false
+	}
+	c.total++
+	c.clients[client]++
+	return true
+}
+
+func (c *concurrency) release(client string) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.total--
+	c.clients[client]--
+	if c.clients[client] <= 0 {
+		delete(c.clients, client)
+	}
+}
+
+// limits are the [rpc] limits of the server
+type limits struct {
+	cfg     *config.RPCConfig
+	costs   map[string]float64
+	ips     *RateLimiter
+	apiKeys *RateLimiter
+	running *concurrency
+}
+
+func newLimits(cfg *config.RPCConfig) (*limits, error) {
+	if cfg == nil {
+		cfg = config.DefaultRPCConfig()
+	}
+	costs, err := ParseMethodCosts(cfg.MethodCosts)
+	if err != nil {
+		return nil, err
+	}
+	return &limits{
+		cfg:     cfg,
+		costs:   costs,
+		ips:     NewRateLimiter(cfg.RateLimit, cfg.RateBurst),
+		apiKeys: NewRateLimiter(cfg.APIKeyRateLimit, cfg.APIKeyRateBurst),
+		running: newConcurrency(cfg.MaxConcurrent, cfg.MaxConcurrentPerClient),
+	}, nil
+}
+
+// limitBody stops reading the body past the limit
+func (l *limits) limitBody(respW http.ResponseWriter, req *http.Request) {
+	if l != nil && l.cfg.MaxBodyBytes > 0 {
+		req.Body = http.MaxBytesReader(respW, req.Body, l.cfg.MaxBodyBytes)
+	}
+}
+
+// maxBatch is the most calls a batch request may hold, 0 for any number
+func (l *limits) maxBatch() int {
+	if l == nil {
+		return 0
+	}
+	return l.cfg.MaxBatchSize
+}
+
+// exempt tells if the limits don't apply to the caller
+func (l *limits) exempt(p *Principal) bool {
+	return l == nil || (p.Auth == AuthLocal && !l.cfg.LimitLocal)
+}
+
+// client names who the limits are kept for, the API key or else the IP address
+func client(p *Principal, req *http.Request) string {
+	if p.Auth == AuthAPIKey {
+		return "apikey " + p.Key
+	}
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+	return "ip " + host
+}
+
+// cost adds up the costs of the methods, or of the paths of the requests that aren't JSON-RPC
+// calls. Those not in method_costs cost 1.
+func (l *limits) cost(methods []string) float64 {
+	total := 0.0
+	for _, m := range methods {
+		cost, ok := l.costs[m]
+		if !ok {
+			cost = 1
+		}
+		total += cost
+	}
+	return total
+}
+
+// allow takes the cost of the methods from the bucket of the client. It fails for requests
+// costing more than the client may spend at once, a batch can't add up to more than single calls.
+func (l *limits) allow(p *Principal, client string, methods []string, now time.Time) (bool, time.Duration, error) {
+	limiter := l.ips
+	if p.Auth == AuthAPIKey {
+		limiter = l.apiKeys
+	}
+	cost := l.cost(methods)
+	if !limiter.Fits(cost) {
+		return false, 0, errors.Errorf("request costs %g, more than the %g allowed at once", cost, limiter.burst)
+	}
+	ok, wait := limiter.Allow(client, cost, now)
+	return ok, wait, nil
+}
+
+// limitRequest applies the rate limit to a request that isn't a JSON-RPC call, it costs what
+// method_costs sets for its path
+func (l *limits) limitRequest(respW http.ResponseWriter, req *http.Request, p *Principal) bool {
+	if l.exempt(p) {
+		return true
+	}
+	ok, wait, err := l.allow(p, client(p, req), []string{req.URL.Path}, time.Now())
+	if err != nil {
+		http.Error(respW, err.Error(), http.StatusRequestEntityTooLarge)
+		return false
+	}
+	if !ok {
+		tooManyRequests(respW, "rate limit exceeded", wait)
+		return false
+	}
+	return true
+}
+
+// limitedHandler rate limits the handlers outside of the JSON-RPC calls that need no
+// authentication, like /health
+type limitedHandler struct {
+	srv     *Server
+	handler http.Handler
+}
+
+func (h limitedHandler) ServeHTTP(respW http.ResponseWriter, req *http.Request) {
+	// the limits are only known once the server is prepared
+	if auth, ok := h.srv.authenticator.(*rpcAuthHandler); ok {
+		p := &Principal{Name: req.RemoteAddr, Auth: AuthAnonymous, Roles: []Role{RolePublic}}
+		if isLocal(req) && !fromPage(req) {
+			p.Auth = AuthLocal
+		}
+		if !auth.limits.limitRequest(respW, req, p) {
+			return
+		}
+	}
+	h.handler.ServeHTTP(respW, req)
+}
+
+// tooManyRequests answers with 429, telling when to try again
+func tooManyRequests(respW http.ResponseWriter, msg string, wait time.Duration) {
+	seconds := int(math.Ceil(wait.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	respW.Header().Set("Retry-After", strconv.Itoa(seconds))
+	http.Error(respW, msg, http.StatusTooManyRequests)
+}
@@ -0,0 +1,144 @@
+package rpc
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Oneledger/protocol/config"
+)
+
+func TestParseMethodCosts(t *testing.T) {
+	costs, err := ParseMethodCosts([]string{"query.Balance:2", "/health:5", "broadcast.TxCommit:0.5"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"query.Balance": 2, "/health": 5, "broadcast.TxCommit": 0.5}, costs)
+
+	for _, s := range []string{"query.Balance", ":2", "query.Balance:-1", "query.Balance:x"} {
+		_, err = ParseMethodCosts([]string{s})
+		assert.Error(t, err, s)
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	assert.True(t, (*RateLimiter)(nil).Fits(1e9))
+	ok, _ := (*RateLimiter)(nil).Allow("a", 1e9, time.Now())
+	assert.True(t, ok)
+	assert.Nil(t, NewRateLimiter(0, 10))
+
+	l := NewRateLimiter(2, 4)
+	now := time.Now()
+	for i := 0; i < 4; i++ {
+		ok, _ := l.Allow("a", 1, now)
+		assert.True(t, ok)
+	}
+	ok, wait := l.Allow("a", 1, now)
+	assert.False(t, ok)
+	assert.Equal(t, 500*time.Millisecond, wait)
+
+	// every client has its own bucket
+	ok, _ = l.Allow("b", 4, now)
+	assert.True(t, ok)
+
+	// refilled at the rate, up to the burst
+	ok, _ = l.Allow("a", 2, now.Add(time.Second))
+	assert.True(t, ok)
+	ok, _ = l.Allow("a", 4, now.Add(time.Hour))
+	assert.True(t, ok)
+
+	// more than the burst is never allowed, however long the client waits
+	assert.False(t, l.Fits(5))
+	ok, _ = l.Allow("c", 5, now.Add(time.Hour))
+	assert.False(t, ok)
+}
+
+func newLimitedAuth(t *testing.T, rpcCfg *config.RPCConfig) *rpcAuthHandler {
+	lim, err := newLimits(rpcCfg)
+	require.NoError(t, err)
+	return &rpcAuthHandler{
+		rpcHandler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("ok"))
+		}),
+		roles:    map[string]Role{"query": RolePublic, "broadcast": RolePublic},
+		disabled: newDisabledServices(),
+		limits:   lim,
+	}
+}
+
+func batchRequest(method string, n int) *http.Request {
+	calls := make([]string, n)
+	for i := range calls {
+		calls[i] = fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"%s","params":{}}`, i, method)
+	}
+	req := httptest.NewRequest(http.MethodPost, Path, strings.NewReader("["+strings.Join(calls, ",")+"]"))
+	req.Header.Set("Content-Type", ContentType)
+	req.RemoteAddr = "10.0.0.2:5000"
+	return req
+}
+
+func TestLimits_Batches(t *testing.T) {
+	cfg := config.DefaultRPCConfig()
+	h := newLimitedAuth(t, cfg)
+
+	// a batch costs the sum of its calls, it can't spend more than the burst at once
+	assert.Equal(t, http.StatusRequestEntityTooLarge, serve(h, batchRequest("broadcast.TxCommit", 5)))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, serve(h, batchRequest("query.Balance", 1000)))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, serve(h, batchRequest("query.Balance", cfg.MaxBatchSize+1)))
+
+	// the refused batches took nothing
+	assert.Equal(t, http.StatusOK, serve(h, batchRequest("broadcast.TxCommit", 4)))
+	assert.Equal(t, http.StatusTooManyRequests, serve(h, batchRequest("broadcast.TxCommit", 1)))
+}
+
+func TestLimits_Local(t *testing.T) {
+	cfg := config.DefaultRPCConfig()
+	cfg.RateBurst = 1
+	h := newLimitedAuth(t, cfg)
+
+	local := func() *http.Request {
+		req := rpcRequest("query.Balance")
+		req.RemoteAddr = "127.0.0.1:5000"
+		return req
+	}
+	assert.Equal(t, http.StatusOK, serve(h, local()))
+	assert.Equal(t, http.StatusOK, serve(h, local()))
+
+	cfg.LimitLocal = true
+	assert.Equal(t, http.StatusOK, serve(h, local()))
+	assert.Equal(t, http.StatusTooManyRequests, serve(h, local()))
+}
+
+func TestLimits_OtherRequests(t *testing.T) {
+	cfg := config.DefaultRPCConfig()
+	srv := NewServer(ioutil.Discard, nil)
+	defer srv.events.Close()
+	srv.authenticator = newLimitedAuth(t, cfg)
+
+	served := 0
+	health := limitedHandler{srv, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served++
+	})}
+	request := func(path string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = "10.0.0.2:5000"
+		return req
+	}
+
+	// /health costs 5 of the burst of 40
+	for i := 0; i < 8; i++ {
+		assert.Equal(t, http.StatusOK, serve(health, request("/health")))
+	}
+	assert.Equal(t, http.StatusTooManyRequests, serve(health, request("/health")))
+	assert.Equal(t, 8, served)
+
+	// websocket upgrades are authorized through the same bucket
+	rec := httptest.NewRecorder()
+	assert.False(t, srv.authenticator.Authorized(rec, request(PathWebSocket)))
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+}
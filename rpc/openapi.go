@@ -0,0 +1,307 @@
+package rpc
+
+import (
+	"encoding"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// OpenAPIVersion is the version of the OpenAPI specification the gateway is described in
+const OpenAPIVersion = "3.0.2"
+
+var (
+	jsonMarshaler = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshaler = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	bigIntType    = reflect.TypeOf(big.Int{})
+	timeType      = reflect.TypeOf(time.Time{})
+)
+
+// Schema is the subset of the OpenAPI schema object the gateway uses
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+type jsonField struct {
+	name     string
+	typ      reflect.Type
+	asString bool
+}
+
+// jsonFields lists the fields of a struct the way encoding/json writes them, fields of
+// embedded structs included
+func jsonFields(typ reflect.Type) []jsonField {
+	fields := make([]jsonField, 0, typ.NumField())
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		opts := strings.Split(tag, ",")
+		name := opts[0]
+
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			fields = append(fields, jsonFields(ft)...)
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		asString := false
+		for _, o := range opts[1:] {
+			asString = asString || o == "string"
+		}
+		fields = append(fields, jsonField{name, f.Type, asString})
+	}
+	return fields
+}
+
+func marshalsItself(typ reflect.Type) bool {
+	ptr := reflect.PtrTo(typ)
+	return typ.Implements(jsonMarshaler) || ptr.Implements(jsonMarshaler) ||
+		typ.Implements(textMarshaler) || ptr.Implements(textMarshaler)
+}
+
+// isStringLike tells if values of the type are written as json strings
+func isStringLike(typ reflect.Type) bool {
+	if typ == bigIntType {
+		return false
+	}
+	if typ == timeType || typ.Kind() == reflect.String || marshalsItself(typ) {
+		return true
+	}
+	return typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.Uint8
+}
+
+// schemas builds the schemas of the types used by the routes, structs become components
+// referred to by name
+type schemas struct {
+	components map[string]*Schema
+	names      map[reflect.Type]string
+}
+
+// name gives the component name of a struct, types outside the client package carry their
+// package to keep the names apart
+func (s *schemas) name(typ reflect.Type) string {
+	if name, ok := s.names[typ]; ok {
+		return name
+	}
+	name := typ.Name()
+	if pkg := path.Base(typ.PkgPath()); pkg != "client" && pkg != "." {
+		r := []rune(pkg)
+		r[0] = unicode.ToUpper(r[0])
+		name = string(r) + name
+	}
+	s.names[typ] = name
+	return name
+}
+
+func (s *schemas) schema(typ reflect.Type) *Schema {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	switch {
+	case typ == bigIntType:
+		return &Schema{Type: "integer", Description: "arbitrary precision integer"}
+	case typ == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.Uint8 && !marshalsItself(typ):
+		return &Schema{Type: "string", Format: "byte"}
+	case isStringLike(typ):
+		return &Schema{Type: "string"}
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: s.schema(typ.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.schema(typ.Elem())}
+	case reflect.Struct:
+		obj := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+		if typ.Name() == "" {
+			s.properties(obj, typ)
+			return obj
+		}
+		name := s.name(typ)
+		ref := &Schema{Ref: "#/components/schemas/" + name}
+		if _, ok := s.components[name]; !ok {
+			// registered before its fields, so types referring to themselves end
+			s.components[name] = obj
+			s.properties(obj, typ)
+		}
+		return ref
+	}
+	// interfaces and the like can hold anything
+	return &Schema{}
+}
+
+func (s *schemas) properties(obj *Schema, typ reflect.Type) {
+	for _, f := range jsonFields(typ) {
+		if f.asString {
+			obj.Properties[f.name] = &Schema{Type: "string"}
+			continue
+		}
+		obj.Properties[f.name] = s.schema(f.typ)
+	}
+}
+
+type openAPIParameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *Schema `json:"schema"`
+}
+
+type openAPIMedia struct {
+	Schema *Schema `json:"schema"`
+}
+
+type openAPIBody struct {
+	Required bool                    `json:"required"`
+	Content  map[string]openAPIMedia `json:"content"`
+}
+
+type openAPIResponse struct {
+	Description string                  `json:"description"`
+	Content     map[string]openAPIMedia `json:"content,omitempty"`
+}
+
+type openAPIOperation struct {
+	OperationID string                     `json:"operationId"`
+	Summary     string                     `json:"summary,omitempty"`
+	Tags        []string                   `json:"tags"`
+	Parameters  []openAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *openAPIBody               `json:"requestBody,omitempty"`
+	Responses   map[string]openAPIResponse `json:"responses"`
+	RPCMethod   string                     `json:"x-rpc-method"`
+}
+
+// OpenAPI is the document describing the REST routes
+type OpenAPI struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       map[string]string                       `json:"info"`
+	Paths      map[string]map[string]*openAPIOperation `json:"paths"`
+	Components map[string]interface{}                  `json:"components"`
+	Security   []map[string][]string                   `json:"security"`
+}
+
+func jsonContent(schema *Schema) map[string]openAPIMedia {
+	return map[string]openAPIMedia{ContentType: {Schema: schema}}
+}
+
+func openAPI(routes []*route) *OpenAPI {
+	s := &schemas{
+		components: make(map[string]*Schema),
+		names:      make(map[reflect.Type]string),
+	}
+	s.components["Error"] = &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"code":    {Type: "integer", Format: "int32"},
+			"message": {Type: "string"},
+			"data":    {},
+		},
+	}
+
+	doc := &OpenAPI{
+		OpenAPI: OpenAPIVersion,
+		Info: map[string]string{
+			"title":       "OneLedger SDK",
+			"version":     strings.Trim(PathREST, "/"),
+			"description": "REST routes of the JSON-RPC services of the SDK port, every route calls the JSON-RPC method in x-rpc-method.",
+		},
+		Paths: make(map[string]map[string]*openAPIOperation),
+		Components: map[string]interface{}{
+			"schemas": s.components,
+			"securitySchemes": map[string]interface{}{
+				"token":  map[string]string{"type": "http", "scheme": "bearer"},
+				"apiKey": map[string]string{"type": "apiKey", "in": "header", "name": APIKeyHeader},
+			},
+		},
+		Security: []map[string][]string{{}, {"token": {}}, {"apiKey": {}}},
+	}
+
+	sorted := make([]*route, len(routes))
+	copy(sorted, routes)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Path < sorted[j].Path })
+
+	for _, rt := range sorted {
+		op := &openAPIOperation{
+			OperationID: strings.Replace(rt.RPC, ".", "_", -1),
+			Summary:     rt.Summary,
+			Tags:        []string{strings.Split(rt.RPC, ".")[0]},
+			Responses: map[string]openAPIResponse{
+				"200":     {Description: "OK", Content: jsonContent(s.schema(rt.replyType))},
+				"default": {Description: "Error", Content: jsonContent(&Schema{Ref: "#/components/schemas/Error"})},
+			},
+			RPCMethod: rt.RPC,
+		}
+
+		inPath := make(map[string]bool)
+		for _, seg := range rt.segments {
+			if isParam(seg) {
+				name := strings.Trim(seg, "{}")
+				inPath[name] = true
+				op.Parameters = append(op.Parameters, openAPIParameter{
+					Name: name, In: "path", Required: true, Schema: s.schema(fieldType(rt.reqType, name)),
+				})
+			}
+		}
+
+		if rt.Method == http.MethodGet {
+			req := rt.reqType
+			for req.Kind() == reflect.Ptr {
+				req = req.Elem()
+			}
+			if req.Kind() == reflect.Struct {
+				for _, f := range jsonFields(req) {
+					if inPath[f.name] {
+						continue
+					}
+					op.Parameters = append(op.Parameters, openAPIParameter{
+						Name: f.name, In: "query", Schema: s.schema(f.typ),
+					})
+				}
+			}
+		} else {
+			op.RequestBody = &openAPIBody{Required: true, Content: jsonContent(s.schema(rt.reqType))}
+		}
+
+		if doc.Paths[rt.Path] == nil {
+			doc.Paths[rt.Path] = make(map[string]*openAPIOperation)
+		}
+		doc.Paths[rt.Path][strings.ToLower(rt.Method)] = op
+	}
+	return doc
+}
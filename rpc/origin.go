@@ -0,0 +1,29 @@
+package rpc
+
+import (
+	"net/http"
+	"strings"
+)
+
+// originAllowed tells if the web page the request comes from may use the rpc port. Requests
+// without an Origin don't come from a page. "*" allows any origin.
+func originAllowed(req *http.Request, allowed []string) bool {
+	origin := req.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	for _, o := range allowed {
+		if o == "*" || strings.EqualFold(strings.TrimSuffix(o, "/"), origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowedOrigins returns the origins of rpc_allowed_origins
+func (srv *Server) allowedOrigins() []string {
+	if srv.cfg == nil || srv.cfg.Node == nil {
+		return nil
+	}
+	return srv.cfg.Node.AllowedOrigins
+}
@@ -0,0 +1,145 @@
+/*
+
+ */
+
+package pubsub
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/Oneledger/protocol/data/keys"
+)
+
+type EventType string
+
+const (
+	EventNewBlock EventType = "new_block"
+	EventTx       EventType = "tx"
+	EventBalance  EventType = "balance"
+	EventDomain   EventType = "domain"
+	EventTracker  EventType = "tracker"
+)
+
+// attributes events can be filtered on
+const (
+	KeyAddress  = "address"
+	KeyTxType   = "txType"
+	KeyCurrency = "currency"
+	KeyName     = "name"
+	KeyAction   = "action"
+	KeyChain    = "chain"
+	KeyState    = "state"
+)
+
+// filterKeys lists the attributes each event type can be filtered on
+var filterKeys = map[EventType][]string{
+	EventNewBlock: {},
+	EventTx:       {KeyAddress, KeyTxType},
+	EventBalance:  {KeyAddress, KeyCurrency},
+	EventDomain:   {KeyName, KeyAction, KeyAddress},
+	EventTracker:  {KeyChain, KeyName, KeyState},
+}
+
+var ErrInvalidQuery = errors.New("invalid subscription query")
+
+// Event is something that happened in a committed block. Data is sent to the subscribers,
+// Attributes only serve the filters.
+type Event struct {
+	Type       EventType           `json:"type"`
+	Height     int64               `json:"height"`
+	Data       interface{}         `json:"data"`
+	Attributes map[string][]string `json:"-"`
+}
+
+func NewEvent(typ EventType, height int64, data interface{}) *Event {
+	return &Event{
+		Type:       typ,
+		Height:     height,
+		Data:       data,
+		Attributes: make(map[string][]string),
+	}
+}
+
+// With adds values of an attribute to the event
+func (e *Event) With(key string, values ...string) *Event {
+	for _, v := range values {
+		e.Attributes[key] = append(e.Attributes[key], normalize(key, v))
+	}
+	return e
+}
+
+// WithAddress adds addresses to the event, empty ones are skipped
+func (e *Event) WithAddress(addrs ...keys.Address) *Event {
+	for _, a := range addrs {
+		if len(a) > 0 {
+			e.With(KeyAddress, a.String())
+		}
+	}
+	return e
+}
+
+func (e *Event) has(key, value string) bool {
+	for _, v := range e.Attributes[key] {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Query selects the events of a type whose attributes have all the values in Match
+type Query struct {
+	Type  EventType         `json:"type"`
+	Match map[string]string `json:"match,omitempty"`
+}
+
+// Validate checks that the event type exists and can be filtered on the attributes in Match,
+// the values are normalized like the ones of the events
+func (q *Query) Validate() error {
+	allowed, ok := filterKeys[q.Type]
+	if !ok {
+		return errors.Wrapf(ErrInvalidQuery, "unknown event type %q", q.Type)
+	}
+
+	for key, value := range q.Match {
+		found := false
+		for _, k := range allowed {
+			found = found || k == key
+		}
+		if !found {
+			return errors.Wrapf(ErrInvalidQuery, "%s events can't be filtered on %q", q.Type, key)
+		}
+
+		if key == KeyAddress {
+			addr := keys.Address{}
+			if err := addr.UnmarshalText([]byte(value)); err != nil {
+				return errors.Wrap(ErrInvalidQuery, err.Error())
+			}
+			value = addr.String()
+		}
+		q.Match[key] = normalize(key, value)
+	}
+	return nil
+}
+
+func (q Query) Matches(e *Event) bool {
+	if e.Type != q.Type {
+		return false
+	}
+	for key, value := range q.Match {
+		if !e.has(key, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// normalize makes attribute values compare case insensitive
+func normalize(key, value string) string {
+	if key == KeyTxType {
+		return strings.ToUpper(value)
+	}
+	return strings.ToLower(value)
+}
@@ -0,0 +1,156 @@
+/*
+
+ */
+
+package pubsub
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrSlowSubscriber = errors.New("subscriber is not reading events fast enough")
+	ErrUnsubscribed   = errors.New("unsubscribed")
+	ErrHubClosed      = errors.New("event hub closed")
+)
+
+// Subscription receives the events matching its query on Out until it is cancelled
+type Subscription struct {
+	ID    string
+	Query Query
+
+	out       chan *Event
+	cancelled chan struct{}
+	err       error
+}
+
+func (s *Subscription) Out() <-chan *Event {
+	return s.out
+}
+
+// Cancelled is closed once the subscription ends, Err tells why
+func (s *Subscription) Cancelled() <-chan struct{} {
+	return s.cancelled
+}
+
+func (s *Subscription) Err() error {
+	select {
+	case <-s.cancelled:
+		return s.err
+	default:
+		return nil
+	}
+}
+
+// Hub hands out the published events to the subscriptions. Publishing never blocks, every
+// subscription has a buffer of its own and is cancelled with ErrSlowSubscriber when that is
+// full, so one slow client can't hold up the node or the other clients.
+type Hub struct {
+	sync.RWMutex
+
+	subs   map[string]*Subscription
+	buffer int
+	next   uint64
+	closed bool
+}
+
+func NewHub(buffer int) *Hub {
+	return &Hub{
+		subs:   make(map[string]*Subscription),
+		buffer: buffer,
+	}
+}
+
+func (h *Hub) Subscribe(q Query) (*Subscription, error) {
+	if q.Match == nil {
+		q.Match = make(map[string]string)
+	}
+	err := q.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	h.Lock()
+	defer h.Unlock()
+	if h.closed {
+		return nil, ErrHubClosed
+	}
+
+	h.next++
+	s := &Subscription{
+		ID:        strconv.FormatUint(h.next, 10),
+		Query:     q,
+		out:       make(chan *Event, h.buffer),
+		cancelled: make(chan struct{}),
+	}
+	h.subs[s.ID] = s
+	return s, nil
+}
+
+func (h *Hub) Unsubscribe(id string) {
+	h.cancel(id, ErrUnsubscribed)
+}
+
+func (h *Hub) cancel(id string, reason error) {
+	h.Lock()
+	defer h.Unlock()
+
+	s, ok := h.subs[id]
+	if !ok {
+		return
+	}
+	delete(h.subs, id)
+	s.err = reason
+	close(s.cancelled)
+}
+
+// Publish sends the events, in order, to every subscription whose query matches them
+func (h *Hub) Publish(events ...*Event) {
+	slow := make([]string, 0)
+
+	h.RLock()
+	for id, s := range h.subs {
+	events:
+		for _, e := range events {
+			if !s.Query.Matches(e) {
+				continue
+			}
+			select {
+			case s.out <- e:
+			default:
+				slow = append(slow, id)
+				break events
+			}
+		}
+	}
+	h.RUnlock()
+
+	for _, id := range slow {
+		h.cancel(id, ErrSlowSubscriber)
+	}
+}
+
+// Subscribers returns the number of open subscriptions
+func (h *Hub) Subscribers() int {
+	h.RLock()
+	defer h.RUnlock()
+	return len(h.subs)
+}
+
+// Close cancels all subscriptions and refuses new ones
+func (h *Hub) Close() {
+	h.Lock()
+	h.closed = true
+	ids := make([]string, 0, len(h.subs))
+	for id := range h.subs {
+		ids = append(ids, id)
+	}
+	h.Unlock()
+
+	for _, id := range ids {
+		h.cancel(id, ErrHubClosed)
+	}
+}
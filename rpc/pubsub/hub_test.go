@@ -0,0 +1,66 @@
+package pubsub
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Oneledger/protocol/data/keys"
+)
+
+func TestQuery(t *testing.T) {
+	alice := keys.Address("01234567890123456789")
+	tx := NewEvent(EventTx, 1, nil).With(KeyTxType, "SEND").WithAddress(alice, nil)
+
+	q := Query{Type: EventTx, Match: map[string]string{KeyAddress: alice.String(), KeyTxType: "send"}}
+	require.NoError(t, q.Validate())
+	assert.True(t, q.Matches(tx))
+
+	q = Query{Type: EventTx, Match: map[string]string{KeyTxType: "DOMAIN_CREATE"}}
+	require.NoError(t, q.Validate())
+	assert.False(t, q.Matches(tx))
+
+	q = Query{Type: EventBalance}
+	require.NoError(t, q.Validate())
+	assert.False(t, q.Matches(tx))
+
+	for _, q := range []Query{
+		{Type: "unknown"},
+		{Type: EventNewBlock, Match: map[string]string{KeyAddress: alice.String()}},
+		{Type: EventTx, Match: map[string]string{KeyAddress: "not hex"}},
+	} {
+		assert.Equal(t, ErrInvalidQuery, errors.Cause(q.Validate()))
+	}
+}
+
+func TestHub(t *testing.T) {
+	hub := NewHub(2)
+	blocks, err := hub.Subscribe(Query{Type: EventNewBlock})
+	require.NoError(t, err)
+	trackers, err := hub.Subscribe(Query{Type: EventTracker, Match: map[string]string{KeyChain: "BTC"}})
+	require.NoError(t, err)
+	_, err = hub.Subscribe(Query{Type: "unknown"})
+	assert.Error(t, err)
+
+	btc := NewEvent(EventTracker, 1, nil).With(KeyChain, "btc")
+	eth := NewEvent(EventTracker, 1, nil).With(KeyChain, "eth")
+	hub.Publish(NewEvent(EventNewBlock, 1, nil), btc, eth)
+	assert.Equal(t, btc, <-trackers.Out())
+	assert.Len(t, trackers.Out(), 0)
+
+	// a full buffer cancels the subscription instead of blocking
+	hub.Publish(NewEvent(EventNewBlock, 2, nil), NewEvent(EventNewBlock, 3, nil))
+	<-blocks.Cancelled()
+	assert.Equal(t, ErrSlowSubscriber, blocks.Err())
+	assert.Nil(t, trackers.Err())
+	assert.Equal(t, 1, hub.Subscribers())
+
+	hub.Unsubscribe(trackers.ID)
+	assert.Equal(t, ErrUnsubscribed, trackers.Err())
+
+	hub.Close()
+	_, err = hub.Subscribe(Query{Type: EventNewBlock})
+	assert.Equal(t, ErrHubClosed, err)
+}
@@ -2,21 +2,25 @@ package rpc
 
 import (
 	"context"
-	"encoding/base64"
+	"crypto/tls"
 	"io"
 	"net"
 	"net/http"
 	"net/rpc"
 	"net/url"
+	"os"
+	"os/signal"
+	"reflect"
+	"syscall"
 	"time"
 
-	"github.com/btcsuite/btcutil/base58"
 	"github.com/pkg/errors"
 	"github.com/powerman/rpc-codec/jsonrpc2"
 
 	"github.com/Oneledger/protocol/config"
-	"github.com/Oneledger/protocol/data/keys"
 	"github.com/Oneledger/protocol/log"
+	"github.com/Oneledger/protocol/metrics"
+	"github.com/Oneledger/protocol/rpc/pubsub"
 )
 
 // The http path used for our rpc handlers
@@ -39,6 +43,20 @@ type Server struct {
 	// Request multiplexer
 	mux *http.ServeMux
 	cfg *config.Server
+	// Events published to the websocket subscribers
+	events *pubsub.Hub
+	// Role needed to call each service
+	roles    map[string]Role
+	disabled *disabledServices
+	// type of every method by its full name, e.g. query.Balance
+	methods map[string]reflect.Type
+	// REST routes onto the methods
+	routes  []*route
+	audit   *AuditLog
+	metrics *metrics.Metrics
+	// certificates of the listener when it serves TLS
+	tls    *serverTLS
+	hangup chan os.Signal
 }
 
 func NewServer(w io.Writer, config *config.Server) *Server {
@@ -50,9 +68,25 @@ func NewServer(w io.Writer, config *config.Server) *Server {
 		mux:           http.NewServeMux(),
 		logger:        logger,
 		cfg:           config,
+		events:        pubsub.NewHub(eventBufferSize),
+		roles:         make(map[string]Role),
+		disabled:      newDisabledServices(),
+		methods:       make(map[string]reflect.Type),
+		metrics:       metrics.NopMetrics(),
 	}
 }
 
+// SetMetrics sets the metrics the request durations are recorded to, they are served at
+// metrics.Path when enabled
+func (srv *Server) SetMetrics(m *metrics.Metrics) {
+	srv.metrics = m
+}
+
+// Events returns the hub the node publishes its events to
+func (srv *Server) Events() *pubsub.Hub {
+	return srv.events
+}
+
 // Register creates a service on the Server with the given name.
 // The criteria of a service method is the same as defined in the net/rpc package:
 // - the method's type is exported.
@@ -61,93 +95,29 @@ func NewServer(w io.Writer, config *config.Server) *Server {
 // - the method's second argument is a pointer.
 // - the method has return type error.
 func (srv *Server) Register(name string, rcvr interface{}) error {
-	return srv.rpc.RegisterName(name, rcvr)
+	return srv.RegisterWithRole(name, rcvr, RolePublic)
 }
 
-// RegisterRestfulMap registers all restful API functions in a map on the Server
-func (srv *Server) RegisterRestfulMap(routerMap map[string]http.HandlerFunc) {
-	for path, handlerFun := range routerMap {
-		srv.mux.HandleFunc(path, handlerFun)
+// RegisterWithRole creates a service that only callers with the role can use
+func (srv *Server) RegisterWithRole(name string, rcvr interface{}, role Role) error {
+	err := srv.rpc.RegisterName(name, rcvr)
+	if err != nil {
+		return err
 	}
-}
+	srv.roles[name] = role
 
-type authHandler interface {
-	http.Handler
-	Authorized(respW http.ResponseWriter, req *http.Request) bool
-}
-
-var _ authHandler = &rpcAuthHandler{}
-
-type rpcAuthHandler struct {
-	rpcHandler http.Handler
-	cfg        *config.Server
-}
-
-func (r rpcAuthHandler) ServeHTTP(respW http.ResponseWriter, req *http.Request) {
-	if r.Authorized(respW, req) {
-		r.rpcHandler.ServeHTTP(respW, req)
+	typ := reflect.TypeOf(rcvr)
+	for i := 0; i < typ.NumMethod(); i++ {
+		srv.methods[name+"."+typ.Method(i).Name] = typ.Method(i).Type
 	}
+	return nil
 }
 
-func (r *rpcAuthHandler) Authorized(respW http.ResponseWriter, req *http.Request) bool {
-	if r.cfg != nil && r.cfg.Node.RPCPrivateKey != "" {
-		respErr := ""
-		defer func() {
-			if respErr != "" {
-				http.Error(respW, respErr, 401)
-			}
-		}()
-
-		respW.Header().Set("WWW-Authenticate", `Basic realm="Restricted"`)
-		token := req.Header.Get("Authorization")
-
-		data := base58.Decode(token)
-		if len(data) <= 20 {
-			respErr = "invalid token"
-			return false
-		}
-		signData := data[:20]
-		signature := data[20:]
-
-		var keyData []byte
-		//Get Private Key for signature verification.
-		keyData, err := base64.StdEncoding.DecodeString(r.cfg.Node.RPCPrivateKey)
-		if err != nil {
-			respErr = err.Error()
-			return false
-		}
-
-		privateKey, err := keys.GetPrivateKeyFromBytes(keyData, keys.ED25519)
-		if err != nil {
-			respErr = err.Error()
-			return false
-		}
-
-		//Private Key Handler
-		privateKeyHandler, err := privateKey.GetHandler()
-		if err != nil {
-			respErr = err.Error()
-			return false
-		}
-
-		//Get public key from private key handler
-		pubKey := privateKeyHandler.PubKey()
-		pubKeyHandler, err := pubKey.GetHandler()
-		if err != nil {
-			respErr = err.Error()
-			return false
-		}
-
-		//Verify Message with public key
-		verified := pubKeyHandler.VerifyBytes(signData, signature)
-
-		if !verified {
-			respErr = "not authorized"
-			return false
-		}
+// RegisterRestfulMap registers all restful API functions in a map on the Server
+func (srv *Server) RegisterRestfulMap(routerMap map[string]http.HandlerFunc) {
+	for path, handlerFun := range routerMap {
+		srv.mux.Handle(path, limitedHandler{srv, handlerFun})
 	}
-
-	return true
 }
 
 // Prepare injects all the data necessary for serving over the specified URL.
@@ -166,11 +136,70 @@ func (srv *Server) Prepare(u *url.URL) error {
 		return errors.Wrap(err, "invalid URL provided, failed to create listener")
 	}
 
+	if srv.cfg != nil && srv.cfg.SDKTLSEnabled() {
+		if u.Scheme != "https" {
+			_ = l.Close()
+			return errors.New("sdk_address has to be https:// to serve TLS")
+		}
+		network := srv.cfg.Network
+		srv.tls, err = newServerTLS(srv.cfg.RootPath(network.SDKTLSCertFile), srv.cfg.RootPath(network.SDKTLSKeyFile),
+			srv.cfg.RootPath(network.SDKTLSClientCAFile), network.SDKTLSRequireClientCert)
+		if err != nil {
+			_ = l.Close()
+			return err
+		}
+		l = tls.NewListener(l, srv.tls.listenerConfig())
+	}
+
 	//Register jsonrpc handler to authenticator.
-	srv.authenticator = &rpcAuthHandler{jsonrpc2.HTTPHandler(srv.rpc), srv.cfg}
+	auth := &rpcAuthHandler{
+		rpcHandler: jsonrpc2.HTTPHandler(srv.rpc),
+		cfg:        srv.cfg,
+		roles:      srv.roles,
+		disabled:   srv.disabled,
+		methods:    srv.methods,
+		metrics:    srv.metrics,
+	}
+	if srv.cfg != nil && srv.cfg.Node != nil {
+		auth.localRoles, err = ParseRoles(srv.cfg.Node.RPCLocalRoles())
+		if err != nil {
+			_ = l.Close()
+			return errors.Wrap(err, "invalid rpc_local_roles")
+		}
+		creds, err := ParseCredentials(srv.cfg.Node.OwnerCredentials)
+		if err != nil {
+			_ = l.Close()
+			return errors.Wrap(err, "invalid owner_credentials")
+		}
+		for _, c := range creds {
+			if !c.Hashed() {
+				srv.logger.Warn("owner credentials of", c.User, "hold a plaintext password, hash it with olfullnode hash_password")
+			}
+		}
+		auth.limits, err = newLimits(srv.cfg.RPC)
+		if err != nil {
+			_ = l.Close()
+			return errors.Wrap(err, "invalid [rpc] config")
+		}
+		auth.apiKeys = NewAPIKeyStore(srv.cfg.APIKeysPath())
+		srv.audit, err = OpenAuditLog(srv.cfg.AuditLogPath())
+		if err != nil {
+			_ = l.Close()
+			return err
+		}
+		auth.audit = srv.audit
+	}
+	srv.authenticator = auth
 
 	// Register the handlers with our mux
 	srv.mux.Handle(Path, srv.authenticator)
+	srv.mux.Handle(PathWebSocket, wsHandler{srv})
+	if len(srv.routes) > 0 {
+		srv.mux.Handle(PathREST, gateway{srv})
+	}
+	if srv.metrics.Enabled() {
+		srv.mux.Handle(metrics.Path, publicHandler{srv.authenticator, srv.metrics.Handler()})
+	}
 	srv.http.Handler = srv.mux
 	srv.listener = l
 	return nil
@@ -183,6 +212,21 @@ func (srv *Server) Start() error {
 	if srv.listener == nil {
 		return errors.New("no listener specified on server, was Prepare called?")
 	}
+	if srv.tls != nil {
+		srv.hangup = make(chan os.Signal, 1)
+		signal.Notify(srv.hangup, syscall.SIGHUP)
+		go func() {
+			for range srv.hangup {
+				err := srv.ReloadTLS()
+				if err != nil {
+					srv.logger.Error("failed to reload the sdk certificates", err)
+					continue
+				}
+				srv.logger.Info("reloaded the sdk certificates")
+			}
+		}()
+	}
+
 	go func(l net.Listener) {
 		srv.logger.Info("starting RPC server on " + l.Addr().String())
 		err := srv.http.Serve(l)
@@ -194,11 +238,25 @@ func (srv *Server) Start() error {
 	return nil
 }
 
+// ReloadTLS reads the certificates of the listener again, it happens on SIGHUP too
+func (srv *Server) ReloadTLS() error {
+	if srv.tls == nil {
+		return errors.New("the sdk port doesn't serve TLS")
+	}
+	return srv.tls.Reload()
+}
+
 // Close terminates the underlying HTTP server and listener
 func (srv *Server) Close() {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	srv.logger.Info("closing server")
+	if srv.hangup != nil {
+		signal.Stop(srv.hangup)
+		close(srv.hangup)
+	}
+	srv.events.Close()
+	_ = srv.audit.Close()
 	err := srv.http.Shutdown(ctx)
 	if err != nil {
 		srv.logger.Error("Error shutting down", err)
@@ -0,0 +1,75 @@
+package rpc
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+var ErrUnknownService = errors.New("unknown service")
+
+// ServiceState is a service registered on the server, and whether it can be called
+type ServiceState struct {
+	Name    string
+	Role    Role
+	Enabled bool
+}
+
+// disabledServices are services turned off while the server runs, calling them fails
+type disabledServices struct {
+	sync.RWMutex
+	names map[string]bool
+}
+
+func newDisabledServices() *disabledServices {
+	return &disabledServices{names: make(map[string]bool)}
+}
+
+func (d *disabledServices) has(name string) bool {
+	if d == nil {
+		return false
+	}
+	d.RLock()
+	defer d.RUnlock()
+	return d.names[name]
+}
+
+func (d *disabledServices) set(name string, disabled bool) {
+	d.Lock()
+	defer d.Unlock()
+	if disabled {
+		d.names[name] = true
+	} else {
+		delete(d.names, name)
+	}
+}
+
+// Services returns the registered services sorted by name
+func (srv *Server) Services() []ServiceState {
+	states := make([]ServiceState, 0, len(srv.roles))
+	for name, role := range srv.roles {
+		states = append(states, ServiceState{Name: name, Role: role, Enabled: !srv.disabled.has(name)})
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Name < states[j].Name })
+	return states
+}
+
+// EnableService lets a disabled service be called again
+func (srv *Server) EnableService(name string) error {
+	if _, ok := srv.roles[name]; !ok {
+		return errors.Wrap(ErrUnknownService, name)
+	}
+	srv.disabled.set(name, false)
+	return nil
+}
+
+// DisableService makes the calls to a service fail until it's enabled again, only services
+// registered before the server started can be disabled
+func (srv *Server) DisableService(name string) error {
+	if _, ok := srv.roles[name]; !ok {
+		return errors.Wrap(ErrUnknownService, name)
+	}
+	srv.disabled.set(name, true)
+	return nil
+}
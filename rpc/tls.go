@@ -0,0 +1,120 @@
+package rpc
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// certPool reads the PEM certificates of a CA file
+func certPool(caFile string) (*x509.CertPool, error) {
+	ca, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read ca")
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, errors.New("no certificates found in " + caFile)
+	}
+	return pool, nil
+}
+
+// serverTLS holds the certificate of the listener and the CA of client certificates, they are
+// read again on Reload so renewed certificates apply without a restart
+type serverTLS struct {
+	sync.RWMutex
+
+	certFile, keyFile string
+	clientCAFile      string
+	requireClientCert bool
+
+	config *tls.Config
+}
+
+func newServerTLS(certFile, keyFile, clientCAFile string, requireClientCert bool) (*serverTLS, error) {
+	if requireClientCert && clientCAFile == "" {
+		return nil, errors.New("client certificates can't be required without a client ca")
+	}
+	s := &serverTLS{
+		certFile:          certFile,
+		keyFile:           keyFile,
+		clientCAFile:      clientCAFile,
+		requireClientCert: requireClientCert,
+	}
+	return s, s.Reload()
+}
+
+// Reload reads the files again, the old certificates stay in use when that fails
+func (s *serverTLS) Reload() error {
+	cert, err := tls.LoadX509KeyPair(s.certFile, s.keyFile)
+	if err != nil {
+		return errors.Wrap(err, "failed to load sdk key pair")
+	}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if s.clientCAFile != "" {
+		config.ClientCAs, err = certPool(s.clientCAFile)
+		if err != nil {
+			return err
+		}
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+		if s.requireClientCert {
+			config.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+
+	s.Lock()
+	s.config = config
+	s.Unlock()
+	return nil
+}
+
+// listenerConfig hands every new connection the certificates read last
+func (s *serverTLS) listenerConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			s.RLock()
+			defer s.RUnlock()
+			return s.config, nil
+		},
+	}
+}
+
+// ClientTLS configures the connection of a client to an https SDK address. All files are
+// optional: without CAFile the system roots verify the node, CertFile and KeyFile are the
+// client certificate for nodes asking for one.
+type ClientTLS struct {
+	CAFile     string
+	CertFile   string
+	KeyFile    string
+	ServerName string
+}
+
+func (c ClientTLS) config() (*tls.Config, error) {
+	config := &tls.Config{
+		ServerName: c.ServerName,
+		MinVersion: tls.VersionTLS12,
+	}
+	if c.CAFile != "" {
+		pool, err := certPool(c.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = pool
+	}
+	if c.CertFile != "" || c.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load client key pair")
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
@@ -0,0 +1,192 @@
+package rpc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePEM(t *testing.T, path, kind string, der []byte) {
+	err := ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: kind, Bytes: der}), 0600)
+	require.NoError(t, err)
+}
+
+// writeCerts creates the CA named ca in dir and a certificate signed by it for each name,
+// valid for localhost
+func writeCerts(t *testing.T, dir, ca string, names ...string) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: ca},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	writePEM(t, filepath.Join(dir, ca+".pem"), "CERTIFICATE", caDER)
+
+	for i, name := range names {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		tmpl := &x509.Certificate{
+			SerialNumber: big.NewInt(int64(i + 2)),
+			Subject:      pkix.Name{CommonName: name},
+			DNSNames:     []string{"localhost"},
+			IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, caTmpl, &key.PublicKey, caKey)
+		require.NoError(t, err)
+		writePEM(t, filepath.Join(dir, name+".pem"), "CERTIFICATE", der)
+
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		require.NoError(t, err)
+		writePEM(t, filepath.Join(dir, name+".key"), "EC PRIVATE KEY", keyDER)
+	}
+}
+
+// serveTLS serves the name of the client certificate, "-" without one, over s
+func serveTLS(t *testing.T, s *serverTLS) (string, func()) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := "-"
+		if len(r.TLS.PeerCertificates) > 0 {
+			name = r.TLS.PeerCertificates[0].Subject.CommonName
+		}
+		_, _ = fmt.Fprint(w, name)
+	})}
+	go func() { _ = srv.Serve(tls.NewListener(l, s.listenerConfig())) }()
+	return "https://" + l.Addr().String(), func() { _ = srv.Close() }
+}
+
+// get fetches url with a new connection, and returns the body and the certificate of the server
+func get(c ClientTLS, url string) (string, string, error) {
+	config, err := c.config()
+	if err != nil {
+		return "", "", err
+	}
+	clt := &http.Client{Transport: &http.Transport{TLSClientConfig: config, DisableKeepAlives: true}}
+	resp, err := clt.Get(url)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	return string(body), resp.TLS.PeerCertificates[0].Subject.CommonName, err
+}
+
+func TestTLS(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tls")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	writeCerts(t, dir, "ca", "node", "renewed", "client")
+	writeCerts(t, dir, "other_ca", "stranger")
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	_, err = newServerTLS(path("node.pem"), path("node.key"), "", true)
+	assert.Error(t, err, "client certs required without a ca")
+	_, err = newServerTLS(path("node.pem"), path("missing.key"), "", false)
+	assert.Error(t, err)
+
+	anon := ClientTLS{CAFile: path("ca.pem")}
+	client := ClientTLS{CAFile: path("ca.pem"), CertFile: path("client.pem"), KeyFile: path("client.key")}
+	stranger := ClientTLS{CAFile: path("ca.pem"), CertFile: path("stranger.pem"), KeyFile: path("stranger.key")}
+
+	t.Run("server only", func(t *testing.T) {
+		s, err := newServerTLS(path("node.pem"), path("node.key"), "", false)
+		require.NoError(t, err)
+		url, stop := serveTLS(t, s)
+		defer stop()
+
+		body, node, err := get(anon, url)
+		require.NoError(t, err)
+		assert.Equal(t, "-", body)
+		assert.Equal(t, "node", node)
+
+		// the node isn't trusted without its ca
+		_, _, err = get(ClientTLS{CAFile: path("other_ca.pem")}, url)
+		assert.Error(t, err)
+	})
+
+	t.Run("optional client certificates", func(t *testing.T) {
+		s, err := newServerTLS(path("node.pem"), path("node.key"), path("ca.pem"), false)
+		require.NoError(t, err)
+		url, stop := serveTLS(t, s)
+		defer stop()
+
+		body, _, err := get(anon, url)
+		require.NoError(t, err)
+		assert.Equal(t, "-", body)
+		body, _, err = get(client, url)
+		require.NoError(t, err)
+		assert.Equal(t, "client", body)
+		// a certificate of another ca doesn't count
+		body, _, err = get(stranger, url)
+		require.NoError(t, err)
+		assert.Equal(t, "-", body)
+	})
+
+	t.Run("required client certificates", func(t *testing.T) {
+		s, err := newServerTLS(path("node.pem"), path("node.key"), path("ca.pem"), true)
+		require.NoError(t, err)
+		url, stop := serveTLS(t, s)
+		defer stop()
+
+		_, _, err = get(anon, url)
+		assert.Error(t, err)
+		_, _, err = get(stranger, url)
+		assert.Error(t, err)
+		body, _, err := get(client, url)
+		require.NoError(t, err)
+		assert.Equal(t, "client", body)
+	})
+
+	t.Run("reload", func(t *testing.T) {
+		s, err := newServerTLS(path("node.pem"), path("node.key"), "", false)
+		require.NoError(t, err)
+		url, stop := serveTLS(t, s)
+		defer stop()
+
+		// a failed reload keeps the certificate in use
+		s.certFile = path("missing.pem")
+		assert.Error(t, s.Reload())
+		_, node, err := get(anon, url)
+		require.NoError(t, err)
+		assert.Equal(t, "node", node)
+
+		s.certFile, s.keyFile = path("renewed.pem"), path("renewed.key")
+		require.NoError(t, s.Reload())
+		_, node, err = get(anon, url)
+		require.NoError(t, err)
+		assert.Equal(t, "renewed", node)
+	})
+
+	_, err = ClientTLS{CertFile: path("client.pem")}.config()
+	assert.Error(t, err, "certificate without a key")
+	_, err = ClientTLS{CAFile: path("client.key")}.config()
+	assert.Error(t, err, "ca without certificates")
+}
@@ -0,0 +1,281 @@
+package rpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/Oneledger/protocol/rpc/pubsub"
+)
+
+const (
+	PathWebSocket = "/ws"
+
+	// events buffered for every subscription before it is dropped as too slow
+	eventBufferSize = 100
+
+	maxSubscriptionsPerConn = 20
+	maxMessageSize          = 64 * 1024
+
+	writeWait  = 10 * time.Second
+	pongWait   = 60 * time.Second
+	pingPeriod = pongWait * 9 / 10
+)
+
+// JSON-RPC 2.0 messages spoken over the websocket. Clients call "subscribe" with a
+// pubsub.Query and "unsubscribe" with a subscription id, the server notifies them with
+// "event" and, when it drops a subscription, "cancelled".
+type wsRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+}
+
+type wsError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type wsResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  interface{}     `json:"params,omitempty"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *wsError        `json:"error,omitempty"`
+}
+
+type SubscriptionReply struct {
+	Subscription string `json:"subscription"`
+}
+
+type EventNotification struct {
+	Subscription string        `json:"subscription"`
+	Event        *pubsub.Event `json:"event"`
+}
+
+type CancelledNotification struct {
+	Subscription string `json:"subscription"`
+	Reason       string `json:"reason"`
+}
+
+// JSON-RPC 2.0 error codes
+const (
+	wsParseError     = -32700
+	wsInvalidRequest = -32600
+	wsMethodNotFound = -32601
+	wsInvalidParams  = -32602
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	// wsHandler checks the origin against the allowed origins first
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// wsHandler upgrades authorized requests to websocket connections serving event subscriptions
+type wsHandler struct {
+	srv *Server
+}
+
+func (h wsHandler) ServeHTTP(respW http.ResponseWriter, req *http.Request) {
+	// browsers let any page open a websocket, only the allowed ones may
+	if !originAllowed(req, h.srv.allowedOrigins()) {
+		http.Error(respW, "origin not allowed", http.StatusForbidden)
+		return
+	}
+	if !h.srv.authenticator.Authorized(respW, req) {
+		return
+	}
+
+	conn, err := upgrader.Upgrade(respW, req, nil)
+	if err != nil {
+		h.srv.logger.Debug("websocket upgrade failed", err)
+		return
+	}
+
+	c := &wsConn{
+		conn:   conn,
+		events: h.srv.events,
+		subs:   make(map[string]*pubsub.Subscription),
+		send:   make(chan *wsResponse, eventBufferSize),
+		done:   make(chan struct{}),
+	}
+	go c.writeLoop()
+	c.readLoop()
+}
+
+// wsConn is one websocket client, all writes go through the send channel to writeLoop
+type wsConn struct {
+	conn   *websocket.Conn
+	events *pubsub.Hub
+
+	sync.Mutex
+	subs map[string]*pubsub.Subscription
+
+	send chan *wsResponse
+	done chan struct{}
+}
+
+func (c *wsConn) readLoop() {
+	defer c.close()
+
+	c.conn.SetReadLimit(maxMessageSize)
+	_ = c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, msg, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		req := &wsRequest{}
+		if err := json.Unmarshal(msg, req); err != nil {
+			c.reply(nil, nil, &wsError{wsParseError, err.Error()})
+			continue
+		}
+		if req.JSONRPC != "2.0" {
+			c.reply(req.ID, nil, &wsError{wsInvalidRequest, "jsonrpc must be 2.0"})
+			continue
+		}
+
+		switch req.Method {
+		case "subscribe":
+			c.subscribe(req)
+		case "unsubscribe":
+			c.unsubscribe(req)
+		default:
+			c.reply(req.ID, nil, &wsError{wsMethodNotFound, "method not found: " + req.Method})
+		}
+	}
+}
+
+func (c *wsConn) subscribe(req *wsRequest) {
+	q := pubsub.Query{}
+	if err := json.Unmarshal(req.Params, &q); err != nil {
+		c.reply(req.ID, nil, &wsError{wsInvalidParams, err.Error()})
+		return
+	}
+
+	c.Lock()
+	full := len(c.subs) >= maxSubscriptionsPerConn
+	c.Unlock()
+	if full {
+		c.reply(req.ID, nil, &wsError{wsInvalidRequest, "too many subscriptions"})
+		return
+	}
+
+	sub, err := c.events.Subscribe(q)
+	if err != nil {
+		c.reply(req.ID, nil, &wsError{wsInvalidParams, err.Error()})
+		return
+	}
+
+	c.Lock()
+	c.subs[sub.ID] = sub
+	c.Unlock()
+
+	c.reply(req.ID, SubscriptionReply{sub.ID}, nil)
+	go c.forward(sub)
+}
+
+func (c *wsConn) unsubscribe(req *wsRequest) {
+	params := SubscriptionReply{}
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		c.reply(req.ID, nil, &wsError{wsInvalidParams, err.Error()})
+		return
+	}
+
+	c.Lock()
+	_, ok := c.subs[params.Subscription]
+	delete(c.subs, params.Subscription)
+	c.Unlock()
+	if !ok {
+		c.reply(req.ID, nil, &wsError{wsInvalidParams, "unknown subscription " + params.Subscription})
+		return
+	}
+
+	c.events.Unsubscribe(params.Subscription)
+	c.reply(req.ID, params, nil)
+}
+
+// forward passes the events of a subscription to the client until either one goes away
+func (c *wsConn) forward(sub *pubsub.Subscription) {
+	for {
+		select {
+		case e := <-sub.Out():
+			c.notify("event", EventNotification{sub.ID, e})
+		case <-sub.Cancelled():
+			c.Lock()
+			delete(c.subs, sub.ID)
+			c.Unlock()
+			if sub.Err() != pubsub.ErrUnsubscribed {
+				c.notify("cancelled", CancelledNotification{sub.ID, sub.Err().Error()})
+			}
+			return
+		case <-c.done:
+			return
+		}
+	}
+}
+
+func (c *wsConn) reply(id json.RawMessage, result interface{}, err *wsError) {
+	c.write(&wsResponse{JSONRPC: "2.0", ID: id, Result: result, Error: err})
+}
+
+func (c *wsConn) notify(method string, params interface{}) {
+	c.write(&wsResponse{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+func (c *wsConn) write(msg *wsResponse) {
+	select {
+	case c.send <- msg:
+	case <-c.done:
+	}
+}
+
+func (c *wsConn) writeLoop() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		_ = c.conn.Close()
+	}()
+
+	for {
+		select {
+		case msg := <-c.send:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteJSON(msg); err != nil {
+				return
+			}
+		case <-ticker.C:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// close drops the subscriptions of the connection and stops its goroutines
+func (c *wsConn) close() {
+	c.Lock()
+	for id := range c.subs {
+		c.events.Unsubscribe(id)
+	}
+	c.subs = make(map[string]*pubsub.Subscription)
+	c.Unlock()
+
+	close(c.done)
+	_ = c.conn.Close()
+}
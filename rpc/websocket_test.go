@@ -0,0 +1,65 @@
+package rpc
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Oneledger/protocol/config"
+)
+
+func TestWebSocket_Origin(t *testing.T) {
+	cfg := config.DefaultServerConfig()
+	cfg.Node.AllowedOrigins = []string{"https://wallet.example.com"}
+	srv := NewServer(ioutil.Discard, cfg)
+	defer srv.events.Close()
+
+	server := httptest.NewServer(wsHandler{srv})
+	defer server.Close()
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	dial := func(origin string) (*http.Response, error) {
+		header := http.Header{}
+		if origin != "" {
+			header.Set("Origin", origin)
+		}
+		conn, resp, err := websocket.DefaultDialer.Dial(url, header)
+		if conn != nil {
+			_ = conn.Close()
+		}
+		return resp, err
+	}
+
+	// not from a browser
+	_, err := dial("")
+	require.NoError(t, err)
+
+	_, err = dial("https://wallet.example.com")
+	require.NoError(t, err)
+
+	resp, err := dial("https://evil.example.com")
+	require.Error(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	// a page rebinding its name to the node's address is still refused
+	resp, err = dial("http://127.0.0.1.evil.example.com")
+	require.Error(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
+func TestOriginAllowed(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	assert.True(t, originAllowed(req, nil))
+
+	req.Header.Set("Origin", "https://a.example.com")
+	assert.False(t, originAllowed(req, nil))
+	assert.False(t, originAllowed(req, []string{"https://b.example.com"}))
+	assert.True(t, originAllowed(req, []string{"https://A.example.com/"}))
+	assert.True(t, originAllowed(req, []string{"*"}))
+}
@@ -40,15 +40,37 @@ def rpc_call(method, params):
 def converBigInt(value):
     return str(value)
 
-def create_domain(name, owner_hex, price):
-    resp = rpc_call('tx.ONS_CreateRawCreate', {
+def bid_domain(name, owner_hex, price, salt):
+    resp = rpc_call('tx.ONS_CreateRawBid', {
         "name": name,
-        "owner": owner_hex,
-        "account": owner_hex,
-        "price": {
+        "bidder": owner_hex,
+        "amount": {
+            "currency": "OLT",
+            "value": converBigInt(price),
+        },
+        "salt": salt,
+        "deposit": {
+            "currency": "OLT",
+            "value": converBigInt(price),
+        },
+        "gasprice": {
+            "currency": "OLT",
+            "value": "1000000000",
+        },
+        "gas": 40000,
+    })
+    return resp["result"]["rawTx"]
+
+
+def reveal_domain(name, owner_hex, price, salt):
+    resp = rpc_call('tx.ONS_CreateRawReveal', {
+        "name": name,
+        "bidder": owner_hex,
+        "amount": {
             "currency": "OLT",
             "value": converBigInt(price),
         },
+        "salt": salt,
         "gasprice": {
             "currency": "OLT",
             "value": "1000000000",
@@ -58,6 +80,37 @@ def create_domain(name, owner_hex, price):
     return resp["result"]["rawTx"]
 
 
+def wait_for_height(name, height):
+    while True:
+        resp = rpc_call('query.ONS_GetAuction', {"name": name})
+        if "result" not in resp or resp["result"]["height"] > height:
+            return
+        time.sleep(1)
+
+
+def register_domain(name, owner_hex, price):
+    # top level names are auctioned, bid and reveal once bidding is over
+    salt = "c2FsdA=="
+    raw_txn = bid_domain(name, owner_hex, price, salt)
+    signed = sign(raw_txn, owner_hex)
+    result = broadcast_commit(raw_txn, signed['signature']['Signed'], signed['signature']['Signer'])
+    print result
+    if result["ok"] != True:
+        return result
+
+    auction = rpc_call('query.ONS_GetAuction', {"name": name})["result"]["auction"]
+    wait_for_height(name, auction["commitEnd"])
+
+    raw_txn = reveal_domain(name, owner_hex, price, salt)
+    signed = sign(raw_txn, owner_hex)
+    result = broadcast_commit(raw_txn, signed['signature']['Signed'], signed['signature']['Signer'])
+    print result
+    if result["ok"] != True:
+        return result
+
+    wait_for_height(name, auction["revealEnd"] + 1)
+    return result
+
 
 def send_domain(name, frm, price):
     resp = rpc_call('tx.ONS_CreateRawSend', {
@@ -198,22 +251,17 @@ if __name__ == "__main__":
     create_price = (int("1002345")*10**14)
     print "create price:", create_price
 
-    raw_txn = create_domain("bob2.olt", addrs[0], create_price)
-    print raw_txn
-
-    signed = sign(raw_txn, addrs[0])
-    print signed
-    print
-
-    result = broadcast_commit(raw_txn, signed['signature']['Signed'], signed['signature']['Signer'])
+    result = register_domain("bob2", addrs[0], create_price)
     print result
-    print "#################" \
-          "##"
+    print "###################"
     print
 
+    if result["ok"] != True:
+        sys.exit(-1)
+
     sell_price = (int("105432")*10**14)
 
-    raw_txn = send_domain("bob2.olt", addrs[0], (int("100")*10**18))
+    raw_txn = send_domain("bob2", addrs[0], (int("100")*10**18))
     print raw_txn
 
     signed = sign(raw_txn, addrs[0])
@@ -226,7 +274,7 @@ if __name__ == "__main__":
           "##"
     print
     time.sleep(2)
-    raw_txn = sell_domain("bob2.olt", addrs[0], sell_price)
+    raw_txn = sell_domain("bob2", addrs[0], sell_price)
     print raw_txn
     print
 
@@ -267,7 +315,7 @@ if __name__ == "__main__":
 
 
     print bcolors.WARNING + "*** Putting Domain on sale ***" + bcolors.ENDC
-    raw_txn = sell_domain("bob2.olt", addrs[3], (int("993242")*10**14))
+    raw_txn = sell_domain("bob2", addrs[3], (int("993242")*10**14))
     print raw_txn
     print
 
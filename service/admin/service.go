@@ -0,0 +1,326 @@
+// Package admin serves the management of a running node: its jobs, peers, services and logging
+package admin
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/Oneledger/protocol/client"
+	"github.com/Oneledger/protocol/data/chain"
+	"github.com/Oneledger/protocol/data/jobs"
+	"github.com/Oneledger/protocol/event"
+	"github.com/Oneledger/protocol/log"
+	"github.com/Oneledger/protocol/rpc"
+)
+
+// Node is the running node, the app implements it
+type Node interface {
+	// SetOption changes an option of the running node, as ABCI SetOption does
+	SetOption(key, value string) error
+	// Config returns the config the node runs with as TOML, without its secrets
+	Config() ([]byte, error)
+
+	Peers() []client.AdminPeer
+	BannedPeers() []string
+	DialPeers(addrs []string, persistent bool) error
+	// BanPeer disconnects a peer and refuses its connections, the ban is saved in the config
+	BanPeer(id string) error
+	UnbanPeer(id string) error
+}
+
+// Services switches the services of the SDK port on and off, rpc.Server implements it
+type Services interface {
+	Services() []rpc.ServiceState
+	EnableService(name string) error
+	DisableService(name string) error
+}
+
+type Service struct {
+	node     Node
+	services Services
+	jobBus   *event.JobBus
+	logger   *log.Logger
+}
+
+func NewService(node Node, services Services, jobBus *event.JobBus, logger *log.Logger) *Service {
+	return &Service{
+		node:     node,
+		services: services,
+		jobBus:   jobBus,
+		logger:   logger,
+	}
+}
+
+func Name() string {
+	return "admin"
+}
+
+var jobChains = []chain.Type{chain.BITCOIN, chain.ETHEREUM}
+
+// parseChain reads the chain of the jobs by its name or ticker
+func parseChain(name string) (chain.Type, error) {
+	switch strings.ToLower(name) {
+	case "bitcoin", "btc":
+		return chain.BITCOIN, nil
+	case "ethereum", "eth":
+		return chain.ETHEREUM, nil
+	}
+	return 0, rpc.InvalidParamsError("unknown chain " + name + ", expected bitcoin or ethereum")
+}
+
+func adminJob(c chain.Type, job jobs.Job) client.AdminJob {
+	data, _ := json.Marshal(job)
+	state := job.JobState()
+	aj := client.AdminJob{
+		Chain:     c.String(),
+		ID:        job.GetJobID(),
+		Type:      job.GetType(),
+		Done:      job.IsDone(),
+		Status:    state.Status.String(),
+		Attempts:  state.Attempts,
+		LastError: state.LastError,
+		Job:       data,
+	}
+	if state.NextRun > 0 {
+		next := time.Unix(0, state.NextRun)
+		aj.NextRun = &next
+	}
+	return aj
+}
+
+// listJobs returns the jobs of the chains, those with the status only unless it's empty
+func (svc *Service) listJobs(chains []chain.Type, status string) ([]client.AdminJob, error) {
+	list := make([]client.AdminJob, 0)
+	for _, c := range chains {
+		js, err := svc.jobBus.Jobs(c)
+		if err != nil {
+			return nil, rpc.InternalError(err.Error())
+		}
+		for _, job := range js {
+			if status != "" && job.JobState().Status.String() != status {
+				continue
+			}
+			list = append(list, adminJob(c, job))
+		}
+	}
+	return list, nil
+}
+
+var jobStatuses = []jobs.Status{jobs.New, jobs.InProgress, jobs.Completed, jobs.Failed, jobs.Dead}
+
+func parseStatus(name string) (string, error) {
+	name = strings.ToLower(name)
+	if name == "" {
+		return "", nil
+	}
+	names := make([]string, 0, len(jobStatuses))
+	for _, s := range jobStatuses {
+		if s.String() == name {
+			return name, nil
+		}
+		names = append(names, s.String())
+	}
+	return "", rpc.InvalidParamsError("unknown job status " + name + ", expected one of " + strings.Join(names, ", "))
+}
+
+// ListJobs returns the jobs in the job store, the dead ones are those out of retries
+func (svc *Service) ListJobs(req client.AdminListJobsRequest, reply *client.AdminListJobsReply) error {
+	chains := jobChains
+	if req.Chain != "" {
+		c, err := parseChain(req.Chain)
+		if err != nil {
+			return err
+		}
+		chains = []chain.Type{c}
+	}
+	status, err := parseStatus(req.Status)
+	if err != nil {
+		return err
+	}
+
+	list, err := svc.listJobs(chains, status)
+	if err != nil {
+		return err
+	}
+	reply.Jobs = list
+	return nil
+}
+
+// RetryJob resets the status and attempts of a job, failed and dead jobs run again in the
+// next cycle
+func (svc *Service) RetryJob(req client.AdminJobRequest, reply *client.AdminJobReply) error {
+	c, err := parseChain(req.Chain)
+	if err != nil {
+		return err
+	}
+	job, err := svc.jobBus.RetryJob(c, req.ID)
+	if err != nil {
+		return svc.jobError(err)
+	}
+
+	svc.logger.Info("retrying job", req.ID, "of", c)
+	reply.Job = adminJob(c, job)
+	return nil
+}
+
+// CancelJob deletes a job from the store, a job running can't be cancelled
+func (svc *Service) CancelJob(req client.AdminJobRequest, reply *client.AdminJobReply) error {
+	c, err := parseChain(req.Chain)
+	if err != nil {
+		return err
+	}
+	job, err := svc.jobBus.CancelJob(c, req.ID)
+	if err != nil {
+		return svc.jobError(err)
+	}
+
+	svc.logger.Info("cancelled job", req.ID, "of", c)
+	reply.Job = adminJob(c, job)
+	return nil
+}
+
+func (svc *Service) jobError(err error) error {
+	switch errors.Cause(err) {
+	case event.ErrJobNotFound:
+		return rpc.NewError(rpc.CodeNotFound, err.Error())
+	case event.ErrJobRunning:
+		return rpc.NewError(rpc.CodeNotAllowed, err.Error())
+	}
+	return rpc.InternalError(err.Error())
+}
+
+// RunJobs runs a cycle of the job bus now, the reply holds the jobs left once it's over
+func (svc *Service) RunJobs(_ client.AdminRunJobsRequest, reply *client.AdminRunJobsReply) error {
+	err := svc.jobBus.Cycle()
+	if err != nil {
+		return rpc.InternalError(err.Error())
+	}
+
+	list, err := svc.listJobs(jobChains, "")
+	if err != nil {
+		return err
+	}
+	reply.Jobs = list
+	return nil
+}
+
+// SetLogLevel changes the log levels until the node restarts
+func (svc *Service) SetLogLevel(req client.AdminLogLevelRequest, reply *client.AdminLogLevelReply) error {
+	err := svc.node.SetOption("log_level", req.Levels)
+	if err != nil {
+		return rpc.InvalidParamsError(err.Error())
+	}
+	reply.Levels = log.FormatLevels(log.Levels())
+	return nil
+}
+
+// SetOption changes an option of the running node, the keys are the ones of ABCI SetOption
+func (svc *Service) SetOption(req client.AdminSetOptionRequest, reply *client.AdminSetOptionReply) error {
+	err := svc.node.SetOption(req.Key, req.Value)
+	if err != nil {
+		return rpc.InvalidParamsError(err.Error())
+	}
+	reply.OK = true
+	return nil
+}
+
+// ListPeers returns the connected and the banned peers
+func (svc *Service) ListPeers(_ client.AdminListPeersRequest, reply *client.AdminListPeersReply) error {
+	reply.Peers = svc.node.Peers()
+	reply.Banned = svc.node.BannedPeers()
+	return nil
+}
+
+// DialPeers connects to the peers in the background, the reply holds the peers connected
+// when the dials started
+func (svc *Service) DialPeers(req client.AdminDialPeersRequest, reply *client.AdminListPeersReply) error {
+	if len(req.Peers) == 0 {
+		return rpc.InvalidParamsError("no peers to dial")
+	}
+	err := svc.node.DialPeers(req.Peers, req.Persistent)
+	if err != nil {
+		return rpc.InvalidParamsError(err.Error())
+	}
+	return svc.ListPeers(client.AdminListPeersRequest{}, reply)
+}
+
+// BanPeer disconnects a peer and keeps it from connecting again
+func (svc *Service) BanPeer(req client.AdminPeerRequest, reply *client.AdminListPeersReply) error {
+	if req.ID == "" {
+		return rpc.InvalidParamsError("no peer id")
+	}
+	err := svc.node.BanPeer(req.ID)
+	if err != nil {
+		return rpc.InternalError(err.Error())
+	}
+
+	svc.logger.Info("banned peer", req.ID)
+	return svc.ListPeers(client.AdminListPeersRequest{}, reply)
+}
+
+// UnbanPeer lets a banned peer connect again
+func (svc *Service) UnbanPeer(req client.AdminPeerRequest, reply *client.AdminListPeersReply) error {
+	err := svc.node.UnbanPeer(req.ID)
+	if err != nil {
+		return rpc.InternalError(err.Error())
+	}
+
+	svc.logger.Info("unbanned peer", req.ID)
+	return svc.ListPeers(client.AdminListPeersRequest{}, reply)
+}
+
+func (svc *Service) listServices() []client.AdminService {
+	states := svc.services.Services()
+	list := make([]client.AdminService, 0, len(states))
+	for _, s := range states {
+		list = append(list, client.AdminService{Name: s.Name, Role: string(s.Role), Enabled: s.Enabled})
+	}
+	return list
+}
+
+// ListServices returns the services of the SDK port
+func (svc *Service) ListServices(_ client.AdminListServicesRequest, reply *client.AdminServicesReply) error {
+	reply.Services = svc.listServices()
+	return nil
+}
+
+// EnableService lets a disabled service be called again
+func (svc *Service) EnableService(req client.AdminServiceRequest, reply *client.AdminServicesReply) error {
+	err := svc.services.EnableService(req.Name)
+	if err != nil {
+		return rpc.InvalidParamsError(err.Error())
+	}
+
+	svc.logger.Info("enabled service", req.Name)
+	reply.Services = svc.listServices()
+	return nil
+}
+
+// DisableService makes the calls to a service fail until it's enabled again, the admin
+// service itself stays enabled
+func (svc *Service) DisableService(req client.AdminServiceRequest, reply *client.AdminServicesReply) error {
+	if req.Name == Name() {
+		return rpc.InvalidParamsError("the admin service can't be disabled")
+	}
+	err := svc.services.DisableService(req.Name)
+	if err != nil {
+		return rpc.InvalidParamsError(err.Error())
+	}
+
+	svc.logger.Info("disabled service", req.Name)
+	reply.Services = svc.listServices()
+	return nil
+}
+
+// Config returns the config the node runs with, without its passwords, keys and tokens
+func (svc *Service) Config(_ client.AdminConfigRequest, reply *client.AdminConfigReply) error {
+	text, err := svc.node.Config()
+	if err != nil {
+		return rpc.InternalError(err.Error())
+	}
+	reply.Config = string(text)
+	return nil
+}
@@ -0,0 +1,216 @@
+package admin
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Oneledger/protocol/client"
+	"github.com/Oneledger/protocol/config"
+	"github.com/Oneledger/protocol/data/chain"
+	"github.com/Oneledger/protocol/data/jobs"
+	"github.com/Oneledger/protocol/event"
+	"github.com/Oneledger/protocol/log"
+	"github.com/Oneledger/protocol/rpc"
+	"github.com/Oneledger/protocol/serialize"
+)
+
+// testJob completes unless it's set to fail
+type testJob struct {
+	ID    string
+	Fails bool
+	jobs.State
+}
+
+func (j *testJob) DoMyJob(ctx interface{}) {
+	if j.Fails {
+		j.Fail(errors.New("failed"))
+		return
+	}
+	j.Status = jobs.Completed
+}
+func (j *testJob) GetType() string  { return "test" }
+func (j *testJob) GetJobID() string { return j.ID }
+
+func init() {
+	serialize.RegisterConcrete(new(testJob), "admin_test_job")
+}
+
+type testNode struct {
+	options map[string]string
+	peers   []client.AdminPeer
+	banned  []string
+}
+
+func (n *testNode) SetOption(key, value string) error {
+	if key != "log_level" && key != "min_fee" {
+		return errors.New("unknown option " + key)
+	}
+	n.options[key] = value
+	return nil
+}
+func (n *testNode) Config() ([]byte, error)   { return []byte("[node]"), nil }
+func (n *testNode) Peers() []client.AdminPeer { return n.peers }
+func (n *testNode) BannedPeers() []string     { return n.banned }
+func (n *testNode) DialPeers(addrs []string, persistent bool) error {
+	for _, a := range addrs {
+		n.peers = append(n.peers, client.AdminPeer{ID: a, Persistent: persistent})
+	}
+	return nil
+}
+func (n *testNode) BanPeer(id string) error {
+	n.banned = append(n.banned, id)
+	return nil
+}
+func (n *testNode) UnbanPeer(id string) error {
+	for i, b := range n.banned {
+		if b == id {
+			n.banned = append(n.banned[:i], n.banned[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("peer isn't banned")
+}
+
+type testServices map[string]bool
+
+func (s testServices) Services() []rpc.ServiceState {
+	states := make([]rpc.ServiceState, 0)
+	for _, name := range []string{"admin", "query"} {
+		states = append(states, rpc.ServiceState{Name: name, Role: rpc.RoleAdmin, Enabled: s[name]})
+	}
+	return states
+}
+func (s testServices) EnableService(name string) error  { return s.set(name, true) }
+func (s testServices) DisableService(name string) error { return s.set(name, false) }
+func (s testServices) set(name string, enabled bool) error {
+	if _, ok := s[name]; !ok {
+		return rpc.ErrUnknownService
+	}
+	s[name] = enabled
+	return nil
+}
+
+func newTestService(t *testing.T) (*Service, *jobs.JobStore, func()) {
+	dir, err := ioutil.TempDir("", "admin")
+	require.NoError(t, err)
+	store := jobs.NewJobStore(*config.DefaultServerConfig(), dir)
+	bus := event.NewJobBus(event.Option{
+		BtcInterval: time.Hour,
+		EthInterval: time.Hour,
+		Retry:       jobs.RetryPolicy{MaxAttempts: 1, Backoff: time.Hour, MaxBackoff: time.Hour},
+	}, store)
+	logger := log.NewLoggerWithPrefix(ioutil.Discard, "admin")
+	require.NoError(t, bus.Start(&event.JobsContext{Logger: logger}))
+
+	node := &testNode{options: make(map[string]string)}
+	svc := NewService(node, testServices{"admin": true, "query": true}, bus, logger)
+	return svc, store, func() {
+		bus.Close()
+		store.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func code(err error) int {
+	if e, ok := err.(*rpc.Error); ok {
+		return e.Code
+	}
+	return 0
+}
+
+func TestService_Jobs(t *testing.T) {
+	svc, store, done := newTestService(t)
+	defer done()
+
+	require.NoError(t, store.WithChain(chain.ETHEREUM).SaveJob(&testJob{ID: "ok"}))
+	require.NoError(t, store.WithChain(chain.ETHEREUM).SaveJob(&testJob{ID: "fails", Fails: true}))
+	require.NoError(t, store.WithChain(chain.BITCOIN).SaveJob(&testJob{ID: "btc"}))
+
+	list := &client.AdminListJobsReply{}
+	require.NoError(t, svc.ListJobs(client.AdminListJobsRequest{Chain: "ETH"}, list))
+	assert.Len(t, list.Jobs, 2)
+	err := svc.ListJobs(client.AdminListJobsRequest{Chain: "dogecoin"}, list)
+	assert.Equal(t, int(rpc.CodeInvalidParams), code(err))
+	err = svc.ListJobs(client.AdminListJobsRequest{Status: "sleeping"}, list)
+	assert.Equal(t, int(rpc.CodeInvalidParams), code(err))
+
+	// completed bitcoin jobs are deleted by the cycle, the failing one is out of retries
+	run := &client.AdminRunJobsReply{}
+	require.NoError(t, svc.RunJobs(client.AdminRunJobsRequest{}, run))
+	require.NoError(t, svc.ListJobs(client.AdminListJobsRequest{Status: "dead"}, list))
+	require.Len(t, list.Jobs, 1)
+	assert.Equal(t, client.AdminJob{Chain: chain.ETHEREUM.String(), ID: "fails", Type: "test", Status: "dead", Attempts: 1, LastError: "failed"},
+		withoutData(list.Jobs[0]))
+	require.NoError(t, svc.ListJobs(client.AdminListJobsRequest{Chain: "bitcoin"}, list))
+	assert.Empty(t, list.Jobs)
+
+	reply := &client.AdminJobReply{}
+	require.NoError(t, svc.RetryJob(client.AdminJobRequest{Chain: "ethereum", ID: "fails"}, reply))
+	assert.Equal(t, "new", reply.Job.Status)
+	assert.Zero(t, reply.Job.Attempts)
+
+	require.NoError(t, svc.CancelJob(client.AdminJobRequest{Chain: "ethereum", ID: "fails"}, reply))
+	err = svc.CancelJob(client.AdminJobRequest{Chain: "ethereum", ID: "fails"}, reply)
+	assert.Equal(t, int(rpc.CodeNotFound), code(err))
+	err = svc.RetryJob(client.AdminJobRequest{Chain: "bitcoin", ID: "ok"}, reply)
+	assert.Equal(t, int(rpc.CodeNotFound), code(err))
+}
+
+func withoutData(j client.AdminJob) client.AdminJob {
+	j.Job = nil
+	return j
+}
+
+func TestService_Node(t *testing.T) {
+	svc, _, done := newTestService(t)
+	defer done()
+	node := svc.node.(*testNode)
+
+	ok := &client.AdminSetOptionReply{}
+	require.NoError(t, svc.SetOption(client.AdminSetOptionRequest{Key: "min_fee", Value: "1"}, ok))
+	assert.True(t, ok.OK)
+	assert.Equal(t, "1", node.options["min_fee"])
+	err := svc.SetOption(client.AdminSetOptionRequest{Key: "nonsense"}, ok)
+	assert.Equal(t, int(rpc.CodeInvalidParams), code(err))
+
+	peers := &client.AdminListPeersReply{}
+	err = svc.DialPeers(client.AdminDialPeersRequest{}, peers)
+	assert.Equal(t, int(rpc.CodeInvalidParams), code(err))
+	require.NoError(t, svc.DialPeers(client.AdminDialPeersRequest{Peers: []string{"id@1.2.3.4:26656"}, Persistent: true}, peers))
+	assert.Equal(t, []client.AdminPeer{{ID: "id@1.2.3.4:26656", Persistent: true}}, peers.Peers)
+
+	err = svc.BanPeer(client.AdminPeerRequest{}, peers)
+	assert.Equal(t, int(rpc.CodeInvalidParams), code(err))
+	require.NoError(t, svc.BanPeer(client.AdminPeerRequest{ID: "id"}, peers))
+	assert.Equal(t, []string{"id"}, peers.Banned)
+	require.NoError(t, svc.UnbanPeer(client.AdminPeerRequest{ID: "id"}, peers))
+	assert.Empty(t, peers.Banned)
+	assert.Error(t, svc.UnbanPeer(client.AdminPeerRequest{ID: "id"}, peers))
+
+	cfg := &client.AdminConfigReply{}
+	require.NoError(t, svc.Config(client.AdminConfigRequest{}, cfg))
+	assert.Equal(t, "[node]", cfg.Config)
+}
+
+func TestService_Services(t *testing.T) {
+	svc, _, done := newTestService(t)
+	defer done()
+
+	reply := &client.AdminServicesReply{}
+	require.NoError(t, svc.DisableService(client.AdminServiceRequest{Name: "query"}, reply))
+	assert.Equal(t, []client.AdminService{{Name: "admin", Role: "admin", Enabled: true}, {Name: "query", Role: "admin"}}, reply.Services)
+	require.NoError(t, svc.EnableService(client.AdminServiceRequest{Name: "query"}, reply))
+	assert.True(t, reply.Services[1].Enabled)
+
+	// the admin service can't lock itself out
+	err := svc.DisableService(client.AdminServiceRequest{Name: "admin"}, reply)
+	assert.Equal(t, int(rpc.CodeInvalidParams), code(err))
+	err = svc.EnableService(client.AdminServiceRequest{Name: "nonsense"}, reply)
+	assert.Equal(t, int(rpc.CodeInvalidParams), code(err))
+}
@@ -64,7 +64,7 @@ func (svc *Service) validateAndSignTx(req client.BroadcastRequest) ([]byte, erro
 
 	handler := svc.router.Handler(tx.Type)
 	ctx := action.NewContext(svc.router, nil, nil, nil, nil, svc.currencies,
-		svc.feeOpt, nil, nil, nil, svc.trackers, nil, nil, nil,
+		svc.feeOpt, nil, nil, nil, nil, svc.trackers, nil, nil, nil,
 		nil, svc.blockCypherToken, svc.blockCypherchainType, svc.logger)
 	_, err = handler.Validate(ctx, signedTx)
 	if err != nil {
@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 
 	"github.com/Oneledger/protocol/client"
+	"github.com/Oneledger/protocol/rpc"
 	"github.com/Oneledger/protocol/status_codes"
 )
 
@@ -23,3 +24,15 @@ func (s *Service) GetTracker(args client.BTCGetTrackerRequest, reply *client.BTC
 
 	return nil
 }
+
+// GetTrackerHistory returns the state transitions of a tracker, with the block and the tx
+// behind each of them
+func (s *Service) GetTrackerHistory(args client.TrackerHistoryRequest, reply *client.TrackerHistoryReply) error {
+	if args.Name == "" {
+		return rpc.InvalidParamsError("no tracker name")
+	}
+
+	reply.Name = args.Name
+	reply.Entries = s.history.List(args.Name)
+	return nil
+}
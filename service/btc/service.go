@@ -9,6 +9,7 @@ import (
 	"github.com/Oneledger/protocol/data/accounts"
 	"github.com/Oneledger/protocol/data/balance"
 	"github.com/Oneledger/protocol/data/bitcoin"
+	"github.com/Oneledger/protocol/data/history"
 	"github.com/Oneledger/protocol/identity"
 	"github.com/Oneledger/protocol/log"
 )
@@ -26,6 +27,7 @@ type Service struct {
 
 	validators   *identity.ValidatorStore
 	trackerStore *bitcoin.TrackerStore
+	history      *history.Store
 
 	blockCypherToken string
 	btcChainType     string
@@ -38,6 +40,7 @@ func NewService(
 	nodeCtx node.Context,
 	validators *identity.ValidatorStore,
 	trackerStore *bitcoin.TrackerStore,
+	history *history.Store,
 	logger *log.Logger,
 	blockCypherToken, btcChainType string,
 ) *Service {
@@ -59,6 +62,7 @@ func NewService(
 		accounts:     accounts,
 		validators:   validators,
 		trackerStore: trackerStore,
+		history:      history,
 		logger:       logger,
 
 		blockCypherToken: blockCypherToken,
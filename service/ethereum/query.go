@@ -0,0 +1,20 @@
+package ethereum
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/Oneledger/protocol/rpc"
+)
+
+// GetTrackerHistory returns the state transitions of a tracker, named by the hash of its
+// ethereum tx, with the block and the tx behind each of them
+func (svc *Service) GetTrackerHistory(req TrackerHistoryRequest, out *TrackerHistoryReply) error {
+	if req.Name == "" {
+		return rpc.InvalidParamsError("no tracker name")
+	}
+
+	name := common.HexToHash(req.Name).String()
+	out.Name = name
+	out.Entries = svc.history.List(name)
+	return nil
+}
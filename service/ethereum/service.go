@@ -8,10 +8,11 @@ import (
 	"github.com/Oneledger/protocol/action"
 	"github.com/Oneledger/protocol/app/node"
 	chain "github.com/Oneledger/protocol/chains/ethereum"
+	"github.com/Oneledger/protocol/client"
 	"github.com/Oneledger/protocol/config"
 	"github.com/Oneledger/protocol/data/accounts"
 	tracker "github.com/Oneledger/protocol/data/ethereum"
-	"github.com/Oneledger/protocol/data/keys"
+	"github.com/Oneledger/protocol/data/history"
 	"github.com/Oneledger/protocol/identity"
 	"github.com/Oneledger/protocol/log"
 )
@@ -28,6 +29,7 @@ type Service struct {
 	nodeContext  node.Context
 	validators   *identity.ValidatorStore
 	trackerStore *tracker.TrackerStore
+	history      *history.Store
 }
 
 // Returns a new Service, should be passed as an RPC handler
@@ -39,6 +41,7 @@ func NewService(
 	nodeCtx node.Context,
 	validators *identity.ValidatorStore,
 	//trackerStore *bitcoin.TrackerStore,
+	history *history.Store,
 	logger *log.Logger,
 ) *Service {
 	return &Service{
@@ -49,43 +52,26 @@ func NewService(
 		accounts:    accounts,
 		validators:  validators,
 		//	trackerStore: trackerStore,
-		logger: logger,
+		history: history,
+		logger:  logger,
 	}
 }
 
-type OLTLockRequest struct {
-	// RawTransaction of a Lock call from the user to the smart contract
-	// This should be signed and RLP encoded with the ethereum address of the user
-	//OLTAddress common.Address  `json:"oltAddress"`
-	RawTx   []byte `json:"rawTx"`
-	Address keys.Address
-	Fee     action.Amount `json:"fee"`
-	Gas     int64         `json:"gas"`
-}
-
-type OLTLockReply struct {
-	RawTX []byte `json:"UnsignedOLTLock"`
-}
+// the request and reply types of the service are defined in the client package
+type (
+	OLTLockRequest = client.ETHExtLockRequest
+	OLTLockReply   = client.ETHExtLockReply
+	RedeemRequest  = client.ETHRedeemRequest
+	ETHLockRequest = client.ETHRawLockRequest
+	ETHLockRawTX   = client.ETHRawLockReply
 
-type RedeemRequest struct {
-	UserOLTaddress action.Address `json:"user_olt_address"`
-	UserETHaddress action.Address `json:"user_eth_address"`
-	ETHTxn         []byte         `json:"eth_txn"`
-	Fee            action.Amount  `json:"fee"`
-	Gas            int64          `json:"gas"`
-}
+	TrackerHistoryRequest = client.TrackerHistoryRequest
+	TrackerHistoryReply   = client.TrackerHistoryReply
+)
 
 type RedeemReply struct {
 	OK bool `json:"ok"`
 }
-type ETHLockRequest struct {
-	UserAddress common.Address `json:"user_eth_address"`
-	Amount      *big.Int       `json:"amount"`
-}
-
-type ETHLockRawTX struct {
-	UnsignedRawTx []byte `json:"unsigned_raw_tx"`
-}
 
 type SignRequest struct {
 	Wei       *big.Int       `json:"wei"`
@@ -0,0 +1,308 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/pkg/errors"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+
+	"github.com/Oneledger/protocol/serialize"
+)
+
+// Statuses of the node on /health. The readiness probe, the default, gets 503 for all of them
+// but HealthOK, the liveness probe (?probe=liveness) only for HealthUnhealthy.
+const (
+	HealthOK        = "ok"
+	HealthSyncing   = "syncing"
+	HealthDegraded  = "degraded"
+	HealthUnhealthy = "unhealthy"
+)
+
+// statuses of a single check
+const (
+	checkOK      = "ok"
+	checkFailed  = "failed"
+	checkSkipped = "skipped"
+)
+
+// how long a check may take
+var healthTimeout = 5 * time.Second
+
+const (
+	// how long the result of checking an external node is reused
+	externalCheckTTL = 15 * time.Second
+	// the job bus counts as stalled after this many intervals without a cycle
+	stalledCycles = 3
+)
+
+// HealthCheck is the outcome of one of the checks of /health
+type HealthCheck struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Health is the report of /health
+type Health struct {
+	Status          string                 `json:"status"`
+	CatchingUp      bool                   `json:"catching_up"`
+	LastBlockHeight int64                  `json:"last_block_height"`
+	LastBlockTime   time.Time              `json:"last_block_time"`
+	Peers           int                    `json:"peers"`
+	IsValidator     bool                   `json:"is_validator"`
+	Checks          map[string]HealthCheck `json:"checks"`
+}
+
+func passed(err error) HealthCheck {
+	if err != nil {
+		return HealthCheck{Status: checkFailed, Error: err.Error()}
+	}
+	return HealthCheck{Status: checkOK}
+}
+
+// cachedCheck runs a check for /health. A result is reused for ttl, and a check that hasn't
+// returned yet is waited on instead of run again, so callers of /health don't pile up calls
+// to a node that hangs or dial the external nodes on every request.
+type cachedCheck struct {
+	ttl time.Duration
+
+	lock    sync.Mutex
+	at      time.Time
+	value   interface{}
+	err     error
+	running chan struct{}
+}
+
+// run returns the result of f, or an error if it takes longer than the health timeout
+func (c *cachedCheck) run(f func() (interface{}, error)) (interface{}, error) {
+	c.lock.Lock()
+	if c.running == nil && !c.at.IsZero() && time.Since(c.at) < c.ttl {
+		defer c.lock.Unlock()
+		return c.value, c.err
+	}
+	if c.running == nil {
+		done := make(chan struct{})
+		c.running = done
+		go func() {
+			value, err := f()
+			c.lock.Lock()
+			c.at, c.value, c.err, c.running = time.Now(), value, err, nil
+			c.lock.Unlock()
+			close(done)
+		}()
+	}
+	running := c.running
+	c.lock.Unlock()
+
+	timer := time.NewTimer(healthTimeout)
+	defer timer.Stop()
+	select {
+	case <-running:
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		return c.value, c.err
+	case <-timer.C:
+		return nil, errors.New("timed out")
+	}
+}
+
+// healthChecks are the checks of /health that call other services
+type healthChecks struct {
+	consensus cachedCheck
+	peers     cachedCheck
+	bitcoin   cachedCheck
+	ethereum  cachedCheck
+}
+
+func newHealthChecks() *healthChecks {
+	return &healthChecks{
+		bitcoin:  cachedCheck{ttl: externalCheckTTL},
+		ethereum: cachedCheck{ttl: externalCheckTTL},
+	}
+}
+
+func checked(_ interface{}, err error) HealthCheck {
+	if err == errSkipped {
+		return HealthCheck{Status: checkSkipped}
+	}
+	return passed(err)
+}
+
+// Health checks the node and the chains it bridges to
+func (rs RestfulService) Health() *Health {
+	h := &Health{
+		Status:      HealthOK,
+		IsValidator: rs.ctx.ValidatorSet != nil && rs.ctx.ValidatorSet.IsValidator(),
+		Checks:      make(map[string]HealthCheck),
+	}
+
+	var bitcoin, ethereum HealthCheck
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		bitcoin = checked(rs.checks.bitcoin.run(rs.checkBitcoin))
+	}()
+	go func() {
+		defer wg.Done()
+		ethereum = checked(rs.checks.ethereum.run(rs.checkEthereum))
+	}()
+
+	h.Checks["consensus"] = rs.checkConsensus(h)
+	h.Checks["peers"] = rs.checkPeers(h)
+	h.Checks["job_bus"] = rs.checkJobBus()
+	wg.Wait()
+	h.Checks["bitcoin"] = bitcoin
+	h.Checks["ethereum"] = ethereum
+
+	switch {
+	case h.Checks["consensus"].Status == checkFailed || h.Checks["job_bus"].Status == checkFailed:
+		h.Status = HealthUnhealthy
+	case h.CatchingUp:
+		h.Status = HealthSyncing
+	case bitcoin.Status == checkFailed || ethereum.Status == checkFailed || h.Checks["peers"].Status == checkFailed:
+		h.Status = HealthDegraded
+	}
+	return h
+}
+
+func (rs RestfulService) checkConsensus(h *Health) HealthCheck {
+	res, err := rs.checks.consensus.run(func() (interface{}, error) {
+		return rs.ctx.Services.Status()
+	})
+	if err != nil {
+		return passed(errors.Wrap(err, "failed to get the consensus status"))
+	}
+	status := res.(*ctypes.ResultStatus)
+	h.CatchingUp = status.SyncInfo.CatchingUp
+	h.LastBlockHeight = status.SyncInfo.LatestBlockHeight
+	h.LastBlockTime = status.SyncInfo.LatestBlockTime
+	return passed(nil)
+}
+
+// checkPeers fails without peers when the node has seeds or persistent peers to connect to,
+// a lone node is fine otherwise
+func (rs RestfulService) checkPeers(h *Health) HealthCheck {
+	res, err := rs.checks.peers.run(func() (interface{}, error) {
+		return rs.ctx.Services.NetInfo()
+	})
+	if err != nil {
+		return passed(errors.Wrap(err, "failed to get the peers"))
+	}
+	h.Peers = res.(*ctypes.ResultNetInfo).NPeers
+
+	p2p := rs.ctx.Cfg.P2P
+	if h.Peers == 0 && p2p != nil && (len(p2p.Seeds) > 0 || len(p2p.PersistentPeers) > 0) {
+		return passed(errors.New("no peers connected"))
+	}
+	return passed(nil)
+}
+
+func (rs RestfulService) checkJobBus() HealthCheck {
+	bus := rs.ctx.JobBus
+	if bus == nil {
+		return HealthCheck{Status: checkSkipped}
+	}
+	if !bus.Alive() {
+		return passed(errors.New("job bus isn't running"))
+	}
+	if since := time.Since(bus.LastCycle()); since > stalledCycles*bus.Interval() {
+		return passed(errors.Errorf("job bus stalled, last cycle %s ago", since.Round(time.Second)))
+	}
+	return passed(nil)
+}
+
+// errSkipped is returned by the checks of the nodes that aren't configured
+var errSkipped = errors.New("skipped")
+
+// checkBitcoin asks the bitcoin node for its block count
+func (rs RestfulService) checkBitcoin() (interface{}, error) {
+	cfg := rs.ctx.Cfg.ChainDriver
+	if cfg == nil || cfg.BitcoinNodeAddress == "" {
+		return nil, errSkipped
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), healthTimeout)
+	defer cancel()
+
+	body := strings.NewReader(`{"jsonrpc":"1.0","id":"health","method":"getblockcount","params":[]}`)
+	req, err := http.NewRequest(http.MethodPost, "http://"+cfg.BitcoinNodeAddress+":"+cfg.BitcoinRPCPort, body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to reach bitcoin node")
+	}
+	req.SetBasicAuth(cfg.BitcoinRPCUsername, cfg.BitcoinRPCPassword)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to reach bitcoin node")
+	}
+	defer resp.Body.Close()
+
+	reply := struct {
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}{}
+	err = json.NewDecoder(resp.Body).Decode(&reply)
+	if err != nil {
+		return nil, errors.Wrapf(err, "bitcoin node answered %s", resp.Status)
+	}
+	if reply.Error != nil {
+		return nil, errors.Errorf("bitcoin node answered %s", reply.Error.Message)
+	}
+	return nil, nil
+}
+
+// checkEthereum asks the ethereum node for its latest header
+func (rs RestfulService) checkEthereum() (interface{}, error) {
+	cfg := rs.ctx.Cfg.EthChainDriver
+	if cfg == nil || cfg.Connection == "" {
+		return nil, errSkipped
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), healthTimeout)
+	defer cancel()
+
+	clt, err := ethclient.DialContext(ctx, cfg.Connection)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to reach ethereum node")
+	}
+	defer clt.Close()
+
+	_, err = clt.HeaderByNumber(ctx, nil)
+	return nil, errors.Wrap(err, "failed to reach ethereum node")
+}
+
+// health answers with the report of Health, the status code tells the probes how the node is
+func (rs RestfulService) health() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h := rs.Health()
+
+		code := http.StatusOK
+		if h.Status != HealthOK {
+			code = http.StatusServiceUnavailable
+		}
+		if strings.EqualFold(r.URL.Query().Get("probe"), "liveness") && h.Status != HealthUnhealthy {
+			code = http.StatusOK
+		}
+
+		bytes, err := serialize.GetSerializer(serialize.NETWORK).Serialize(h)
+		if err != nil {
+			rs.ctx.Logger.Error("failed to serialize health", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if h.Status != HealthOK {
+			rs.ctx.Logger.Debug("health check", h.Status, string(bytes))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		_, _ = w.Write(bytes)
+	}
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Oneledger/protocol/config"
+)
+
+func TestCachedCheck(t *testing.T) {
+	defer func(timeout time.Duration) { healthTimeout = timeout }(healthTimeout)
+	healthTimeout = 50 * time.Millisecond
+
+	var calls int32
+	c := &cachedCheck{ttl: time.Hour}
+	check := func() (interface{}, error) {
+		return atomic.AddInt32(&calls, 1), nil
+	}
+
+	// a result is reused for the ttl
+	v, err := c.run(check)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, v)
+	v, _ = c.run(check)
+	assert.EqualValues(t, 1, v)
+
+	c = &cachedCheck{}
+	v, _ = c.run(check)
+	assert.EqualValues(t, 2, v)
+	v, _ = c.run(check)
+	assert.EqualValues(t, 3, v)
+
+	// a check that hangs times out and isn't started again until it returns
+	release := make(chan struct{})
+	hanging := func() (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return nil, errors.New("failed")
+	}
+	for i := 0; i < 3; i++ {
+		_, err = c.run(hanging)
+		assert.EqualError(t, err, "timed out")
+	}
+	assert.EqualValues(t, 4, atomic.LoadInt32(&calls))
+
+	close(release)
+	for i := 0; i < 100 && c.isRunning(); i++ {
+		time.Sleep(time.Millisecond)
+	}
+	v, err = c.run(check)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 5, v)
+}
+
+func (c *cachedCheck) isRunning() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.running != nil
+}
+
+func newHealthService(cfg config.Server) RestfulService {
+	return RestfulService{ctx: &Context{Cfg: cfg}, checks: newHealthChecks()}
+}
+
+func TestCheckBitcoin(t *testing.T) {
+	rs := newHealthService(config.Server{})
+	assert.Equal(t, HealthCheck{Status: checkSkipped}, checked(rs.checkBitcoin()))
+
+	var reply string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, _ := r.BasicAuth()
+		req := map[string]interface{}{}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if user != "user" || pass != "pass" || req["method"] != "getblockcount" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(reply))
+	}))
+	defer srv.Close()
+	host, port, err := net.SplitHostPort(srv.Listener.Addr().String())
+	require.NoError(t, err)
+
+	cfg := config.ChainDriverConfig{BitcoinNodeAddress: host, BitcoinRPCPort: port, BitcoinRPCUsername: "user", BitcoinRPCPassword: "pass"}
+	rs = newHealthService(config.Server{ChainDriver: &cfg})
+	reply = `{"result":100,"error":null,"id":"health"}`
+	assert.Equal(t, HealthCheck{Status: checkOK}, checked(rs.checkBitcoin()))
+
+	reply = `{"result":null,"error":{"code":-28,"message":"Loading block index..."},"id":"health"}`
+	assert.Equal(t, checkFailed, checked(rs.checkBitcoin()).Status)
+
+	cfg.BitcoinRPCPassword = "wrong"
+	assert.Equal(t, checkFailed, checked(rs.checkBitcoin()).Status)
+}
+
+func TestCheckEthereum(t *testing.T) {
+	rs := newHealthService(config.Server{})
+	assert.Equal(t, HealthCheck{Status: checkSkipped}, checked(rs.checkEthereum()))
+
+	rs = newHealthService(config.Server{EthChainDriver: &config.EthereumChainDriverConfig{Connection: "http://127.0.0.1:1"}})
+	assert.Equal(t, checkFailed, checked(rs.checkEthereum()).Status)
+}
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"strings"
+
 	"github.com/pkg/errors"
 
 	"github.com/Oneledger/protocol/action"
@@ -12,9 +14,14 @@ import (
 	"github.com/Oneledger/protocol/data/balance"
 	"github.com/Oneledger/protocol/data/bitcoin"
 	"github.com/Oneledger/protocol/data/fees"
+	"github.com/Oneledger/protocol/data/governance"
+	"github.com/Oneledger/protocol/data/history"
 	"github.com/Oneledger/protocol/data/ons"
+	"github.com/Oneledger/protocol/event"
 	"github.com/Oneledger/protocol/identity"
 	"github.com/Oneledger/protocol/log"
+	"github.com/Oneledger/protocol/rpc"
+	"github.com/Oneledger/protocol/service/admin"
 	"github.com/Oneledger/protocol/service/broadcast"
 	"github.com/Oneledger/protocol/service/btc"
 	"github.com/Oneledger/protocol/service/ethereum"
@@ -22,6 +29,8 @@ import (
 	"github.com/Oneledger/protocol/service/owner"
 	"github.com/Oneledger/protocol/service/query"
 	"github.com/Oneledger/protocol/service/tx"
+	"github.com/Oneledger/protocol/signer"
+	"github.com/Oneledger/protocol/storage"
 )
 
 // Context is the master context for creating new contexts
@@ -30,14 +39,25 @@ type Context struct {
 	Accounts     accounts.Wallet
 	Balances     *balance.Store
 	Domains      *ons.DomainStore
+	Govern       *governance.Store
 	ValidatorSet *identity.ValidatorStore
 	Trackers     *bitcoin.TrackerStore
+	BTCHistory   *history.Store
+	ETHHistory   *history.Store
+	ChainState   *storage.ChainState
+	TxSimulator  query.TxSimulator
+	JobBus       *event.JobBus
+
+	// the running node and its SDK port, managed by the admin service
+	Admin       admin.Node
+	RPCServices admin.Services
 
 	// configurations
 	Cfg         config.Server
 	Currencies  *balance.CurrencySet
 	FeeOpt      *fees.FeeOption
 	NodeContext node.Context
+	Signer      signer.Signer
 
 	Router   action.Router
 	Services client.ExtServiceContext
@@ -55,11 +75,12 @@ func NewMap(ctx *Context) (Map, error) {
 		broadcast.Name(): broadcast.NewService(ctx.Services, ctx.Router, ctx.Currencies, ctx.FeeOpt, ctx.Logger, ctx.Trackers, ctx.Cfg.ChainDriver.BlockCypherToken, bcct),
 		nodesvc.Name():   nodesvc.NewService(ctx.NodeContext, &ctx.Cfg, ctx.Logger),
 		owner.Name():     owner.NewService(ctx.Accounts, ctx.Logger),
-		query.Name():     query.NewService(ctx.Services, ctx.Balances, ctx.Currencies, ctx.ValidatorSet, ctx.Domains, ctx.Logger),
-		tx.Name():        tx.NewService(ctx.Balances, ctx.Router, ctx.Accounts, ctx.FeeOpt, ctx.NodeContext, ctx.Logger),
-		btc.Name(): btc.NewService(ctx.Balances, ctx.Accounts, ctx.NodeContext, ctx.ValidatorSet, ctx.Trackers, ctx.Logger,
+		query.Name():     query.NewService(ctx.Services, ctx.ChainState, ctx.TxSimulator, ctx.Balances, ctx.Currencies, ctx.ValidatorSet, ctx.Domains, ctx.Govern, ctx.Logger),
+		tx.Name():        tx.NewService(ctx.Balances, ctx.Router, ctx.Accounts, ctx.FeeOpt, ctx.NodeContext, ctx.Signer, ctx.Logger),
+		btc.Name(): btc.NewService(ctx.Balances, ctx.Accounts, ctx.NodeContext, ctx.ValidatorSet, ctx.Trackers, ctx.BTCHistory, ctx.Logger,
 			ctx.Cfg.ChainDriver.BlockCypherToken, ctx.Cfg.ChainDriver.BitcoinChainType),
-		ethereum.Name(): ethereum.NewService(ctx.Cfg.EthChainDriver, ctx.Router, ctx.Accounts, ctx.NodeContext, ctx.ValidatorSet, ctx.Logger),
+		ethereum.Name(): ethereum.NewService(ctx.Cfg.EthChainDriver, ctx.Router, ctx.Accounts, ctx.NodeContext, ctx.ValidatorSet, ctx.ETHHistory, ctx.Logger),
+		admin.Name():    admin.NewService(ctx.Admin, ctx.RPCServices, ctx.JobBus, ctx.Logger),
 	}
 
 	serviceMap := Map{}
@@ -73,3 +94,55 @@ func NewMap(ctx *Context) (Map, error) {
 
 	return serviceMap, nil
 }
+
+// Types returns a nil value of every service of NewMap by name, the typed client in the
+// client package is generated from their methods
+func Types() Map {
+	return Map{
+		broadcast.Name(): (*broadcast.Service)(nil),
+		nodesvc.Name():   (*nodesvc.Service)(nil),
+		owner.Name():     (*owner.Service)(nil),
+		query.Name():     (*query.Service)(nil),
+		tx.Name():        (*tx.Service)(nil),
+		btc.Name():       (*btc.Service)(nil),
+		ethereum.Name():  (*ethereum.Service)(nil),
+		admin.Name():     (*admin.Service)(nil),
+	}
+}
+
+// roles needed to call the services by default, the services that use the accounts and keys
+// of the node are privileged, managing the node needs the admin role
+var defaultRoles = map[string]rpc.Role{
+	broadcast.Name(): rpc.RolePublic,
+	nodesvc.Name():   rpc.RolePublic,
+	query.Name():     rpc.RolePublic,
+	owner.Name():     rpc.RoleOwner,
+	tx.Name():        rpc.RoleOwner,
+	btc.Name():       rpc.RoleOwner,
+	ethereum.Name():  rpc.RoleOwner,
+}
+
+// Roles returns the role needed to call each service, service_roles in the config
+// overrides the defaults
+func Roles(cfg config.Server) (map[string]rpc.Role, error) {
+	roles := make(map[string]rpc.Role)
+	for name, role := range defaultRoles {
+		roles[name] = role
+	}
+
+	for _, s := range cfg.Node.ServiceRoles {
+		pair := strings.Split(s, ":")
+		if len(pair) != 2 {
+			return nil, errors.Errorf("invalid service role %q, expected service:role", s)
+		}
+		if _, ok := defaultRoles[pair[0]]; !ok {
+			return nil, errors.Wrap(errors.New("Service doesn't exist "), pair[0])
+		}
+		role, err := rpc.ParseRole(pair[1])
+		if err != nil {
+			return nil, err
+		}
+		roles[pair[0]] = role
+	}
+	return roles, nil
+}
@@ -2,8 +2,11 @@ package query
 
 import (
 	"github.com/Oneledger/protocol/client"
+	"github.com/Oneledger/protocol/data/chain"
 	"github.com/Oneledger/protocol/data/ons"
+	"github.com/Oneledger/protocol/rpc"
 	codes "github.com/Oneledger/protocol/status_codes"
+	"github.com/Oneledger/protocol/storage"
 )
 
 func (sv *Service) ONS_GetDomainByName(req client.ONSGetDomainsRequest, reply *client.ONSGetDomainsReply) error {
@@ -13,7 +16,7 @@ func (sv *Service) ONS_GetDomainByName(req client.ONSGetDomainsRequest, reply *c
 	}
 
 	d, err := domains.Get(req.Name)
-	if err != nil {
+	if err != nil || d.IsReleased(sv.nextHeight()) {
 		return codes.ErrDomainNotFound
 	}
 
@@ -35,9 +38,8 @@ func (sv *Service) ONS_GetDomainByOwner(req client.ONSGetDomainsRequest, reply *
 	}
 	ds := make([]ons.Domain, 0)
 
-	domains.Iterate(func(name string, domain *ons.Domain) bool {
-
-		if domain.OwnerAddress.Equal(req.Owner) {
+	domains.IterateOwnerDomains(req.Owner, func(domain *ons.Domain) bool {
+		if !domain.IsReleased(sv.nextHeight()) {
 			if req.OnSale && !domain.OnSaleFlag {
 				return false
 			}
@@ -61,7 +63,7 @@ func (sv *Service) ONS_GetDomainOnSale(req client.ONSGetDomainsRequest, reply *c
 
 	ds := make([]ons.Domain, 0)
 	domains.Iterate(func(name string, domain *ons.Domain) bool {
-		if domain.OnSaleFlag {
+		if domain.OnSaleFlag && !domain.IsExpired(sv.nextHeight()) {
 			ds = append(ds, *domain)
 		}
 		return false
@@ -80,8 +82,8 @@ func (sv *Service) ONS_GetDomainByBeneficiary(req client.ONSGetDomainsRequest, r
 	}
 
 	ds := make([]ons.Domain, 0)
-	domains.Iterate(func(name string, domain *ons.Domain) bool {
-		if domain.AccountAddress.Equal(req.Beneficiary) {
+	domains.IterateAccountDomains(req.Beneficiary, func(domain *ons.Domain) bool {
+		if !domain.IsExpired(sv.nextHeight()) {
 			ds = append(ds, *domain)
 		}
 		return false
@@ -92,3 +94,189 @@ func (sv *Service) ONS_GetDomainByBeneficiary(req client.ONSGetDomainsRequest, r
 	}
 	return nil
 }
+
+// ONS_ReverseLookup returns the primary name of an address, Name is empty when the address
+// has none or the name no longer points to it
+func (sv *Service) ONS_ReverseLookup(req client.ONSReverseLookupRequest, reply *client.ONSReverseLookupReply) error {
+	if req.Address == nil {
+		return codes.ErrBadAddress
+	}
+
+	*reply = client.ONSReverseLookupReply{Address: req.Address}
+	d, err := sv.ons.ReverseLookup(req.Address, sv.nextHeight())
+	if err == ons.ErrPrimaryNotFound {
+		return nil
+	}
+	if err != nil {
+		sv.logger.Error("failed to look up primary name", err)
+		return rpc.InternalError("failed to look up primary name")
+	}
+
+	reply.Name = d.Name
+	reply.Display = ons.DisplayName(d.Name)
+	return nil
+}
+
+// ONS_GetSubdomains lists the subdomains directly below a domain
+func (sv *Service) ONS_GetSubdomains(req client.ONSGetDomainsRequest, reply *client.ONSGetDomainsReply) error {
+	if len(req.Name) <= 0 {
+		return codes.ErrBadName
+	}
+
+	d, err := sv.ons.Get(req.Name)
+	if err != nil || d.IsReleased(sv.nextHeight()) {
+		return codes.ErrDomainNotFound
+	}
+
+	ds := make([]ons.Domain, 0)
+	sv.ons.IterateSubdomains(req.Name, func(domain *ons.Domain) bool {
+		ds = append(ds, *domain)
+		return false
+	})
+
+	*reply = client.ONSGetDomainsReply{
+		Domains: ds,
+	}
+	return nil
+}
+
+// ONS_Resolve returns the address a domain points to on a chain together with its records
+func (sv *Service) ONS_Resolve(req client.ONSResolveRequest, reply *client.ONSResolveReply) error {
+	if len(req.Name) <= 0 {
+		return codes.ErrBadName
+	}
+
+	c := chain.ONELEDGER
+	if req.Chain != "" {
+		var err error
+		c, err = chain.TypeFromName(req.Chain)
+		if err != nil {
+			return codes.ErrBadChain
+		}
+	}
+
+	d, err := sv.ons.Get(req.Name)
+	if err != nil || d.IsExpired(sv.nextHeight()) {
+		return codes.ErrDomainNotFound
+	}
+
+	address, ok := d.Resolve(c)
+	if !ok {
+		return codes.ErrNoRecord
+	}
+
+	*reply = client.ONSResolveReply{
+		Name:    d.Name,
+		Chain:   c.String(),
+		Address: address,
+		Records: d.Records,
+	}
+	return nil
+}
+
+// ONS_GetAuction returns a running auction together with its sealed bids
+func (sv *Service) ONS_GetAuction(req client.ONSGetAuctionRequest, reply *client.ONSGetAuctionReply) error {
+	if len(req.Name) <= 0 {
+		return codes.ErrBadName
+	}
+
+	a, err := sv.ons.GetAuction(req.Name)
+	if err != nil {
+		return codes.ErrAuctionNotFound
+	}
+
+	bids := make([]ons.Bid, 0)
+	sv.ons.IterateBids(req.Name, func(b *ons.Bid) bool {
+		bids = append(bids, *b)
+		return false
+	})
+
+	*reply = client.ONSGetAuctionReply{
+		Auction: *a,
+		Bids:    bids,
+		Height:  sv.nextHeight(),
+	}
+	return nil
+}
+
+// ONS_CheckName validates a name and returns its normalized form, so clients can check a
+// name before sending a tx for it. Invalid names are reported in the reply, not as an error.
+func (sv *Service) ONS_CheckName(req client.ONSCheckNameRequest, reply *client.ONSCheckNameReply) error {
+	*reply = client.ONSCheckNameReply{Name: req.Name}
+
+	normalized, err := ons.NormalizeName(req.Name)
+	if err != nil {
+		reply.Reason = err.Error()
+		return nil
+	}
+	reply.Normalized = normalized
+	reply.Display = ons.DisplayName(normalized)
+	reply.Valid = true
+
+	if !ons.IsSubdomain(normalized) {
+		govern := *sv.govern
+		reply.Reserved, err = govern.WithState(storage.NewState(sv.chainstate)).IsReservedName(normalized)
+		if err != nil {
+			sv.logger.Error("failed to read the reserved names", err)
+			return rpc.InternalError("failed to read the reserved names")
+		}
+	}
+
+	d, err := sv.ons.Get(normalized)
+	reply.Available = !reply.Reserved && (err != nil || d.IsReleased(sv.nextHeight()))
+	return nil
+}
+
+// ONS_GetOffers lists the open offers for a domain or the ones made by a buyer
+func (sv *Service) ONS_GetOffers(req client.ONSGetOffersRequest, reply *client.ONSGetOffersReply) error {
+	offers := make([]ons.Offer, 0)
+	collect := func(o *ons.Offer) bool {
+		if req.Buyer == nil || o.Buyer.Equal(req.Buyer) {
+			offers = append(offers, *o)
+		}
+		return false
+	}
+
+	switch {
+	case len(req.Name) > 0:
+		sv.ons.IterateDomainOffers(req.Name, collect)
+	case req.Buyer != nil:
+		sv.ons.IterateOffers(collect)
+	default:
+		return codes.ErrBadName
+	}
+
+	*reply = client.ONSGetOffersReply{
+		Offers: offers,
+		Height: sv.nextHeight(),
+	}
+	return nil
+}
+
+// ONS_GetExpiringDomains lists the domains that expire within the next blocks, and the expired
+// ones their owners can still renew
+func (sv *Service) ONS_GetExpiringDomains(req client.ONSGetExpiringDomainsRequest, reply *client.ONSGetExpiringDomainsReply) error {
+	if req.Blocks < 0 {
+		return codes.ErrBadBlocks
+	}
+
+	height := sv.nextHeight()
+	ds := make([]ons.Domain, 0)
+	sv.ons.IterateExpiring(height-ons.GRACE_PERIOD_BLOCKS, height+req.Blocks, func(domain *ons.Domain) bool {
+		if !domain.IsReleased(height) {
+			ds = append(ds, *domain)
+		}
+		return false
+	})
+
+	*reply = client.ONSGetExpiringDomainsReply{
+		Domains: ds,
+		Height:  height,
+	}
+	return nil
+}
+
+// nextHeight is the height the next block, which queries are answered for, will have
+func (sv *Service) nextHeight() int64 {
+	return sv.ons.State.Version() + 1
+}
@@ -1,21 +1,34 @@
 package query
 
 import (
+	"github.com/Oneledger/protocol/action"
 	"github.com/Oneledger/protocol/client"
 	"github.com/Oneledger/protocol/data/balance"
+	"github.com/Oneledger/protocol/data/governance"
 	"github.com/Oneledger/protocol/data/ons"
 	"github.com/Oneledger/protocol/identity"
 	"github.com/Oneledger/protocol/log"
+	"github.com/Oneledger/protocol/rpc"
+	"github.com/Oneledger/protocol/serialize"
 	codes "github.com/Oneledger/protocol/status_codes"
+	"github.com/Oneledger/protocol/storage"
 )
 
+// TxSimulator runs a tx against a throwaway copy of the chain state
+type TxSimulator interface {
+	SimulateTx(tx action.SignedTx, size action.Gas) (bool, action.Response)
+}
+
 type Service struct {
 	name       string
 	ext        client.ExtServiceContext
+	chainstate *storage.ChainState
+	simulator  TxSimulator
 	balances   *balance.Store
 	currencies *balance.CurrencySet
 	validators *identity.ValidatorStore
 	ons        *ons.DomainStore
+	govern     *governance.Store
 	logger     *log.Logger
 }
 
@@ -23,15 +36,18 @@ func Name() string {
 	return "query"
 }
 
-func NewService(ctx client.ExtServiceContext, balances *balance.Store, currencies *balance.CurrencySet, validators *identity.ValidatorStore,
-	domains *ons.DomainStore, logger *log.Logger) *Service {
+func NewService(ctx client.ExtServiceContext, chainstate *storage.ChainState, simulator TxSimulator, balances *balance.Store, currencies *balance.CurrencySet,
+	validators *identity.ValidatorStore, domains *ons.DomainStore, govern *governance.Store, logger *log.Logger) *Service {
 	return &Service{
 		name:       "query",
 		ext:        ctx,
+		chainstate: chainstate,
+		simulator:  simulator,
 		currencies: currencies,
 		balances:   balances,
 		validators: validators,
 		ons:        domains,
+		govern:     govern,
 		logger:     logger,
 	}
 }
@@ -106,3 +122,46 @@ func (svc *Service) CurrencyBalance(req client.CurrencyBalanceRequest, resp *cli
 	}
 	return nil
 }
+
+// StateVersions lists the chain state versions that have not been pruned, which are the
+// heights historical queries can be made at
+func (svc *Service) StateVersions(_ client.StateVersionsRequest, reply *client.StateVersionsReply) error {
+	available := make([]client.VersionRange, 0)
+	for _, v := range svc.chainstate.AvailableVersions() {
+		last := len(available) - 1
+		if last >= 0 && available[last].To+1 == v {
+			available[last].To = v
+			continue
+		}
+		available = append(available, client.VersionRange{From: v, To: v})
+	}
+
+	*reply = client.StateVersionsReply{
+		Latest:    svc.chainstate.Version,
+		Available: available,
+	}
+	return nil
+}
+
+// SimulateTx returns the gas a tx would use if it was checked against the latest state,
+// so clients can set Fee.Gas before signing
+func (svc *Service) SimulateTx(req client.SimulateTxRequest, reply *client.SimulateTxReply) error {
+	var tx action.RawTx
+	err := serialize.GetSerializer(serialize.NETWORK).Deserialize(req.RawTx, &tx)
+	if err != nil {
+		return rpc.InvalidRequestError("invalid rawTx given")
+	}
+
+	signedTx := action.SignedTx{
+		RawTx:      tx,
+		Signatures: req.Signatures,
+	}
+	ok, resp := svc.simulator.SimulateTx(signedTx, action.Gas(len(signedTx.SignedBytes())))
+
+	*reply = client.SimulateTxReply{
+		OK:      ok,
+		GasUsed: resp.GasUsed,
+		Log:     resp.Log,
+	}
+	return nil
+}
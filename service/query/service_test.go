@@ -0,0 +1,40 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/libs/db"
+
+	"github.com/Oneledger/protocol/client"
+	"github.com/Oneledger/protocol/storage"
+)
+
+func TestService_StateVersions(t *testing.T) {
+	cs := storage.NewChainState("chainstate", db.NewDB("test", db.MemDBBackend, ""))
+	// keep the last 2 versions and every 3rd one, two cycles of those
+	cs.SetupRotation(2, 3, 2)
+	svc := &Service{chainstate: cs}
+
+	reply := client.StateVersionsReply{}
+	require.NoError(t, svc.StateVersions(client.StateVersionsRequest{}, &reply))
+	assert.Equal(t, int64(0), reply.Latest)
+	assert.Empty(t, reply.Available)
+
+	for i := 1; i <= 4; i++ {
+		require.NoError(t, cs.Set(storage.StoreKey("key"), []byte{byte(i)}))
+		cs.Commit()
+	}
+	require.NoError(t, svc.StateVersions(client.StateVersionsRequest{}, &reply))
+	assert.Equal(t, int64(4), reply.Latest)
+	assert.Equal(t, []client.VersionRange{{From: 2, To: 4}}, reply.Available)
+
+	for i := 5; i <= 12; i++ {
+		require.NoError(t, cs.Set(storage.StoreKey("key"), []byte{byte(i)}))
+		cs.Commit()
+	}
+	require.NoError(t, svc.StateVersions(client.StateVersionsRequest{}, &reply))
+	assert.Equal(t, int64(12), reply.Latest)
+	assert.Equal(t, []client.VersionRange{{From: 6, To: 6}, {From: 9, To: 12}}, reply.Available)
+}
@@ -1,37 +1,39 @@
 package service
 
 import (
-	"crypto/sha256"
-	"encoding/base64"
 	"fmt"
 	"io/ioutil"
 	"net/http"
-	"strings"
+	"path/filepath"
+	"time"
 
-	"github.com/btcsuite/btcutil/base58"
 	"github.com/pkg/errors"
 
-	"github.com/Oneledger/protocol/data/keys"
+	"github.com/Oneledger/protocol/rpc"
 	"github.com/Oneledger/protocol/serialize"
+	"github.com/Oneledger/protocol/snapshot"
 )
 
 type RestfulRouter map[string]http.HandlerFunc
 
-//basic RestfulService entry point
+// basic RestfulService entry point
 type RestfulService struct {
 	ctx    *Context
 	router RestfulRouter
+	checks *healthChecks
 }
 
 func NewRestfulService(ctx *Context) RestfulService {
 	svc := RestfulService{
 		ctx:    ctx,
 		router: make(RestfulRouter),
+		checks: newHealthChecks(),
 	}
 
 	svc.router["/"] = svc.restfulRoot()
 	svc.router["/health"] = svc.health()
 	svc.router["/token"] = svc.GetToken()
+	svc.router[snapshot.PathPrefix] = snapshot.NewStore(filepath.Join(ctx.Cfg.RootDir(), "snapshots")).Handler()
 
 	return svc
 }
@@ -57,15 +59,6 @@ func (rs RestfulService) restfulRoot() http.HandlerFunc {
 	}
 }
 
-func (rs RestfulService) health() http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		_, err := fmt.Fprintf(w, "health check for SDK port %v : OK\n", rs.ctx.Cfg.Network.SDKAddress)
-		if err != nil {
-			rs.ctx.Logger.Errorf("failed to display SDK port health check info")
-		}
-	}
-}
-
 type clientTokenReq struct {
 	ClientID string `json:"client_id"`
 	Username string `json:"username"`
@@ -77,10 +70,11 @@ type clientTokenResp struct {
 	Message string `json:"message"`
 }
 
+// GetToken hands out a token for the client after checking the owner credentials, the token
+// carries the role of the user. Without configured credentials tokens only give the public role.
 func (rs RestfulService) GetToken() http.HandlerFunc {
 
 	return func(w http.ResponseWriter, r *http.Request) {
-		request := make([]byte, 0, 10)
 		serializer := serialize.GetSerializer(serialize.NETWORK)
 		clientReq := &clientTokenReq{}
 		clientResp := &clientTokenResp{}
@@ -91,74 +85,55 @@ func (rs RestfulService) GetToken() http.HandlerFunc {
 			_ = r.Body.Close()
 		}()
 
-		h := sha256.New()
-
-		rs.ctx.Logger.Error("Deserializing request into Object")
 		request, err := ioutil.ReadAll(r.Body)
-		err = serializer.Deserialize(request, clientReq)
+		if err == nil {
+			err = serializer.Deserialize(request, clientReq)
+		}
 		if err != nil {
 			clientResp.Message = errors.Wrap(err, "failed to get request body").Error()
 			return
 		}
 
-		rs.ctx.Logger.Error("Validating Username and Password")
-		//Validate Username and Password from request
-		if rs.ctx.Cfg.Node.OwnerCredentials != nil {
-			userList := rs.ctx.Cfg.Node.OwnerCredentials
-
-			for index, value := range userList {
-				//parse value into a pair
-				pair := strings.Split(value, ":")
-				if pair[0] == clientReq.Username && pair[1] == clientReq.Password {
-					break
-				}
-
-				if index == len(userList)-1 {
-					clientResp.Message = "Invalid Username and/or Password"
-					return
-				}
-			}
+		if rs.ctx.Cfg.Node.RPCPrivateKey == "" {
+			clientResp.Message = "No Private Key configured for this NODE"
+			return
 		}
 
-		if rs.ctx.Cfg.Node.RPCPrivateKey != "" {
-
-			//Hash clientID given in the request and create an encoded token based on the hash
-			h.Write([]byte(clientReq.ClientID))
-			data := h.Sum(nil)[:20]
-
-			//Get Private Key for signature.
-			keyData, err := base64.StdEncoding.DecodeString(rs.ctx.Cfg.Node.RPCPrivateKey)
-			if err != nil {
-				rs.ctx.Logger.Error("Error decoding rpc private key", err)
-				clientResp.Message = err.Error()
-				return
-			}
+		claims := rpc.TokenClaims{
+			Client:  clientReq.ClientID,
+			Roles:   []rpc.Role{rpc.RolePublic},
+			Expires: time.Now().Add(rpc.TokenTTL).Unix(),
+		}
 
-			privateKey, err := keys.GetPrivateKeyFromBytes(keyData, keys.ED25519)
-			if err != nil {
-				rs.ctx.Logger.Error("Error Getting private key from Bytes", err)
-				clientResp.Message = err.Error()
+		//Validate Username and Password from request
+		creds, err := rpc.ParseCredentials(rs.ctx.Cfg.Node.OwnerCredentials)
+		if err != nil {
+			rs.ctx.Logger.Error("Invalid owner credentials", err)
+			clientResp.Message = "Invalid owner credentials configured for this NODE"
+			return
+		}
+		if len(creds) > 0 {
+			cred, ok := rpc.CheckCredentials(creds, clientReq.Username, clientReq.Password)
+			if !ok {
+				clientResp.Message = "Invalid Username and/or Password"
 				return
 			}
-
-			//Get Key handler
-			privateKeyHandler, err := privateKey.GetHandler()
-			if err != nil {
-				rs.ctx.Logger.Error("Error Getting private key Handler", err)
-				clientResp.Message = err.Error()
-				return
+			if !cred.Hashed() {
+				rs.ctx.Logger.Warn("owner credentials of", cred.User, "hold a plaintext password, hash it with olfullnode hash_password")
 			}
+			claims.User = cred.User
+			claims.Roles = []rpc.Role{cred.Role}
+		}
 
-			//Generate signature using private key
-			signature, err := privateKeyHandler.Sign(data)
-			tokenBytes := append(data, signature...)
-
-			//Populate response fields.
-			clientResp.Token = base58.Encode(tokenBytes)
-			clientResp.Message = "Token generated successfully"
-
-		} else {
-			clientResp.Message = "No Private Key configured for this NODE"
+		token, err := rpc.NewToken(rs.ctx.Cfg.Node.RPCPrivateKey, claims)
+		if err != nil {
+			rs.ctx.Logger.Error("Error generating token", err)
+			clientResp.Message = err.Error()
+			return
 		}
+
+		//Populate response fields.
+		clientResp.Token = token
+		clientResp.Message = "Token generated successfully"
 	}
 }
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"net/http"
+
+	"github.com/Oneledger/protocol/rpc"
+)
+
+// Routes are the REST routes onto the JSON-RPC services, served under /v1/ with their
+// OpenAPI description at /v1/openapi.json. Routes of disabled services are left out.
+func Routes() []rpc.Route {
+	get, post := http.MethodGet, http.MethodPost
+	return []rpc.Route{
+		// query
+		{Method: get, Path: "/v1/balances/{address}", RPC: "query.Balance", Summary: "Balance of an account"},
+		{Method: get, Path: "/v1/balances/{address}/{currency}", RPC: "query.CurrencyBalance", Summary: "Balance of an account in one currency"},
+		{Method: get, Path: "/v1/validators", RPC: "query.ListValidators", Summary: "Active validators"},
+		{Method: get, Path: "/v1/currencies", RPC: "query.ListCurrencies", Summary: "Registered currencies"},
+		{Method: get, Path: "/v1/state/versions", RPC: "query.StateVersions", Summary: "Versions of the chain state that can be queried"},
+		{Method: post, Path: "/v1/txs/simulate", RPC: "query.SimulateTx", Summary: "Run a tx against the current state without committing it"},
+
+		{Method: get, Path: "/v1/domains/on-sale", RPC: "query.ONS_GetDomainOnSale", Summary: "Domains on sale"},
+		{Method: get, Path: "/v1/domains/expiring", RPC: "query.ONS_GetExpiringDomains", Summary: "Domains expiring within the given blocks"},
+		{Method: get, Path: "/v1/domains/{name}", RPC: "query.ONS_GetDomainByName", Summary: "Domain by name"},
+		{Method: get, Path: "/v1/domains/{name}/subdomains", RPC: "query.ONS_GetSubdomains", Summary: "Subdomains of a domain"},
+		{Method: get, Path: "/v1/domains/{name}/resolve", RPC: "query.ONS_Resolve", Summary: "Address a domain resolves to"},
+		{Method: get, Path: "/v1/domains/{name}/check", RPC: "query.ONS_CheckName", Summary: "Validate and normalize a domain name"},
+		{Method: get, Path: "/v1/domains/{name}/auction", RPC: "query.ONS_GetAuction", Summary: "Auction of a domain"},
+		{Method: get, Path: "/v1/domains/{name}/offers", RPC: "query.ONS_GetOffers", Summary: "Offers for a domain"},
+		{Method: get, Path: "/v1/accounts/{owner}/domains", RPC: "query.ONS_GetDomainByOwner", Summary: "Domains of an owner"},
+		{Method: get, Path: "/v1/accounts/{beneficiary}/beneficiary-domains", RPC: "query.ONS_GetDomainByBeneficiary", Summary: "Domains resolving to an account"},
+		{Method: get, Path: "/v1/accounts/{address}/name", RPC: "query.ONS_ReverseLookup", Summary: "Primary name of an account"},
+		{Method: get, Path: "/v1/accounts/{buyer}/offers", RPC: "query.ONS_GetOffers", Summary: "Offers made by a buyer"},
+
+		// broadcast
+		{Method: post, Path: "/v1/broadcast/async", RPC: "broadcast.TxAsync", Summary: "Broadcast a signed tx without waiting for it"},
+		{Method: post, Path: "/v1/broadcast/sync", RPC: "broadcast.TxSync", Summary: "Broadcast a signed tx and wait for CheckTx"},
+		{Method: post, Path: "/v1/broadcast/commit", RPC: "broadcast.TxCommit", Summary: "Broadcast a signed tx and wait for its block"},
+
+		// tx
+		{Method: post, Path: "/v1/tx/send", RPC: "tx.SendTx", Summary: "Send funds, signed by an account of the node"},
+		{Method: post, Path: "/v1/tx/raw/send", RPC: "tx.CreateRawSend", Summary: "Unsigned send tx"},
+		{Method: post, Path: "/v1/tx/apply-validator", RPC: "tx.ApplyValidator", Summary: "Apply to become a validator"},
+		{Method: post, Path: "/v1/tx/vote-gas-schedule", RPC: "tx.VoteGasSchedule", Summary: "Vote of the node's validator for a new gas schedule"},
+		{Method: post, Path: "/v1/tx/vote-reserved-names", RPC: "tx.VoteReservedNames", Summary: "Vote of the node's validator for new reserved ONS names"},
+		{Method: post, Path: "/v1/tx/withdraw-reward", RPC: "tx.WithdrawReward", Summary: "Withdraw validator rewards"},
+		{Method: post, Path: "/v1/tx/raw/ons/create", RPC: "tx.ONS_CreateRawCreate", Summary: "Unsigned domain create tx"},
+		{Method: post, Path: "/v1/tx/raw/ons/update", RPC: "tx.ONS_CreateRawUpdate", Summary: "Unsigned domain update tx"},
+		{Method: post, Path: "/v1/tx/raw/ons/sale", RPC: "tx.ONS_CreateRawSale", Summary: "Unsigned domain sale tx"},
+		{Method: post, Path: "/v1/tx/raw/ons/buy", RPC: "tx.ONS_CreateRawBuy", Summary: "Unsigned domain purchase tx"},
+		{Method: post, Path: "/v1/tx/raw/ons/send", RPC: "tx.ONS_CreateRawSend", Summary: "Unsigned send to domain tx"},
+		{Method: post, Path: "/v1/tx/raw/ons/renew", RPC: "tx.ONS_CreateRawRenew", Summary: "Unsigned domain renew tx"},
+		{Method: post, Path: "/v1/tx/raw/ons/delegate", RPC: "tx.ONS_CreateRawDelegate", Summary: "Unsigned subdomain delegation tx"},
+		{Method: post, Path: "/v1/tx/raw/ons/records", RPC: "tx.ONS_CreateRawSetRecords", Summary: "Unsigned domain records tx"},
+		{Method: post, Path: "/v1/tx/raw/ons/bid", RPC: "tx.ONS_CreateRawBid", Summary: "Unsigned sealed auction bid tx"},
+		{Method: post, Path: "/v1/tx/raw/ons/reveal", RPC: "tx.ONS_CreateRawReveal", Summary: "Unsigned auction bid reveal tx"},
+		{Method: post, Path: "/v1/tx/raw/ons/offer", RPC: "tx.ONS_CreateRawOffer", Summary: "Unsigned domain offer tx"},
+		{Method: post, Path: "/v1/tx/raw/ons/accept-offer", RPC: "tx.ONS_CreateRawAcceptOffer", Summary: "Unsigned accept offer tx"},
+		{Method: post, Path: "/v1/tx/raw/ons/cancel-offer", RPC: "tx.ONS_CreateRawCancelOffer", Summary: "Unsigned cancel offer tx"},
+		{Method: post, Path: "/v1/tx/raw/ons/primary", RPC: "tx.ONS_CreateRawSetPrimary", Summary: "Unsigned primary name tx"},
+
+		// btc
+		{Method: get, Path: "/v1/btc/trackers/{name}", RPC: "btc.GetTracker", Summary: "Bitcoin tracker by name"},
+		{Method: post, Path: "/v1/btc/lock/prepare", RPC: "btc.PrepareLock", Summary: "Bitcoin lock tx to sign"},
+		{Method: post, Path: "/v1/btc/lock", RPC: "btc.AddUserSignatureAndProcessLock", Summary: "Unsigned lock tx from a signed bitcoin lock"},
+		{Method: post, Path: "/v1/btc/redeem/prepare", RPC: "btc.PrepareRedeem", Summary: "Unsigned bitcoin redeem tx"},
+
+		// eth
+		{Method: post, Path: "/v1/eth/lock/raw", RPC: "eth.GetRawLockTX", Summary: "Unsigned ethereum lock contract call"},
+		{Method: post, Path: "/v1/eth/lock", RPC: "eth.CreateRawExtLock", Summary: "Unsigned lock tx from a signed ethereum lock"},
+		{Method: post, Path: "/v1/eth/redeem", RPC: "eth.CreateRawExtRedeem", Summary: "Unsigned ethereum redeem tx"},
+	}
+}
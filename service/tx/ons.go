@@ -12,6 +12,7 @@ import (
 	"github.com/Oneledger/protocol/action"
 	"github.com/Oneledger/protocol/action/ons"
 	"github.com/Oneledger/protocol/client"
+	dataons "github.com/Oneledger/protocol/data/ons"
 	"github.com/Oneledger/protocol/serialize"
 	codes "github.com/Oneledger/protocol/status_codes"
 )
@@ -192,3 +193,321 @@ func (s *Service) ONS_CreateRawSend(args client.ONSSendRequest, reply *client.Se
 
 	return nil
 }
+
+func (s *Service) ONS_CreateRawRenew(args client.ONSRenewRequest, reply *client.SendTxReply) error {
+
+	domainRenew := ons.DomainRenew{
+		Owner: args.Owner,
+		Name:  args.Name,
+		Price: args.Price,
+	}
+	data, err := domainRenew.Marshal()
+	if err != nil {
+		s.logger.Error("error in serializing domain renew object", err)
+		return codes.ErrSerialization
+	}
+
+	uuidNew, _ := uuid.NewUUID()
+	fee := action.Fee{args.GasPrice, args.Gas}
+	tx := &action.RawTx{
+		Type: action.DOMAIN_RENEW,
+		Data: data,
+		Fee:  fee,
+		Memo: uuidNew.String(),
+	}
+
+	packet, err := serialize.GetSerializer(serialize.NETWORK).Serialize(tx)
+	if err != nil {
+		s.logger.Error("error in serializing domain renew transaction", err)
+		return codes.ErrSerialization
+	}
+
+	*reply = client.SendTxReply{
+		RawTx: packet,
+	}
+
+	return nil
+}
+
+func (s *Service) ONS_CreateRawDelegate(args client.ONSDelegateRequest, reply *client.SendTxReply) error {
+
+	domainDelegate := ons.DomainDelegate{
+		Owner:    args.Owner,
+		Name:     args.Name,
+		Delegate: args.Delegate,
+		Revoke:   args.Revoke,
+	}
+	data, err := domainDelegate.Marshal()
+	if err != nil {
+		s.logger.Error("error in serializing domain delegate object", err)
+		return codes.ErrSerialization
+	}
+
+	uuidNew, _ := uuid.NewUUID()
+	fee := action.Fee{args.GasPrice, args.Gas}
+	tx := &action.RawTx{
+		Type: action.DOMAIN_DELEGATE,
+		Data: data,
+		Fee:  fee,
+		Memo: uuidNew.String(),
+	}
+
+	packet, err := serialize.GetSerializer(serialize.NETWORK).Serialize(tx)
+	if err != nil {
+		s.logger.Error("error in serializing domain delegate transaction", err)
+		return codes.ErrSerialization
+	}
+
+	*reply = client.SendTxReply{
+		RawTx: packet,
+	}
+
+	return nil
+}
+
+func (s *Service) ONS_CreateRawSetRecords(args client.ONSSetRecordsRequest, reply *client.SendTxReply) error {
+
+	domainSetRecords := ons.DomainSetRecords{
+		Owner:   args.Owner,
+		Name:    args.Name,
+		Records: args.Records,
+	}
+	data, err := domainSetRecords.Marshal()
+	if err != nil {
+		s.logger.Error("error in serializing domain records object", err)
+		return codes.ErrSerialization
+	}
+
+	uuidNew, _ := uuid.NewUUID()
+	fee := action.Fee{args.GasPrice, args.Gas}
+	tx := &action.RawTx{
+		Type: action.DOMAIN_SET_RECORDS,
+		Data: data,
+		Fee:  fee,
+		Memo: uuidNew.String(),
+	}
+
+	packet, err := serialize.GetSerializer(serialize.NETWORK).Serialize(tx)
+	if err != nil {
+		s.logger.Error("error in serializing domain records transaction", err)
+		return codes.ErrSerialization
+	}
+
+	*reply = client.SendTxReply{
+		RawTx: packet,
+	}
+
+	return nil
+}
+
+func (s *Service) ONS_CreateRawBid(args client.ONSBidRequest, reply *client.SendTxReply) error {
+
+	domainBid := ons.DomainBid{
+		Bidder:     args.Bidder,
+		Name:       args.Name,
+		Commitment: dataons.BidCommitment(args.Name, args.Bidder, args.Amount.Value, args.Salt),
+		Deposit:    args.Deposit,
+	}
+	data, err := domainBid.Marshal()
+	if err != nil {
+		s.logger.Error("error in serializing domain bid object", err)
+		return codes.ErrSerialization
+	}
+
+	uuidNew, _ := uuid.NewUUID()
+	fee := action.Fee{args.GasPrice, args.Gas}
+	tx := &action.RawTx{
+		Type: action.DOMAIN_BID,
+		Data: data,
+		Fee:  fee,
+		Memo: uuidNew.String(),
+	}
+
+	packet, err := serialize.GetSerializer(serialize.NETWORK).Serialize(tx)
+	if err != nil {
+		s.logger.Error("error in serializing domain bid transaction", err)
+		return codes.ErrSerialization
+	}
+
+	*reply = client.SendTxReply{
+		RawTx: packet,
+	}
+
+	return nil
+}
+
+func (s *Service) ONS_CreateRawReveal(args client.ONSRevealRequest, reply *client.SendTxReply) error {
+
+	domainReveal := ons.DomainReveal{
+		Bidder: args.Bidder,
+		Name:   args.Name,
+		Amount: args.Amount,
+		Salt:   args.Salt,
+	}
+	data, err := domainReveal.Marshal()
+	if err != nil {
+		s.logger.Error("error in serializing domain reveal object", err)
+		return codes.ErrSerialization
+	}
+
+	uuidNew, _ := uuid.NewUUID()
+	fee := action.Fee{args.GasPrice, args.Gas}
+	tx := &action.RawTx{
+		Type: action.DOMAIN_REVEAL,
+		Data: data,
+		Fee:  fee,
+		Memo: uuidNew.String(),
+	}
+
+	packet, err := serialize.GetSerializer(serialize.NETWORK).Serialize(tx)
+	if err != nil {
+		s.logger.Error("error in serializing domain reveal transaction", err)
+		return codes.ErrSerialization
+	}
+
+	*reply = client.SendTxReply{
+		RawTx: packet,
+	}
+
+	return nil
+}
+
+func (s *Service) ONS_CreateRawOffer(args client.ONSOfferRequest, reply *client.SendTxReply) error {
+
+	domainOffer := ons.DomainOffer{
+		Buyer:   args.Buyer,
+		Account: args.Account,
+		Name:    args.Name,
+		Amount:  args.Amount,
+		Blocks:  args.Blocks,
+	}
+	data, err := domainOffer.Marshal()
+	if err != nil {
+		s.logger.Error("error in serializing domain offer object", err)
+		return codes.ErrSerialization
+	}
+
+	uuidNew, _ := uuid.NewUUID()
+	fee := action.Fee{args.GasPrice, args.Gas}
+	tx := &action.RawTx{
+		Type: action.DOMAIN_OFFER,
+		Data: data,
+		Fee:  fee,
+		Memo: uuidNew.String(),
+	}
+
+	packet, err := serialize.GetSerializer(serialize.NETWORK).Serialize(tx)
+	if err != nil {
+		s.logger.Error("error in serializing domain offer transaction", err)
+		return codes.ErrSerialization
+	}
+
+	*reply = client.SendTxReply{
+		RawTx: packet,
+	}
+
+	return nil
+}
+
+func (s *Service) ONS_CreateRawAcceptOffer(args client.ONSAcceptOfferRequest, reply *client.SendTxReply) error {
+
+	domainAccept := ons.DomainAcceptOffer{
+		Owner: args.Owner,
+		Name:  args.Name,
+		Buyer: args.Buyer,
+	}
+	data, err := domainAccept.Marshal()
+	if err != nil {
+		s.logger.Error("error in serializing domain accept offer object", err)
+		return codes.ErrSerialization
+	}
+
+	uuidNew, _ := uuid.NewUUID()
+	fee := action.Fee{args.GasPrice, args.Gas}
+	tx := &action.RawTx{
+		Type: action.DOMAIN_ACCEPT_OFFER,
+		Data: data,
+		Fee:  fee,
+		Memo: uuidNew.String(),
+	}
+
+	packet, err := serialize.GetSerializer(serialize.NETWORK).Serialize(tx)
+	if err != nil {
+		s.logger.Error("error in serializing domain accept offer transaction", err)
+		return codes.ErrSerialization
+	}
+
+	*reply = client.SendTxReply{
+		RawTx: packet,
+	}
+
+	return nil
+}
+
+func (s *Service) ONS_CreateRawCancelOffer(args client.ONSCancelOfferRequest, reply *client.SendTxReply) error {
+
+	domainCancel := ons.DomainCancelOffer{
+		Buyer: args.Buyer,
+		Name:  args.Name,
+	}
+	data, err := domainCancel.Marshal()
+	if err != nil {
+		s.logger.Error("error in serializing domain cancel offer object", err)
+		return codes.ErrSerialization
+	}
+
+	uuidNew, _ := uuid.NewUUID()
+	fee := action.Fee{args.GasPrice, args.Gas}
+	tx := &action.RawTx{
+		Type: action.DOMAIN_CANCEL_OFFER,
+		Data: data,
+		Fee:  fee,
+		Memo: uuidNew.String(),
+	}
+
+	packet, err := serialize.GetSerializer(serialize.NETWORK).Serialize(tx)
+	if err != nil {
+		s.logger.Error("error in serializing domain cancel offer transaction", err)
+		return codes.ErrSerialization
+	}
+
+	*reply = client.SendTxReply{
+		RawTx: packet,
+	}
+
+	return nil
+}
+
+func (s *Service) ONS_CreateRawSetPrimary(args client.ONSSetPrimaryRequest, reply *client.SendTxReply) error {
+
+	domainPrimary := ons.DomainSetPrimary{
+		Address: args.Address,
+		Name:    args.Name,
+	}
+	data, err := domainPrimary.Marshal()
+	if err != nil {
+		s.logger.Error("error in serializing domain set primary object", err)
+		return codes.ErrSerialization
+	}
+
+	uuidNew, _ := uuid.NewUUID()
+	fee := action.Fee{args.GasPrice, args.Gas}
+	tx := &action.RawTx{
+		Type: action.DOMAIN_SET_PRIMARY,
+		Data: data,
+		Fee:  fee,
+		Memo: uuidNew.String(),
+	}
+
+	packet, err := serialize.GetSerializer(serialize.NETWORK).Serialize(tx)
+	if err != nil {
+		s.logger.Error("error in serializing domain set primary transaction", err)
+		return codes.ErrSerialization
+	}
+
+	*reply = client.SendTxReply{
+		RawTx: packet,
+	}
+
+	return nil
+}
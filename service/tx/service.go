@@ -4,6 +4,7 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/Oneledger/protocol/action"
+	"github.com/Oneledger/protocol/action/governance"
 	"github.com/Oneledger/protocol/action/staking"
 	"github.com/Oneledger/protocol/action/transfer"
 	"github.com/Oneledger/protocol/app/node"
@@ -11,9 +12,11 @@ import (
 	"github.com/Oneledger/protocol/data/accounts"
 	"github.com/Oneledger/protocol/data/balance"
 	"github.com/Oneledger/protocol/data/fees"
+	gov "github.com/Oneledger/protocol/data/governance"
 	"github.com/Oneledger/protocol/data/keys"
 	"github.com/Oneledger/protocol/log"
 	"github.com/Oneledger/protocol/serialize"
+	"github.com/Oneledger/protocol/signer"
 	codes "github.com/Oneledger/protocol/status_codes"
 )
 
@@ -28,6 +31,7 @@ type Service struct {
 	feeOpt      *fees.FeeOption
 	logger      *log.Logger
 	nodeContext node.Context
+	signer      signer.Signer
 }
 
 func NewService(
@@ -36,12 +40,14 @@ func NewService(
 	accounts accounts.Wallet,
 	feeOpt *fees.FeeOption,
 	nodeCtx node.Context,
+	signer signer.Signer,
 	logger *log.Logger,
 ) *Service {
 	return &Service{
 		balances:    balances,
 		router:      router,
 		nodeContext: nodeCtx,
+		signer:      signer,
 		accounts:    accounts,
 		feeOpt:      feeOpt,
 		logger:      logger,
@@ -151,7 +157,7 @@ func (svc *Service) ApplyValidator(args client.ApplyValidatorRequest, reply *cli
 		*pubkey = svc.nodeContext.ValidatorPubKey()
 	}
 
-	ecdsaPubKey := svc.nodeContext.ValidatorECDSAPubKey()
+	ecdsaPubKey := svc.signer.BTCPubKey()
 
 	handler, err := pubkey.GetHandler()
 	if err != nil {
@@ -216,6 +222,116 @@ func (svc *Service) ApplyValidator(args client.ApplyValidatorRequest, reply *cli
 	return nil
 }
 
+// VoteGasSchedule returns the vote of the validator of the node for a new gas schedule, signed
+// and ready to broadcast
+func (svc *Service) VoteGasSchedule(args client.VoteGasScheduleRequest, reply *client.VoteGasScheduleReply) error {
+	err := args.Schedule.Validate()
+	if err != nil {
+		return err
+	}
+
+	h, err := svc.nodeContext.PrivVal().GetHandler()
+	if err != nil {
+		svc.logger.Error("error get validator handler", err)
+		return codes.ErrLoadingNodeKey
+	}
+	pubKey, err := h.PubKey().GetHandler()
+	if err != nil {
+		return err
+	}
+
+	vote := governance.GasScheduleVote{
+		ValidatorAddress: pubKey.Address(),
+		Schedule:         args.Schedule,
+	}
+	data, err := vote.Marshal()
+	if err != nil {
+		svc.logger.Error("error in serializing gas schedule vote", err)
+		return codes.ErrSerialization
+	}
+
+	uuidNew, _ := uuid.NewUUID()
+	tx := action.RawTx{
+		Type: action.GAS_SCHEDULE_VOTE,
+		Data: data,
+		Memo: uuidNew.String(),
+	}
+
+	signed, err := h.Sign(tx.RawBytes())
+	if err != nil {
+		svc.logger.Error("error signing gas schedule vote", err)
+		return codes.ErrSigningError
+	}
+	signedTx := &action.SignedTx{
+		RawTx:      tx,
+		Signatures: []action.Signature{{Signer: h.PubKey(), Signed: signed}},
+	}
+
+	packet, err := serialize.GetSerializer(serialize.NETWORK).Serialize(signedTx)
+	if err != nil {
+		svc.logger.Error("error in serializing signed transaction", err)
+		return codes.ErrSerialization
+	}
+
+	*reply = client.VoteGasScheduleReply{RawTx: packet}
+	return nil
+}
+
+// VoteReservedNames returns the vote of the validator of the node for new reserved ONS names,
+// signed and ready to broadcast
+func (svc *Service) VoteReservedNames(args client.VoteReservedNamesRequest, reply *client.VoteReservedNamesReply) error {
+	names, err := gov.NormalizeReservedNames(args.Names)
+	if err != nil {
+		return err
+	}
+
+	h, err := svc.nodeContext.PrivVal().GetHandler()
+	if err != nil {
+		svc.logger.Error("error get validator handler", err)
+		return codes.ErrLoadingNodeKey
+	}
+	pubKey, err := h.PubKey().GetHandler()
+	if err != nil {
+		return err
+	}
+
+	vote := governance.ReservedNamesVote{
+		ValidatorAddress: pubKey.Address(),
+		Names:            names,
+	}
+	data, err := vote.Marshal()
+	if err != nil {
+		svc.logger.Error("error in serializing reserved names vote", err)
+		return codes.ErrSerialization
+	}
+
+	uuidNew, _ := uuid.NewUUID()
+	tx := action.RawTx{
+		Type: action.RESERVED_NAMES_VOTE,
+		Data: data,
+		Memo: uuidNew.String(),
+	}
+
+	signed, err := h.Sign(tx.RawBytes())
+	if err != nil {
+		svc.logger.Error("error signing reserved names vote", err)
+		return codes.ErrSigningError
+	}
+	signedTx := &action.SignedTx{
+		RawTx:      tx,
+		Signatures: []action.Signature{{Signer: h.PubKey(), Signed: signed}},
+	}
+
+	packet, err := serialize.GetSerializer(serialize.NETWORK).Serialize(signedTx)
+	if err != nil {
+		svc.logger.Error("error in serializing signed transaction", err)
+		return codes.ErrSerialization
+	}
+
+	*reply = client.VoteReservedNamesReply{RawTx: packet}
+	return nil
+}
+
 func (svc *Service) WithdrawReward(args client.WithdrawRewardRequest, reply *client.WithdrawRewardReply) error {
 
 	if len(args.To) < 1 {
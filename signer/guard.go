@@ -0,0 +1,80 @@
+package signer
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/Oneledger/protocol/storage"
+)
+
+// Guard remembers what has been signed for each tracker so that a compromised node
+// cannot get a second, conflicting signature out of the signer.
+type Guard struct {
+	store storage.SessionedStorage
+	lock  sync.Mutex
+}
+
+// NewGuard returns a Guard persisted in dbDir. Signatures handed out before a
+// restart are still remembered afterwards.
+func NewGuard(dbDir, dbType string) *Guard {
+	return &Guard{
+		store: storage.NewStorageDB(storage.KEYVALUE, "signerGuard", dbDir, dbType),
+	}
+}
+
+// Allow records digest as the payload signed under key. It returns ErrAlreadySigned if a
+// different digest has been recorded before; signing the same digest again is allowed.
+func (g *Guard) Allow(key string, digest []byte) error {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	storeKey := storage.StoreKey(key)
+	prev, err := g.store.Get(storeKey)
+	if err == nil && prev != nil {
+		if bytes.Equal(prev, digest) {
+			return nil
+		}
+		return ErrAlreadySigned
+	}
+
+	session := g.store.BeginSession()
+	err = session.Set(storeKey, digest)
+	if err != nil {
+		return errors.Wrap(err, "failed to record signature")
+	}
+	if !session.Commit() {
+		return errors.New("failed to commit signer guard")
+	}
+	return nil
+}
+
+// Replace records digest under key whatever was signed before. The caller must have
+// checked that the earlier payload was given up.
+func (g *Guard) Replace(key string, digest []byte) error {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	session := g.store.BeginSession()
+	err := session.Set(storage.StoreKey(key), digest)
+	if err != nil {
+		return errors.Wrap(err, "failed to record signature")
+	}
+	if !session.Commit() {
+		return errors.New("failed to commit signer guard")
+	}
+	return nil
+}
+
+func (g *Guard) Close() {
+	g.store.Close()
+}
+
+func btcGuardKey(trackerName, outpoint string) string {
+	return "btc" + storage.DB_PREFIX + trackerName + storage.DB_PREFIX + outpoint
+}
+
+func ethGuardKey(trackerName string) string {
+	return "eth" + storage.DB_PREFIX + trackerName
+}
@@ -0,0 +1,153 @@
+package signer
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+
+	"github.com/Oneledger/protocol/data/bitcoin"
+	"github.com/Oneledger/protocol/data/keys"
+)
+
+var _ Signer = &LocalSigner{}
+
+// LocalSigner signs with keys held in the memory of the current process
+type LocalSigner struct {
+	validator keys.PrivateKeyHandler
+	ethKey    *keys.PrivateKey
+	btcKey    *keys.PrivateKey
+
+	guard    *Guard
+	trackers TrackerSource
+}
+
+// NewLocalSigner creates a signer over the given keys. The ETH and BTC keys may be nil,
+// in which case signing with them fails with ErrNoKey. A nil guard disables tracker checks.
+func NewLocalSigner(validator keys.PrivateKey, ethKey, btcKey *keys.PrivateKey, guard *Guard) (*LocalSigner, error) {
+	h, err := validator.GetHandler()
+	if err != nil {
+		return nil, errors.Wrap(err, "wrong node private validator key")
+	}
+
+	return &LocalSigner{
+		validator: h,
+		ethKey:    ethKey,
+		btcKey:    btcKey,
+		guard:     guard,
+	}, nil
+}
+
+// WithTrackers lets the signer read committed trackers, so that an outpoint can be signed
+// again once the chain has reset its tracker. Without it a signed outpoint stays final.
+func (s *LocalSigner) WithTrackers(trackers TrackerSource) *LocalSigner {
+	s.trackers = trackers
+	return s
+}
+
+func (s *LocalSigner) ValidatorPubKey() keys.PublicKey {
+	return s.validator.PubKey()
+}
+
+func (s *LocalSigner) SignValidator(msg []byte) ([]byte, error) {
+	return s.validator.Sign(msg)
+}
+
+func (s *LocalSigner) ETHAddress() common.Address {
+	if !hasKey(s.ethKey) {
+		return common.Address{}
+	}
+	privkey := keys.ETHSECP256K1TOECDSA(s.ethKey.Data)
+	return crypto.PubkeyToAddress(privkey.PublicKey)
+}
+
+// SignETHTx signs a redeem transaction for the tracker. The guard only looks at the
+// destination and call data, so a retry with a new nonce or gas price is still allowed.
+func (s *LocalSigner) SignETHTx(trackerName string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	if !hasKey(s.ethKey) {
+		return nil, ErrNoKey
+	}
+
+	if s.guard != nil {
+		var to []byte
+		if tx.To() != nil {
+			to = tx.To().Bytes()
+		}
+		digest := crypto.Keccak256(to, tx.Data())
+		if err := s.guard.Allow(ethGuardKey(trackerName), digest); err != nil {
+			return nil, errors.Wrap(err, trackerName)
+		}
+	}
+
+	privkey := keys.ETHSECP256K1TOECDSA(s.ethKey.Data)
+	return types.SignTx(tx, types.NewEIP155Signer(chainID), privkey)
+}
+
+func (s *LocalSigner) BTCPubKey() keys.PublicKey {
+	if !hasKey(s.btcKey) {
+		return keys.PublicKey{}
+	}
+	h, err := s.btcKey.GetHandler()
+	if err != nil {
+		return keys.PublicKey{}
+	}
+	return h.PubKey()
+}
+
+// SignBTCInput signs input idx of tx. The guard allows a single sighash per tracker
+// and spent outpoint, unless the committed tracker is signing tx in a new process.
+func (s *LocalSigner) SignBTCInput(trackerName string, tx *wire.MsgTx, idx int, script []byte) ([]byte, error) {
+	if !hasKey(s.btcKey) {
+		return nil, ErrNoKey
+	}
+	if idx < 0 || idx >= len(tx.TxIn) {
+		return nil, ErrInvalidInput
+	}
+
+	if s.guard != nil {
+		digest, err := txscript.CalcSignatureHash(script, txscript.SigHashAll, tx, idx)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to calculate signature hash")
+		}
+		key := btcGuardKey(trackerName, tx.TxIn[idx].PreviousOutPoint.String())
+		err = s.guard.Allow(key, digest)
+		if err == ErrAlreadySigned && s.resetTo(trackerName, tx) {
+			err = s.guard.Replace(key, digest)
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, trackerName)
+		}
+	}
+
+	pk, _ := btcec.PrivKeyFromBytes(btcec.S256(), s.btcKey.Data)
+	return txscript.RawTxInSignature(tx, idx, script, txscript.SigHashAll, pk)
+}
+
+// resetTo tells if the committed tracker is collecting signatures for tx. The lock signed
+// before for the same outpoint was then given up by a reset the validators voted for.
+func (s *LocalSigner) resetTo(trackerName string, tx *wire.MsgTx) bool {
+	if s.trackers == nil {
+		return false
+	}
+	tracker, err := s.trackers.BTCTracker(trackerName)
+	if err != nil || tracker.State != bitcoin.BusySigning || len(tracker.ProcessUnsignedTx) == 0 {
+		return false
+	}
+
+	committed := wire.NewMsgTx(wire.TxVersion)
+	err = committed.Deserialize(bytes.NewReader(tracker.ProcessUnsignedTx))
+	if err != nil {
+		return false
+	}
+	return committed.TxHash() == tx.TxHash()
+}
+
+func hasKey(key *keys.PrivateKey) bool {
+	return key != nil && len(key.Data) > 0
+}
@@ -0,0 +1,164 @@
+package signer
+
+import (
+	"bytes"
+	"crypto/tls"
+	"math/big"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pkg/errors"
+	cmn "github.com/tendermint/tendermint/libs/common"
+
+	"github.com/Oneledger/protocol/config"
+	"github.com/Oneledger/protocol/data/keys"
+)
+
+var _ Signer = &RemoteSigner{}
+
+const defaultTimeout = 5 * time.Second
+
+// RemoteSigner forwards signing requests to a signing daemon over a mutually
+// authenticated TLS connection.
+type RemoteSigner struct {
+	protocol string
+	address  string
+	tlsCfg   *tls.Config
+	timeout  time.Duration
+
+	pubKeys PubKeysReply
+
+	client *rpc.Client
+	lock   sync.Mutex
+}
+
+// NewRemoteSigner connects to the daemon configured in cfg and caches its public keys
+func NewRemoteSigner(cfg *config.SignerConfig) (*RemoteSigner, error) {
+	tlsCfg, err := clientTLS(cfg.CertFile, cfg.KeyFile, cfg.CAFile, cfg.ServerName)
+	if err != nil {
+		return nil, err
+	}
+
+	protocol, address := cmn.ProtocolAndAddress(cfg.Address)
+	s := &RemoteSigner{
+		protocol: protocol,
+		address:  address,
+		tlsCfg:   tlsCfg,
+		timeout:  cfg.Timeout.Nanoseconds(),
+	}
+	if s.timeout <= 0 {
+		s.timeout = defaultTimeout
+	}
+
+	err = s.call("PubKeys", PubKeysRequest{}, &s.pubKeys)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch keys from signer")
+	}
+	return s, nil
+}
+
+func (s *RemoteSigner) ValidatorPubKey() keys.PublicKey {
+	return s.pubKeys.Validator
+}
+
+func (s *RemoteSigner) SignValidator(msg []byte) ([]byte, error) {
+	reply := SignValidatorReply{}
+	err := s.call("SignValidator", SignValidatorRequest{Msg: msg}, &reply)
+	return reply.Signature, err
+}
+
+func (s *RemoteSigner) ETHAddress() common.Address {
+	return s.pubKeys.ETH
+}
+
+func (s *RemoteSigner) SignETHTx(trackerName string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	raw, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := SignETHTxReply{}
+	err = s.call("SignETHTx", SignETHTxRequest{TrackerName: trackerName, RawTx: raw, ChainID: chainID}, &reply)
+	if err != nil {
+		return nil, err
+	}
+
+	signed := &types.Transaction{}
+	err = rlp.DecodeBytes(reply.SignedTx, signed)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid transaction returned by signer")
+	}
+	return signed, nil
+}
+
+func (s *RemoteSigner) BTCPubKey() keys.PublicKey {
+	return s.pubKeys.BTC
+}
+
+func (s *RemoteSigner) SignBTCInput(trackerName string, tx *wire.MsgTx, idx int, script []byte) ([]byte, error) {
+	buf := bytes.NewBuffer(nil)
+	err := tx.Serialize(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	req := SignBTCInputRequest{
+		TrackerName: trackerName,
+		RawTx:       buf.Bytes(),
+		Index:       idx,
+		Script:      script,
+	}
+	reply := SignBTCInputReply{}
+	err = s.call("SignBTCInput", req, &reply)
+	return reply.Signature, err
+}
+
+func (s *RemoteSigner) Close() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.client != nil {
+		_ = s.client.Close()
+		s.client = nil
+	}
+}
+
+// call sends a single request, dialing the daemon first if there is no live connection.
+// A connection that fails or times out is dropped so the next call redials.
+func (s *RemoteSigner) call(method string, args interface{}, reply interface{}) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.client == nil {
+		dialer := &net.Dialer{Timeout: s.timeout}
+		conn, err := tls.DialWithDialer(dialer, s.protocol, s.address, s.tlsCfg)
+		if err != nil {
+			return errors.Wrap(err, "failed to connect to signer")
+		}
+		s.client = rpc.NewClientWithCodec(jsonrpc.NewClientCodec(conn))
+	}
+
+	call := s.client.Go(serviceName+"."+method, args, reply, make(chan *rpc.Call, 1))
+	select {
+	case <-call.Done:
+		if _, ok := call.Error.(rpc.ServerError); ok {
+			return call.Error
+		}
+		if call.Error != nil {
+			_ = s.client.Close()
+			s.client = nil
+		}
+		return call.Error
+	case <-time.After(s.timeout):
+		_ = s.client.Close()
+		s.client = nil
+		return errors.New("signer request timed out: " + method)
+	}
+}
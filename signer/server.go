@@ -0,0 +1,147 @@
+package signer
+
+import (
+	"bytes"
+	"crypto/tls"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+	"sync"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pkg/errors"
+	cmn "github.com/tendermint/tendermint/libs/common"
+
+	"github.com/Oneledger/protocol/config"
+	"github.com/Oneledger/protocol/log"
+)
+
+// Server is the signing daemon. It serves a LocalSigner to nodes that authenticate
+// with a client certificate signed by the configured CA.
+type Server struct {
+	signer   *LocalSigner
+	cfg      *config.SignerConfig
+	logger   *log.Logger
+	listener net.Listener
+
+	wg sync.WaitGroup
+}
+
+func NewServer(signer *LocalSigner, cfg *config.SignerConfig, logger *log.Logger) *Server {
+	return &Server{
+		signer: signer,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+// Start listens on the configured address and serves connections in the background
+func (s *Server) Start() error {
+	tlsCfg, err := serverTLS(s.cfg.CertFile, s.cfg.KeyFile, s.cfg.CAFile)
+	if err != nil {
+		return err
+	}
+
+	protocol, address := cmn.ProtocolAndAddress(s.cfg.Address)
+	if protocol == "unix" {
+		// a stale socket from an unclean shutdown would make Listen fail
+		_ = os.Remove(address)
+	}
+
+	l, err := net.Listen(protocol, address)
+	if err != nil {
+		return errors.Wrap(err, "failed to listen on "+s.cfg.Address)
+	}
+	s.listener = tls.NewListener(l, tlsCfg)
+
+	srv := rpc.NewServer()
+	err = srv.RegisterName(serviceName, &handler{signer: s.signer, logger: s.logger})
+	if err != nil {
+		return errors.Wrap(err, "failed to register signer service")
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			conn, err := s.listener.Accept()
+			if err != nil {
+				s.logger.Info("signer stopped accepting connections", err)
+				return
+			}
+			s.logger.Info("signer connection from", conn.RemoteAddr())
+			go srv.ServeCodec(jsonrpc.NewServerCodec(conn))
+		}
+	}()
+
+	s.logger.Info("signer listening on", s.cfg.Address)
+	return nil
+}
+
+func (s *Server) Close() {
+	if s.listener != nil {
+		_ = s.listener.Close()
+	}
+	s.wg.Wait()
+}
+
+// handler exposes the LocalSigner over net/rpc
+type handler struct {
+	signer *LocalSigner
+	logger *log.Logger
+}
+
+func (h *handler) PubKeys(_ PubKeysRequest, reply *PubKeysReply) error {
+	*reply = PubKeysReply{
+		Validator: h.signer.ValidatorPubKey(),
+		ETH:       h.signer.ETHAddress(),
+		BTC:       h.signer.BTCPubKey(),
+	}
+	return nil
+}
+
+func (h *handler) SignValidator(req SignValidatorRequest, reply *SignValidatorReply) error {
+	signed, err := h.signer.SignValidator(req.Msg)
+	if err != nil {
+		return err
+	}
+	reply.Signature = signed
+	return nil
+}
+
+func (h *handler) SignETHTx(req SignETHTxRequest, reply *SignETHTxReply) error {
+	tx := &types.Transaction{}
+	err := rlp.DecodeBytes(req.RawTx, tx)
+	if err != nil {
+		return errors.Wrap(err, "invalid eth transaction")
+	}
+
+	signed, err := h.signer.SignETHTx(req.TrackerName, tx, req.ChainID)
+	if err != nil {
+		h.logger.Error("refused to sign eth tx", req.TrackerName, err)
+		return err
+	}
+
+	reply.SignedTx, err = rlp.EncodeToBytes(signed)
+	return err
+}
+
+func (h *handler) SignBTCInput(req SignBTCInputRequest, reply *SignBTCInputReply) error {
+	tx := wire.NewMsgTx(wire.TxVersion)
+	err := tx.Deserialize(bytes.NewReader(req.RawTx))
+	if err != nil {
+		return errors.Wrap(err, "invalid btc transaction")
+	}
+
+	sig, err := h.signer.SignBTCInput(req.TrackerName, tx, req.Index, req.Script)
+	if err != nil {
+		h.logger.Error("refused to sign btc input", req.TrackerName, err)
+		return err
+	}
+
+	reply.Signature = sig
+	return nil
+}
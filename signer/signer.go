@@ -0,0 +1,103 @@
+/*
+   ____             _              _                      _____           _                  _
+  / __ \           | |            | |                    |  __ \         | |                | |
+ | |  | |_ __   ___| |     ___  __| | __ _  ___ _ __     | |__) | __ ___ | |_ ___   ___ ___ | |
+ | |  | | '_ \ / _ \ |    / _ \/ _` |/ _` |/ _ \ '__|    |  ___/ '__/ _ \| __/ _ \ / __/ _ \| |
+ | |__| | | | |  __/ |___|  __/ (_| | (_| |  __/ |       | |   | | | (_) | || (_) | (_| (_) | |
+  \____/|_| |_|\___|______\___|\__,_|\__, |\___|_|       |_|   |_|  \___/ \__\___/ \___\___/|_|
+                                      __/ |
+                                     |___/
+
+	Copyright 2017 - 2019 OneLedger
+
+*/
+
+// Package signer abstracts the keys a validator signs with. The node never touches
+// the validator or bridge custody keys directly; it asks a Signer, which is either
+// backed by the key files on the local host or by a separate signing daemon.
+package signer
+
+import (
+	"math/big"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+
+	"github.com/Oneledger/protocol/data/keys"
+)
+
+const (
+	ModeLocal  = "local"
+	ModeRemote = "remote"
+
+	// RPC service name exposed by the signing daemon
+	serviceName = "Signer"
+)
+
+var (
+	ErrAlreadySigned = errors.New("tracker already signed with a different payload")
+	ErrNoKey         = errors.New("signer does not hold the requested key")
+	ErrInvalidInput  = errors.New("invalid input index")
+	ErrUnknownMode   = errors.New("unknown signer mode")
+)
+
+// Signer holds the validator's keys and signs on its behalf.
+//
+// The validator key signs internal transactions broadcast by the node, the ETH key signs
+// redeem transactions against the lock contract and the BTC key signs lock transaction inputs.
+// Signing for a tracker is idempotent: the same payload may be signed again, but a
+// different payload for a tracker already signed is refused with ErrAlreadySigned.
+// A bitcoin tracker whose process has been reset spends the same outpoint with a new
+// transaction; that is only signed once the committed tracker state asks for it, see
+// TrackerSource.
+type Signer interface {
+	ValidatorPubKey() keys.PublicKey
+	SignValidator(msg []byte) ([]byte, error)
+
+	ETHAddress() common.Address
+	SignETHTx(trackerName string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+
+	BTCPubKey() keys.PublicKey
+	SignBTCInput(trackerName string, tx *wire.MsgTx, idx int, script []byte) ([]byte, error)
+}
+
+/*
+	Wire types for the signing daemon
+*/
+type PubKeysRequest struct{}
+type PubKeysReply struct {
+	Validator keys.PublicKey `json:"validator"`
+	ETH       common.Address `json:"eth"`
+	BTC       keys.PublicKey `json:"btc"`
+}
+
+type SignValidatorRequest struct {
+	Msg []byte `json:"msg"`
+}
+type SignValidatorReply struct {
+	Signature []byte `json:"signature"`
+}
+
+type SignETHTxRequest struct {
+	TrackerName string `json:"trackerName"`
+	// RLP encoded unsigned transaction
+	RawTx   []byte   `json:"rawTx"`
+	ChainID *big.Int `json:"chainId"`
+}
+type SignETHTxReply struct {
+	// RLP encoded signed transaction
+	SignedTx []byte `json:"signedTx"`
+}
+
+type SignBTCInputRequest struct {
+	TrackerName string `json:"trackerName"`
+	// Serialized unsigned transaction
+	RawTx  []byte `json:"rawTx"`
+	Index  int    `json:"index"`
+	Script []byte `json:"script"`
+}
+type SignBTCInputReply struct {
+	Signature []byte `json:"signature"`
+}
@@ -0,0 +1,245 @@
+package signer
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Oneledger/protocol/config"
+	"github.com/Oneledger/protocol/data/bitcoin"
+	"github.com/Oneledger/protocol/data/keys"
+	"github.com/Oneledger/protocol/log"
+)
+
+func newTestSigner(t *testing.T, dir string) *LocalSigner {
+	_, priv, err := keys.NewKeyPairFromTendermint()
+	require.NoError(t, err)
+
+	k, err := btcec.NewPrivateKey(btcec.S256())
+	require.NoError(t, err)
+	ecdsaKey, err := keys.GetPrivateKeyFromBytes(k.Serialize(), keys.BTCECSECP)
+	require.NoError(t, err)
+
+	s, err := NewLocalSigner(priv, &ecdsaKey, &ecdsaKey, NewGuard(dir, "goleveldb"))
+	require.NoError(t, err)
+	return s
+}
+
+func btcTx(prev byte, amount int64) *wire.MsgTx {
+	tx := wire.NewMsgTx(wire.TxVersion)
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{prev}, 0), nil, nil))
+	tx.AddTxOut(wire.NewTxOut(amount, []byte{0x51}))
+	return tx
+}
+
+// trackers are committed trackers kept in memory
+type trackers map[string]*bitcoin.Tracker
+
+func (t trackers) BTCTracker(name string) (*bitcoin.Tracker, error) {
+	tracker, ok := t[name]
+	if !ok {
+		return nil, errors.New("tracker not found")
+	}
+	return tracker, nil
+}
+
+// signing returns a tracker committed to collect signatures for tx
+func signing(t *testing.T, tx *wire.MsgTx) *bitcoin.Tracker {
+	buf := bytes.NewBuffer(nil)
+	require.NoError(t, tx.Serialize(buf))
+	return &bitcoin.Tracker{State: bitcoin.BusySigning, ProcessUnsignedTx: buf.Bytes()}
+}
+
+func ethTx(nonce uint64, data []byte) *types.Transaction {
+	return types.NewTransaction(nonce, common.HexToAddress("0x01"), big.NewInt(0), 21000, big.NewInt(1), data)
+}
+
+func TestLocalSigner_Guard(t *testing.T) {
+	dir, err := ioutil.TempDir("", "signer")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	s := newTestSigner(t, dir)
+	script := []byte{0x51}
+
+	_, err = s.SignBTCInput("tracker_0", btcTx(1, 100), 0, script)
+	assert.NoError(t, err)
+
+	// same payload again is allowed
+	_, err = s.SignBTCInput("tracker_0", btcTx(1, 100), 0, script)
+	assert.NoError(t, err)
+
+	// a different spend of the same outpoint is refused
+	_, err = s.SignBTCInput("tracker_0", btcTx(1, 200), 0, script)
+	assert.Error(t, err)
+
+	// the tracker may move on to the next outpoint
+	_, err = s.SignBTCInput("tracker_0", btcTx(2, 200), 0, script)
+	assert.NoError(t, err)
+
+	_, err = s.SignBTCInput("tracker_0", btcTx(3, 200), 1, script)
+	assert.Equal(t, ErrInvalidInput, err)
+
+	// a new lock for the same outpoint needs the committed tracker to be signing it
+	committed := trackers{}
+	s.WithTrackers(committed)
+	_, err = s.SignBTCInput("tracker_1", btcTx(1, 100), 0, script)
+	assert.NoError(t, err)
+	_, err = s.SignBTCInput("tracker_0", btcTx(1, 300), 0, script)
+	assert.Error(t, err)
+
+	committed["tracker_0"] = signing(t, btcTx(1, 300))
+	committed["tracker_0"].State = bitcoin.BusyBroadcasting
+	_, err = s.SignBTCInput("tracker_0", btcTx(1, 300), 0, script)
+	assert.Error(t, err)
+
+	committed["tracker_0"] = signing(t, btcTx(1, 300))
+	_, err = s.SignBTCInput("tracker_0", btcTx(1, 300), 0, script)
+	assert.NoError(t, err)
+	_, err = s.SignBTCInput("tracker_0", btcTx(1, 400), 0, script)
+	assert.Error(t, err)
+	_, err = s.SignBTCInput("tracker_1", btcTx(1, 200), 0, script)
+	assert.Error(t, err)
+
+	// the committed tracker moving on does not bring back an older lock
+	committed["tracker_0"] = signing(t, btcTx(1, 500))
+	_, err = s.SignBTCInput("tracker_0", btcTx(1, 100), 0, script)
+	assert.Error(t, err)
+
+	chainID := big.NewInt(1)
+	_, err = s.SignETHTx("0xabc", ethTx(0, []byte{1}), chainID)
+	assert.NoError(t, err)
+
+	// a retry with a new nonce is fine, a different redeem is not
+	_, err = s.SignETHTx("0xabc", ethTx(1, []byte{1}), chainID)
+	assert.NoError(t, err)
+	_, err = s.SignETHTx("0xabc", ethTx(2, []byte{2}), chainID)
+	assert.Error(t, err)
+}
+
+func writePEM(t *testing.T, path, kind string, der []byte) {
+	err := ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: kind, Bytes: der}), 0600)
+	require.NoError(t, err)
+}
+
+// writeCerts creates a CA and a certificate signed by it for each name
+func writeCerts(t *testing.T, dir string, names ...string) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	writePEM(t, filepath.Join(dir, "ca.pem"), "CERTIFICATE", caDER)
+
+	for i, name := range names {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		tmpl := &x509.Certificate{
+			SerialNumber: big.NewInt(int64(i + 2)),
+			Subject:      pkix.Name{CommonName: name},
+			DNSNames:     []string{name},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, caTmpl, &key.PublicKey, caKey)
+		require.NoError(t, err)
+		writePEM(t, filepath.Join(dir, name+".pem"), "CERTIFICATE", der)
+
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		require.NoError(t, err)
+		writePEM(t, filepath.Join(dir, name+".key"), "EC PRIVATE KEY", keyDER)
+	}
+}
+
+func signerConfig(dir, name string) *config.SignerConfig {
+	cfg := config.DefaultSignerConfig()
+	cfg.Mode = ModeRemote
+	cfg.Address = "unix://" + filepath.Join(dir, "signer.sock")
+	cfg.CertFile = filepath.Join(dir, name+".pem")
+	cfg.KeyFile = filepath.Join(dir, name+".key")
+	cfg.CAFile = filepath.Join(dir, "ca.pem")
+	return cfg
+}
+
+func TestRemoteSigner(t *testing.T) {
+	dir, err := ioutil.TempDir("", "signer")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeCerts(t, dir, "olsigner", "node")
+
+	committed := trackers{}
+	local := newTestSigner(t, dir).WithTrackers(committed)
+	srv := NewServer(local, signerConfig(dir, "olsigner"), log.NewLoggerWithPrefix(ioutil.Discard, "signer"))
+	require.NoError(t, srv.Start())
+	defer srv.Close()
+
+	remote, err := NewRemoteSigner(signerConfig(dir, "node"))
+	require.NoError(t, err)
+	defer remote.Close()
+
+	assert.Equal(t, local.ValidatorPubKey(), remote.ValidatorPubKey())
+	assert.Equal(t, local.ETHAddress(), remote.ETHAddress())
+	assert.Equal(t, local.BTCPubKey(), remote.BTCPubKey())
+
+	msg := []byte("internal tx")
+	signed, err := remote.SignValidator(msg)
+	require.NoError(t, err)
+	h, err := remote.ValidatorPubKey().GetHandler()
+	require.NoError(t, err)
+	assert.True(t, h.VerifyBytes(msg, signed))
+
+	chainID := big.NewInt(3)
+	tx, err := remote.SignETHTx("0xabc", ethTx(0, []byte{1}), chainID)
+	require.NoError(t, err)
+	from, err := types.Sender(types.NewEIP155Signer(chainID), tx)
+	require.NoError(t, err)
+	assert.Equal(t, remote.ETHAddress(), from)
+
+	_, err = remote.SignBTCInput("tracker_1", btcTx(1, 100), 0, []byte{0x51})
+	assert.NoError(t, err)
+	_, err = remote.SignBTCInput("tracker_1", btcTx(1, 300), 0, []byte{0x51})
+	assert.Error(t, err)
+	committed["tracker_1"] = signing(t, btcTx(1, 300))
+	_, err = remote.SignBTCInput("tracker_1", btcTx(1, 300), 0, []byte{0x51})
+	assert.NoError(t, err)
+
+	// the daemon refuses clients without a certificate signed by its CA
+	otherDir := filepath.Join(dir, "other")
+	require.NoError(t, os.Mkdir(otherDir, 0700))
+	writeCerts(t, otherDir, "node")
+	other := signerConfig(dir, "node")
+	other.CertFile = filepath.Join(otherDir, "node.pem")
+	other.KeyFile = filepath.Join(otherDir, "node.key")
+	_, err = NewRemoteSigner(other)
+	assert.Error(t, err)
+}
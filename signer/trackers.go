@@ -0,0 +1,45 @@
+package signer
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/Oneledger/protocol/client"
+	"github.com/Oneledger/protocol/data/bitcoin"
+)
+
+// TrackerSource gives the committed state of a bitcoin tracker
+type TrackerSource interface {
+	BTCTracker(name string) (*bitcoin.Tracker, error)
+}
+
+var _ TrackerSource = &NodeTrackers{}
+
+// NodeTrackers reads trackers over the SDK RPC of a node
+type NodeTrackers struct {
+	client *client.ServiceClient
+}
+
+// NewNodeTrackers connects to the SDK RPC at address
+func NewNodeTrackers(address string) (*NodeTrackers, error) {
+	c, err := client.NewServiceClient(address)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect to the tracker rpc")
+	}
+	return &NodeTrackers{client: c}, nil
+}
+
+func (n *NodeTrackers) BTCTracker(name string) (*bitcoin.Tracker, error) {
+	reply, err := n.client.GetTracker(name)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get tracker")
+	}
+
+	tracker := &bitcoin.Tracker{}
+	err = json.Unmarshal([]byte(reply.TrackerData), tracker)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read tracker")
+	}
+	return tracker, nil
+}
@@ -0,0 +1,137 @@
+package snapshot
+
+import (
+	"bytes"
+
+	"github.com/pkg/errors"
+	"github.com/tendermint/go-amino"
+	"github.com/tendermint/tendermint/blockchain"
+	tmdb "github.com/tendermint/tendermint/libs/db"
+	sm "github.com/tendermint/tendermint/state"
+	"github.com/tendermint/tendermint/types"
+)
+
+var cdc = amino.NewCodec()
+
+func init() {
+	types.RegisterBlockAmino(cdc)
+}
+
+// consensusData is what tendermint needs to resume consensus right after Height,
+// without any of the blocks before it.
+type consensusData struct {
+	State      sm.State
+	Block      *types.Block
+	SeenCommit *types.Commit
+}
+
+// ExportConsensus reads the tendermint state and the block at height from the node's
+// tendermint databases. It returns nil if tendermint is not at height anymore.
+func ExportConsensus(stateDB, blockDB tmdb.DB, height int64) ([]byte, error) {
+	state := sm.LoadState(stateDB)
+	if state.LastBlockHeight != height {
+		return nil, nil
+	}
+
+	store := blockchain.NewBlockStore(blockDB)
+	data := consensusData{
+		State:      state,
+		Block:      store.LoadBlock(height),
+		SeenCommit: store.LoadSeenCommit(height),
+	}
+	if data.Block == nil || data.SeenCommit == nil {
+		return nil, errors.Errorf("block %d missing from block store", height)
+	}
+
+	return cdc.MarshalBinaryBare(data)
+}
+
+// ImportConsensus writes the tendermint state and last block, so that tendermint starts at
+// the height of the snapshot. Both databases must be empty. Nothing is written before the data
+// is checked against trusted, the header of the block after the snapshot height.
+func ImportConsensus(bz []byte, stateDB, blockDB tmdb.DB, m *Manifest, trusted *types.Header) error {
+	data := consensusData{}
+	err := cdc.UnmarshalBinaryBare(bz, &data)
+	if err != nil {
+		return errors.Wrap(err, "invalid consensus data")
+	}
+
+	if data.State.LastBlockHeight != m.Height || data.Block == nil || data.Block.Height != m.Height {
+		return errors.New("consensus data does not match the snapshot height")
+	}
+	if data.State.ChainID != m.ChainID {
+		return ErrChainID
+	}
+	err = verifyConsensus(&data, trusted)
+	if err != nil {
+		return err
+	}
+
+	store := blockchain.NewBlockStore(blockDB)
+	if store.Height() != 0 || sm.LoadState(stateDB).LastBlockHeight != 0 {
+		return errors.New("tendermint data already exists")
+	}
+
+	// Store the full validator set and consensus params, there is no earlier
+	// height for tendermint to find them at.
+	state := data.State
+	state.LastHeightValidatorsChanged = state.LastBlockHeight + 2
+	state.LastHeightConsensusParamsChanged = state.LastBlockHeight + 1
+	sm.SaveState(stateDB, state)
+
+	// The block store only accepts contiguous blocks, so it is moved up to just below
+	// the snapshot height first.
+	blockchain.BlockStoreStateJSON{Height: m.Height - 1}.Save(blockDB)
+	store = blockchain.NewBlockStore(blockDB)
+	parts := data.Block.MakePartSet(types.BlockPartSizeBytes)
+	store.SaveBlock(data.Block, parts, data.SeenCommit)
+
+	return nil
+}
+
+// verifyConsensus checks the state, the block and its commit against the trusted header of the
+// next block. The header commits to the block, the state's app hash, validators and consensus
+// params, and the block to the validators that signed the commit.
+func verifyConsensus(data *consensusData, trusted *types.Header) error {
+	if trusted == nil {
+		return ErrUntrustedConsensus
+	}
+	state := data.State
+	if trusted.Height != state.LastBlockHeight+1 || trusted.ChainID != state.ChainID {
+		return errors.Wrap(ErrUntrustedConsensus, "trusted header is not of the next block")
+	}
+
+	fail := func(what string) error {
+		return errors.Wrapf(ErrUntrustedConsensus, "%s does not match the trusted header", what)
+	}
+	if !bytes.Equal(state.AppHash, trusted.AppHash) {
+		return fail("app hash")
+	}
+	if !bytes.Equal(data.Block.Hash(), trusted.LastBlockID.Hash) || !state.LastBlockID.Equals(trusted.LastBlockID) {
+		return fail("block")
+	}
+	if state.Validators == nil || !bytes.Equal(state.Validators.Hash(), trusted.ValidatorsHash) {
+		return fail("validator set")
+	}
+	if state.NextValidators == nil || !bytes.Equal(state.NextValidators.Hash(), trusted.NextValidatorsHash) {
+		return fail("next validator set")
+	}
+	if !bytes.Equal(state.ConsensusParams.Hash(), trusted.ConsensusHash) {
+		return fail("consensus params")
+	}
+	if !bytes.Equal(state.LastResultsHash, trusted.LastResultsHash) {
+		return fail("last results")
+	}
+
+	if state.LastValidators == nil || !bytes.Equal(state.LastValidators.Hash(), data.Block.ValidatorsHash) {
+		return errors.Wrap(ErrUntrustedConsensus, "last validator set does not match the block")
+	}
+	if data.SeenCommit == nil {
+		return errors.Wrap(ErrUntrustedConsensus, "commit missing")
+	}
+	err := state.LastValidators.VerifyCommit(state.ChainID, state.LastBlockID, state.LastBlockHeight, data.SeenCommit)
+	if err != nil {
+		return errors.Wrap(ErrUntrustedConsensus, err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package snapshot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/blockchain"
+	tmdb "github.com/tendermint/tendermint/libs/db"
+	sm "github.com/tendermint/tendermint/state"
+	"github.com/tendermint/tendermint/types"
+
+	"github.com/Oneledger/protocol/storage"
+)
+
+func newValidators() (*types.ValidatorSet, *types.MockPV) {
+	pv := types.NewMockPV()
+	return types.NewValidatorSet([]*types.Validator{types.NewValidator(pv.GetPubKey(), 10)}), pv
+}
+
+// newConsensus returns the consensus data of a chain at height signed by a single validator,
+// with the header of the next block
+func newConsensus(t *testing.T, chainID string, height int64, appHash []byte) (consensusData, *types.Header) {
+	vals, pv := newValidators()
+	params := types.DefaultConsensusParams()
+
+	block := types.MakeBlock(height, nil, &types.Commit{}, nil)
+	block.ChainID = chainID
+	block.Time = time.Unix(1000, 0).UTC()
+	block.ValidatorsHash = vals.Hash()
+	block.NextValidatorsHash = vals.Hash()
+	block.ConsensusHash = params.Hash()
+	blockID := types.BlockID{Hash: block.Hash(), PartsHeader: block.MakePartSet(types.BlockPartSizeBytes).Header()}
+
+	vote := &types.Vote{
+		Type:             types.PrecommitType,
+		Height:           height,
+		BlockID:          blockID,
+		Timestamp:        block.Time,
+		ValidatorAddress: pv.GetPubKey().Address(),
+	}
+	require.NoError(t, pv.SignVote(chainID, vote))
+
+	state := sm.State{
+		ChainID:         chainID,
+		LastBlockHeight: height,
+		LastBlockID:     blockID,
+		LastBlockTime:   block.Time,
+		Validators:      vals,
+		NextValidators:  vals.Copy(),
+		LastValidators:  vals.Copy(),
+		ConsensusParams: *params,
+		AppHash:         appHash,
+	}
+	data := consensusData{
+		State:      state,
+		Block:      block,
+		SeenCommit: types.NewCommit(blockID, []*types.CommitSig{vote.CommitSig()}),
+	}
+	header := &types.Header{
+		ChainID:            chainID,
+		Height:             height + 1,
+		LastBlockID:        blockID,
+		ValidatorsHash:     vals.Hash(),
+		NextValidatorsHash: vals.Hash(),
+		ConsensusHash:      params.Hash(),
+		AppHash:            appHash,
+	}
+	return data, header
+}
+
+func TestImportConsensus(t *testing.T) {
+	m := &Manifest{ChainID: "test-chain", Height: 5, AppHash: []byte("app hash")}
+	data, header := newConsensus(t, m.ChainID, m.Height, m.AppHash)
+	bz, err := cdc.MarshalBinaryBare(data)
+	require.NoError(t, err)
+
+	stateDB, blockDB := tmdb.NewMemDB(), tmdb.NewMemDB()
+	require.NoError(t, ImportConsensus(bz, stateDB, blockDB, m, header))
+	assert.EqualValues(t, m.Height, sm.LoadState(stateDB).LastBlockHeight)
+	assert.EqualValues(t, m.Height, blockchain.NewBlockStore(blockDB).Height())
+}
+
+func TestImportConsensus_Rejects(t *testing.T) {
+	m := &Manifest{ChainID: "test-chain", Height: 5, AppHash: []byte("app hash")}
+
+	tamper := map[string]func(data *consensusData, header *types.Header){
+		"header of another block": func(data *consensusData, header *types.Header) {
+			header.Height++
+		},
+		"validator set": func(data *consensusData, header *types.Header) {
+			data.State.Validators, _ = newValidators()
+		},
+		"next validator set": func(data *consensusData, header *types.Header) {
+			data.State.NextValidators, _ = newValidators()
+		},
+		"last validator set and commit": func(data *consensusData, header *types.Header) {
+			// another set signing the block, without the header of the block changed
+			other, _ := newConsensus(t, m.ChainID, m.Height, m.AppHash)
+			data.State.LastValidators = other.State.LastValidators
+			data.SeenCommit = other.SeenCommit
+		},
+		"commit": func(data *consensusData, header *types.Header) {
+			data.SeenCommit.Precommits[0].Signature[0] ^= 0xff
+		},
+		"block": func(data *consensusData, header *types.Header) {
+			data.Block.Time = data.Block.Time.Add(time.Second)
+		},
+		"consensus params": func(data *consensusData, header *types.Header) {
+			data.State.ConsensusParams.Block.MaxGas = 1
+		},
+	}
+	for name, fn := range tamper {
+		t.Run(name, func(t *testing.T) {
+			data, header := newConsensus(t, m.ChainID, m.Height, m.AppHash)
+			fn(&data, header)
+			bz, err := cdc.MarshalBinaryBare(data)
+			require.NoError(t, err)
+
+			stateDB, blockDB := tmdb.NewMemDB(), tmdb.NewMemDB()
+			err = ImportConsensus(bz, stateDB, blockDB, m, header)
+			assert.Equal(t, ErrUntrustedConsensus, errors.Cause(err))
+			// nothing is written
+			assert.EqualValues(t, 0, sm.LoadState(stateDB).LastBlockHeight)
+			assert.EqualValues(t, 0, blockchain.NewBlockStore(blockDB).Height())
+		})
+	}
+}
+
+func TestRestore_NeedsTrustedHeader(t *testing.T) {
+	cs, hashes := newChainState(t, 3, 50)
+	store, cleanup := newStore(t)
+	defer cleanup()
+
+	data, _ := newConsensus(t, "test-chain", cs.Version, cs.Hash)
+	bz, err := cdc.MarshalBinaryBare(data)
+	require.NoError(t, err)
+	_, err = store.Create(cs, "test-chain", cs.Version, 512, bz)
+	require.NoError(t, err)
+
+	appDB := tmdb.NewMemDB()
+	_, err = Restore(store, cs.Version, "test-chain", trusted(hashes, cs.Version), nil, appDB, tmdb.NewMemDB(), tmdb.NewMemDB())
+	assert.Equal(t, ErrUntrustedConsensus, errors.Cause(err))
+	assert.EqualValues(t, 0, storage.NewChainState("chainstate", appDB).Version)
+}
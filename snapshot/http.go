@@ -0,0 +1,161 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// PathPrefix is where snapshots are served on the SDK port:
+//
+//	GET /snapshots/                       list of manifests
+//	GET /snapshots/{height}/manifest      manifest of one snapshot
+//	GET /snapshots/{height}/chunks/{i}    chunk i
+//	GET /snapshots/{height}/consensus     tendermint data
+const PathPrefix = "/snapshots/"
+
+// Handler serves the snapshots of the store over HTTP
+func (s *Store) Handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, PathPrefix), "/"), "/")
+		if len(parts) == 1 && parts[0] == "" {
+			list, err := s.List()
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			writeJSON(w, list)
+			return
+		}
+
+		height, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil || len(parts) < 2 {
+			http.NotFound(w, r)
+			return
+		}
+
+		var data []byte
+		switch {
+		case len(parts) == 2 && parts[1] == "manifest":
+			m, err := s.Manifest(height)
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			writeJSON(w, m)
+			return
+
+		case len(parts) == 2 && parts[1] == "consensus":
+			data, err = s.Consensus(height)
+
+		case len(parts) == 3 && parts[1] == "chunks":
+			index, perr := strconv.Atoi(parts[2])
+			if perr != nil {
+				http.NotFound(w, r)
+				return
+			}
+			data, err = s.Chunk(height, index)
+
+		default:
+			http.NotFound(w, r)
+			return
+		}
+
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write(data)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	if errors.Cause(err) == ErrNotFound {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
+
+var _ Source = &HTTPSource{}
+
+// HTTPSource fetches snapshots served by another node's Handler
+type HTTPSource struct {
+	base   string
+	client *http.Client
+}
+
+// NewHTTPSource takes the SDK address of the serving node, e.g. http://127.0.0.1:26631
+func NewHTTPSource(sdkAddress string) *HTTPSource {
+	return &HTTPSource{
+		base:   strings.TrimRight(sdkAddress, "/") + strings.TrimRight(PathPrefix, "/"),
+		client: &http.Client{Timeout: 5 * time.Minute},
+	}
+}
+
+func (h *HTTPSource) get(path string) ([]byte, error) {
+	resp, err := h.client.Get(h.base + path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	bz, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return bz, nil
+	case http.StatusNotFound:
+		return nil, errors.Wrap(ErrNotFound, path)
+	default:
+		return nil, errors.Errorf("GET %s: %s %s", path, resp.Status, strings.TrimSpace(string(bz)))
+	}
+}
+
+// List returns the manifests offered by the remote node
+func (h *HTTPSource) List() ([]*Manifest, error) {
+	bz, err := h.get("/")
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*Manifest, 0)
+	err = json.Unmarshal(bz, &list)
+	return list, err
+}
+
+func (h *HTTPSource) Manifest(height int64) (*Manifest, error) {
+	bz, err := h.get(fmt.Sprintf("/%d/manifest", height))
+	if err != nil {
+		return nil, err
+	}
+	m := &Manifest{}
+	err = json.Unmarshal(bz, m)
+	return m, err
+}
+
+func (h *HTTPSource) Chunk(height int64, index int) ([]byte, error) {
+	return h.get(fmt.Sprintf("/%d/chunks/%d", height, index))
+}
+
+func (h *HTTPSource) Consensus(height int64) ([]byte, error) {
+	return h.get(fmt.Sprintf("/%d/consensus", height))
+}
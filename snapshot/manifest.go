@@ -0,0 +1,146 @@
+/*
+   ____             _              _                      _____           _                  _
+  / __ \           | |            | |                    |  __ \         | |                | |
+ | |  | |_ __   ___| |     ___  __| | __ _  ___ _ __     | |__) | __ ___ | |_ ___   ___ ___ | |
+ | |  | | '_ \ / _ \ |    / _ \/ _` |/ _` |/ _ \ '__|    |  ___/ '__/ _ \| __/ _ \ / __/ _ \| |
+ | |__| | | | |  __/ |___|  __/ (_| | (_| |  __/ |       | |   | | | (_) | || (_) | (_| (_) | |
+  \____/|_| |_|\___|______\___|\__,_|\__, |\___|_|       |_|   |_|  \___/ \__\___/ \___\___/|_|
+                                      __/ |
+                                     |___/
+
+	Copyright 2017 - 2019 OneLedger
+
+*/
+
+// Package snapshot creates, serves and restores chunked snapshots of the chain state,
+// so that a new node can start from a recent height instead of replaying every block.
+package snapshot
+
+import (
+	"bytes"
+	"crypto/sha256"
+
+	"github.com/pkg/errors"
+
+	"github.com/Oneledger/protocol/data/fees"
+)
+
+// Format is bumped whenever the layout of chunks changes
+const Format = 2
+
+// History is the number of versions before the snapshot height that a snapshot carries.
+// Fee withdrawals read the state that far back, see fees.Store.GetAllowedWithdraw.
+const History = fees.FEE_LOCK_BLOCKS
+
+const (
+	DefaultChunkSize = 4 << 20
+
+	manifestFile  = "manifest.json"
+	consensusFile = "consensus"
+	chunkPattern  = "chunk-%05d"
+)
+
+var (
+	ErrNotFound           = errors.New("snapshot not found")
+	ErrChunkHash          = errors.New("chunk does not match the manifest")
+	ErrUntrustedAppHash   = errors.New("snapshot app hash does not match the trusted app hash")
+	ErrUntrustedConsensus = errors.New("consensus data is not verified by the trusted header")
+	ErrChainID            = errors.New("snapshot belongs to a different chain")
+	ErrFormat             = errors.New("unsupported snapshot format")
+	ErrHistory            = errors.New("snapshot does not carry the versions before its height")
+)
+
+// Version is the root hash of one chainstate version in a snapshot
+type Version struct {
+	Height  int64  `json:"height"`
+	AppHash []byte `json:"appHash"`
+}
+
+// Chunk describes one chunk file of a snapshot
+type Chunk struct {
+	Hash []byte `json:"hash"`
+	Size int    `json:"size"`
+}
+
+// Manifest describes a snapshot taken after the block at Height was committed
+type Manifest struct {
+	Format  int    `json:"format"`
+	ChainID string `json:"chainId"`
+	Height  int64  `json:"height"`
+	// Root hash of the chainstate at Height, the app hash of the next block header
+	AppHash []byte `json:"appHash"`
+	// The versions before Height, newest first, see History
+	History []Version `json:"history"`
+	Chunks  []Chunk   `json:"chunks"`
+
+	// Hash of the tendermint state and last block needed to start consensus at Height.
+	// Empty if the snapshot was taken at a height tendermint had already moved past.
+	ConsensusHash []byte `json:"consensusHash,omitempty"`
+}
+
+func hashOf(data []byte) []byte {
+	h := sha256.Sum256(data)
+	return h[:]
+}
+
+// historyHeights returns the heights of the versions a snapshot at height carries before it,
+// newest first. There are fewer close to the start of the chain.
+func historyHeights(height int64) []int64 {
+	heights := make([]int64, 0, History)
+	for h := height - 1; h > 0 && h >= height-History; h-- {
+		heights = append(heights, h)
+	}
+	return heights
+}
+
+// verifyHistory checks that the manifest carries the versions before its height, with the
+// root hashes of trusted, the app hashes after Height and each height before it, newest first
+func (m *Manifest) verifyHistory(trusted [][]byte) error {
+	heights := historyHeights(m.Height)
+	if len(m.History) != len(heights) {
+		return errors.Wrapf(ErrHistory, "%d versions, not %d", len(m.History), len(heights))
+	}
+	if len(trusted) < len(heights)+1 {
+		return errors.Wrapf(ErrUntrustedAppHash, "%d trusted app hashes for %d versions", len(trusted), len(heights)+1)
+	}
+	if !bytes.Equal(m.AppHash, trusted[0]) {
+		return ErrUntrustedAppHash
+	}
+	for i, v := range m.History {
+		if v.Height != heights[i] {
+			return errors.Wrapf(ErrHistory, "version %d instead of %d", v.Height, heights[i])
+		}
+		if !bytes.Equal(v.AppHash, trusted[i+1]) {
+			return errors.Wrapf(ErrUntrustedAppHash, "version %d", v.Height)
+		}
+	}
+	return nil
+}
+
+// versions returns the heights and root hashes of all versions in the snapshot, newest first
+func (m *Manifest) versions() ([]int64, [][]byte) {
+	heights := []int64{m.Height}
+	roots := [][]byte{m.AppHash}
+	for _, v := range m.History {
+		heights = append(heights, v.Height)
+		roots = append(roots, v.AppHash)
+	}
+	return heights, roots
+}
+
+func (m *Manifest) verifyChunk(index int, data []byte) error {
+	if index < 0 || index >= len(m.Chunks) {
+		return errors.Wrapf(ErrNotFound, "chunk %d", index)
+	}
+	if !bytes.Equal(m.Chunks[index].Hash, hashOf(data)) {
+		return errors.Wrapf(ErrChunkHash, "chunk %d", index)
+	}
+	return nil
+}
+
+func (m *Manifest) verifyConsensus(data []byte) error {
+	if !bytes.Equal(m.ConsensusHash, hashOf(data)) {
+		return errors.Wrap(ErrChunkHash, "consensus data")
+	}
+	return nil
+}
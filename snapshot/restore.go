@@ -0,0 +1,84 @@
+package snapshot
+
+import (
+	"github.com/pkg/errors"
+	tmdb "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/types"
+
+	"github.com/Oneledger/protocol/storage"
+)
+
+// Restore fetches the snapshot at height from src and writes it into empty databases.
+// trustedAppHashes must come from a source the operator trusts, e.g. the headers of blocks
+// height+1 and down from a known validator. They are the app hashes after height and each
+// of the History heights before it, newest first; nothing from src is accepted before it
+// is checked against them. The tendermint data is checked against trustedHeader, that header itself,
+// and can't be restored without it. stateDB and blockDB may be nil to restore only the
+// chainstate.
+func Restore(src Source, height int64, chainID string, trustedAppHashes [][]byte, trustedHeader *types.Header,
+	appDB, stateDB, blockDB tmdb.DB) (*Manifest, error) {
+
+	m, err := src.Manifest(height)
+	if err != nil {
+		return nil, err
+	}
+	if m.Format != Format {
+		return nil, errors.Wrapf(ErrFormat, "format %d", m.Format)
+	}
+	if m.Height != height {
+		return nil, errors.Errorf("manifest is for height %d, not %d", m.Height, height)
+	}
+	if chainID != "" && m.ChainID != chainID {
+		return nil, ErrChainID
+	}
+	err = m.verifyHistory(trustedAppHashes)
+	if err != nil {
+		return nil, err
+	}
+	withConsensus := len(m.ConsensusHash) != 0 && stateDB != nil && blockDB != nil
+	if withConsensus && trustedHeader == nil {
+		return nil, errors.Wrap(ErrUntrustedConsensus, "a trusted header is needed to restore the tendermint data")
+	}
+
+	heights, roots := m.versions()
+	importer, err := storage.NewTreeImporter(appDB, heights, roots)
+	if err != nil {
+		return nil, err
+	}
+	for i := range m.Chunks {
+		chunk, err := src.Chunk(height, i)
+		if err != nil {
+			return nil, err
+		}
+		err = m.verifyChunk(i, chunk)
+		if err != nil {
+			return nil, err
+		}
+		err = importer.AddChunk(chunk)
+		if err != nil {
+			return nil, errors.Wrapf(err, "chunk %d", i)
+		}
+	}
+	err = importer.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	if !withConsensus {
+		return m, nil
+	}
+
+	data, err := src.Consensus(height)
+	if err != nil {
+		return nil, err
+	}
+	err = m.verifyConsensus(data)
+	if err != nil {
+		return nil, err
+	}
+	err = ImportConsensus(data, stateDB, blockDB, m, trustedHeader)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
@@ -0,0 +1,238 @@
+package snapshot
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tmdb "github.com/tendermint/tendermint/libs/db"
+
+	"github.com/Oneledger/protocol/data/balance"
+	"github.com/Oneledger/protocol/data/chain"
+	"github.com/Oneledger/protocol/data/fees"
+	"github.com/Oneledger/protocol/data/keys"
+	"github.com/Oneledger/protocol/storage"
+)
+
+// newChainState commits versions with keys new keys each, it returns the root hash of every
+// version to trust
+func newChainState(t *testing.T, versions, keys int) (*storage.ChainState, map[int64][]byte) {
+	cs := storage.NewChainState("chainstate", tmdb.NewMemDB())
+	cs.SetupRotation(10, 100, 10)
+	hashes := make(map[int64][]byte)
+	for v := 0; v < versions; v++ {
+		for k := 0; k < keys; k++ {
+			key := storage.StoreKey(fmt.Sprintf("key-%d-%d", v, k))
+			require.NoError(t, cs.Set(key, []byte(fmt.Sprintf("value-%d", v))))
+		}
+		hash, version := cs.Commit()
+		hashes[version] = hash
+	}
+	return cs, hashes
+}
+
+// trusted returns the app hashes a restore at height checks the snapshot against
+func trusted(hashes map[int64][]byte, height int64) [][]byte {
+	list := [][]byte{hashes[height]}
+	for _, h := range historyHeights(height) {
+		list = append(list, hashes[h])
+	}
+	return list
+}
+
+func newStore(t *testing.T) (*Store, func()) {
+	dir, err := ioutil.TempDir("", "snapshots")
+	require.NoError(t, err)
+	return NewStore(dir), func() { os.RemoveAll(dir) }
+}
+
+func TestCreateRestore(t *testing.T) {
+	cs, hashes := newChainState(t, 6, 50)
+	store, cleanup := newStore(t)
+	defer cleanup()
+
+	// an older version keeps its own root hash
+	m, err := store.Create(cs, "test-chain", cs.LastVersion, 512, nil)
+	require.NoError(t, err)
+	assert.Equal(t, cs.LastHash, m.AppHash)
+	assert.True(t, len(m.Chunks) > 1)
+
+	m, err = store.Create(cs, "test-chain", cs.Version, 512, nil)
+	require.NoError(t, err)
+	assert.Equal(t, cs.Hash, m.AppHash)
+
+	list, err := store.List()
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, cs.Version, list[0].Height)
+
+	require.Len(t, m.History, int(History))
+	assert.Equal(t, cs.Version-1, m.History[0].Height)
+	assert.Equal(t, cs.LastHash, m.History[0].AppHash)
+
+	db := tmdb.NewMemDB()
+	_, err = Restore(store, cs.Version, "test-chain", trusted(hashes, cs.Version), nil, db, nil, nil)
+	require.NoError(t, err)
+
+	restored := storage.NewChainState("chainstate", db)
+	assert.Equal(t, cs.Version, restored.Version)
+	assert.Equal(t, cs.Hash, restored.Hash)
+	_, value := restored.GetLatestVersioned(storage.StoreKey("key-2-10"))
+	assert.Equal(t, []byte("value-2"), value)
+
+	// the versions before the height read as they did on the original node
+	assert.Equal(t, []int64{cs.Version - 3, cs.Version - 2, cs.Version - 1, cs.Version}, restored.AvailableVersions())
+	_, value, err = restored.GetVersioned(cs.Version-History, storage.StoreKey("key-2-10"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value-2"), value)
+	_, value, err = restored.GetVersioned(cs.Version-History, storage.StoreKey(fmt.Sprintf("key-%d-10", cs.Version-1)))
+	require.NoError(t, err)
+	assert.Nil(t, value)
+
+	// close to the start of the chain there are fewer versions before the height
+	m, err = store.Create(cs, "test-chain", 2, 512, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []Version{{Height: 1, AppHash: hashes[1]}}, m.History)
+}
+
+func TestCreate_NeedsHistory(t *testing.T) {
+	cs, _ := newChainState(t, 5, 10)
+	// keep the last version only
+	cs.SetupRotation(0, 0, 0)
+	cs.Commit()
+	cs.Commit()
+	store, cleanup := newStore(t)
+	defer cleanup()
+
+	_, err := store.Create(cs, "test-chain", cs.Version, 512, nil)
+	assert.Equal(t, storage.ErrVersionPruned, errors.Cause(err))
+	list, err := store.List()
+	require.NoError(t, err)
+	assert.Empty(t, list)
+}
+
+// a node restored from a snapshot reads the fees locked FEE_LOCK_BLOCKS back like any other
+func TestRestore_FeeWithdraw(t *testing.T) {
+	cs := storage.NewChainState("chainstate", tmdb.NewMemDB())
+	cs.SetupRotation(10, 100, 10)
+	state := storage.NewState(cs)
+	feeOpt := &fees.FeeOption{FeeCurrency: balance.Currency{Name: "OLT", Chain: chain.ONELEDGER, Decimal: 18}, MinFeeDecimal: 9}
+	feeStore := fees.NewStore("f", state)
+	feeStore.SetupOpt(feeOpt)
+
+	addr := keys.Address("validator")
+	hashes := make(map[int64][]byte)
+	for v := 0; v < 8; v++ {
+		require.NoError(t, feeStore.AddToAddress(addr, feeOpt.FeeCurrency.NewCoinFromInt(1)))
+		hash, version := state.Commit()
+		hashes[version] = hash
+	}
+	expected, err := feeStore.GetAllowedWithdraw(addr)
+	require.NoError(t, err)
+	assert.Equal(t, feeOpt.FeeCurrency.NewCoinFromInt(8-fees.FEE_LOCK_BLOCKS).String(), expected.String())
+
+	store, cleanup := newStore(t)
+	defer cleanup()
+	_, err = store.Create(cs, "test-chain", cs.Version, 512, nil)
+	require.NoError(t, err)
+	db := tmdb.NewMemDB()
+	_, err = Restore(store, cs.Version, "test-chain", trusted(hashes, cs.Version), nil, db, nil, nil)
+	require.NoError(t, err)
+
+	restored := fees.NewStore("f", storage.NewState(storage.NewChainState("chainstate", db)))
+	restored.SetupOpt(feeOpt)
+	allowed, err := restored.GetAllowedWithdraw(addr)
+	require.NoError(t, err)
+	assert.Equal(t, expected.String(), allowed.String())
+}
+
+func TestRestore_Rejects(t *testing.T) {
+	cs, hashes := newChainState(t, 5, 50)
+	store, cleanup := newStore(t)
+	defer cleanup()
+
+	_, err := store.Create(cs, "test-chain", cs.Version, 512, nil)
+	require.NoError(t, err)
+	good := trusted(hashes, cs.Version)
+
+	_, err = Restore(store, cs.Version, "test-chain", trusted(hashes, cs.LastVersion), nil, tmdb.NewMemDB(), nil, nil)
+	assert.Equal(t, ErrUntrustedAppHash, errors.Cause(err))
+
+	// every version before the height is checked, not only the last one
+	bad := append([][]byte{}, good...)
+	bad[len(bad)-1] = hashes[1]
+	_, err = Restore(store, cs.Version, "test-chain", bad, nil, tmdb.NewMemDB(), nil, nil)
+	assert.Equal(t, ErrUntrustedAppHash, errors.Cause(err))
+	_, err = Restore(store, cs.Version, "test-chain", good[:1], nil, tmdb.NewMemDB(), nil, nil)
+	assert.Equal(t, ErrUntrustedAppHash, errors.Cause(err))
+
+	_, err = Restore(store, cs.Version, "other-chain", good, nil, tmdb.NewMemDB(), nil, nil)
+	assert.Equal(t, ErrChainID, errors.Cause(err))
+
+	_, err = Restore(store, cs.Version+1, "test-chain", good, nil, tmdb.NewMemDB(), nil, nil)
+	assert.Equal(t, ErrNotFound, errors.Cause(err))
+
+	// a snapshot without the versions before its height would fail fee withdrawals
+	m, err := store.Manifest(cs.Version)
+	require.NoError(t, err)
+	m.History = m.History[:1]
+	_, err = Restore(&fixedManifest{Source: store, m: m}, cs.Version, "test-chain", good, nil, tmdb.NewMemDB(), nil, nil)
+	assert.Equal(t, ErrHistory, errors.Cause(err))
+
+	// a tampered chunk is caught by the manifest
+	name := filepath.Join(store.heightDir(cs.Version), fmt.Sprintf(chunkPattern, 1))
+	chunk, err := ioutil.ReadFile(name)
+	require.NoError(t, err)
+	chunk[len(chunk)-1] ^= 0xff
+	require.NoError(t, ioutil.WriteFile(name, chunk, 0664))
+
+	_, err = Restore(store, cs.Version, "test-chain", good, nil, tmdb.NewMemDB(), nil, nil)
+	assert.Equal(t, ErrChunkHash, errors.Cause(err))
+
+	// and a manifest rewritten to match it by the node hashes
+	m, err = store.Manifest(cs.Version)
+	require.NoError(t, err)
+	m.Chunks[1].Hash = hashOf(chunk)
+	_, err = Restore(&fixedManifest{Source: store, m: m}, cs.Version, "test-chain", good, nil, tmdb.NewMemDB(), nil, nil)
+	assert.Equal(t, storage.ErrSnapshotCorrupted, errors.Cause(err))
+}
+
+func TestHTTPSource(t *testing.T) {
+	cs, hashes := newChainState(t, 3, 50)
+	store, cleanup := newStore(t)
+	defer cleanup()
+
+	_, err := store.Create(cs, "test-chain", cs.Version, 512, nil)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(store.Handler())
+	defer server.Close()
+
+	src := NewHTTPSource(server.URL)
+	list, err := src.List()
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+
+	db := tmdb.NewMemDB()
+	_, err = Restore(src, cs.Version, "test-chain", trusted(hashes, cs.Version), nil, db, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, cs.Hash, storage.NewChainState("chainstate", db).Hash)
+
+	_, err = src.Manifest(cs.Version + 1)
+	assert.Equal(t, ErrNotFound, errors.Cause(err))
+}
+
+type fixedManifest struct {
+	Source
+	m *Manifest
+}
+
+func (f *fixedManifest) Manifest(height int64) (*Manifest, error) {
+	return f.m, nil
+}
@@ -0,0 +1,163 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/Oneledger/protocol/storage"
+)
+
+// Source is anything snapshots can be restored from
+type Source interface {
+	Manifest(height int64) (*Manifest, error)
+	Chunk(height int64, index int) ([]byte, error)
+	Consensus(height int64) ([]byte, error)
+}
+
+var _ Source = &Store{}
+
+// Store keeps snapshots on disk, one directory per height
+type Store struct {
+	dir string
+}
+
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+func (s *Store) heightDir(height int64) string {
+	return filepath.Join(s.dir, strconv.FormatInt(height, 10))
+}
+
+// Create writes a snapshot of the given chainstate version and the History versions before it.
+// It fails if any of them has been pruned. consensus may be nil if tendermint data for the height
+// is not available.
+func (s *Store) Create(cs *storage.ChainState, chainID string, height int64, chunkSize int, consensus []byte) (*Manifest, error) {
+	dir := s.heightDir(height)
+	if _, err := os.Stat(dir); err == nil {
+		return nil, errors.Errorf("snapshot at height %d already exists", height)
+	}
+
+	tmp := dir + ".tmp"
+	_ = os.RemoveAll(tmp)
+	err := os.MkdirAll(tmp, 0775)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	m := &Manifest{
+		Format:  Format,
+		ChainID: chainID,
+		Height:  height,
+		Chunks:  make([]Chunk, 0),
+	}
+
+	heights := append([]int64{height}, historyHeights(height)...)
+	roots, err := cs.ExportVersions(heights, chunkSize, func(chunk []byte) error {
+		name := filepath.Join(tmp, fmt.Sprintf(chunkPattern, len(m.Chunks)))
+		m.Chunks = append(m.Chunks, Chunk{Hash: hashOf(chunk), Size: len(chunk)})
+		return ioutil.WriteFile(name, chunk, 0664)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to export chainstate")
+	}
+	m.AppHash = roots[0]
+	m.History = make([]Version, 0, len(heights)-1)
+	for i := 1; i < len(heights); i++ {
+		m.History = append(m.History, Version{Height: heights[i], AppHash: roots[i]})
+	}
+
+	if consensus != nil {
+		m.ConsensusHash = hashOf(consensus)
+		err = ioutil.WriteFile(filepath.Join(tmp, consensusFile), consensus, 0664)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	bz, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	err = ioutil.WriteFile(filepath.Join(tmp, manifestFile), bz, 0664)
+	if err != nil {
+		return nil, err
+	}
+
+	return m, os.Rename(tmp, dir)
+}
+
+// List returns the manifests of all snapshots, newest first
+func (s *Store) List() ([]*Manifest, error) {
+	entries, err := ioutil.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return []*Manifest{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	manifests := make([]*Manifest, 0, len(entries))
+	for _, e := range entries {
+		height, err := strconv.ParseInt(e.Name(), 10, 64)
+		if err != nil || !e.IsDir() {
+			continue
+		}
+		m, err := s.Manifest(height)
+		if err != nil {
+			continue
+		}
+		manifests = append(manifests, m)
+	}
+
+	sort.Slice(manifests, func(i, j int) bool {
+		return manifests[i].Height > manifests[j].Height
+	})
+	return manifests, nil
+}
+
+func (s *Store) Manifest(height int64) (*Manifest, error) {
+	bz, err := ioutil.ReadFile(filepath.Join(s.heightDir(height), manifestFile))
+	if os.IsNotExist(err) {
+		return nil, errors.Wrapf(ErrNotFound, "height %d", height)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Manifest{}
+	err = json.Unmarshal(bz, m)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid manifest")
+	}
+	return m, nil
+}
+
+func (s *Store) Chunk(height int64, index int) ([]byte, error) {
+	return s.readFile(height, fmt.Sprintf(chunkPattern, index))
+}
+
+func (s *Store) Consensus(height int64) ([]byte, error) {
+	return s.readFile(height, consensusFile)
+}
+
+func (s *Store) readFile(height int64, name string) ([]byte, error) {
+	bz, err := ioutil.ReadFile(filepath.Join(s.heightDir(height), name))
+	if os.IsNotExist(err) {
+		return nil, errors.Wrapf(ErrNotFound, "%s at height %d", name, height)
+	}
+	return bz, err
+}
+
+// Delete removes the snapshot at height
+func (s *Store) Delete(height int64) error {
+	return os.RemoveAll(s.heightDir(height))
+}
@@ -10,6 +10,8 @@ const (
 	DomainMissing       = 100102
 	OwnerAddressMissing = 100103
 	OnSaleFlagNotSet    = 100104
+	InvalidBlocks       = 100105
+	InvalidChain        = 100106
 
 	IOError        = 1002
 	IOErrorNodeKey = 100201
@@ -26,6 +28,8 @@ const (
 	AccountNotFound       = 100501
 	DomainNotFound        = 100502
 	CurrencyNotFound      = 100503
+	RecordNotFound        = 100504
+	AuctionNotFound       = 100505
 
 	InternalError                           = 1006
 	InternalErrorSerialization              = 100601
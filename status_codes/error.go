@@ -64,10 +64,14 @@ var (
 	ErrFindingCurrency = ProtocolError{CurrencyNotFound, "error  finding currency"}
 
 	// ONS errors
-	ErrBadName        = ProtocolError{DomainMissing, "domain name not provided"}
-	ErrBadOwner       = ProtocolError{OwnerAddressMissing, "owner address not provided"}
-	ErrDomainNotFound = ProtocolError{DomainNotFound, "domain not found"}
-	ErrFlagNotSet     = ProtocolError{OnSaleFlagNotSet, "onsale flag not set"}
+	ErrBadName         = ProtocolError{DomainMissing, "domain name not provided"}
+	ErrBadOwner        = ProtocolError{OwnerAddressMissing, "owner address not provided"}
+	ErrDomainNotFound  = ProtocolError{DomainNotFound, "domain not found"}
+	ErrFlagNotSet      = ProtocolError{OnSaleFlagNotSet, "onsale flag not set"}
+	ErrBadBlocks       = ProtocolError{InvalidBlocks, "number of blocks must not be negative"}
+	ErrBadChain        = ProtocolError{InvalidChain, "unknown chain"}
+	ErrNoRecord        = ProtocolError{RecordNotFound, "domain has no address on this chain"}
+	ErrAuctionNotFound = ProtocolError{AuctionNotFound, "auction not found"}
 
 	// Tx errors
 
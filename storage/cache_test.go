@@ -21,7 +21,7 @@ import (
 )
 
 func TestNewCache(t *testing.T) {
-	assert.Equal(t, &cache{"name", map[string][]byte{}}, NewCache("name"))
+	assert.Equal(t, &cache{name: "name", store: map[string][]byte{}, keys: []string{}}, NewCache("name"))
 }
 
 func TestCache_SetGet(t *testing.T) {
@@ -42,6 +42,8 @@ type ChainState struct {
 	Hash        []byte
 	TreeHeight  int8
 
+	db tmdb.DB
+
 	//persistent data config
 
 	// "recent" : latest number of version to persist
@@ -134,8 +136,37 @@ func (state *ChainState) GetLatestVersioned(key StoreKey) (int64, []byte) {
 
 }
 
-func (state *ChainState) GetVersioned(version int64, key StoreKey) (int64, []byte) {
-	return state.Delivered.GetVersioned(key, version)
+// GetVersioned reads key at an earlier version. Versions before the first block read as empty,
+// versions removed by pruning return ErrVersionPruned.
+func (state *ChainState) GetVersioned(version int64, key StoreKey) (int64, []byte, error) {
+	if version <= 0 {
+		return 0, nil, nil
+	}
+	if version > state.Delivered.Version() {
+		return 0, nil, ErrVersionNotFound
+	}
+	if !state.Delivered.VersionExists(version) {
+		return 0, nil, ErrVersionPruned
+	}
+
+	index, value := state.Delivered.GetVersioned(key, version)
+	return index, value, nil
+}
+
+// AvailableVersions returns the versions that have not been pruned, in ascending order
+func (state *ChainState) AvailableVersions() []int64 {
+	state.RLock()
+	defer state.RUnlock()
+
+	versions := make([]int64, 0)
+	it := state.db.Iterator(rootKeyFormat.Key(), []byte{rootKeyFormat.Key()[0] + 1})
+	defer it.Close()
+	for ; it.Valid(); it.Next() {
+		var version int64
+		rootKeyFormat.Scan(it.Key(), &version)
+		versions = append(versions, version)
+	}
+	return versions
 }
 
 // TODO: Should be against the commit tree, not the delivered one!!!
@@ -190,6 +221,7 @@ func (state *ChainState) Commit() ([]byte, int64) {
 
 // Reset the chain state from persistence
 func (state *ChainState) loadDB(db tmdb.DB) ([]byte, int64) {
+	state.db = db
 	tree := iavl.NewMutableTree(db, CHAINSTATE_CACHE_SIZE) // Do I need a historic tree here?
 	version, err := tree.Load()
 	if err != nil {
@@ -209,6 +241,24 @@ func (state *ChainState) loadDB(db tmdb.DB) ([]byte, int64) {
 	return state.Hash, state.Version
 }
 
+// LoadVersion points the delivered tree at an older version without deleting the later ones,
+// for reading the state at that version. Nothing should be committed afterwards.
+func (state *ChainState) LoadVersion(version int64) error {
+	state.Lock()
+	defer state.Unlock()
+
+	_, err := state.Delivered.LoadVersion(version)
+	if err != nil {
+		log.Error("failed to load chainstate version", "version", version, "err", err)
+		return ErrVersionNotFound
+	}
+
+	state.Hash = state.Delivered.Hash()
+	state.Version = state.Delivered.Version()
+	state.TreeHeight = state.Delivered.Height()
+	return nil
+}
+
 func (state *ChainState) ClearFrom(version int64) error {
 	version, err := state.Delivered.LoadVersionForOverwriting(version)
 	log.Info("cleared version after: ", version)
@@ -19,12 +19,13 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	tmdb "github.com/tendermint/tendermint/libs/db"
 )
 
 func TestPersistence(t *testing.T) {
 	log.Debug("Create new chain state")
 
-	state := NewChainState("PersistentTest", "./", "goleveldb", PERSISTENT)
+	state := NewChainState("PersistentTest", tmdb.NewMemDB())
 
 	key := "Hello"
 	value := "The Value"
@@ -46,7 +47,7 @@ func TestPersistence(t *testing.T) {
 }
 
 func TestChainState(t *testing.T) {
-	state := NewChainState("ChainState", "./", "goleveldb", PERSISTENT)
+	state := NewChainState("ChainState", tmdb.NewMemDB())
 	key := make([]byte, 20)
 	key[0] = 0xaf
 	value := []byte("value1")
@@ -54,14 +55,15 @@ func TestChainState(t *testing.T) {
 	err := state.Set(key, value)
 	assert.Nil(t, err)
 	state.Commit()
-	result := state.Get(key, false)
+	result, err := state.Get(key)
+	assert.Nil(t, err)
 
 	assert.Equal(t, value, result, "These should be equal")
 }
 
 func TestChainStateContinueUpdate(t *testing.T) {
 
-	state := NewChainState("Continue", "./", "goleveldb", PERSISTENT)
+	state := NewChainState("Continue", tmdb.NewMemDB())
 
 	key := make([]byte, 20)
 	key[0] = 0x03
@@ -74,7 +76,7 @@ func TestChainStateContinueUpdate(t *testing.T) {
 	state.Commit()
 
 	//_, b1 := state.Delivered.ImmutableTree.Get(key)
-	r1 := state.Get(key, false)
+	r1, _ := state.Get(key)
 
 	assert.Equal(t, value, r1, "value not eqaul after commit")
 
@@ -83,7 +85,7 @@ func TestChainStateContinueUpdate(t *testing.T) {
 	err = state.Set(key, value2)
 	assert.Nil(t, err)
 
-	r2 := state.Get(key, false)
+	r2, _ := state.Get(key)
 
 	assert.Equal(t, value2, r2, "value not eqaul without commit")
 
@@ -91,7 +93,8 @@ func TestChainStateContinueUpdate(t *testing.T) {
 
 func TestChainState_Commit(t *testing.T) {
 
-	state := NewChainState("commit", "./", "goleveldb", PERSISTENT)
+	db := tmdb.NewMemDB()
+	state := NewChainState("commit", db)
 
 	key := make([]byte, 20)
 	key[0] = 0x05
@@ -101,15 +104,39 @@ func TestChainState_Commit(t *testing.T) {
 
 	hash, version := state.Commit()
 
-	// Force the database to completely close, then repoen it.
-	state.database.Close()
-	state.database = nil
-
-	// Update all of the chain parameters
-	nhash, nversion := state.reset()
+	// Load the chain state again from the database
+	nhash, nversion := NewChainState("commit", db).loadDB(db)
 
 	// Check the reset
 	assert.Equal(t, 0, bytes.Compare(hash, nhash), "hash of persistent after commit not match")
 
 	assert.Equal(t, version, nversion, "version of persistent after commit not match")
 }
+
+func TestChainState_Pruning(t *testing.T) {
+	state := NewChainState("pruning", tmdb.NewMemDB())
+	// keep the last 2 versions and every 3rd one, one cycle of those
+	state.SetupRotation(2, 3, 1)
+
+	key := StoreKey("key")
+	for i := 1; i <= 10; i++ {
+		assert.NoError(t, state.Set(key, []byte{byte(i)}))
+		state.Commit()
+	}
+	assert.Equal(t, []int64{6, 8, 9, 10}, state.AvailableVersions())
+
+	_, value, err := state.GetVersioned(6, key)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{6}, value)
+
+	_, value, err = state.GetVersioned(0, key)
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+
+	_, _, err = state.GetVersioned(3, key)
+	assert.Equal(t, ErrVersionPruned, err)
+	_, _, err = state.GetVersioned(7, key)
+	assert.Equal(t, ErrVersionPruned, err)
+	_, _, err = state.GetVersioned(11, key)
+	assert.Equal(t, ErrVersionNotFound, err)
+}
@@ -20,4 +20,9 @@ var (
 	ErrNotFound       = errors.New("key not found")
 	ErrSetFailed      = errors.New("failed to set data")
 	ErrExceedGasLimit = errors.New("gas exceeds limit")
+
+	ErrInvalidGasSchedule = errors.New("gas schedule has a negative cost")
+
+	ErrVersionNotFound = errors.New("version not found in chainstate")
+	ErrVersionPruned   = errors.New("version has been pruned from chainstate")
 )
@@ -2,6 +2,7 @@ package storage
 
 type Gas int64
 
+// default costs of the gas schedule, a chain can change them through governance
 const (
 	STOREBYTES Gas = 20
 	READFLAT   Gas = 20
@@ -15,6 +16,48 @@ const (
 	FLAT       Gas = 1
 )
 
+// GasSchedule holds the cost of each category of gas a tx consumes
+type GasSchedule struct {
+	// flat cost of every tx
+	TxFlat Gas `json:"txFlat"`
+
+	StoreBytes Gas `json:"storeBytes"`
+	ReadFlat   Gas `json:"readFlat"`
+	ReadBytes  Gas `json:"readBytes"`
+	WriteFlat  Gas `json:"writeFlat"`
+	WriteBytes Gas `json:"writeBytes"`
+	VerifySig  Gas `json:"verifySig"`
+	HashBytes  Gas `json:"hashBytes"`
+	CheckExist Gas `json:"checkExist"`
+	Delete     Gas `json:"delete"`
+}
+
+func DefaultGasSchedule() GasSchedule {
+	return GasSchedule{
+		TxFlat:     0,
+		StoreBytes: STOREBYTES,
+		ReadFlat:   READFLAT,
+		ReadBytes:  READBYTES,
+		WriteFlat:  WRITEFLAT,
+		WriteBytes: WRITEBYTES,
+		VerifySig:  VERIFYSIG,
+		HashBytes:  HASHBYTES,
+		CheckExist: CHECKEXIST,
+		Delete:     DELETE,
+	}
+}
+
+// Validate rejects schedules with negative costs
+func (s GasSchedule) Validate() error {
+	for _, cost := range []Gas{s.TxFlat, s.StoreBytes, s.ReadFlat, s.ReadBytes, s.WriteFlat,
+		s.WriteBytes, s.VerifySig, s.HashBytes, s.CheckExist, s.Delete} {
+		if cost < 0 {
+			return ErrInvalidGasSchedule
+		}
+	}
+	return nil
+}
+
 // Calculate the gas used for each action, will be embedded with GasStore.
 type GasCalculator interface {
 	// Consume amount of Gas for the Category
@@ -28,6 +71,9 @@ type GasCalculator interface {
 
 	// Check if the block has fullfill the Gas Limit
 	IsEnough() bool
+
+	// Get the costs the Gas is consumed with
+	Schedule() GasSchedule
 }
 
 var _ GasCalculator = &gasCalculator{}
@@ -35,6 +81,7 @@ var _ GasCalculator = &gasCalculator{}
 type gasCalculator struct {
 	limit    Gas
 	consumed Gas
+	schedule GasSchedule
 }
 
 func (g gasCalculator) IsEnough() bool {
@@ -67,10 +114,19 @@ func (g gasCalculator) GetConsumed() Gas {
 	return g.consumed
 }
 
+func (g gasCalculator) Schedule() GasSchedule {
+	return g.schedule
+}
+
 func NewGasCalculator(limit Gas) GasCalculator {
+	return NewGasCalculatorWithSchedule(limit, DefaultGasSchedule())
+}
+
+func NewGasCalculatorWithSchedule(limit Gas, schedule GasSchedule) GasCalculator {
 	return &gasCalculator{
 		limit:    limit,
 		consumed: 0,
+		schedule: schedule,
 	}
 }
 
@@ -88,7 +144,7 @@ func NewGasStore(store Store, gc GasCalculator) *GasStore {
 }
 
 func (g *GasStore) Set(key StoreKey, value []byte) error {
-	ok := g.GasCalculator.Consume(Gas(1), WRITEFLAT, false)
+	ok := g.GasCalculator.Consume(Gas(1), g.Schedule().WriteFlat, false)
 	if !ok {
 		return ErrExceedGasLimit
 	}
@@ -96,12 +152,12 @@ func (g *GasStore) Set(key StoreKey, value []byte) error {
 	if err != nil {
 		return err
 	}
-	g.GasCalculator.Consume(Gas(len(value)), WRITEBYTES, true)
+	g.GasCalculator.Consume(Gas(len(value)), g.Schedule().WriteBytes, true)
 	return nil
 }
 
 func (g *GasStore) Get(key StoreKey) ([]byte, error) {
-	ok := g.GasCalculator.Consume(Gas(1), READFLAT, false)
+	ok := g.GasCalculator.Consume(Gas(1), g.Schedule().ReadFlat, false)
 	if !ok {
 		//log.Error(ErrExceedGasLimit.Error())
 		return nil, ErrExceedGasLimit
@@ -110,12 +166,12 @@ func (g *GasStore) Get(key StoreKey) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-	ok = g.GasCalculator.Consume(Gas(len(value)), READBYTES, true)
+	ok = g.GasCalculator.Consume(Gas(len(value)), g.Schedule().ReadBytes, true)
 	return value, nil
 }
 
 func (g *GasStore) Exists(key StoreKey) bool {
-	ok := g.GasCalculator.Consume(Gas(1), CHECKEXIST, false)
+	ok := g.GasCalculator.Consume(Gas(1), g.Schedule().CheckExist, false)
 	if !ok {
 		log.Error(ErrExceedGasLimit.Error())
 		return false
@@ -125,7 +181,7 @@ func (g *GasStore) Exists(key StoreKey) bool {
 }
 
 func (g *GasStore) Delete(key StoreKey) (bool, error) {
-	ok := g.GasCalculator.Consume(Gas(1), DELETE, false)
+	ok := g.GasCalculator.Consume(Gas(1), g.Schedule().Delete, false)
 	if !ok {
 		log.Error(ErrExceedGasLimit.Error())
 		return false, ErrExceedGasLimit
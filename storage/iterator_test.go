@@ -15,54 +15,35 @@ Copyright 2017 - 2019 OneLedger
 package storage
 
 import (
-	"github.com/stretchr/testify/assert"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	tmdb "github.com/tendermint/tendermint/libs/db"
 )
 
 func TestIterator(t *testing.T) {
-	items := []IterItem{
-		{[]byte("a"), []byte("a123")},
-		{[]byte("b"), []byte("a123")},
-		{[]byte("c"), []byte("a123")},
-		{[]byte("d"), []byte("a123")},
-		{[]byte("e"), []byte("a123")},
-		{[]byte("f"), []byte("a123")},
-		{[]byte("g"), []byte("a123")},
-		{[]byte("h"), []byte("a123")},
+	state := NewChainState("iterator", tmdb.NewMemDB())
+	keys := []string{"e", "a", "h", "c", "b", "g", "d", "f"}
+	for _, k := range keys {
+		assert.NoError(t, state.Set(StoreKey(k), []byte("a123")))
 	}
-
-	iter := newIterator(items)
-
-	assert.Equal(t, []byte("a"), iter.Key())
-	assert.Equal(t, []byte("a123"), iter.Value())
-	assert.True(t, iter.Next())
-
-	assert.Equal(t, []byte("b"), iter.Key())
-	assert.Equal(t, []byte("a123"), iter.Value())
-	assert.True(t, iter.Next())
-
-	assert.Equal(t, []byte("c"), iter.Key())
-	assert.Equal(t, []byte("a123"), iter.Value())
-	assert.True(t, iter.Next())
-
-	assert.Equal(t, []byte("d"), iter.Key())
-	assert.Equal(t, []byte("a123"), iter.Value())
-	assert.True(t, iter.Next())
-
-	assert.Equal(t, []byte("e"), iter.Key())
-	assert.Equal(t, []byte("a123"), iter.Value())
-	assert.True(t, iter.Next())
-
-	assert.Equal(t, []byte("f"), iter.Key())
-	assert.Equal(t, []byte("a123"), iter.Value())
-	assert.True(t, iter.Next())
-
-	assert.Equal(t, []byte("g"), iter.Key())
-	assert.Equal(t, []byte("a123"), iter.Value())
-	assert.True(t, iter.Next())
-
-	assert.Equal(t, []byte("h"), iter.Key())
-	assert.Equal(t, []byte("a123"), iter.Value())
-	assert.False(t, iter.Next())
-
+	state.Commit()
+
+	// keys come in order
+	iterated := make([]string, 0)
+	state.Iterate(func(key, value []byte) bool {
+		assert.Equal(t, []byte("a123"), value)
+		iterated = append(iterated, string(key))
+		return false
+	})
+	assert.Equal(t, []string{"a", "b", "c", "d", "e", "f", "g", "h"}, iterated)
+
+	// and stop when asked to
+	iterated = iterated[:0]
+	stopped := state.IterateRange([]byte("c"), []byte("g"), false, func(key, value []byte) bool {
+		iterated = append(iterated, string(key))
+		return string(key) == "e"
+	})
+	assert.True(t, stopped)
+	assert.Equal(t, []string{"f", "e"}, iterated)
 }
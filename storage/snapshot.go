@@ -0,0 +1,314 @@
+/*
+   ____             _              _                      _____           _                  _
+  / __ \           | |            | |                    |  __ \         | |                | |
+ | |  | |_ __   ___| |     ___  __| | __ _  ___ _ __     | |__) | __ ___ | |_ ___   ___ ___ | |
+ | |  | | '_ \ / _ \ |    / _ \/ _` |/ _` |/ _ \ '__|    |  ___/ '__/ _ \| __/ _ \ / __/ _ \| |
+ | |__| | | | |  __/ |___|  __/ (_| | (_| |  __/ |       | |   | | | (_) | || (_) | (_| (_) | |
+  \____/|_| |_|\___|______\___|\__,_|\__, |\___|_|       |_|   |_|  \___/ \__\___/ \___\___/|_|
+                                      __/ |
+                                     |___/
+
+	Copyright 2017 - 2019 OneLedger
+
+	Export and import of ChainState versions as raw IAVL nodes.
+
+	Nodes are written exactly as IAVL persists them, so an imported tree keeps
+	the node versions and therefore the root hash of the original. Every node is
+	re-hashed on import and must be referenced by an already verified parent,
+	which ties the whole import to the root hashes it was started with. Nodes
+	shared by several versions are written once.
+*/
+
+package storage
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+	"github.com/tendermint/go-amino"
+	"github.com/tendermint/iavl"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	tmdb "github.com/tendermint/tendermint/libs/db"
+)
+
+var (
+	ErrSnapshotIncomplete  = errors.New("snapshot is missing nodes")
+	ErrSnapshotUnexpected  = errors.New("snapshot contains a node not referenced by the tree")
+	ErrSnapshotCorrupted   = errors.New("snapshot node does not match its hash")
+	ErrSnapshotNonEmptyDB  = errors.New("snapshot can only be imported into an empty database")
+	nodeKeyFormat          = iavl.NewKeyFormat('n', tmhash.Size)
+	rootKeyFormat          = iavl.NewKeyFormat('r', 8)
+	orphanKeyFormat        = iavl.NewKeyFormat('o', 8, 8, tmhash.Size)
+	snapshotRecordOverhead = 2 * binary.MaxVarintLen64
+)
+
+// iavlNode holds the fields of a persisted IAVL node needed to verify it
+type iavlNode struct {
+	height    int8
+	size      int64
+	version   int64
+	key       []byte
+	value     []byte
+	leftHash  []byte
+	rightHash []byte
+}
+
+func decodeIAVLNode(buf []byte) (*iavlNode, error) {
+	node := &iavlNode{}
+
+	var n int
+	var err error
+	node.height, n, err = amino.DecodeInt8(buf)
+	if err != nil {
+		return nil, err
+	}
+	buf = buf[n:]
+
+	node.size, n, err = amino.DecodeVarint(buf)
+	if err != nil {
+		return nil, err
+	}
+	buf = buf[n:]
+
+	node.version, n, err = amino.DecodeVarint(buf)
+	if err != nil {
+		return nil, err
+	}
+	buf = buf[n:]
+
+	node.key, n, err = amino.DecodeByteSlice(buf)
+	if err != nil {
+		return nil, err
+	}
+	buf = buf[n:]
+
+	if node.height == 0 {
+		node.value, _, err = amino.DecodeByteSlice(buf)
+		return node, err
+	}
+
+	node.leftHash, n, err = amino.DecodeByteSlice(buf)
+	if err != nil {
+		return nil, err
+	}
+	buf = buf[n:]
+
+	node.rightHash, _, err = amino.DecodeByteSlice(buf)
+	return node, err
+}
+
+// hash computes the node hash the same way IAVL does
+func (node *iavlNode) hash() []byte {
+	buf := new(bytes.Buffer)
+	_ = amino.EncodeInt8(buf, node.height)
+	_ = amino.EncodeVarint(buf, node.size)
+	_ = amino.EncodeVarint(buf, node.version)
+	if node.height == 0 {
+		_ = amino.EncodeByteSlice(buf, node.key)
+		_ = amino.EncodeByteSlice(buf, tmhash.Sum(node.value))
+	} else {
+		_ = amino.EncodeByteSlice(buf, node.leftHash)
+		_ = amino.EncodeByteSlice(buf, node.rightHash)
+	}
+	return tmhash.Sum(buf.Bytes())
+}
+
+// ExportVersions walks the trees of the given versions, newest first, and passes their persisted
+// nodes to fn, grouped in chunks of roughly chunkSize bytes. Parents always come before their
+// children and a node shared with a version exported before is left out with its children. It
+// returns the root hash of each version.
+func (state *ChainState) ExportVersions(versions []int64, chunkSize int, fn func(chunk []byte) error) ([][]byte, error) {
+	state.RLock()
+	defer state.RUnlock()
+
+	roots := make([][]byte, len(versions))
+	for i, version := range versions {
+		roots[i] = state.db.Get(rootKeyFormat.Key(version))
+		if roots[i] != nil {
+			continue
+		}
+		if version > 0 && version < state.Version {
+			return nil, errors.Wrapf(ErrVersionPruned, "version %d", version)
+		}
+		return nil, errors.Wrapf(ErrVersionNotFound, "version %d", version)
+	}
+
+	chunk := new(bytes.Buffer)
+	flush := func() error {
+		if chunk.Len() == 0 {
+			return nil
+		}
+		err := fn(chunk.Bytes())
+		chunk = new(bytes.Buffer)
+		return err
+	}
+
+	exported := make(map[string]bool)
+	stack := make([][]byte, 0, 64)
+	for _, root := range roots {
+		if len(root) > 0 {
+			stack = append(stack, root)
+		}
+		for len(stack) > 0 {
+			hash := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if exported[string(hash)] {
+				continue
+			}
+			exported[string(hash)] = true
+
+			buf := state.db.Get(nodeKeyFormat.KeyBytes(hash))
+			if buf == nil {
+				return nil, errors.Wrapf(ErrSnapshotIncomplete, "node %X", hash)
+			}
+			node, err := decodeIAVLNode(buf)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to decode node %X", hash)
+			}
+			if node.height > 0 {
+				stack = append(stack, node.rightHash, node.leftHash)
+			}
+
+			if chunk.Len() > 0 && chunk.Len()+len(hash)+len(buf)+snapshotRecordOverhead > chunkSize {
+				if err := flush(); err != nil {
+					return nil, err
+				}
+			}
+			writeRecord(chunk, hash)
+			writeRecord(chunk, buf)
+		}
+	}
+
+	return roots, flush()
+}
+
+func writeRecord(w *bytes.Buffer, data []byte) {
+	var lenBuf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(lenBuf[:], uint64(len(data)))
+	w.Write(lenBuf[:n])
+	w.Write(data)
+}
+
+func readRecord(r *bytes.Reader) ([]byte, error) {
+	l, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	if l > uint64(r.Len()) {
+		return nil, errors.New("truncated snapshot record")
+	}
+	data := make([]byte, l)
+	_, err = r.Read(data)
+	return data, err
+}
+
+// TreeImporter rebuilds ChainState versions from the chunks written by ExportVersions
+type TreeImporter struct {
+	db       tmdb.DB
+	versions []int64
+	roots    [][]byte
+
+	// hashes referenced by verified parents but not imported yet, with the newest
+	// version whose tree holds them
+	pending map[string]int64
+}
+
+// NewTreeImporter prepares db to receive the nodes of the trees with the given root hashes,
+// the versions newest first as they were exported. db must not hold a chainstate already.
+func NewTreeImporter(db tmdb.DB, versions []int64, roots [][]byte) (*TreeImporter, error) {
+	if len(versions) == 0 || len(versions) != len(roots) {
+		return nil, errors.New("every version to import needs a root hash")
+	}
+	it := db.Iterator(nil, nil)
+	empty := !it.Valid()
+	it.Close()
+	if !empty {
+		return nil, ErrSnapshotNonEmptyDB
+	}
+
+	im := &TreeImporter{
+		db:       db,
+		versions: versions,
+		roots:    roots,
+		pending:  make(map[string]int64),
+	}
+	for i := len(roots) - 1; i >= 0; i-- {
+		if len(roots[i]) > 0 {
+			im.pending[string(roots[i])] = versions[i]
+		}
+	}
+	return im, nil
+}
+
+// AddChunk verifies and writes the nodes of one chunk. Chunks must be added in the order
+// they were exported.
+func (im *TreeImporter) AddChunk(chunk []byte) error {
+	batch := im.db.NewBatch()
+	// nodes of this chunk, not in the db before the batch is written
+	added := make(map[string]bool)
+	imported := func(hash []byte) bool {
+		return added[string(hash)] || im.db.Has(nodeKeyFormat.KeyBytes(hash))
+	}
+
+	r := bytes.NewReader(chunk)
+	for r.Len() > 0 {
+		hash, err := readRecord(r)
+		if err != nil {
+			return err
+		}
+		buf, err := readRecord(r)
+		if err != nil {
+			return err
+		}
+
+		version, ok := im.pending[string(hash)]
+		if !ok {
+			return errors.Wrapf(ErrSnapshotUnexpected, "node %X", hash)
+		}
+		node, err := decodeIAVLNode(buf)
+		if err != nil {
+			return errors.Wrapf(err, "failed to decode node %X", hash)
+		}
+		if !bytes.Equal(node.hash(), hash) {
+			return errors.Wrapf(ErrSnapshotCorrupted, "node %X", hash)
+		}
+
+		delete(im.pending, string(hash))
+		if node.height > 0 {
+			for _, child := range [][]byte{node.leftHash, node.rightHash} {
+				if !imported(child) && im.pending[string(child)] < version {
+					im.pending[string(child)] = version
+				}
+			}
+		}
+		batch.Set(nodeKeyFormat.KeyBytes(hash), buf)
+		added[string(hash)] = true
+
+		// IAVL deletes a node with the last version holding it, once that is pruned
+		if version < im.versions[0] {
+			batch.Set(orphanKeyFormat.Key(version, node.version, hash), hash)
+		}
+	}
+	batch.Write()
+	return nil
+}
+
+// Commit checks that every node of the trees has been imported and saves the roots,
+// after which the versions can be loaded by NewChainState.
+func (im *TreeImporter) Commit() error {
+	if len(im.pending) > 0 {
+		return errors.Wrapf(ErrSnapshotIncomplete, "%d nodes missing", len(im.pending))
+	}
+
+	batch := im.db.NewBatch()
+	for i, version := range im.versions {
+		root := im.roots[i]
+		if root == nil {
+			root = []byte{}
+		}
+		batch.Set(rootKeyFormat.Key(version), root)
+	}
+	batch.WriteSync()
+	return nil
+}
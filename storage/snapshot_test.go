@@ -0,0 +1,76 @@
+package storage
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tmdb "github.com/tendermint/tendermint/libs/db"
+)
+
+func countNodes(db tmdb.DB) int {
+	count := 0
+	it := tmdb.IteratePrefix(db, nodeKeyFormat.Key())
+	defer it.Close()
+	for ; it.Valid(); it.Next() {
+		count++
+	}
+	return count
+}
+
+func importVersions(t *testing.T, cs *ChainState, versions []int64) tmdb.DB {
+	chunks := make([][]byte, 0)
+	roots, err := cs.ExportVersions(versions, 256, func(chunk []byte) error {
+		chunks = append(chunks, append([]byte{}, chunk...))
+		return nil
+	})
+	require.NoError(t, err)
+
+	db := tmdb.NewMemDB()
+	im, err := NewTreeImporter(db, versions, roots)
+	require.NoError(t, err)
+	for _, chunk := range chunks {
+		require.NoError(t, im.AddChunk(chunk))
+	}
+	require.NoError(t, im.Commit())
+	return db
+}
+
+func TestExportImportVersions(t *testing.T) {
+	cs := NewChainState("chainstate", tmdb.NewMemDB())
+	cs.SetupRotation(10, 100, 10)
+	for v := 0; v < 6; v++ {
+		for k := 0; k < 20; k++ {
+			require.NoError(t, cs.Set(StoreKey(fmt.Sprintf("key-%d", k*(v+1))), []byte(fmt.Sprintf("value-%d", v))))
+		}
+		cs.Commit()
+	}
+	// a version without changes has the same root as the one before
+	cs.Commit()
+	top := cs.Version
+
+	db := importVersions(t, cs, []int64{top, top - 1, top - 2, top - 3})
+	restored := NewChainState("chainstate", db)
+	assert.Equal(t, []int64{top - 3, top - 2, top - 1, top}, restored.AvailableVersions())
+	for _, version := range restored.AvailableVersions() {
+		for k := 0; k < 60; k++ {
+			key := StoreKey(fmt.Sprintf("key-%d", k))
+			_, expected, err := cs.GetVersioned(version, key)
+			require.NoError(t, err)
+			_, value, err := restored.GetVersioned(version, key)
+			require.NoError(t, err)
+			assert.Equal(t, expected, value, "%s at version %d", key, version)
+		}
+	}
+
+	// once the earlier versions are pruned only the nodes of the last one are left
+	for _, version := range []int64{top - 3, top - 2, top - 1} {
+		require.NoError(t, restored.Delivered.DeleteVersion(version))
+	}
+	assert.Equal(t, countNodes(importVersions(t, cs, []int64{top})), countNodes(db))
+
+	_, err := cs.ExportVersions([]int64{top + 1}, 256, func([]byte) error { return nil })
+	assert.Equal(t, ErrVersionNotFound, errors.Cause(err))
+}
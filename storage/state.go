@@ -1,5 +1,7 @@
 package storage
 
+import "bytes"
+
 var _ Store = &State{}
 var _ Iteratable = &State{}
 
@@ -8,6 +10,11 @@ type State struct {
 	cache  Store
 	gc     GasCalculator
 	delete Store
+
+	// keys deleted since the last commit, read without gas
+	deleted Store
+	// reads skip deleted keys and setting a key cancels its delete, see WithDeleteTracking
+	trackDeletes bool
 }
 
 func (s *State) Get(key StoreKey) ([]byte, error) {
@@ -17,25 +24,40 @@ func (s *State) Get(key StoreKey) ([]byte, error) {
 		// if got result, return directly
 		return result, err
 	}
+	if s.isDeleted(key) {
+		return nil, nil
+	}
 	// if didn't get result in cache, get from ChainState
 	return s.cs.Get(key)
 }
 
 func (s *State) Set(key StoreKey, value []byte) error {
 	// set only for cache, waiting to be committed
-	return s.cache.Set(key, value)
+	err := s.cache.Set(key, value)
+	if err != nil || !s.trackDeletes {
+		return err
+	}
+	_, err = s.deleted.Delete(key)
+	return err
 }
 
 func (s *State) Exists(key StoreKey) bool {
 	// check existence in cache, because it's cheaper
 	exist := s.cache.Exists(key)
 	if !exist {
+		if s.isDeleted(key) {
+			return false
+		}
 		// if not existed in cache, check ChainState
 		return s.cs.Exists(key)
 	}
 	return exist
 }
 
+func (s *State) isDeleted(key StoreKey) bool {
+	return s.trackDeletes && s.deleted.Exists(key)
+}
+
 func (s *State) Delete(key StoreKey) (bool, error) {
 	//cache delete is always true
 	_, _ = s.cache.Delete(key)
@@ -52,30 +74,60 @@ func (s *State) GetIterator() Iteratable {
 }
 
 func (s *State) Iterate(fn func(key []byte, value []byte) bool) (stopped bool) {
-	return s.GetIterator().Iterate(fn)
+	return s.GetIterator().Iterate(s.skipDeleted(fn))
 }
 
 func (s *State) IterateRange(start, end []byte, ascending bool, fn func(key, value []byte) bool) (stop bool) {
-	return s.GetIterator().IterateRange(start, end, ascending, fn)
+	return s.GetIterator().IterateRange(start, end, ascending, s.skipDeleted(fn))
+}
+
+// skipDeleted leaves out the committed keys deleted since the last commit
+func (s *State) skipDeleted(fn func(key, value []byte) bool) func(key, value []byte) bool {
+	if !s.trackDeletes {
+		return fn
+	}
+	return func(key, value []byte) bool {
+		if s.deleted.Exists(key) {
+			return false
+		}
+		return fn(key, value)
+	}
+}
+
+// IterateUpdates walks the keys under prefix set since the last commit, each key once with its
+// latest value. Deleted keys are not visited.
+func (s *State) IterateUpdates(prefix []byte, fn func(key, value []byte) bool) (stopped bool) {
+	seen := make(map[string]bool)
+	return s.cache.GetIterator().Iterate(func(key, value []byte) bool {
+		if seen[string(key)] || !bytes.HasPrefix(key, prefix) {
+			return false
+		}
+		seen[string(key)] = true
+		return fn(key, value)
+	})
 }
 
 func NewState(state *ChainState) *State {
+	del := NewStorage(CACHE, "state_delete")
 	return &State{
-		cs:     state,
-		cache:  NewStorage(CACHE, "state"),
-		gc:     NewGasCalculator(0),
-		delete: NewStorage(CACHE, "state_delete"),
+		cs:      state,
+		cache:   NewStorage(CACHE, "state"),
+		gc:      NewGasCalculator(0),
+		delete:  del,
+		deleted: del,
 	}
 }
 
 func (s *State) WithGas(gc GasCalculator) *State {
 	gs := NewGasStore(s.cache, gc)
-	del := NewGasStore(s.delete, gc)
+	del := NewGasStore(s.deleted, gc)
 	return &State{
-		cs:     s.cs,
-		cache:  gs,
-		gc:     gc,
-		delete: del,
+		cs:           s.cs,
+		cache:        gs,
+		gc:           gc,
+		delete:       del,
+		deleted:      s.deleted,
+		trackDeletes: s.trackDeletes,
 	}
 }
 
@@ -83,9 +135,24 @@ func (s *State) WithoutGas() *State {
 	s.cache = NewStorage(CACHE, "state")
 	//s.gc = NewGasCalculator(0)
 	s.delete = NewStorage(CACHE, "state_delete")
+	s.deleted = s.delete
+	return s
+}
+
+// WithDeleteTracking makes reads skip the keys deleted since the last commit and lets a set
+// cancel the delete of its key. Without it a deleted key is still read from the last commit
+// and stays deleted even if it's set again before the commit. This changes the app hash, so
+// chains turn it on at an upgrade height, see governance.Upgrades.
+func (s *State) WithDeleteTracking(on bool) *State {
+	s.trackDeletes = on
 	return s
 }
 
+// TracksDeletes tells if reads skip the keys deleted since the last commit
+func (s State) TracksDeletes() bool {
+	return s.trackDeletes
+}
+
 func (s State) Version() int64 {
 	return s.cs.Version
 }
@@ -99,7 +166,7 @@ func (s State) Write() bool {
 		_ = s.cs.Set(key, value)
 		return false
 	})
-	s.delete.GetIterator().Iterate(func(key, value []byte) bool {
+	s.deleted.GetIterator().Iterate(func(key, value []byte) bool {
 		_, _ = s.cs.Delete(key)
 		return false
 	})
@@ -109,6 +176,10 @@ func (s State) Write() bool {
 func (s *State) Commit() (hash []byte, version int64) {
 	s.Write()
 	s.cache = NewStorage(CACHE, "state")
+	if s.trackDeletes {
+		s.deleted = NewStorage(CACHE, "state_delete")
+		s.delete = s.deleted
+	}
 	return s.cs.Commit()
 }
 
@@ -121,19 +192,24 @@ func (s *State) ConsumeUpfront(gas Gas) bool {
 }
 
 func (s *State) ConsumeVerifySigGas(gas Gas) bool {
-	return s.gc.Consume(gas, VERIFYSIG, true)
+	return s.gc.Consume(gas, s.gc.Schedule().VerifySig, true)
 }
 
 func (s *State) ConsumeStorageGas(gas Gas) bool {
-	return s.gc.Consume(gas, STOREBYTES, true)
+	return s.gc.Consume(gas, s.gc.Schedule().StoreBytes, true)
+}
+
+func (s *State) GasSchedule() GasSchedule {
+	return s.gc.Schedule()
 }
 
-func (s *State) GetVersioned(version int64, key StoreKey) []byte {
-	_, value := s.cs.GetVersioned(version, key)
-	return value
+func (s *State) GetVersioned(version int64, key StoreKey) ([]byte, error) {
+	_, value, err := s.cs.GetVersioned(version, key)
+	return value, err
 }
 
-func (s *State) GetPrevious(num int64, key StoreKey) []byte {
+// GetPrevious reads key as it was num versions before the last commit
+func (s *State) GetPrevious(num int64, key StoreKey) ([]byte, error) {
 	ver := s.cs.Version
 	return s.GetVersioned(ver-num, key)
 }
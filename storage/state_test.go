@@ -1 +1,104 @@
 package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	tmdb "github.com/tendermint/tendermint/libs/db"
+)
+
+func newTestState(trackDeletes bool) (*State, *ChainState) {
+	cs := NewChainState("state", tmdb.NewMemDB())
+	s := NewState(cs).WithDeleteTracking(trackDeletes)
+	for _, k := range []string{"a", "b", "c"} {
+		_ = s.Set(StoreKey(k), []byte("old"))
+	}
+	s.Commit()
+	return s, cs
+}
+
+func iterated(s *State) []string {
+	keys := make([]string, 0)
+	s.Iterate(func(key, value []byte) bool {
+		keys = append(keys, string(key))
+		return false
+	})
+	return keys
+}
+
+func TestState_DeleteThenRead(t *testing.T) {
+	s, _ := newTestState(true)
+
+	_, err := s.Delete(StoreKey("b"))
+	assert.NoError(t, err)
+
+	// the delete is seen before the commit
+	value, err := s.Get(StoreKey("b"))
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+	assert.False(t, s.Exists(StoreKey("b")))
+	assert.Equal(t, []string{"a", "c"}, iterated(s))
+
+	s.Commit()
+	assert.False(t, s.Exists(StoreKey("b")))
+	assert.Equal(t, []string{"a", "c"}, iterated(s))
+}
+
+func TestState_DeleteThenSet(t *testing.T) {
+	s, cs := newTestState(true)
+
+	// deleted and set again in the same block keeps the new value
+	_, err := s.Delete(StoreKey("b"))
+	assert.NoError(t, err)
+	assert.NoError(t, s.Set(StoreKey("b"), []byte("new")))
+
+	value, err := s.Get(StoreKey("b"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("new"), value)
+	assert.True(t, s.Exists(StoreKey("b")))
+
+	s.Commit()
+	value, err = cs.Get(StoreKey("b"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("new"), value)
+
+	// an earlier delete isn't applied again by later commits
+	assert.NoError(t, s.Set(StoreKey("d"), []byte("new")))
+	s.Commit()
+	assert.True(t, cs.Exists(StoreKey("b")))
+}
+
+func TestState_SetThenDelete(t *testing.T) {
+	for _, track := range []bool{false, true} {
+		s, cs := newTestState(track)
+
+		// set and then deleted in the same block is gone
+		assert.NoError(t, s.Set(StoreKey("b"), []byte("new")))
+		_, err := s.Delete(StoreKey("b"))
+		assert.NoError(t, err)
+		s.Commit()
+
+		assert.False(t, cs.Exists(StoreKey("b")))
+	}
+}
+
+// without delete tracking the state works as it did before the upgrade, replaying old blocks
+// must give the same app hash
+func TestState_WithoutDeleteTracking(t *testing.T) {
+	s, cs := newTestState(false)
+
+	_, err := s.Delete(StoreKey("b"))
+	assert.NoError(t, err)
+
+	// reads go to the last commit
+	value, err := s.Get(StoreKey("b"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("old"), value)
+	assert.True(t, s.Exists(StoreKey("b")))
+	assert.Equal(t, []string{"a", "b", "c"}, iterated(s))
+
+	// and the delete wins over a later set
+	assert.NoError(t, s.Set(StoreKey("b"), []byte("new")))
+	s.Commit()
+	assert.False(t, cs.Exists(StoreKey("b")))
+}
@@ -1,31 +1,81 @@
 package transition
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
 	"github.com/pkg/errors"
 )
 
 var (
-	errTransitionExist       = errors.New("transition already exist")
-	errTransitionInvalid     = errors.New("transition state not valid")
-	errTransitionWrongStatus = errors.New("transition from current state not allowed")
+	ErrTransitionExist   = errors.New("transition already exist")
+	ErrTransitionInvalid = errors.New("transition state not valid")
+
+	// the kinds of the errors of Process
+	ErrTransitionNotFound    = errors.New("transition not registered")
+	ErrTransitionWrongStatus = errors.New("transition from current state not allowed")
+	ErrTransitionFailed      = errors.New("transition failed")
 )
 
 const (
 	NOOP string = "noop"
+
+	// NoTransition is the status Process returns when nothing happened, for NOOP or a guard
+	// that doesn't pass
+	NoTransition Status = -1
 )
 
 type Status int
 
+// Transition moves from a status to another, Fn does the work of it. Guard, when set, tells
+// if the transition can happen yet.
 type Transition struct {
-	Name string
-	Fn   func(ctx interface{}) error
-	From Status
-	To   Status
+	Name  string
+	Fn    func(ctx interface{}) error
+	Guard func(ctx interface{}) bool
+	From  Status
+	To    Status
 }
 
+// Hook is run when a status is left or entered, an error fails the transition
+type Hook func(ctx interface{}) error
+
 type Engine interface {
 	Register(ts Transition) error
+	// OnEnter adds a hook run after the transitions to the status, OnExit one run before the
+	// transitions from it
+	OnEnter(s Status, hook Hook) error
+	OnExit(s Status, hook Hook) error
+
+	// Process runs the transition from the current status and returns the status it leads
+	// to. On an error the current status is returned, the changes to ctx are not to be kept.
 	Process(name string, ctx interface{}, current Status) (Status, error)
+
+	States() []Status
+	Transitions() []Transition
+}
+
+// Error is an error of Process, Kind is one of ErrTransitionNotFound,
+// ErrTransitionWrongStatus and ErrTransitionFailed, and errors.Cause returns it
+type Error struct {
+	Name    string
+	Current Status
+	Kind    error
+	// the error of Fn or of a hook, nil for the other kinds
+	Err error
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("%s: %s from status %d", e.Kind, e.Name, e.Current)
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *Error) Cause() error {
+	return e.Kind
 }
 
 var _ Engine = &engine{}
@@ -33,45 +83,138 @@ var _ Engine = &engine{}
 type engine struct {
 	states      []Status
 	transitions map[string]Transition
+	enter       map[Status][]Hook
+	exit        map[Status][]Hook
 }
 
 func NewEngine(states []Status) Engine {
 	return &engine{
 		states:      states,
 		transitions: make(map[string]Transition),
+		enter:       make(map[Status][]Hook),
+		exit:        make(map[Status][]Hook),
 	}
 }
 
+func (m *engine) valid(s Status) bool {
+	for _, state := range m.states {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
 func (m *engine) Register(ts Transition) error {
 	if _, ok := m.transitions[ts.Name]; ok {
-		return errTransitionExist
+		return ErrTransitionExist
 	}
-	valid := 0
-	for _, s := range m.states {
-		if ts.From == s || ts.To == s {
-			valid++
-		}
-	}
-	if valid != 2 {
-		return errTransitionInvalid
+	if !m.valid(ts.From) || !m.valid(ts.To) {
+		return ErrTransitionInvalid
 	}
 
 	m.transitions[ts.Name] = ts
 	return nil
 }
 
+func (m *engine) OnEnter(s Status, hook Hook) error {
+	if !m.valid(s) {
+		return ErrTransitionInvalid
+	}
+	m.enter[s] = append(m.enter[s], hook)
+	return nil
+}
+
+func (m *engine) OnExit(s Status, hook Hook) error {
+	if !m.valid(s) {
+		return ErrTransitionInvalid
+	}
+	m.exit[s] = append(m.exit[s], hook)
+	return nil
+}
+
 func (m *engine) Process(name string, ctx interface{}, current Status) (Status, error) {
 
 	if name == NOOP {
-		return -1, nil
+		return NoTransition, nil
 	}
 	ts, ok := m.transitions[name]
 	if !ok {
-		panic("requested transition not registered: " + name)
+		return current, &Error{Name: name, Current: current, Kind: ErrTransitionNotFound}
 	}
 	if ts.From != current {
-		return ts.From, errTransitionWrongStatus
+		return current, &Error{Name: name, Current: current, Kind: ErrTransitionWrongStatus}
+	}
+	if ts.Guard != nil && !ts.Guard(ctx) {
+		return NoTransition, nil
+	}
+
+	err := run(ctx, m.exit[ts.From], ts.Fn, m.enter[ts.To])
+	if err != nil {
+		return current, &Error{Name: name, Current: current, Kind: ErrTransitionFailed, Err: err}
+	}
+	return ts.To, nil
+}
+
+// run calls the exit hooks, fn and the enter hooks in turn, up to the first error. A panic is
+// returned as an error.
+func run(ctx interface{}, exit []Hook, fn func(ctx interface{}) error, enter []Hook) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("panic: %v", r)
+		}
+	}()
+
+	for _, hook := range exit {
+		if err := hook(ctx); err != nil {
+			return errors.Wrap(err, "exit hook")
+		}
+	}
+	if fn != nil {
+		if err := fn(ctx); err != nil {
+			return err
+		}
+	}
+	for _, hook := range enter {
+		if err := hook(ctx); err != nil {
+			return errors.Wrap(err, "enter hook")
+		}
+	}
+	return nil
+}
+
+func (m *engine) States() []Status {
+	return append([]Status{}, m.states...)
+}
+
+// Transitions returns the registered transitions by name
+func (m *engine) Transitions() []Transition {
+	list := make([]Transition, 0, len(m.transitions))
+	for _, ts := range m.transitions {
+		list = append(list, ts)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// Dot renders the states and the transitions of the engine as a Graphviz digraph, stateName
+// names the states. Guarded transitions are dashed.
+func Dot(name string, e Engine, stateName func(Status) string) string {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "digraph %q {\n", name)
+	fmt.Fprintf(b, "\trankdir=LR;\n")
+	for _, s := range e.States() {
+		fmt.Fprintf(b, "\t%q;\n", stateName(s))
+	}
+	for _, ts := range e.Transitions() {
+		style := ""
+		if ts.Guard != nil {
+			style = ", style=dashed"
+		}
+		fmt.Fprintf(b, "\t%q -> %q [label=%q%s];\n", stateName(ts.From), stateName(ts.To), ts.Name, style)
 	}
-	err := ts.Fn(ctx)
-	return ts.To, err
+	b.WriteString("}\n")
+	return b.String()
 }
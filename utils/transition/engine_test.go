@@ -0,0 +1,118 @@
+package transition
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	first Status = iota
+	second
+	third
+)
+
+type testCtx struct {
+	ready bool
+	calls []string
+	fail  string
+}
+
+func (c *testCtx) call(name string) error {
+	c.calls = append(c.calls, name)
+	if c.fail == name {
+		return errors.New(name + " failed")
+	}
+	if c.fail == "panic" && name == "fn" {
+		panic("boom")
+	}
+	return nil
+}
+
+func newTestEngine(t *testing.T) Engine {
+	e := NewEngine([]Status{first, second, third})
+	require.NoError(t, e.Register(Transition{
+		Name: "next",
+		Fn: func(ctx interface{}) error {
+			return ctx.(*testCtx).call("fn")
+		},
+		Guard: func(ctx interface{}) bool {
+			return ctx.(*testCtx).ready
+		},
+		From: first,
+		To:   second,
+	}))
+	require.NoError(t, e.OnExit(first, func(ctx interface{}) error {
+		return ctx.(*testCtx).call("exit")
+	}))
+	require.NoError(t, e.OnEnter(second, func(ctx interface{}) error {
+		return ctx.(*testCtx).call("enter")
+	}))
+	return e
+}
+
+func TestEngine_Register(t *testing.T) {
+	e := newTestEngine(t)
+	assert.Equal(t, ErrTransitionExist, e.Register(Transition{Name: "next", From: second, To: third}))
+	assert.Equal(t, ErrTransitionInvalid, e.Register(Transition{Name: "other", From: second, To: Status(7)}))
+	assert.Equal(t, ErrTransitionInvalid, e.OnEnter(Status(7), nil))
+}
+
+func TestEngine_Process(t *testing.T) {
+	e := newTestEngine(t)
+
+	s, err := e.Process(NOOP, &testCtx{}, first)
+	assert.NoError(t, err)
+	assert.Equal(t, NoTransition, s)
+
+	s, err = e.Process("missing", &testCtx{}, first)
+	assert.Equal(t, ErrTransitionNotFound, errors.Cause(err))
+	assert.Equal(t, first, s)
+
+	s, err = e.Process("next", &testCtx{ready: true}, third)
+	assert.Equal(t, ErrTransitionWrongStatus, errors.Cause(err))
+	assert.Equal(t, third, s)
+
+	ctx := &testCtx{}
+	s, err = e.Process("next", ctx, first)
+	assert.NoError(t, err)
+	assert.Equal(t, NoTransition, s)
+	assert.Empty(t, ctx.calls)
+
+	ctx = &testCtx{ready: true}
+	s, err = e.Process("next", ctx, first)
+	assert.NoError(t, err)
+	assert.Equal(t, second, s)
+	assert.Equal(t, []string{"exit", "fn", "enter"}, ctx.calls)
+}
+
+func TestEngine_ProcessFailure(t *testing.T) {
+	e := newTestEngine(t)
+
+	for _, fail := range []string{"exit", "fn", "enter", "panic"} {
+		ctx := &testCtx{ready: true, fail: fail}
+		s, err := e.Process("next", ctx, first)
+		require.Error(t, err, fail)
+		assert.Equal(t, ErrTransitionFailed, errors.Cause(err), fail)
+		assert.Equal(t, first, s, fail)
+		assert.Equal(t, "next", err.(*Error).Name)
+	}
+}
+
+func TestDot(t *testing.T) {
+	e := newTestEngine(t)
+	names := []string{"first", "second", "third"}
+	dot := Dot("test", e, func(s Status) string {
+		return names[s]
+	})
+	assert.Equal(t, `digraph "test" {
+	rankdir=LR;
+	"first";
+	"second";
+	"third";
+	"first" -> "second" [label="next", style=dashed];
+}
+`, dot)
+}